@@ -0,0 +1,1476 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: api/subscription/v1/subscription.proto
+
+// Subscription service gRPC API. It calls the same service layer as the REST API
+// and follows the same rules: permissions, tenant isolation and validation.
+//
+// Request metadata mirrors the REST headers:
+//   authorization - required: "Bearer <credential>" or the raw credential, where the credential
+//                   is an API key (sk_...) or a user token that binds the call to its user;
+//   x-time-zone   - IANA time zone of dates, UTC by default. A Timestamp that carries a date
+//                   (start_date, from, month, ...) means the calendar day it falls on in this
+//                   zone; dates are returned as the start of the day in it;
+//   x-request-id  - request ID for logs and the audit log, generated when absent.
+
+package subscriptionv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Subscription struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Id     string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	UserId string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// Organization owning a work subscription; empty for personal subscriptions.
+	OrganizationId string `protobuf:"bytes,3,opt,name=organization_id,json=organizationId,proto3" json:"organization_id,omitempty"`
+	ServiceName    string `protobuf:"bytes,4,opt,name=service_name,json=serviceName,proto3" json:"service_name,omitempty"`
+	ServiceId      string `protobuf:"bytes,5,opt,name=service_id,json=serviceId,proto3" json:"service_id,omitempty"`
+	PlanId         string `protobuf:"bytes,6,opt,name=plan_id,json=planId,proto3" json:"plan_id,omitempty"`
+	// Decimal amount, for example "9.99".
+	Price string `protobuf:"bytes,7,opt,name=price,proto3" json:"price,omitempty"`
+	// ISO 4217 code.
+	Currency string `protobuf:"bytes,8,opt,name=currency,proto3" json:"currency,omitempty"`
+	// monthly or yearly.
+	BillingPeriod string                 `protobuf:"bytes,9,opt,name=billing_period,json=billingPeriod,proto3" json:"billing_period,omitempty"`
+	StartDate     *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`
+	EndDate       *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=end_date,json=endDate,proto3" json:"end_date,omitempty"`
+	// trialing, active, paused, cancel_scheduled or ended. Ignored on create and update.
+	Status        string                 `protobuf:"bytes,12,opt,name=status,proto3" json:"status,omitempty"`
+	TrialEndDate  *timestamppb.Timestamp `protobuf:"bytes,13,opt,name=trial_end_date,json=trialEndDate,proto3" json:"trial_end_date,omitempty"`
+	CategoryIds   []string               `protobuf:"bytes,14,rep,name=category_ids,json=categoryIds,proto3" json:"category_ids,omitempty"`
+	Tags          []string               `protobuf:"bytes,15,rep,name=tags,proto3" json:"tags,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,16,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,17,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Subscription) Reset() {
+	*x = Subscription{}
+	mi := &file_api_subscription_v1_subscription_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Subscription) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Subscription) ProtoMessage() {}
+
+func (x *Subscription) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscription_v1_subscription_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Subscription.ProtoReflect.Descriptor instead.
+func (*Subscription) Descriptor() ([]byte, []int) {
+	return file_api_subscription_v1_subscription_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Subscription) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Subscription) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *Subscription) GetOrganizationId() string {
+	if x != nil {
+		return x.OrganizationId
+	}
+	return ""
+}
+
+func (x *Subscription) GetServiceName() string {
+	if x != nil {
+		return x.ServiceName
+	}
+	return ""
+}
+
+func (x *Subscription) GetServiceId() string {
+	if x != nil {
+		return x.ServiceId
+	}
+	return ""
+}
+
+func (x *Subscription) GetPlanId() string {
+	if x != nil {
+		return x.PlanId
+	}
+	return ""
+}
+
+func (x *Subscription) GetPrice() string {
+	if x != nil {
+		return x.Price
+	}
+	return ""
+}
+
+func (x *Subscription) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *Subscription) GetBillingPeriod() string {
+	if x != nil {
+		return x.BillingPeriod
+	}
+	return ""
+}
+
+func (x *Subscription) GetStartDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartDate
+	}
+	return nil
+}
+
+func (x *Subscription) GetEndDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndDate
+	}
+	return nil
+}
+
+func (x *Subscription) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Subscription) GetTrialEndDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.TrialEndDate
+	}
+	return nil
+}
+
+func (x *Subscription) GetCategoryIds() []string {
+	if x != nil {
+		return x.CategoryIds
+	}
+	return nil
+}
+
+func (x *Subscription) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *Subscription) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Subscription) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type CreateSubscriptionRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// ID, status and timestamps are ignored.
+	Subscription  *Subscription `protobuf:"bytes,1,opt,name=subscription,proto3" json:"subscription,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateSubscriptionRequest) Reset() {
+	*x = CreateSubscriptionRequest{}
+	mi := &file_api_subscription_v1_subscription_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateSubscriptionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateSubscriptionRequest) ProtoMessage() {}
+
+func (x *CreateSubscriptionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscription_v1_subscription_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateSubscriptionRequest.ProtoReflect.Descriptor instead.
+func (*CreateSubscriptionRequest) Descriptor() ([]byte, []int) {
+	return file_api_subscription_v1_subscription_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CreateSubscriptionRequest) GetSubscription() *Subscription {
+	if x != nil {
+		return x.Subscription
+	}
+	return nil
+}
+
+type CreateSubscriptionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateSubscriptionResponse) Reset() {
+	*x = CreateSubscriptionResponse{}
+	mi := &file_api_subscription_v1_subscription_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateSubscriptionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateSubscriptionResponse) ProtoMessage() {}
+
+func (x *CreateSubscriptionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscription_v1_subscription_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateSubscriptionResponse.ProtoReflect.Descriptor instead.
+func (*CreateSubscriptionResponse) Descriptor() ([]byte, []int) {
+	return file_api_subscription_v1_subscription_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CreateSubscriptionResponse) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type GetSubscriptionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSubscriptionRequest) Reset() {
+	*x = GetSubscriptionRequest{}
+	mi := &file_api_subscription_v1_subscription_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSubscriptionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSubscriptionRequest) ProtoMessage() {}
+
+func (x *GetSubscriptionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscription_v1_subscription_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSubscriptionRequest.ProtoReflect.Descriptor instead.
+func (*GetSubscriptionRequest) Descriptor() ([]byte, []int) {
+	return file_api_subscription_v1_subscription_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetSubscriptionRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type UpdateSubscriptionRequest struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Id           string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Subscription *Subscription          `protobuf:"bytes,2,opt,name=subscription,proto3" json:"subscription,omitempty"`
+	// Categories and tags are left unchanged unless the corresponding flag is set.
+	ReplaceCategoryIds bool `protobuf:"varint,3,opt,name=replace_category_ids,json=replaceCategoryIds,proto3" json:"replace_category_ids,omitempty"`
+	ReplaceTags        bool `protobuf:"varint,4,opt,name=replace_tags,json=replaceTags,proto3" json:"replace_tags,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *UpdateSubscriptionRequest) Reset() {
+	*x = UpdateSubscriptionRequest{}
+	mi := &file_api_subscription_v1_subscription_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateSubscriptionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateSubscriptionRequest) ProtoMessage() {}
+
+func (x *UpdateSubscriptionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscription_v1_subscription_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateSubscriptionRequest.ProtoReflect.Descriptor instead.
+func (*UpdateSubscriptionRequest) Descriptor() ([]byte, []int) {
+	return file_api_subscription_v1_subscription_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *UpdateSubscriptionRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateSubscriptionRequest) GetSubscription() *Subscription {
+	if x != nil {
+		return x.Subscription
+	}
+	return nil
+}
+
+func (x *UpdateSubscriptionRequest) GetReplaceCategoryIds() bool {
+	if x != nil {
+		return x.ReplaceCategoryIds
+	}
+	return false
+}
+
+func (x *UpdateSubscriptionRequest) GetReplaceTags() bool {
+	if x != nil {
+		return x.ReplaceTags
+	}
+	return false
+}
+
+type DeleteSubscriptionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteSubscriptionRequest) Reset() {
+	*x = DeleteSubscriptionRequest{}
+	mi := &file_api_subscription_v1_subscription_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteSubscriptionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteSubscriptionRequest) ProtoMessage() {}
+
+func (x *DeleteSubscriptionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscription_v1_subscription_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteSubscriptionRequest.ProtoReflect.Descriptor instead.
+func (*DeleteSubscriptionRequest) Descriptor() ([]byte, []int) {
+	return file_api_subscription_v1_subscription_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *DeleteSubscriptionRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// Subscription selection, the same as the REST query parameters.
+type SubscriptionFilter struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	UserId string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// Selects subscriptions of the organization instead of the user's.
+	OrganizationId *string `protobuf:"bytes,2,opt,name=organization_id,json=organizationId,proto3,oneof" json:"organization_id,omitempty"`
+	// Service name or any of its catalog aliases.
+	ServiceName *string `protobuf:"bytes,3,opt,name=service_name,json=serviceName,proto3,oneof" json:"service_name,omitempty"`
+	ServiceId   *string `protobuf:"bytes,4,opt,name=service_id,json=serviceId,proto3,oneof" json:"service_id,omitempty"`
+	CategoryId  *string `protobuf:"bytes,5,opt,name=category_id,json=categoryId,proto3,oneof" json:"category_id,omitempty"`
+	// Case-insensitive.
+	Tag           *string `protobuf:"bytes,6,opt,name=tag,proto3,oneof" json:"tag,omitempty"`
+	Currency      *string `protobuf:"bytes,7,opt,name=currency,proto3,oneof" json:"currency,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubscriptionFilter) Reset() {
+	*x = SubscriptionFilter{}
+	mi := &file_api_subscription_v1_subscription_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubscriptionFilter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscriptionFilter) ProtoMessage() {}
+
+func (x *SubscriptionFilter) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscription_v1_subscription_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscriptionFilter.ProtoReflect.Descriptor instead.
+func (*SubscriptionFilter) Descriptor() ([]byte, []int) {
+	return file_api_subscription_v1_subscription_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *SubscriptionFilter) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *SubscriptionFilter) GetOrganizationId() string {
+	if x != nil && x.OrganizationId != nil {
+		return *x.OrganizationId
+	}
+	return ""
+}
+
+func (x *SubscriptionFilter) GetServiceName() string {
+	if x != nil && x.ServiceName != nil {
+		return *x.ServiceName
+	}
+	return ""
+}
+
+func (x *SubscriptionFilter) GetServiceId() string {
+	if x != nil && x.ServiceId != nil {
+		return *x.ServiceId
+	}
+	return ""
+}
+
+func (x *SubscriptionFilter) GetCategoryId() string {
+	if x != nil && x.CategoryId != nil {
+		return *x.CategoryId
+	}
+	return ""
+}
+
+func (x *SubscriptionFilter) GetTag() string {
+	if x != nil && x.Tag != nil {
+		return *x.Tag
+	}
+	return ""
+}
+
+func (x *SubscriptionFilter) GetCurrency() string {
+	if x != nil && x.Currency != nil {
+		return *x.Currency
+	}
+	return ""
+}
+
+type ListSubscriptionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Filter        *SubscriptionFilter    `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSubscriptionsRequest) Reset() {
+	*x = ListSubscriptionsRequest{}
+	mi := &file_api_subscription_v1_subscription_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSubscriptionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSubscriptionsRequest) ProtoMessage() {}
+
+func (x *ListSubscriptionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscription_v1_subscription_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSubscriptionsRequest.ProtoReflect.Descriptor instead.
+func (*ListSubscriptionsRequest) Descriptor() ([]byte, []int) {
+	return file_api_subscription_v1_subscription_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ListSubscriptionsRequest) GetFilter() *SubscriptionFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+type ListSubscriptionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Subscriptions []*Subscription        `protobuf:"bytes,1,rep,name=subscriptions,proto3" json:"subscriptions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSubscriptionsResponse) Reset() {
+	*x = ListSubscriptionsResponse{}
+	mi := &file_api_subscription_v1_subscription_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSubscriptionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSubscriptionsResponse) ProtoMessage() {}
+
+func (x *ListSubscriptionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscription_v1_subscription_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSubscriptionsResponse.ProtoReflect.Descriptor instead.
+func (*ListSubscriptionsResponse) Descriptor() ([]byte, []int) {
+	return file_api_subscription_v1_subscription_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ListSubscriptionsResponse) GetSubscriptions() []*Subscription {
+	if x != nil {
+		return x.Subscriptions
+	}
+	return nil
+}
+
+type CostPeriod struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// monthly (default) or daily.
+	Proration string `protobuf:"bytes,1,opt,name=proration,proto3" json:"proration,omitempty"`
+	// from and to are calendar days in the x-time-zone zone. With monthly proration only their months
+	// matter: the period runs from the first day of the month of from to the last day of the month of to.
+	// With daily proration the days are used as is.
+	From          *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`
+	To            *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=to,proto3" json:"to,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CostPeriod) Reset() {
+	*x = CostPeriod{}
+	mi := &file_api_subscription_v1_subscription_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CostPeriod) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CostPeriod) ProtoMessage() {}
+
+func (x *CostPeriod) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscription_v1_subscription_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CostPeriod.ProtoReflect.Descriptor instead.
+func (*CostPeriod) Descriptor() ([]byte, []int) {
+	return file_api_subscription_v1_subscription_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *CostPeriod) GetProration() string {
+	if x != nil {
+		return x.Proration
+	}
+	return ""
+}
+
+func (x *CostPeriod) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *CostPeriod) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+type CalculateTotalCostRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Filter *SubscriptionFilter    `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
+	Period *CostPeriod            `protobuf:"bytes,2,opt,name=period,proto3" json:"period,omitempty"`
+	// Optional: service, category or tag.
+	GroupBy string `protobuf:"bytes,3,opt,name=group_by,json=groupBy,proto3" json:"group_by,omitempty"`
+	// Returns a line item per subscription and month; ignored with group_by.
+	LineItems     bool `protobuf:"varint,4,opt,name=line_items,json=lineItems,proto3" json:"line_items,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CalculateTotalCostRequest) Reset() {
+	*x = CalculateTotalCostRequest{}
+	mi := &file_api_subscription_v1_subscription_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CalculateTotalCostRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CalculateTotalCostRequest) ProtoMessage() {}
+
+func (x *CalculateTotalCostRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscription_v1_subscription_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CalculateTotalCostRequest.ProtoReflect.Descriptor instead.
+func (*CalculateTotalCostRequest) Descriptor() ([]byte, []int) {
+	return file_api_subscription_v1_subscription_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *CalculateTotalCostRequest) GetFilter() *SubscriptionFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+func (x *CalculateTotalCostRequest) GetPeriod() *CostPeriod {
+	if x != nil {
+		return x.Period
+	}
+	return nil
+}
+
+func (x *CalculateTotalCostRequest) GetGroupBy() string {
+	if x != nil {
+		return x.GroupBy
+	}
+	return ""
+}
+
+func (x *CalculateTotalCostRequest) GetLineItems() bool {
+	if x != nil {
+		return x.LineItems
+	}
+	return false
+}
+
+type CostSummary struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	TotalCost string                 `protobuf:"bytes,1,opt,name=total_cost,json=totalCost,proto3" json:"total_cost,omitempty"`
+	// Empty when no subscription falls into the period.
+	Currency      string          `protobuf:"bytes,2,opt,name=currency,proto3" json:"currency,omitempty"`
+	Breakdown     []*CostGroup    `protobuf:"bytes,3,rep,name=breakdown,proto3" json:"breakdown,omitempty"`
+	LineItems     []*CostLineItem `protobuf:"bytes,4,rep,name=line_items,json=lineItems,proto3" json:"line_items,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CostSummary) Reset() {
+	*x = CostSummary{}
+	mi := &file_api_subscription_v1_subscription_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CostSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CostSummary) ProtoMessage() {}
+
+func (x *CostSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscription_v1_subscription_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CostSummary.ProtoReflect.Descriptor instead.
+func (*CostSummary) Descriptor() ([]byte, []int) {
+	return file_api_subscription_v1_subscription_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *CostSummary) GetTotalCost() string {
+	if x != nil {
+		return x.TotalCost
+	}
+	return ""
+}
+
+func (x *CostSummary) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *CostSummary) GetBreakdown() []*CostGroup {
+	if x != nil {
+		return x.Breakdown
+	}
+	return nil
+}
+
+func (x *CostSummary) GetLineItems() []*CostLineItem {
+	if x != nil {
+		return x.LineItems
+	}
+	return nil
+}
+
+type CostGroup struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	TotalCost     string                 `protobuf:"bytes,3,opt,name=total_cost,json=totalCost,proto3" json:"total_cost,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CostGroup) Reset() {
+	*x = CostGroup{}
+	mi := &file_api_subscription_v1_subscription_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CostGroup) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CostGroup) ProtoMessage() {}
+
+func (x *CostGroup) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscription_v1_subscription_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CostGroup.ProtoReflect.Descriptor instead.
+func (*CostGroup) Descriptor() ([]byte, []int) {
+	return file_api_subscription_v1_subscription_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *CostGroup) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *CostGroup) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CostGroup) GetTotalCost() string {
+	if x != nil {
+		return x.TotalCost
+	}
+	return ""
+}
+
+type CostLineItem struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Month          *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=month,proto3" json:"month,omitempty"`
+	SubscriptionId string                 `protobuf:"bytes,2,opt,name=subscription_id,json=subscriptionId,proto3" json:"subscription_id,omitempty"`
+	ServiceName    string                 `protobuf:"bytes,3,opt,name=service_name,json=serviceName,proto3" json:"service_name,omitempty"`
+	// Price before and after the discount.
+	BasePrice string `protobuf:"bytes,4,opt,name=base_price,json=basePrice,proto3" json:"base_price,omitempty"`
+	Price     string `protobuf:"bytes,5,opt,name=price,proto3" json:"price,omitempty"`
+	// Applied discount, if any.
+	DiscountId string `protobuf:"bytes,6,opt,name=discount_id,json=discountId,proto3" json:"discount_id,omitempty"`
+	// Billable days of the period and the period length with daily proration.
+	Days       int32 `protobuf:"varint,7,opt,name=days,proto3" json:"days,omitempty"`
+	PeriodDays int32 `protobuf:"varint,8,opt,name=period_days,json=periodDays,proto3" json:"period_days,omitempty"`
+	// Part of the price paid by the user.
+	Cost          string `protobuf:"bytes,9,opt,name=cost,proto3" json:"cost,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CostLineItem) Reset() {
+	*x = CostLineItem{}
+	mi := &file_api_subscription_v1_subscription_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CostLineItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CostLineItem) ProtoMessage() {}
+
+func (x *CostLineItem) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscription_v1_subscription_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CostLineItem.ProtoReflect.Descriptor instead.
+func (*CostLineItem) Descriptor() ([]byte, []int) {
+	return file_api_subscription_v1_subscription_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *CostLineItem) GetMonth() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Month
+	}
+	return nil
+}
+
+func (x *CostLineItem) GetSubscriptionId() string {
+	if x != nil {
+		return x.SubscriptionId
+	}
+	return ""
+}
+
+func (x *CostLineItem) GetServiceName() string {
+	if x != nil {
+		return x.ServiceName
+	}
+	return ""
+}
+
+func (x *CostLineItem) GetBasePrice() string {
+	if x != nil {
+		return x.BasePrice
+	}
+	return ""
+}
+
+func (x *CostLineItem) GetPrice() string {
+	if x != nil {
+		return x.Price
+	}
+	return ""
+}
+
+func (x *CostLineItem) GetDiscountId() string {
+	if x != nil {
+		return x.DiscountId
+	}
+	return ""
+}
+
+func (x *CostLineItem) GetDays() int32 {
+	if x != nil {
+		return x.Days
+	}
+	return 0
+}
+
+func (x *CostLineItem) GetPeriodDays() int32 {
+	if x != nil {
+		return x.PeriodDays
+	}
+	return 0
+}
+
+func (x *CostLineItem) GetCost() string {
+	if x != nil {
+		return x.Cost
+	}
+	return ""
+}
+
+type ForecastRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Filter *SubscriptionFilter    `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
+	// Forecast horizon, 12 months when zero.
+	Months        int32 `protobuf:"varint,2,opt,name=months,proto3" json:"months,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ForecastRequest) Reset() {
+	*x = ForecastRequest{}
+	mi := &file_api_subscription_v1_subscription_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ForecastRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ForecastRequest) ProtoMessage() {}
+
+func (x *ForecastRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscription_v1_subscription_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ForecastRequest.ProtoReflect.Descriptor instead.
+func (*ForecastRequest) Descriptor() ([]byte, []int) {
+	return file_api_subscription_v1_subscription_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ForecastRequest) GetFilter() *SubscriptionFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+func (x *ForecastRequest) GetMonths() int32 {
+	if x != nil {
+		return x.Months
+	}
+	return 0
+}
+
+type SpendingForecast struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Months        []*ForecastMonth       `protobuf:"bytes,1,rep,name=months,proto3" json:"months,omitempty"`
+	TotalCost     string                 `protobuf:"bytes,2,opt,name=total_cost,json=totalCost,proto3" json:"total_cost,omitempty"`
+	Currency      string                 `protobuf:"bytes,3,opt,name=currency,proto3" json:"currency,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SpendingForecast) Reset() {
+	*x = SpendingForecast{}
+	mi := &file_api_subscription_v1_subscription_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SpendingForecast) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SpendingForecast) ProtoMessage() {}
+
+func (x *SpendingForecast) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscription_v1_subscription_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SpendingForecast.ProtoReflect.Descriptor instead.
+func (*SpendingForecast) Descriptor() ([]byte, []int) {
+	return file_api_subscription_v1_subscription_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *SpendingForecast) GetMonths() []*ForecastMonth {
+	if x != nil {
+		return x.Months
+	}
+	return nil
+}
+
+func (x *SpendingForecast) GetTotalCost() string {
+	if x != nil {
+		return x.TotalCost
+	}
+	return ""
+}
+
+func (x *SpendingForecast) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+type ForecastMonth struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Month     *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=month,proto3" json:"month,omitempty"`
+	TotalCost string                 `protobuf:"bytes,2,opt,name=total_cost,json=totalCost,proto3" json:"total_cost,omitempty"`
+	// Yearly subscriptions renewed in this month.
+	Renewals      []*ForecastRenewal `protobuf:"bytes,3,rep,name=renewals,proto3" json:"renewals,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ForecastMonth) Reset() {
+	*x = ForecastMonth{}
+	mi := &file_api_subscription_v1_subscription_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ForecastMonth) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ForecastMonth) ProtoMessage() {}
+
+func (x *ForecastMonth) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscription_v1_subscription_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ForecastMonth.ProtoReflect.Descriptor instead.
+func (*ForecastMonth) Descriptor() ([]byte, []int) {
+	return file_api_subscription_v1_subscription_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ForecastMonth) GetMonth() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Month
+	}
+	return nil
+}
+
+func (x *ForecastMonth) GetTotalCost() string {
+	if x != nil {
+		return x.TotalCost
+	}
+	return ""
+}
+
+func (x *ForecastMonth) GetRenewals() []*ForecastRenewal {
+	if x != nil {
+		return x.Renewals
+	}
+	return nil
+}
+
+type ForecastRenewal struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	SubscriptionId string                 `protobuf:"bytes,1,opt,name=subscription_id,json=subscriptionId,proto3" json:"subscription_id,omitempty"`
+	ServiceName    string                 `protobuf:"bytes,2,opt,name=service_name,json=serviceName,proto3" json:"service_name,omitempty"`
+	Cost           string                 `protobuf:"bytes,3,opt,name=cost,proto3" json:"cost,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ForecastRenewal) Reset() {
+	*x = ForecastRenewal{}
+	mi := &file_api_subscription_v1_subscription_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ForecastRenewal) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ForecastRenewal) ProtoMessage() {}
+
+func (x *ForecastRenewal) ProtoReflect() protoreflect.Message {
+	mi := &file_api_subscription_v1_subscription_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ForecastRenewal.ProtoReflect.Descriptor instead.
+func (*ForecastRenewal) Descriptor() ([]byte, []int) {
+	return file_api_subscription_v1_subscription_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ForecastRenewal) GetSubscriptionId() string {
+	if x != nil {
+		return x.SubscriptionId
+	}
+	return ""
+}
+
+func (x *ForecastRenewal) GetServiceName() string {
+	if x != nil {
+		return x.ServiceName
+	}
+	return ""
+}
+
+func (x *ForecastRenewal) GetCost() string {
+	if x != nil {
+		return x.Cost
+	}
+	return ""
+}
+
+var File_api_subscription_v1_subscription_proto protoreflect.FileDescriptor
+
+const file_api_subscription_v1_subscription_proto_rawDesc = "" +
+	"\n" +
+	"&api/subscription/v1/subscription.proto\x12\x0fsubscription.v1\x1a\x1bgoogle/protobuf/empty.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\x8d\x05\n" +
+	"\fSubscription\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12'\n" +
+	"\x0forganization_id\x18\x03 \x01(\tR\x0eorganizationId\x12!\n" +
+	"\fservice_name\x18\x04 \x01(\tR\vserviceName\x12\x1d\n" +
+	"\n" +
+	"service_id\x18\x05 \x01(\tR\tserviceId\x12\x17\n" +
+	"\aplan_id\x18\x06 \x01(\tR\x06planId\x12\x14\n" +
+	"\x05price\x18\a \x01(\tR\x05price\x12\x1a\n" +
+	"\bcurrency\x18\b \x01(\tR\bcurrency\x12%\n" +
+	"\x0ebilling_period\x18\t \x01(\tR\rbillingPeriod\x129\n" +
+	"\n" +
+	"start_date\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\tstartDate\x125\n" +
+	"\bend_date\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\aendDate\x12\x16\n" +
+	"\x06status\x18\f \x01(\tR\x06status\x12@\n" +
+	"\x0etrial_end_date\x18\r \x01(\v2\x1a.google.protobuf.TimestampR\ftrialEndDate\x12!\n" +
+	"\fcategory_ids\x18\x0e \x03(\tR\vcategoryIds\x12\x12\n" +
+	"\x04tags\x18\x0f \x03(\tR\x04tags\x129\n" +
+	"\n" +
+	"created_at\x18\x10 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\x11 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"^\n" +
+	"\x19CreateSubscriptionRequest\x12A\n" +
+	"\fsubscription\x18\x01 \x01(\v2\x1d.subscription.v1.SubscriptionR\fsubscription\",\n" +
+	"\x1aCreateSubscriptionResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"(\n" +
+	"\x16GetSubscriptionRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\xc3\x01\n" +
+	"\x19UpdateSubscriptionRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12A\n" +
+	"\fsubscription\x18\x02 \x01(\v2\x1d.subscription.v1.SubscriptionR\fsubscription\x120\n" +
+	"\x14replace_category_ids\x18\x03 \x01(\bR\x12replaceCategoryIds\x12!\n" +
+	"\freplace_tags\x18\x04 \x01(\bR\vreplaceTags\"+\n" +
+	"\x19DeleteSubscriptionRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\xde\x02\n" +
+	"\x12SubscriptionFilter\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12,\n" +
+	"\x0forganization_id\x18\x02 \x01(\tH\x00R\x0eorganizationId\x88\x01\x01\x12&\n" +
+	"\fservice_name\x18\x03 \x01(\tH\x01R\vserviceName\x88\x01\x01\x12\"\n" +
+	"\n" +
+	"service_id\x18\x04 \x01(\tH\x02R\tserviceId\x88\x01\x01\x12$\n" +
+	"\vcategory_id\x18\x05 \x01(\tH\x03R\n" +
+	"categoryId\x88\x01\x01\x12\x15\n" +
+	"\x03tag\x18\x06 \x01(\tH\x04R\x03tag\x88\x01\x01\x12\x1f\n" +
+	"\bcurrency\x18\a \x01(\tH\x05R\bcurrency\x88\x01\x01B\x12\n" +
+	"\x10_organization_idB\x0f\n" +
+	"\r_service_nameB\r\n" +
+	"\v_service_idB\x0e\n" +
+	"\f_category_idB\x06\n" +
+	"\x04_tagB\v\n" +
+	"\t_currency\"W\n" +
+	"\x18ListSubscriptionsRequest\x12;\n" +
+	"\x06filter\x18\x01 \x01(\v2#.subscription.v1.SubscriptionFilterR\x06filter\"`\n" +
+	"\x19ListSubscriptionsResponse\x12C\n" +
+	"\rsubscriptions\x18\x01 \x03(\v2\x1d.subscription.v1.SubscriptionR\rsubscriptions\"\x86\x01\n" +
+	"\n" +
+	"CostPeriod\x12\x1c\n" +
+	"\tproration\x18\x01 \x01(\tR\tproration\x12.\n" +
+	"\x04from\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x04from\x12*\n" +
+	"\x02to\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x02to\"\xc7\x01\n" +
+	"\x19CalculateTotalCostRequest\x12;\n" +
+	"\x06filter\x18\x01 \x01(\v2#.subscription.v1.SubscriptionFilterR\x06filter\x123\n" +
+	"\x06period\x18\x02 \x01(\v2\x1b.subscription.v1.CostPeriodR\x06period\x12\x19\n" +
+	"\bgroup_by\x18\x03 \x01(\tR\agroupBy\x12\x1d\n" +
+	"\n" +
+	"line_items\x18\x04 \x01(\bR\tlineItems\"\xc0\x01\n" +
+	"\vCostSummary\x12\x1d\n" +
+	"\n" +
+	"total_cost\x18\x01 \x01(\tR\ttotalCost\x12\x1a\n" +
+	"\bcurrency\x18\x02 \x01(\tR\bcurrency\x128\n" +
+	"\tbreakdown\x18\x03 \x03(\v2\x1a.subscription.v1.CostGroupR\tbreakdown\x12<\n" +
+	"\n" +
+	"line_items\x18\x04 \x03(\v2\x1d.subscription.v1.CostLineItemR\tlineItems\"P\n" +
+	"\tCostGroup\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1d\n" +
+	"\n" +
+	"total_cost\x18\x03 \x01(\tR\ttotalCost\"\xab\x02\n" +
+	"\fCostLineItem\x120\n" +
+	"\x05month\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x05month\x12'\n" +
+	"\x0fsubscription_id\x18\x02 \x01(\tR\x0esubscriptionId\x12!\n" +
+	"\fservice_name\x18\x03 \x01(\tR\vserviceName\x12\x1d\n" +
+	"\n" +
+	"base_price\x18\x04 \x01(\tR\tbasePrice\x12\x14\n" +
+	"\x05price\x18\x05 \x01(\tR\x05price\x12\x1f\n" +
+	"\vdiscount_id\x18\x06 \x01(\tR\n" +
+	"discountId\x12\x12\n" +
+	"\x04days\x18\a \x01(\x05R\x04days\x12\x1f\n" +
+	"\vperiod_days\x18\b \x01(\x05R\n" +
+	"periodDays\x12\x12\n" +
+	"\x04cost\x18\t \x01(\tR\x04cost\"f\n" +
+	"\x0fForecastRequest\x12;\n" +
+	"\x06filter\x18\x01 \x01(\v2#.subscription.v1.SubscriptionFilterR\x06filter\x12\x16\n" +
+	"\x06months\x18\x02 \x01(\x05R\x06months\"\x85\x01\n" +
+	"\x10SpendingForecast\x126\n" +
+	"\x06months\x18\x01 \x03(\v2\x1e.subscription.v1.ForecastMonthR\x06months\x12\x1d\n" +
+	"\n" +
+	"total_cost\x18\x02 \x01(\tR\ttotalCost\x12\x1a\n" +
+	"\bcurrency\x18\x03 \x01(\tR\bcurrency\"\x9e\x01\n" +
+	"\rForecastMonth\x120\n" +
+	"\x05month\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x05month\x12\x1d\n" +
+	"\n" +
+	"total_cost\x18\x02 \x01(\tR\ttotalCost\x12<\n" +
+	"\brenewals\x18\x03 \x03(\v2 .subscription.v1.ForecastRenewalR\brenewals\"q\n" +
+	"\x0fForecastRenewal\x12'\n" +
+	"\x0fsubscription_id\x18\x01 \x01(\tR\x0esubscriptionId\x12!\n" +
+	"\fservice_name\x18\x02 \x01(\tR\vserviceName\x12\x12\n" +
+	"\x04cost\x18\x03 \x01(\tR\x04cost2\xb0\x05\n" +
+	"\x13SubscriptionService\x12m\n" +
+	"\x12CreateSubscription\x12*.subscription.v1.CreateSubscriptionRequest\x1a+.subscription.v1.CreateSubscriptionResponse\x12Y\n" +
+	"\x0fGetSubscription\x12'.subscription.v1.GetSubscriptionRequest\x1a\x1d.subscription.v1.Subscription\x12X\n" +
+	"\x12UpdateSubscription\x12*.subscription.v1.UpdateSubscriptionRequest\x1a\x16.google.protobuf.Empty\x12X\n" +
+	"\x12DeleteSubscription\x12*.subscription.v1.DeleteSubscriptionRequest\x1a\x16.google.protobuf.Empty\x12j\n" +
+	"\x11ListSubscriptions\x12).subscription.v1.ListSubscriptionsRequest\x1a*.subscription.v1.ListSubscriptionsResponse\x12^\n" +
+	"\x12CalculateTotalCost\x12*.subscription.v1.CalculateTotalCostRequest\x1a\x1c.subscription.v1.CostSummary\x12O\n" +
+	"\bForecast\x12 .subscription.v1.ForecastRequest\x1a!.subscription.v1.SpendingForecastBVZTgithub.com/vasiliy-maslov/go-subscription-service/api/subscription/v1;subscriptionv1b\x06proto3"
+
+var (
+	file_api_subscription_v1_subscription_proto_rawDescOnce sync.Once
+	file_api_subscription_v1_subscription_proto_rawDescData []byte
+)
+
+func file_api_subscription_v1_subscription_proto_rawDescGZIP() []byte {
+	file_api_subscription_v1_subscription_proto_rawDescOnce.Do(func() {
+		file_api_subscription_v1_subscription_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_api_subscription_v1_subscription_proto_rawDesc), len(file_api_subscription_v1_subscription_proto_rawDesc)))
+	})
+	return file_api_subscription_v1_subscription_proto_rawDescData
+}
+
+var file_api_subscription_v1_subscription_proto_msgTypes = make([]protoimpl.MessageInfo, 18)
+var file_api_subscription_v1_subscription_proto_goTypes = []any{
+	(*Subscription)(nil),               // 0: subscription.v1.Subscription
+	(*CreateSubscriptionRequest)(nil),  // 1: subscription.v1.CreateSubscriptionRequest
+	(*CreateSubscriptionResponse)(nil), // 2: subscription.v1.CreateSubscriptionResponse
+	(*GetSubscriptionRequest)(nil),     // 3: subscription.v1.GetSubscriptionRequest
+	(*UpdateSubscriptionRequest)(nil),  // 4: subscription.v1.UpdateSubscriptionRequest
+	(*DeleteSubscriptionRequest)(nil),  // 5: subscription.v1.DeleteSubscriptionRequest
+	(*SubscriptionFilter)(nil),         // 6: subscription.v1.SubscriptionFilter
+	(*ListSubscriptionsRequest)(nil),   // 7: subscription.v1.ListSubscriptionsRequest
+	(*ListSubscriptionsResponse)(nil),  // 8: subscription.v1.ListSubscriptionsResponse
+	(*CostPeriod)(nil),                 // 9: subscription.v1.CostPeriod
+	(*CalculateTotalCostRequest)(nil),  // 10: subscription.v1.CalculateTotalCostRequest
+	(*CostSummary)(nil),                // 11: subscription.v1.CostSummary
+	(*CostGroup)(nil),                  // 12: subscription.v1.CostGroup
+	(*CostLineItem)(nil),               // 13: subscription.v1.CostLineItem
+	(*ForecastRequest)(nil),            // 14: subscription.v1.ForecastRequest
+	(*SpendingForecast)(nil),           // 15: subscription.v1.SpendingForecast
+	(*ForecastMonth)(nil),              // 16: subscription.v1.ForecastMonth
+	(*ForecastRenewal)(nil),            // 17: subscription.v1.ForecastRenewal
+	(*timestamppb.Timestamp)(nil),      // 18: google.protobuf.Timestamp
+	(*emptypb.Empty)(nil),              // 19: google.protobuf.Empty
+}
+var file_api_subscription_v1_subscription_proto_depIdxs = []int32{
+	18, // 0: subscription.v1.Subscription.start_date:type_name -> google.protobuf.Timestamp
+	18, // 1: subscription.v1.Subscription.end_date:type_name -> google.protobuf.Timestamp
+	18, // 2: subscription.v1.Subscription.trial_end_date:type_name -> google.protobuf.Timestamp
+	18, // 3: subscription.v1.Subscription.created_at:type_name -> google.protobuf.Timestamp
+	18, // 4: subscription.v1.Subscription.updated_at:type_name -> google.protobuf.Timestamp
+	0,  // 5: subscription.v1.CreateSubscriptionRequest.subscription:type_name -> subscription.v1.Subscription
+	0,  // 6: subscription.v1.UpdateSubscriptionRequest.subscription:type_name -> subscription.v1.Subscription
+	6,  // 7: subscription.v1.ListSubscriptionsRequest.filter:type_name -> subscription.v1.SubscriptionFilter
+	0,  // 8: subscription.v1.ListSubscriptionsResponse.subscriptions:type_name -> subscription.v1.Subscription
+	18, // 9: subscription.v1.CostPeriod.from:type_name -> google.protobuf.Timestamp
+	18, // 10: subscription.v1.CostPeriod.to:type_name -> google.protobuf.Timestamp
+	6,  // 11: subscription.v1.CalculateTotalCostRequest.filter:type_name -> subscription.v1.SubscriptionFilter
+	9,  // 12: subscription.v1.CalculateTotalCostRequest.period:type_name -> subscription.v1.CostPeriod
+	12, // 13: subscription.v1.CostSummary.breakdown:type_name -> subscription.v1.CostGroup
+	13, // 14: subscription.v1.CostSummary.line_items:type_name -> subscription.v1.CostLineItem
+	18, // 15: subscription.v1.CostLineItem.month:type_name -> google.protobuf.Timestamp
+	6,  // 16: subscription.v1.ForecastRequest.filter:type_name -> subscription.v1.SubscriptionFilter
+	16, // 17: subscription.v1.SpendingForecast.months:type_name -> subscription.v1.ForecastMonth
+	18, // 18: subscription.v1.ForecastMonth.month:type_name -> google.protobuf.Timestamp
+	17, // 19: subscription.v1.ForecastMonth.renewals:type_name -> subscription.v1.ForecastRenewal
+	1,  // 20: subscription.v1.SubscriptionService.CreateSubscription:input_type -> subscription.v1.CreateSubscriptionRequest
+	3,  // 21: subscription.v1.SubscriptionService.GetSubscription:input_type -> subscription.v1.GetSubscriptionRequest
+	4,  // 22: subscription.v1.SubscriptionService.UpdateSubscription:input_type -> subscription.v1.UpdateSubscriptionRequest
+	5,  // 23: subscription.v1.SubscriptionService.DeleteSubscription:input_type -> subscription.v1.DeleteSubscriptionRequest
+	7,  // 24: subscription.v1.SubscriptionService.ListSubscriptions:input_type -> subscription.v1.ListSubscriptionsRequest
+	10, // 25: subscription.v1.SubscriptionService.CalculateTotalCost:input_type -> subscription.v1.CalculateTotalCostRequest
+	14, // 26: subscription.v1.SubscriptionService.Forecast:input_type -> subscription.v1.ForecastRequest
+	2,  // 27: subscription.v1.SubscriptionService.CreateSubscription:output_type -> subscription.v1.CreateSubscriptionResponse
+	0,  // 28: subscription.v1.SubscriptionService.GetSubscription:output_type -> subscription.v1.Subscription
+	19, // 29: subscription.v1.SubscriptionService.UpdateSubscription:output_type -> google.protobuf.Empty
+	19, // 30: subscription.v1.SubscriptionService.DeleteSubscription:output_type -> google.protobuf.Empty
+	8,  // 31: subscription.v1.SubscriptionService.ListSubscriptions:output_type -> subscription.v1.ListSubscriptionsResponse
+	11, // 32: subscription.v1.SubscriptionService.CalculateTotalCost:output_type -> subscription.v1.CostSummary
+	15, // 33: subscription.v1.SubscriptionService.Forecast:output_type -> subscription.v1.SpendingForecast
+	27, // [27:34] is the sub-list for method output_type
+	20, // [20:27] is the sub-list for method input_type
+	20, // [20:20] is the sub-list for extension type_name
+	20, // [20:20] is the sub-list for extension extendee
+	0,  // [0:20] is the sub-list for field type_name
+}
+
+func init() { file_api_subscription_v1_subscription_proto_init() }
+func file_api_subscription_v1_subscription_proto_init() {
+	if File_api_subscription_v1_subscription_proto != nil {
+		return
+	}
+	file_api_subscription_v1_subscription_proto_msgTypes[6].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_subscription_v1_subscription_proto_rawDesc), len(file_api_subscription_v1_subscription_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   18,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_api_subscription_v1_subscription_proto_goTypes,
+		DependencyIndexes: file_api_subscription_v1_subscription_proto_depIdxs,
+		MessageInfos:      file_api_subscription_v1_subscription_proto_msgTypes,
+	}.Build()
+	File_api_subscription_v1_subscription_proto = out.File
+	file_api_subscription_v1_subscription_proto_goTypes = nil
+	file_api_subscription_v1_subscription_proto_depIdxs = nil
+}
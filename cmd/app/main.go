@@ -1,16 +1,26 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"log/slog"
+	"net"
+	nethttp "net/http"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
+	_ "time/tzdata" // база часовых поясов для образа без tzdata
 
 	"github.com/vasiliy-maslov/go-subscription-service/internal/app"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/handler/graphql"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/handler/grpc"
 	"github.com/vasiliy-maslov/go-subscription-service/internal/handler/http"
 )
 
 // @title Subscription Service API
 // @version 1.0
-// @description API Server for Subscription Management Application
+// @description API Server for Subscription Management Application. Send the X-Time-Zone header with an IANA time zone name to interpret dates and the current day in the user's time zone (UTC by default). Every request must be authenticated with the Authorization header ("Bearer <credential>"): a user token restricts subscriptions to those the user owns, shares or can access through an organization, and machine clients send an API key (sk_...) instead. Requests without credentials get 401. Requests are rate limited per API key or user: responses carry RateLimit-* headers, and exceeding the limit returns 429 with a Retry-After header. Every response carries an X-Request-ID header: the value sent by the client or a generated UUID, also written to the server logs.
 
 // @host localhost:8080
 // @BasePath /api/v1
@@ -19,6 +29,9 @@ import (
 // @in header
 // @name Authorization
 
+// shutdownTimeout ограничивает время на завершение запросов и отправку спанов при остановке.
+const shutdownTimeout = 10 * time.Second
+
 func main() {
 	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
 	slog.SetDefault(logger) // Устанавливаем его как логгер по умолчанию
@@ -31,12 +44,66 @@ func main() {
 
 	logger.Info("Приложение успешно инициализировано.")
 
-	handler := http.NewHandler(application.Service, logger)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	go application.Relay.Run(ctx)
+	go application.Evaluator.Run(ctx)
+	if application.Limiter != nil {
+		go application.Limiter.Run(ctx)
+	}
+	if application.Hub != nil {
+		go application.Hub.Run(ctx)
+	}
+
+	var graphqlHandler nethttp.Handler
+	if application.GraphQL.Enabled {
+		graphqlHandler = graphql.NewHandler(application.Service, application.Users, application.Categories, application.Audit, application.GraphQL, logger)
+	}
+
+	handler := http.NewHandler(application.Service, application.Catalog, application.Categories, application.Budgets, application.Users, application.Organizations, application.Roles, application.APIKeys, application.Tokens, application.Privacy, application.Audit, graphqlHandler, application.Stream, application.Limiter, logger)
+
+	server := &nethttp.Server{Addr: ":8080", Handler: handler.InitRoutes()}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			logger.Error("Не удалось остановить HTTP-сервер", slog.String("error", err.Error()))
+		}
+	}()
+
+	if application.GRPC.Enabled {
+		grpcServer := grpc.NewHandler(application.Service, application.APIKeys, application.Tokens, application.Audit, application.Limiter, logger).InitServer()
+		listener, err := net.Listen("tcp", ":"+application.GRPC.Port)
+		if err != nil {
+			logger.Error("Не удалось открыть порт gRPC-сервера", slog.String("error", err.Error()))
+			os.Exit(1)
+		}
+		go func() {
+			<-ctx.Done()
+			grpcServer.GracefulStop()
+		}()
+		go func() {
+			logger.Info("Запускаем gRPC-сервер", slog.String("port", application.GRPC.Port))
+			if err := grpcServer.Serve(listener); err != nil {
+				logger.Error("gRPC-сервер остановлен с ошибкой", slog.String("error", err.Error()))
+			}
+		}()
+	}
 
 	logger.Info("Запускаем HTTP-сервер", slog.String("port", "8080"))
 
-	if err := handler.InitRoutes().Run(":8080"); err != nil {
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, nethttp.ErrServerClosed) {
 		logger.Error("Не удалось запустить HTTP-сервер", slog.String("error", err.Error()))
 		os.Exit(1)
 	}
+
+	// Спаны копятся пачками, поэтому перед выходом их нужно отправить.
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := application.ShutdownTracing(shutdownCtx); err != nil {
+		logger.Error("Не удалось отправить спаны трассировки", slog.String("error", err.Error()))
+	}
+	logger.Info("Приложение остановлено")
 }
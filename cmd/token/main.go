@@ -0,0 +1,51 @@
+// Команда token выпускает токен пользователя, подписанный ключом auth.token_secret из конфигурации.
+//
+//	go run ./cmd/token -user <uuid> [-ttl 24h]
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/vasiliy-maslov/go-subscription-service/internal/config"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/service"
+
+	"github.com/google/uuid"
+)
+
+func main() {
+	userFlag := flag.String("user", "", "ID пользователя, от имени которого выполняются запросы")
+	ttlFlag := flag.Duration("ttl", 0, "срок действия токена (по умолчанию auth.token_ttl)")
+	flag.Parse()
+
+	userID, err := uuid.Parse(*userFlag)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "укажите ID пользователя: -user <uuid>")
+		os.Exit(2)
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	ttl := *ttlFlag
+	if ttl == 0 {
+		ttl = cfg.Auth.TokenTTL
+	}
+
+	tokens, err := service.NewTokenService(cfg.Auth.TokenSecret, slog.Default())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	token, err := tokens.Issue(userID, ttl)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	fmt.Println(token)
+}
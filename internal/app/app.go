@@ -5,15 +5,46 @@ import (
 	"fmt"
 	"log/slog"
 
+	"github.com/vasiliy-maslov/go-subscription-service/internal/audit"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/budget"
 	"github.com/vasiliy-maslov/go-subscription-service/internal/config"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/events"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/outbox"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/ratelimit"
 	"github.com/vasiliy-maslov/go-subscription-service/internal/repository"
 	"github.com/vasiliy-maslov/go-subscription-service/internal/service"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/stream"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/tracing"
 
+	"github.com/jackc/pgx/v5/multitracer"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type App struct {
-	Service service.SubscriptionService
+	Service       service.SubscriptionService
+	Catalog       service.CatalogService
+	Categories    service.CategoryService
+	Budgets       service.BudgetService
+	Users         service.UserService
+	Organizations service.OrganizationService
+	Roles         service.RoleService
+	APIKeys       service.APIKeyService
+	Tokens        service.TokenService
+	Privacy       service.PrivacyService
+	Audit         service.AuditService
+	Relay         *outbox.Relay
+	Evaluator     *budget.Evaluator
+	// Stream и Hub равны nil, если поток изменений подписок отключен.
+	Stream service.StreamService
+	Hub    *stream.Hub
+	// Limiter равен nil, если ограничение частоты запросов отключено.
+	Limiter *ratelimit.Limiter
+	// GRPC - настройки gRPC-сервера, который запускается рядом с HTTP-сервером.
+	GRPC config.GRPCConfig
+	// GraphQL - настройки эндпоинта /graphql.
+	GraphQL config.GraphQLConfig
+	// ShutdownTracing отправляет накопленные спаны; вызывается при остановке приложения.
+	ShutdownTracing tracing.Shutdown
 }
 
 func New(logger *slog.Logger) (*App, error) {
@@ -22,16 +53,30 @@ func New(logger *slog.Logger) (*App, error) {
 		return nil, fmt.Errorf("не удалось загрузить конфиг: %w", err)
 	}
 
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось настроить трассировку: %w", err)
+	}
+
 	connString := fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=%s",
-		cfg.User,
-		cfg.Password,
-		cfg.Host,
-		cfg.Port,
-		cfg.DBName,
-		cfg.SSLMode,
+		cfg.Postgres.User,
+		cfg.Postgres.Password,
+		cfg.Postgres.Host,
+		cfg.Postgres.Port,
+		cfg.Postgres.DBName,
+		cfg.Postgres.SSLMode,
 	)
 
-	dbpool, err := pgxpool.New(context.Background(), connString)
+	poolConfig, err := pgxpool.ParseConfig(connString)
+	if err != nil {
+		return nil, fmt.Errorf("некорректные параметры подключения к базе данных: %w", err)
+	}
+	// Соединение получает пользователя запроса для политик row-level security.
+	poolConfig.BeforeAcquire = repository.SetTenant
+	// Запросы пишутся в лог логгером HTTP-запроса с его идентификатором и в спаны трассировки.
+	poolConfig.ConnConfig.Tracer = multitracer.New(repository.NewQueryLogger(logger), repository.NewSpanTracer())
+
+	dbpool, err := pgxpool.NewWithConfig(context.Background(), poolConfig)
 	if err != nil {
 		return nil, fmt.Errorf("не удалось подключиться к базе данных: %w", err)
 	}
@@ -40,8 +85,106 @@ func New(logger *slog.Logger) (*App, error) {
 		return nil, fmt.Errorf("не удалось пингануть базу данных: %w", err)
 	}
 
+	publisher, err := events.NewPublisher(cfg.Outbox, logger)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось создать издателя событий: %w", err)
+	}
+
+	isolation, err := repository.ParseIsolationLevel(cfg.Postgres.IsolationLevel)
+	if err != nil {
+		return nil, fmt.Errorf("некорректная конфигурация транзакций: %w", err)
+	}
+	txManager := repository.NewPgTxManager(dbpool, isolation, cfg.Postgres.TxMaxAttempts)
+
 	repo := repository.NewSubscriptionRepo(dbpool)
-	subService := service.NewSubscriptionService(repo, logger)
+	catalogRepo := repository.NewCatalogRepo(dbpool)
+	categoryRepo := repository.NewCategoryRepo(dbpool)
+	budgetRepo := repository.NewBudgetRepo(dbpool)
+	userRepo := repository.NewUserRepo(dbpool)
+	organizationRepo := repository.NewOrganizationRepo(dbpool)
+	roleRepo := repository.NewRoleRepo(dbpool)
+	apiKeyRepo := repository.NewAPIKeyRepo(dbpool)
+	privacyRepo := repository.NewPrivacyRepo(dbpool)
+	outboxRepo := repository.NewOutboxRepo(dbpool)
+	auditRepo := repository.NewAuditRepo(dbpool)
+	policy := service.NewPolicy(roleRepo)
+	subService := service.NewTracedSubscriptionService(service.NewSubscriptionService(repo, catalogRepo, categoryRepo, userRepo, organizationRepo, policy, outboxRepo, txManager,
+		service.SubscriptionOptions{StrictDuplicates: cfg.Subscriptions.StrictDuplicates}, logger))
+	catalogService := service.NewCatalogService(catalogRepo, policy, txManager, logger)
+	categoryService := service.NewCategoryService(categoryRepo, policy, logger)
+	budgetService := service.NewBudgetService(budgetRepo, catalogRepo, categoryRepo, userRepo, subService, policy, outboxRepo, txManager, logger)
+	userService := service.NewUserService(userRepo, repo, policy, outboxRepo, txManager, logger)
+	organizationService := service.NewOrganizationService(organizationRepo, repo, policy, outboxRepo, txManager, logger)
+	roleService := service.NewRoleService(roleRepo, policy, outboxRepo, txManager, logger)
+	apiKeyService := service.NewAPIKeyService(apiKeyRepo, userRepo, policy, outboxRepo, txManager, logger)
+	tokenService, err := service.NewTokenService(cfg.Auth.TokenSecret, logger)
+	if err != nil {
+		return nil, fmt.Errorf("некорректная конфигурация аутентификации: %w", err)
+	}
+	var auditSink service.AuditSink
+	if cfg.Audit.File != "" {
+		auditSink, err = audit.NewFileSink(cfg.Audit.File)
+		if err != nil {
+			return nil, err
+		}
+	}
+	auditService := service.NewAuditService(auditRepo, auditSink, policy, txManager, logger)
+	privacyService := service.NewPrivacyService(privacyRepo, userRepo, repo, categoryRepo, budgetRepo, apiKeyRepo, auditRepo, policy, outboxRepo, txManager, logger)
+
+	relay := outbox.NewRelay(outboxRepo, publisher, logger, outbox.Options{
+		Interval:     cfg.Outbox.PollInterval,
+		BatchSize:    cfg.Outbox.BatchSize,
+		ClaimTimeout: cfg.Outbox.ClaimTimeout,
+		MaxAttempts:  cfg.Outbox.MaxAttempts,
+	})
+	evaluator := budget.NewEvaluator(budgetService, logger, cfg.Budgets.EvaluateInterval)
+
+	var (
+		hub           *stream.Hub
+		streamService service.StreamService
+	)
+	if cfg.Stream.Enabled {
+		hub = stream.NewHub(repository.NewChangeRepo(dbpool), logger, cfg.Stream.BufferSize)
+		streamService = service.NewStreamService(hub, policy, logger)
+	}
+
+	var limiter *ratelimit.Limiter
+	if cfg.RateLimit.Enabled {
+		var store ratelimit.Store
+		switch cfg.RateLimit.Store {
+		case "memory":
+			store = ratelimit.NewMemoryStore()
+		case "postgres":
+			store = repository.NewRateLimitRepo(dbpool)
+		default:
+			return nil, fmt.Errorf("неизвестное хранилище ограничения запросов: %q", cfg.RateLimit.Store)
+		}
+
+		limiter, err = ratelimit.NewLimiter(cfg.RateLimit, store, logger)
+		if err != nil {
+			return nil, fmt.Errorf("некорректная конфигурация ограничения запросов: %w", err)
+		}
+	}
 
-	return &App{Service: subService}, nil
+	return &App{
+		Service:         subService,
+		Catalog:         catalogService,
+		Categories:      categoryService,
+		Budgets:         budgetService,
+		Users:           userService,
+		Organizations:   organizationService,
+		Roles:           roleService,
+		APIKeys:         apiKeyService,
+		Tokens:          tokenService,
+		Privacy:         privacyService,
+		Audit:           auditService,
+		Stream:          streamService,
+		Relay:           relay,
+		Evaluator:       evaluator,
+		Hub:             hub,
+		Limiter:         limiter,
+		GRPC:            cfg.GRPC,
+		GraphQL:         cfg.GraphQL,
+		ShutdownTracing: shutdownTracing,
+	}, nil
 }
@@ -0,0 +1,51 @@
+// Package audit содержит приемники журнала аудита помимо базы данных.
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/vasiliy-maslov/go-subscription-service/internal/model"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/service"
+)
+
+var _ service.AuditSink = (*FileSink)(nil)
+
+// FileSink дописывает записи журнала аудита в файл по одной JSON-строке (JSON Lines).
+type FileSink struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// NewFileSink открывает файл path на дозапись, создавая его при необходимости.
+func NewFileSink(path string) (*FileSink, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось открыть файл журнала аудита: %w", err)
+	}
+	return &FileSink{file: file}, nil
+}
+
+// Write дописывает запись в файл. Исполнитель, IP-адрес, ID ресурса и параметры в файл не пишутся:
+// из файла их нельзя удалить вместе с данными пользователя, а для сверки с базой достаточно
+// identity_hash и subject_hash.
+func (s *FileSink) Write(_ context.Context, e model.AuditEntry) error {
+	e.ActorID, e.SourceIP, e.ResourceID, e.Params = nil, "", "", nil
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = s.file.Write(append(line, '\n'))
+	return err
+}
+
+// Close закрывает файл.
+func (s *FileSink) Close() error {
+	return s.file.Close()
+}
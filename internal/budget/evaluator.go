@@ -0,0 +1,52 @@
+package budget
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/vasiliy-maslov/go-subscription-service/internal/repository"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/service"
+)
+
+// Evaluator периодически проверяет бюджеты и записывает оповещения о достигнутых порогах в outbox.
+type Evaluator struct {
+	service  service.BudgetService
+	logger   *slog.Logger
+	interval time.Duration
+}
+
+// NewEvaluator создает новый экземпляр фоновой проверки бюджетов.
+func NewEvaluator(service service.BudgetService, logger *slog.Logger, interval time.Duration) *Evaluator {
+	return &Evaluator{
+		service:  service,
+		logger:   logger,
+		interval: interval,
+	}
+}
+
+// Run проверяет бюджеты, пока не будет отменен контекст. Проверка - системный вызов: она читает
+// подписки всех владельцев бюджетов.
+func (e *Evaluator) Run(ctx context.Context) {
+	const op = "budget.Run"
+	log := e.logger.With(slog.String("op", op))
+
+	log.Info("Запущена проверка бюджетов", slog.Duration("interval", e.interval))
+
+	ctx = repository.WithSystem(ctx)
+
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info("Проверка бюджетов остановлена")
+			return
+		case <-ticker.C:
+			if err := e.service.Evaluate(ctx); err != nil {
+				log.Error("Не удалось проверить бюджеты", slog.String("error", err.Error()))
+			}
+		}
+	}
+}
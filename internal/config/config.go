@@ -3,12 +3,23 @@ package config
 import (
 	"fmt"
 	"log"
+	"time"
 
 	"github.com/spf13/viper"
 )
 
 type Config struct {
-	Postgres PostgresConfig
+	Postgres      PostgresConfig      `mapstructure:"postgres"`
+	Auth          AuthConfig          `mapstructure:"auth"`
+	Outbox        OutboxConfig        `mapstructure:"outbox"`
+	Budgets       BudgetsConfig       `mapstructure:"budgets"`
+	Subscriptions SubscriptionsConfig `mapstructure:"subscriptions"`
+	RateLimit     RateLimitConfig     `mapstructure:"rate_limit"`
+	Tracing       TracingConfig       `mapstructure:"tracing"`
+	Audit         AuditConfig         `mapstructure:"audit"`
+	GRPC          GRPCConfig          `mapstructure:"grpc"`
+	GraphQL       GraphQLConfig       `mapstructure:"graphql"`
+	Stream        StreamConfig        `mapstructure:"stream"`
 }
 
 type PostgresConfig struct {
@@ -18,10 +29,123 @@ type PostgresConfig struct {
 	Password string `mapstructure:"password"`
 	DBName   string `mapstructure:"dbname"`
 	SSLMode  string `mapstructure:"sslmode"`
+	// IsolationLevel - уровень изоляции транзакций по умолчанию: read committed, repeatable read или serializable.
+	IsolationLevel string `mapstructure:"isolation_level"`
+	// TxMaxAttempts - число попыток транзакции при ошибке сериализации (SQLSTATE 40001).
+	TxMaxAttempts int `mapstructure:"tx_max_attempts"`
+}
+
+// AuthConfig задает аутентификацию пользователей по токенам.
+type AuthConfig struct {
+	// TokenSecret - ключ подписи токенов пользователей (HS256), не короче 32 байт. Пустой ключ
+	// отключает токены: аутентифицироваться можно только API-ключом.
+	TokenSecret string `mapstructure:"token_secret"`
+	// TokenTTL - срок действия токенов, которые выпускает cmd/token.
+	TokenTTL time.Duration `mapstructure:"token_ttl"`
+}
+
+// OutboxConfig задает параметры публикации доменных событий из outbox.
+type OutboxConfig struct {
+	Publisher  string `mapstructure:"publisher"` // log, webhook или broker
+	WebhookURL string `mapstructure:"webhook_url"`
+	// BrokerSubject - префикс subject издателя broker: события публикуются в <префикс>.<тип события>.
+	BrokerSubject string        `mapstructure:"broker_subject"`
+	PollInterval  time.Duration `mapstructure:"poll_interval"`
+	BatchSize     int           `mapstructure:"batch_size"`
+	// ClaimTimeout - на сколько ретранслятор захватывает пачку событий; должен превышать время ее публикации.
+	ClaimTimeout time.Duration `mapstructure:"claim_timeout"`
+	// MaxAttempts - число попыток публикации, после которого событие переводится в dead letter.
+	MaxAttempts int `mapstructure:"max_attempts"`
+}
+
+// SubscriptionsConfig задает правила проверки подписок.
+type SubscriptionsConfig struct {
+	// StrictDuplicates запрещает пересекающиеся подписки на один сервис (ответ 409).
+	StrictDuplicates bool `mapstructure:"strict_duplicates"`
+}
+
+// BudgetsConfig задает параметры фоновой проверки бюджетов.
+type BudgetsConfig struct {
+	EvaluateInterval time.Duration `mapstructure:"evaluate_interval"`
+}
+
+// RateLimitConfig задает ограничение частоты запросов к API.
+type RateLimitConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Store - хранилище счетчиков: memory (в памяти процесса) или postgres (общее для всех реплик).
+	Store string `mapstructure:"store"`
+	// Default действует на маршруты без собственного ограничения; счетчик у них общий.
+	Default RateLimitRule `mapstructure:"default"`
+	// Routes задает ограничения отдельных маршрутов, например GET /api/v1/subscriptions/total_cost.
+	Routes []RouteRateLimit `mapstructure:"routes"`
+	// PerIP ограничивает запросы с одного IP-адреса до аутентификации, в том числе с неверным
+	// ключом или токеном, чтобы нельзя было перебирать их без ограничений. Счетчик общий для всех маршрутов.
+	PerIP RateLimitRule `mapstructure:"per_ip"`
+	// SweepInterval - период удаления счетчиков клиентов, которые давно не обращались к API.
+	SweepInterval time.Duration `mapstructure:"sweep_interval"`
+}
+
+// RateLimitRule - ограничение в терминах token bucket: Requests запросов за Period
+// с запасом Burst запросов подряд. Burst по умолчанию равен Requests.
+type RateLimitRule struct {
+	Requests int           `mapstructure:"requests"`
+	Period   time.Duration `mapstructure:"period"`
+	Burst    int           `mapstructure:"burst"`
+}
+
+// RouteRateLimit - ограничение маршрута. Path записывается так же, как в роутере: /api/v1/subscriptions/:id.
+type RouteRateLimit struct {
+	Method        string `mapstructure:"method"`
+	Path          string `mapstructure:"path"`
+	RateLimitRule `mapstructure:",squash"`
+}
+
+// TracingConfig задает экспорт трассировки OpenTelemetry.
+type TracingConfig struct {
+	// Exporter - куда отправляются спаны: none (трассировка выключена), otlp, stdout или file.
+	Exporter string `mapstructure:"exporter"`
+	// Endpoint - адрес OTLP/gRPC коллектора, например otel-collector:4317.
+	Endpoint string `mapstructure:"endpoint"`
+	// Insecure отключает TLS при подключении к коллектору.
+	Insecure bool `mapstructure:"insecure"`
+	// File - путь к файлу для экспортера file; спаны пишутся в него построчно в JSON.
+	File string `mapstructure:"file"`
+	// ServiceName - значение service.name в ресурсах спанов.
+	ServiceName string `mapstructure:"service_name"`
+	// SampleRatio - доля трассируемых запросов без родительского спана, от 0 до 1.
+	SampleRatio float64 `mapstructure:"sample_ratio"`
+}
+
+// AuditConfig задает приемники журнала аудита. Журнал всегда пишется в Postgres.
+type AuditConfig struct {
+	// File - путь к файлу JSON Lines, в который записи дублируются; пустой путь отключает файл.
+	File string `mapstructure:"file"`
+}
+
+// GRPCConfig задает gRPC-сервер, который работает рядом с REST API.
+type GRPCConfig struct {
+	Enabled bool   `mapstructure:"enabled"`
+	Port    string `mapstructure:"port"`
+}
+
+// GraphQLConfig задает эндпоинт /graphql для веб-дашборда.
+type GraphQLConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// ComplexityLimit - предельная сложность запроса; запросы сложнее отклоняются до выполнения.
+	ComplexityLimit int `mapstructure:"complexity_limit"`
+	// Introspection разрешает запросы схемы (__schema, __type).
+	Introspection bool `mapstructure:"introspection"`
+}
+
+// StreamConfig задает поток изменений подписок GET /api/v1/subscriptions/stream.
+type StreamConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// BufferSize - сколько последних изменений хранится для продолжения потока по Last-Event-ID.
+	BufferSize int `mapstructure:"buffer_size"`
 }
 
 // LoadConfig читает конфигурацию из файла или переменных окружения.
-func LoadConfig() (*PostgresConfig, error) {
+func LoadConfig() (*Config, error) {
 	viper.AddConfigPath("./configs")
 	viper.SetConfigName("config")
 	viper.SetConfigType("yaml")
@@ -34,8 +158,32 @@ func LoadConfig() (*PostgresConfig, error) {
 	}
 	log.Println("Конфиг успешно прочитан!")
 
-	var cfg PostgresConfig
-	if err := viper.Sub("postgres").Unmarshal(&cfg); err != nil {
+	viper.SetDefault("postgres.isolation_level", "read committed")
+	viper.SetDefault("postgres.tx_max_attempts", 3)
+	viper.SetDefault("auth.token_ttl", 24*time.Hour)
+	viper.SetDefault("outbox.publisher", "log")
+	viper.SetDefault("outbox.broker_subject", "subscriptions")
+	viper.SetDefault("outbox.poll_interval", time.Second)
+	viper.SetDefault("outbox.batch_size", 100)
+	viper.SetDefault("outbox.claim_timeout", 5*time.Minute)
+	viper.SetDefault("outbox.max_attempts", 10)
+	viper.SetDefault("budgets.evaluate_interval", time.Hour)
+	viper.SetDefault("rate_limit.store", "memory")
+	viper.SetDefault("rate_limit.default.requests", 300)
+	viper.SetDefault("rate_limit.default.period", time.Minute)
+	viper.SetDefault("rate_limit.per_ip.requests", 600)
+	viper.SetDefault("rate_limit.per_ip.period", time.Minute)
+	viper.SetDefault("rate_limit.sweep_interval", 10*time.Minute)
+	viper.SetDefault("grpc.port", "9090")
+	viper.SetDefault("graphql.complexity_limit", 500)
+	viper.SetDefault("graphql.introspection", true)
+	viper.SetDefault("stream.buffer_size", 1000)
+	viper.SetDefault("tracing.exporter", "none")
+	viper.SetDefault("tracing.service_name", "subscription-service")
+	viper.SetDefault("tracing.sample_ratio", 1.0)
+
+	var cfg Config
+	if err := viper.Unmarshal(&cfg); err != nil {
 		return nil, fmt.Errorf("ошибка десериализации конфига: %w", err)
 	}
 
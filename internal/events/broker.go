@@ -0,0 +1,90 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/vasiliy-maslov/go-subscription-service/internal/model"
+)
+
+// Message - сообщение для брокера в терминах, общих для NATS JetStream и Kafka.
+type Message struct {
+	// ID используется брокером для дедупликации (Nats-Msg-Id в JetStream, заголовок в Kafka).
+	ID string
+	// Key определяет порядок доставки (ключ партиции в Kafka).
+	Key  string
+	Data []byte
+}
+
+// Broker - минимальный интерфейс клиента брокера сообщений.
+// Адаптеры для NATS и Kafka реализуют его поверх соответствующих клиентов.
+type Broker interface {
+	Publish(ctx context.Context, subject string, msg Message) error
+}
+
+var _ EventPublisher = (*BrokerPublisher)(nil)
+
+// BrokerPublisher публикует события в брокер сообщений.
+// Subject формируется как <prefix>.<тип события>, ключом служит ID подписки.
+type BrokerPublisher struct {
+	broker Broker
+	prefix string
+}
+
+// NewBrokerPublisher создает издателя поверх брокера сообщений.
+func NewBrokerPublisher(broker Broker, prefix string) *BrokerPublisher {
+	return &BrokerPublisher{broker: broker, prefix: prefix}
+}
+
+func (p *BrokerPublisher) Publish(ctx context.Context, event model.Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	return p.broker.Publish(ctx, p.prefix+"."+event.Type, Message{
+		ID:   event.ID.String(),
+		Key:  event.AggregateID.String(),
+		Data: data,
+	})
+}
+
+var _ Broker = (*MemoryBroker)(nil)
+
+// MemoryBroker - брокер в памяти процесса. Отбрасывает повторные сообщения с тем же ID,
+// как это делает JetStream, и подходит для тестов и локального запуска.
+type MemoryBroker struct {
+	mu       sync.Mutex
+	seen     map[string]struct{}
+	messages map[string][]Message
+}
+
+// NewMemoryBroker создает пустой брокер в памяти.
+func NewMemoryBroker() *MemoryBroker {
+	return &MemoryBroker{
+		seen:     make(map[string]struct{}),
+		messages: make(map[string][]Message),
+	}
+}
+
+func (b *MemoryBroker) Publish(_ context.Context, subject string, msg Message) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.seen[msg.ID]; ok {
+		return nil
+	}
+	b.seen[msg.ID] = struct{}{}
+	b.messages[subject] = append(b.messages[subject], msg)
+
+	return nil
+}
+
+// Messages возвращает копию сообщений, опубликованных в subject, в порядке публикации.
+func (b *MemoryBroker) Messages(subject string) []Message {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return append([]Message(nil), b.messages[subject]...)
+}
@@ -0,0 +1,30 @@
+package events
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/vasiliy-maslov/go-subscription-service/internal/model"
+)
+
+var _ EventPublisher = (*LogPublisher)(nil)
+
+// LogPublisher пишет события в лог. Удобен для локальной разработки.
+type LogPublisher struct {
+	logger *slog.Logger
+}
+
+// NewLogPublisher создает издателя, который пишет события в лог.
+func NewLogPublisher(logger *slog.Logger) *LogPublisher {
+	return &LogPublisher{logger: logger}
+}
+
+func (p *LogPublisher) Publish(_ context.Context, event model.Event) error {
+	p.logger.Info("Опубликовано событие",
+		slog.String("event_id", event.ID.String()),
+		slog.String("event_type", event.Type),
+		slog.String("aggregate_id", event.AggregateID.String()),
+		slog.String("payload", string(event.Payload)),
+	)
+	return nil
+}
@@ -0,0 +1,39 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/vasiliy-maslov/go-subscription-service/internal/config"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/model"
+)
+
+// EventPublisher доставляет доменные события во внешний мир.
+// Доставка выполняется по принципу "как минимум один раз", поэтому потребители
+// должны дедуплицировать события по их ID.
+type EventPublisher interface {
+	Publish(ctx context.Context, event model.Event) error
+}
+
+// NewPublisher создает издателя событий по конфигурации. Издатель broker публикует события
+// через BrokerPublisher в брокер в памяти процесса (MemoryBroker): он хранит все сообщения
+// и подходит только для локального запуска, пока нет адаптеров NATS и Kafka.
+func NewPublisher(cfg config.OutboxConfig, logger *slog.Logger) (EventPublisher, error) {
+	switch cfg.Publisher {
+	case "", "log":
+		return NewLogPublisher(logger), nil
+	case "webhook":
+		if cfg.WebhookURL == "" {
+			return nil, fmt.Errorf("не задан webhook_url для издателя webhook")
+		}
+		return NewWebhookPublisher(cfg.WebhookURL, nil), nil
+	case "broker":
+		if cfg.BrokerSubject == "" {
+			return nil, fmt.Errorf("не задан broker_subject для издателя broker")
+		}
+		return NewBrokerPublisher(NewMemoryBroker(), cfg.BrokerSubject), nil
+	default:
+		return nil, fmt.Errorf("неизвестный издатель событий: %s", cfg.Publisher)
+	}
+}
@@ -0,0 +1,71 @@
+package graphql
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/vasiliy-maslov/go-subscription-service/internal/logging"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/model"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/repository"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/service"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/google/uuid"
+)
+
+// auditor записывает мутации в журнал аудита. Один HTTP-запрос может содержать несколько мутаций,
+// поэтому каждая записывается отдельно с действием "mutation <поле>".
+type auditor struct {
+	audit  service.AuditService
+	logger *slog.Logger
+}
+
+// recordMutation - промежуточный обработчик полей. Запросы на чтение в журнал не попадают.
+// IP-адрес клиента берется из контекста, куда его кладет HTTP-обработчик с учетом доверенных прокси.
+// Ошибка записи не меняет ответ, а пишется в лог.
+func (a *auditor) recordMutation(ctx context.Context, next graphql.Resolver) (any, error) {
+	const op = "graphql.recordMutation"
+
+	fc := graphql.GetFieldContext(ctx)
+	if a.audit == nil || fc == nil || fc.Object != "Mutation" {
+		return next(ctx)
+	}
+
+	res, err := next(ctx)
+
+	entry := model.AuditEntry{
+		ActorType:    model.AuditActorAnonymous,
+		Action:       "mutation " + fc.Field.Name,
+		ResourceType: "subscriptions",
+		RequestID:    logging.RequestID(ctx),
+		SourceIP:     logging.ClientIP(ctx),
+		Outcome:      model.AuditOutcomeSuccess,
+		Status:       http.StatusOK,
+	}
+	if key, ok := service.APIKeyFrom(ctx); ok {
+		entry.ActorType, entry.ActorID = model.AuditActorAPIKey, &key.ID
+	} else if userID, ok := repository.TenantFrom(ctx); ok {
+		entry.ActorType, entry.ActorID = model.AuditActorUser, &userID
+	}
+	if id, ok := fc.Args["id"].(uuid.UUID); ok {
+		entry.ResourceID = id.String()
+	} else if sub, ok := res.(*model.Subscription); ok && sub != nil {
+		entry.ResourceID = sub.ID.String()
+	}
+	if err != nil {
+		_, entry.Status = classify(err)
+		entry.Outcome = model.AuditOutcomeFailure
+		if entry.Status == http.StatusForbidden {
+			entry.Outcome = model.AuditOutcomeDenied
+		}
+	}
+
+	// Клиент мог закрыть соединение, но запись о выполненном действии нужна все равно.
+	if _, auditErr := a.audit.Record(context.WithoutCancel(ctx), entry); auditErr != nil {
+		logging.FromContext(ctx, a.logger).Error("Не удалось записать мутацию в журнал аудита",
+			slog.String("op", op), slog.String("error", auditErr.Error()))
+	}
+
+	return res, err
+}
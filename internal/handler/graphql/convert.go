@@ -0,0 +1,95 @@
+package graphql
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/vasiliy-maslov/go-subscription-service/internal/model"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/repository"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/service"
+)
+
+// defaultForecastMonths - горизонт прогноза, если аргумент months не задан.
+const defaultForecastMonths = 12
+
+// toFilter переводит выборку подписок из аргументов запроса. userId обязателен, если не задана организация.
+func toFilter(in SubscriptionFilter) (repository.SubscriptionFilter, error) {
+	filter := repository.SubscriptionFilter{
+		OrganizationID: in.OrganizationID,
+		ServiceName:    in.ServiceName,
+		ServiceID:      in.ServiceID,
+		CategoryID:     in.CategoryID,
+		Tag:            in.Tag,
+	}
+
+	switch {
+	case in.UserID != nil:
+		filter.UserID = *in.UserID
+	case in.OrganizationID == nil:
+		return repository.SubscriptionFilter{}, fmt.Errorf("%w: userId is required", service.ErrValidation)
+	}
+
+	if in.Currency != nil {
+		currency, err := toCurrency(*in.Currency)
+		if err != nil {
+			return repository.SubscriptionFilter{}, err
+		}
+		filter.Currency = &currency
+	}
+
+	return filter, nil
+}
+
+func toCurrency(value string) (model.Currency, error) {
+	currency := model.Currency(strings.ToUpper(value))
+	if !currency.Valid() {
+		return "", fmt.Errorf("%w: invalid currency, use an ISO 4217 code", service.ErrValidation)
+	}
+	return currency, nil
+}
+
+// toCostPeriod переводит период расчета стоимости. При помесячном расчете сервис расширяет период
+// до первого дня месяца from и последнего дня месяца to, как start_period и end_period в REST API.
+func toCostPeriod(in CostPeriodInput) (model.CostPeriod, error) {
+	period := model.CostPeriod{Proration: model.ProrationMonthly, From: in.From, To: in.To}
+	if in.Proration != nil {
+		period.Proration = model.Proration(*in.Proration)
+	}
+	if !period.Proration.Valid() {
+		return model.CostPeriod{}, fmt.Errorf("%w: proration must be monthly or daily", service.ErrValidation)
+	}
+
+	return period, nil
+}
+
+// toSubscription переводит аргументы мутации в модель подписки. Категории и метки остаются nil,
+// если не переданы, и тогда при обновлении не меняются.
+func toSubscription(in SubscriptionInput) (model.Subscription, error) {
+	sub := model.Subscription{
+		UserID:         in.UserID,
+		OrganizationID: in.OrganizationID,
+		ServiceName:    in.ServiceName,
+		ServiceID:      in.ServiceID,
+		PlanID:         in.PlanID,
+		StartDate:      in.StartDate,
+		EndDate:        in.EndDate,
+		TrialEndDate:   in.TrialEndDate,
+		CategoryIDs:    in.CategoryIds,
+		Tags:           in.Tags,
+	}
+	if in.Price != nil {
+		sub.Price = *in.Price
+	}
+	if in.BillingPeriod != nil {
+		sub.BillingPeriod = model.BillingPeriod(*in.BillingPeriod)
+	}
+	if in.Currency != nil {
+		currency, err := toCurrency(*in.Currency)
+		if err != nil {
+			return model.Subscription{}, err
+		}
+		sub.Currency = currency
+	}
+
+	return sub, nil
+}
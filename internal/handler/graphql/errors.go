@@ -0,0 +1,97 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/vasiliy-maslov/go-subscription-service/internal/logging"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/repository"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/service"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// Коды ошибок в extensions.code ответа.
+const (
+	codeBadUserInput = "BAD_USER_INPUT"
+	codeForbidden    = "FORBIDDEN"
+	codeNotFound     = "NOT_FOUND"
+	codeConflict     = "CONFLICT"
+	codeInternal     = "INTERNAL_SERVER_ERROR"
+)
+
+// classify сопоставляет ошибке сервисного слоя код ошибки GraphQL и код ответа HTTP,
+// которым на нее отвечает REST API.
+func classify(err error) (string, int) {
+	var permErr *service.PermissionError
+	switch {
+	case errors.As(err, &permErr), errors.Is(err, repository.ErrTenantMismatch), errors.Is(err, service.ErrForbidden),
+		errors.Is(err, service.ErrOutOfScope):
+		return codeForbidden, http.StatusForbidden
+	case errors.Is(err, repository.ErrNotFound):
+		return codeNotFound, http.StatusNotFound
+	case errors.Is(err, service.ErrValidation), errors.Is(err, repository.ErrUserNotFound):
+		return codeBadUserInput, http.StatusBadRequest
+	case errors.Is(err, service.ErrDuplicateSubscription), errors.Is(err, service.ErrInvalidTransition):
+		return codeConflict, http.StatusConflict
+	default:
+		return codeInternal, http.StatusInternalServerError
+	}
+}
+
+// presentError дополняет ошибку резолвера кодом в extensions.code, а отказ политики доступа -
+// недостающим разрешением, и пишет ее в лог. Ошибки разбора и проверки запроса не меняются.
+func (r *Resolver) presentError(ctx context.Context, err error) *gqlerror.Error {
+	const op = "graphql.presentError"
+
+	presented := graphql.DefaultErrorPresenter(ctx, err)
+	if presented.Err == nil {
+		return presented
+	}
+	err = presented.Err
+
+	code, status := classify(err)
+	if presented.Extensions == nil {
+		presented.Extensions = map[string]any{}
+	}
+	presented.Extensions["code"] = code
+
+	var permErr *service.PermissionError
+	if errors.As(err, &permErr) {
+		presented.Extensions["permission"] = permErr.Permission
+	}
+
+	log := logging.FromContext(ctx, r.logger).With(
+		slog.String("op", op),
+		slog.String("path", presented.Path.String()),
+		slog.String("error", err.Error()),
+	)
+	if status >= http.StatusInternalServerError {
+		log.Error("Ошибка при разрешении поля")
+	} else {
+		log.Warn("Ошибка при разрешении поля")
+	}
+
+	return presented
+}
+
+// recoverPanic пишет панику резолвера в лог со стеком вызовов и возвращает клиенту
+// внутреннюю ошибку с идентификатором запроса.
+func (r *Resolver) recoverPanic(ctx context.Context, rec any) error {
+	const op = "graphql.recoverPanic"
+
+	logging.FromContext(ctx, r.logger).Error("Паника при разрешении поля",
+		slog.String("op", op),
+		slog.Any("panic", rec),
+		slog.String("stack", string(debug.Stack())),
+	)
+
+	return &gqlerror.Error{
+		Message:    "internal server error, request ID " + logging.RequestID(ctx),
+		Extensions: map[string]any{"code": codeInternal},
+	}
+}
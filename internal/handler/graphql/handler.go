@@ -0,0 +1,91 @@
+package graphql
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/vasiliy-maslov/go-subscription-service/internal/config"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/service"
+
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/lru"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// Сложность полей, которые стоят дороже одного поля объекта. Без множителя список подписок
+// считался бы одним объектом, и ограничение сложности не защищало бы от больших выборок.
+const (
+	// listComplexity - ожидаемый размер списка подписок.
+	listComplexity = 10
+	// costComplexity - расчет стоимости или прогноза за период.
+	costComplexity = 10
+)
+
+// queryCacheSize - число разобранных документов запросов, которые хранятся между запросами.
+const queryCacheSize = 1000
+
+// NewHandler создает обработчик эндпоинта /graphql. Запросы принимаются методами GET (только чтение)
+// и POST. Запросы сложнее cfg.ComplexityLimit отклоняются до выполнения, мутации записываются
+// в журнал аудита, если audit не nil.
+func NewHandler(
+	subscriptions service.SubscriptionService,
+	users service.UserService,
+	categories service.CategoryService,
+	audit service.AuditService,
+	cfg config.GraphQLConfig,
+	logger *slog.Logger,
+) http.Handler {
+	resolver := &Resolver{
+		subscriptions: subscriptions,
+		users:         users,
+		categories:    categories,
+		logger:        logger,
+	}
+
+	srv := handler.New(NewExecutableSchema(Config{Resolvers: resolver, Complexity: complexity()}))
+	srv.AddTransport(transport.GET{})
+	srv.AddTransport(transport.POST{})
+	srv.SetQueryCache(lru.New[*ast.QueryDocument](queryCacheSize))
+	if cfg.Introspection {
+		srv.Use(extension.Introspection{})
+	}
+	if cfg.ComplexityLimit > 0 {
+		srv.Use(extension.FixedComplexityLimit(cfg.ComplexityLimit))
+	}
+	srv.SetErrorPresenter(resolver.presentError)
+	srv.SetRecoverFunc(resolver.recoverPanic)
+	srv.AroundFields((&auditor{audit: audit, logger: logger}).recordMutation)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := withLoaders(r.Context(), newLoaders(subscriptions, categories))
+		srv.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// complexity задает сложность списков и расчетов стоимости.
+func complexity() ComplexityRoot {
+	var c ComplexityRoot
+
+	c.Query.Subscriptions = func(childComplexity int, _ SubscriptionFilter) int {
+		return listComplexity * childComplexity
+	}
+	c.User.Subscriptions = func(childComplexity int) int {
+		return listComplexity * childComplexity
+	}
+	c.Query.Spending = func(childComplexity int, _ SubscriptionFilter, _ CostPeriodInput, _ *string, _ *bool) int {
+		return costComplexity + childComplexity
+	}
+	c.Query.Forecast = func(childComplexity int, _ SubscriptionFilter, _ *int) int {
+		return costComplexity + childComplexity
+	}
+	c.User.Spending = func(childComplexity int, _ CostPeriodInput, _ *string, _ *string) int {
+		return costComplexity + childComplexity
+	}
+	c.Subscription.Cost = func(childComplexity int, _ CostPeriodInput) int {
+		return costComplexity + childComplexity
+	}
+
+	return c
+}
@@ -0,0 +1,210 @@
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/vasiliy-maslov/go-subscription-service/internal/config"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/model"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/repository"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/service"
+
+	"github.com/google/uuid"
+)
+
+// fakeSubscriptions отдает заданные подписки и считает обращения к пакетным методам.
+// Каждой подписке из lineItems приходится две строки детализации по 1.00.
+type fakeSubscriptions struct {
+	service.SubscriptionService
+	subs      []model.Subscription
+	lineItems map[uuid.UUID]bool
+
+	mu    sync.Mutex
+	calls map[string]int
+}
+
+func (f *fakeSubscriptions) called(method string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls[method]++
+}
+
+func (f *fakeSubscriptions) List(context.Context, repository.SubscriptionFilter) ([]model.Subscription, error) {
+	f.called("List")
+	return f.subs, nil
+}
+
+func (f *fakeSubscriptions) ListTransitions(_ context.Context, ids []uuid.UUID) (map[uuid.UUID][]model.StatusTransition, error) {
+	f.called("ListTransitions")
+	history := make(map[uuid.UUID][]model.StatusTransition, len(ids))
+	for _, id := range ids {
+		history[id] = []model.StatusTransition{{ID: uuid.New(), SubscriptionID: id, ToStatus: model.StatusActive}}
+	}
+	return history, nil
+}
+
+func (f *fakeSubscriptions) CalculateCostLineItems(_ context.Context, filter repository.SubscriptionFilter, _ model.CostPeriod) (model.CostSummary, error) {
+	f.called("CalculateCostLineItems")
+	var summary model.CostSummary
+	for _, sub := range f.subs {
+		if !f.lineItems[sub.ID] || sub.UserID != filter.UserID || sub.Currency != *filter.Currency {
+			continue
+		}
+		for range 2 {
+			summary.LineItems = append(summary.LineItems, model.CostLineItem{SubscriptionID: sub.ID, Cost: model.NewMoney(100, 2)})
+		}
+	}
+	return summary, nil
+}
+
+// fakeCategories - сервис категорий, у подписок которого нет категорий.
+type fakeCategories struct {
+	service.CategoryService
+	subscriptions *fakeSubscriptions
+}
+
+func (f fakeCategories) ListSubscriptionCategories(context.Context, []uuid.UUID) (map[uuid.UUID][]model.Category, error) {
+	f.subscriptions.called("ListSubscriptionCategories")
+	return nil, nil
+}
+
+const subscriptionsQuery = `{
+  subscriptions(filter: {userId: "%s"}) {
+    id
+    history { id }
+    categories { id }
+    cost(period: {from: "2024-01-01", to: "2024-01-31"})
+  }
+}`
+
+type graphqlResponse struct {
+	Data struct {
+		Subscriptions []struct {
+			ID         uuid.UUID
+			History    []struct{ ID uuid.UUID }
+			Categories []struct{ ID uuid.UUID }
+			Cost       string
+		}
+	}
+	Errors []struct {
+		Message    string
+		Extensions map[string]any
+	}
+}
+
+func newTestSubscriptions(userID uuid.UUID) *fakeSubscriptions {
+	subs := &fakeSubscriptions{lineItems: map[uuid.UUID]bool{}, calls: map[string]int{}}
+	for i := range 5 {
+		sub := model.Subscription{ID: uuid.New(), UserID: userID, Currency: "RUB"}
+		if i == 4 {
+			sub.Currency = "USD"
+		}
+		if i < 3 {
+			subs.lineItems[sub.ID] = true
+		}
+		subs.subs = append(subs.subs, sub)
+	}
+	return subs
+}
+
+func query(t *testing.T, h http.Handler, q string) graphqlResponse {
+	t.Helper()
+
+	body, err := json.Marshal(map[string]string{"query": q})
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	req := httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+
+	var resp graphqlResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response %q: %v", w.Body.String(), err)
+	}
+	return resp
+}
+
+func TestLoadersBatchSubscriptionFields(t *testing.T) {
+	userID := uuid.New()
+	subs := newTestSubscriptions(userID)
+	h := NewHandler(subs, nil, fakeCategories{subscriptions: subs}, nil, config.GraphQLConfig{ComplexityLimit: 1000},
+		slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	resp := query(t, h, fmt.Sprintf(subscriptionsQuery, userID))
+	if len(resp.Errors) > 0 {
+		t.Fatalf("errors = %+v", resp.Errors)
+	}
+	if len(resp.Data.Subscriptions) != len(subs.subs) {
+		t.Fatalf("got %d subscriptions, want %d", len(resp.Data.Subscriptions), len(subs.subs))
+	}
+
+	// Подписки в RUB и USD - две группы расчета стоимости, остальные поля загружаются одним пакетом.
+	wantCalls := map[string]int{"List": 1, "ListTransitions": 1, "ListSubscriptionCategories": 1, "CalculateCostLineItems": 2}
+	for method, want := range wantCalls {
+		if got := subs.calls[method]; got != want {
+			t.Errorf("%s() called %d times, want %d", method, got, want)
+		}
+	}
+
+	for i, sub := range resp.Data.Subscriptions {
+		want := "0.00"
+		if subs.lineItems[sub.ID] {
+			want = "2.00"
+		}
+		if sub.Cost != want {
+			t.Errorf("subscription %d cost = %s, want %s", i, sub.Cost, want)
+		}
+		if len(sub.History) != 1 || sub.Categories == nil || len(sub.Categories) != 0 {
+			t.Errorf("subscription %d history = %v, categories = %v, want one transition and no categories", i, sub.History, sub.Categories)
+		}
+	}
+}
+
+func TestComplexityLimit(t *testing.T) {
+	tests := []struct {
+		name     string
+		limit    int
+		query    string
+		wantCode string
+	}{
+		// Список подписок стоит listComplexity × (id + history + categories + cost) = 10 × (1 + 2 + 2 + 10).
+		{name: "list under the limit", limit: 150, query: subscriptionsQuery},
+		{name: "list over the limit", limit: 149, query: subscriptionsQuery, wantCode: "COMPLEXITY_LIMIT_EXCEEDED"},
+		{name: "list without cost", limit: 50, query: `{ subscriptions(filter: {userId: "%s"}) { id history { id } } }`},
+		{name: "no limit", query: subscriptionsQuery},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userID := uuid.New()
+			subs := newTestSubscriptions(userID)
+			h := NewHandler(subs, nil, fakeCategories{subscriptions: subs}, nil, config.GraphQLConfig{ComplexityLimit: tt.limit},
+				slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+			resp := query(t, h, fmt.Sprintf(tt.query, userID))
+			if tt.wantCode == "" {
+				if len(resp.Errors) > 0 {
+					t.Fatalf("errors = %+v", resp.Errors)
+				}
+				return
+			}
+
+			if len(resp.Errors) != 1 || resp.Errors[0].Extensions["code"] != tt.wantCode {
+				t.Fatalf("errors = %+v, want %s", resp.Errors, tt.wantCode)
+			}
+			if len(subs.calls) != 0 {
+				t.Errorf("rejected query called the services: %v", subs.calls)
+			}
+		})
+	}
+}
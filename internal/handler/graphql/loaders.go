@@ -0,0 +1,144 @@
+package graphql
+
+import (
+	"context"
+	"time"
+
+	"github.com/vasiliy-maslov/go-subscription-service/internal/model"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/repository"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/vikstrous/dataloadgen"
+)
+
+// loaderWait - время, за которое загрузчик собирает ключи одного пакета. Поля подписок одного
+// списка разрешаются параллельно, поэтому за это время успевают прийти ключи всех подписок.
+const loaderWait = 2 * time.Millisecond
+
+type loadersKey struct{}
+
+// loaders собирают обращения полей подписок в пакеты, чтобы список из N подписок загружал
+// категории, историю и стоимость одним запросом на поле, а не N запросами.
+// Загрузчики создаются на каждый HTTP-запрос, поэтому их кэш не переживает запрос.
+type loaders struct {
+	categories *dataloadgen.Loader[uuid.UUID, []model.Category]
+	history    *dataloadgen.Loader[uuid.UUID, []model.StatusTransition]
+	costs      *dataloadgen.Loader[costKey, model.Money]
+}
+
+func newLoaders(subscriptions service.SubscriptionService, categories service.CategoryService) *loaders {
+	return &loaders{
+		categories: newListLoader(categories.ListSubscriptionCategories),
+		history:    newListLoader(subscriptions.ListTransitions),
+		costs:      dataloadgen.NewLoader(costFetcher(subscriptions), dataloadgen.WithWait(loaderWait)),
+	}
+}
+
+// withLoaders кладет загрузчики запроса в контекст.
+func withLoaders(ctx context.Context, l *loaders) context.Context {
+	return context.WithValue(ctx, loadersKey{}, l)
+}
+
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
+
+// newListLoader создает загрузчик списков по ID подписок поверх пакетного метода сервиса.
+// Подписке без записей соответствует пустой список.
+func newListLoader[V any](fetch func(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID][]V, error)) *dataloadgen.Loader[uuid.UUID, []V] {
+	return dataloadgen.NewLoader(func(ctx context.Context, ids []uuid.UUID) ([][]V, []error) {
+		byID, err := fetch(ctx, ids)
+		if err != nil {
+			return nil, []error{err}
+		}
+
+		result := make([][]V, len(ids))
+		for i, id := range ids {
+			result[i] = byID[id]
+			if result[i] == nil {
+				result[i] = []V{}
+			}
+		}
+		return result, nil
+	}, dataloadgen.WithWait(loaderWait))
+}
+
+// costKey - подписка и период, за который нужна ее стоимость.
+type costKey struct {
+	subscriptionID uuid.UUID
+	costGroup
+}
+
+// costGroup - выборка, стоимость подписок которой считается одним вызовом сервиса.
+// OrganizationID равен uuid.Nil для личных подписок.
+type costGroup struct {
+	userID         uuid.UUID
+	organizationID uuid.UUID
+	currency       model.Currency
+	period         model.CostPeriod
+}
+
+func newCostKey(sub model.Subscription, period model.CostPeriod) costKey {
+	key := costKey{
+		subscriptionID: sub.ID,
+		costGroup:      costGroup{userID: sub.UserID, currency: sub.Currency, period: period},
+	}
+	if sub.OrganizationID != nil {
+		key.organizationID = *sub.OrganizationID
+	}
+	return key
+}
+
+// costFetcher считает стоимость подписок через построчную детализацию: по одному вызову
+// CalculateCostLineItems на владельца, валюту и период, после чего строки суммируются по подпискам.
+func costFetcher(subscriptions service.SubscriptionService) func(ctx context.Context, keys []costKey) ([]model.Money, []error) {
+	return func(ctx context.Context, keys []costKey) ([]model.Money, []error) {
+		costs := make(map[costGroup]map[uuid.UUID]model.Money)
+		failed := make(map[costGroup]error)
+
+		for _, key := range keys {
+			group := key.costGroup
+			if _, done := costs[group]; done || failed[group] != nil {
+				continue
+			}
+
+			filter := repository.SubscriptionFilter{UserID: group.userID, Currency: &group.currency}
+			if group.organizationID != uuid.Nil {
+				filter.OrganizationID = &group.organizationID
+			}
+
+			summary, err := subscriptions.CalculateCostLineItems(ctx, filter, group.period)
+			if err != nil {
+				failed[group] = err
+				continue
+			}
+
+			bySubscription := make(map[uuid.UUID]model.Money)
+			for _, item := range summary.LineItems {
+				total, err := bySubscription[item.SubscriptionID].Add(item.Cost)
+				if err != nil {
+					failed[group] = err
+					break
+				}
+				bySubscription[item.SubscriptionID] = total
+			}
+			costs[group] = bySubscription
+		}
+
+		values := make([]model.Money, len(keys))
+		errs := make([]error, len(keys))
+		for i, key := range keys {
+			if err := failed[key.costGroup]; err != nil {
+				errs[i] = err
+				continue
+			}
+			cost, ok := costs[key.costGroup][key.subscriptionID]
+			if !ok {
+				cost = model.NewMoney(0, key.currency.Exponent())
+			}
+			values[i] = cost
+		}
+		return values, errs
+	}
+}
@@ -0,0 +1,54 @@
+// Code generated by github.com/99designs/gqlgen, DO NOT EDIT.
+
+package graphql
+
+import (
+	"github.com/google/uuid"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/model"
+)
+
+type CostPeriodInput struct {
+	// monthly or daily.
+	Proration *string `json:"proration,omitempty"`
+	// With monthly proration the period runs from the first day of the month of from to the last day of the month of to.
+	From model.Date `json:"from"`
+	To   model.Date `json:"to"`
+}
+
+type Mutation struct {
+}
+
+type Query struct {
+}
+
+// Subscription selection, the same as the REST query parameters. userId is required unless organizationId is set.
+type SubscriptionFilter struct {
+	UserID         *uuid.UUID `json:"userId,omitempty"`
+	OrganizationID *uuid.UUID `json:"organizationId,omitempty"`
+	// Service name or any of its catalog aliases.
+	ServiceName *string    `json:"serviceName,omitempty"`
+	ServiceID   *uuid.UUID `json:"serviceId,omitempty"`
+	CategoryID  *uuid.UUID `json:"categoryId,omitempty"`
+	// Case-insensitive.
+	Tag      *string `json:"tag,omitempty"`
+	Currency *string `json:"currency,omitempty"`
+}
+
+type SubscriptionInput struct {
+	UserID         uuid.UUID  `json:"userId"`
+	OrganizationID *uuid.UUID `json:"organizationId,omitempty"`
+	ServiceName    string     `json:"serviceName"`
+	ServiceID      *uuid.UUID `json:"serviceId,omitempty"`
+	PlanID         *uuid.UUID `json:"planId,omitempty"`
+	// Zero or omitted with planId takes the plan price.
+	Price *model.Money `json:"price,omitempty"`
+	// Defaults to the user's or organization's default currency.
+	Currency *string `json:"currency,omitempty"`
+	// monthly (default) or yearly.
+	BillingPeriod *string     `json:"billingPeriod,omitempty"`
+	StartDate     model.Date  `json:"startDate"`
+	EndDate       *model.Date `json:"endDate,omitempty"`
+	TrialEndDate  *model.Date `json:"trialEndDate,omitempty"`
+	CategoryIds   []uuid.UUID `json:"categoryIds,omitempty"`
+	Tags          []string    `json:"tags,omitempty"`
+}
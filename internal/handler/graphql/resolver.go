@@ -0,0 +1,18 @@
+package graphql
+
+//go:generate go tool gqlgen generate
+
+import (
+	"log/slog"
+
+	"github.com/vasiliy-maslov/go-subscription-service/internal/service"
+)
+
+// Resolver разрешает поля схемы через те же сервисы, что и REST API, поэтому права доступа,
+// привязка к пользователю и валидация работают одинаково.
+type Resolver struct {
+	subscriptions service.SubscriptionService
+	users         service.UserService
+	categories    service.CategoryService
+	logger        *slog.Logger
+}
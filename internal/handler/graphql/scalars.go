@@ -0,0 +1,49 @@
+package graphql
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/vasiliy-maslov/go-subscription-service/internal/model"
+
+	"github.com/99designs/gqlgen/graphql"
+)
+
+// MarshalMoney записывает сумму скаляра Money десятичной строкой, чтобы не терять точность.
+func MarshalMoney(m model.Money) graphql.Marshaler {
+	return graphql.WriterFunc(func(w io.Writer) {
+		_, _ = io.WriteString(w, strconv.Quote(m.String()))
+	})
+}
+
+// UnmarshalMoney читает сумму из строки или числа запроса.
+func UnmarshalMoney(v any) (model.Money, error) {
+	switch value := v.(type) {
+	case string:
+		return model.ParseMoney(value)
+	case int64:
+		return model.ParseMoney(strconv.FormatInt(value, 10))
+	case float64:
+		return model.ParseMoney(strconv.FormatFloat(value, 'f', -1, 64))
+	default:
+		return model.Money{}, fmt.Errorf("money must be a decimal string, got %T", v)
+	}
+}
+
+// MarshalDate записывает календарную дату скаляра Date строкой YYYY-MM-DD.
+func MarshalDate(d model.Date) graphql.Marshaler {
+	return graphql.WriterFunc(func(w io.Writer) {
+		_, _ = io.WriteString(w, strconv.Quote(d.String()))
+	})
+}
+
+// UnmarshalDate читает дату YYYY-MM-DD или момент времени RFC 3339. Календарный день момента
+// определяет сервис в часовом поясе пользователя.
+func UnmarshalDate(v any) (model.Date, error) {
+	value, ok := v.(string)
+	if !ok {
+		return model.Date{}, fmt.Errorf("date must be a string, got %T", v)
+	}
+	return model.ParseDateOrMoment(value)
+}
@@ -0,0 +1,271 @@
+package graphql
+
+// This file will be automatically regenerated based on the schema, any resolver
+// implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/model"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/repository"
+)
+
+// DiscountID is the resolver for the discountId field.
+func (r *costLineItemResolver) DiscountID(ctx context.Context, obj *model.CostLineItem) (*uuid.UUID, error) {
+	if obj.Discount == nil {
+		return nil, nil
+	}
+	return &obj.Discount.ID, nil
+}
+
+// CreateSubscription is the resolver for the createSubscription field.
+func (r *mutationResolver) CreateSubscription(ctx context.Context, input SubscriptionInput) (*model.Subscription, error) {
+	sub, err := toSubscription(input)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := r.subscriptions.Create(ctx, sub)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := r.subscriptions.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// UpdateSubscription is the resolver for the updateSubscription field.
+func (r *mutationResolver) UpdateSubscription(ctx context.Context, id uuid.UUID, input SubscriptionInput) (*model.Subscription, error) {
+	sub, err := toSubscription(input)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.subscriptions.Update(ctx, id, sub); err != nil {
+		return nil, err
+	}
+
+	updated, err := r.subscriptions.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// DeleteSubscription is the resolver for the deleteSubscription field.
+func (r *mutationResolver) DeleteSubscription(ctx context.Context, id uuid.UUID) (bool, error) {
+	if err := r.subscriptions.Delete(ctx, id); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// PauseSubscription is the resolver for the pauseSubscription field.
+func (r *mutationResolver) PauseSubscription(ctx context.Context, id uuid.UUID) (*model.Subscription, error) {
+	sub, err := r.subscriptions.Pause(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &sub, nil
+}
+
+// ResumeSubscription is the resolver for the resumeSubscription field.
+func (r *mutationResolver) ResumeSubscription(ctx context.Context, id uuid.UUID) (*model.Subscription, error) {
+	sub, err := r.subscriptions.Resume(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &sub, nil
+}
+
+// CancelSubscription is the resolver for the cancelSubscription field.
+func (r *mutationResolver) CancelSubscription(ctx context.Context, id uuid.UUID, immediate *bool) (*model.Subscription, error) {
+	sub, err := r.subscriptions.Cancel(ctx, id, immediate != nil && *immediate)
+	if err != nil {
+		return nil, err
+	}
+	return &sub, nil
+}
+
+// RestoreSubscription is the resolver for the restoreSubscription field.
+func (r *mutationResolver) RestoreSubscription(ctx context.Context, id uuid.UUID) (*model.Subscription, error) {
+	sub, err := r.subscriptions.Restore(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &sub, nil
+}
+
+// User is the resolver for the user field.
+func (r *queryResolver) User(ctx context.Context, id uuid.UUID) (*model.User, error) {
+	u, err := r.users.GetByID(ctx, id)
+	if errors.Is(err, repository.ErrUserNotFound) || errors.Is(err, repository.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+// Subscription is the resolver for the subscription field.
+func (r *queryResolver) Subscription(ctx context.Context, id uuid.UUID) (*model.Subscription, error) {
+	sub, err := r.subscriptions.GetByID(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &sub, nil
+}
+
+// Subscriptions is the resolver for the subscriptions field.
+func (r *queryResolver) Subscriptions(ctx context.Context, filter SubscriptionFilter) ([]model.Subscription, error) {
+	f, err := toFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+	return r.subscriptions.List(ctx, f)
+}
+
+// Spending is the resolver for the spending field.
+func (r *queryResolver) Spending(ctx context.Context, filter SubscriptionFilter, period CostPeriodInput, groupBy *string, lineItems *bool) (*model.CostSummary, error) {
+	f, err := toFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := toCostPeriod(period)
+	if err != nil {
+		return nil, err
+	}
+
+	var summary model.CostSummary
+	switch {
+	case groupBy != nil:
+		summary, err = r.subscriptions.CalculateCostBreakdown(ctx, f, *groupBy, p)
+	case lineItems != nil && *lineItems:
+		summary, err = r.subscriptions.CalculateCostLineItems(ctx, f, p)
+	default:
+		summary, err = r.subscriptions.CalculateTotalCost(ctx, f, p)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &summary, nil
+}
+
+// Forecast is the resolver for the forecast field.
+func (r *queryResolver) Forecast(ctx context.Context, filter SubscriptionFilter, months *int) (*model.Forecast, error) {
+	f, err := toFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	n := defaultForecastMonths
+	if months != nil {
+		n = *months
+	}
+
+	forecast, err := r.subscriptions.Forecast(ctx, f, n)
+	if err != nil {
+		return nil, err
+	}
+	return &forecast, nil
+}
+
+// Categories is the resolver for the categories field.
+func (r *subscriptionResolver) Categories(ctx context.Context, obj *model.Subscription) ([]model.Category, error) {
+	return loadersFrom(ctx).categories.Load(ctx, obj.ID)
+}
+
+// History is the resolver for the history field.
+func (r *subscriptionResolver) History(ctx context.Context, obj *model.Subscription) ([]model.StatusTransition, error) {
+	return loadersFrom(ctx).history.Load(ctx, obj.ID)
+}
+
+// Cost is the resolver for the cost field.
+func (r *subscriptionResolver) Cost(ctx context.Context, obj *model.Subscription, period CostPeriodInput) (*model.Money, error) {
+	p, err := toCostPeriod(period)
+	if err != nil {
+		return nil, err
+	}
+
+	cost, err := loadersFrom(ctx).costs.Load(ctx, newCostKey(*obj, p))
+	if err != nil {
+		return nil, err
+	}
+	return &cost, nil
+}
+
+// Subscriptions is the resolver for the subscriptions field.
+func (r *userResolver) Subscriptions(ctx context.Context, obj *model.User) ([]model.Subscription, error) {
+	return r.subscriptions.List(ctx, repository.SubscriptionFilter{UserID: obj.ID})
+}
+
+// Categories is the resolver for the categories field.
+func (r *userResolver) Categories(ctx context.Context, obj *model.User) ([]model.Category, error) {
+	return r.categories.ListCategories(ctx, obj.ID)
+}
+
+// Tags is the resolver for the tags field.
+func (r *userResolver) Tags(ctx context.Context, obj *model.User) ([]model.Tag, error) {
+	return r.categories.ListTags(ctx, obj.ID)
+}
+
+// Spending is the resolver for the spending field.
+func (r *userResolver) Spending(ctx context.Context, obj *model.User, period CostPeriodInput, groupBy *string, currency *string) (*model.CostSummary, error) {
+	filter := repository.SubscriptionFilter{UserID: obj.ID}
+	if currency != nil {
+		c, err := toCurrency(*currency)
+		if err != nil {
+			return nil, err
+		}
+		filter.Currency = &c
+	}
+
+	p, err := toCostPeriod(period)
+	if err != nil {
+		return nil, err
+	}
+
+	var summary model.CostSummary
+	if groupBy != nil {
+		summary, err = r.subscriptions.CalculateCostBreakdown(ctx, filter, *groupBy, p)
+	} else {
+		summary, err = r.subscriptions.CalculateTotalCost(ctx, filter, p)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &summary, nil
+}
+
+// CostLineItem returns CostLineItemResolver implementation.
+func (r *Resolver) CostLineItem() CostLineItemResolver { return &costLineItemResolver{r} }
+
+// Mutation returns MutationResolver implementation.
+func (r *Resolver) Mutation() MutationResolver { return &mutationResolver{r} }
+
+// Query returns QueryResolver implementation.
+func (r *Resolver) Query() QueryResolver { return &queryResolver{r} }
+
+// Subscription returns SubscriptionResolver implementation.
+func (r *Resolver) Subscription() SubscriptionResolver { return &subscriptionResolver{r} }
+
+// User returns UserResolver implementation.
+func (r *Resolver) User() UserResolver { return &userResolver{r} }
+
+type costLineItemResolver struct{ *Resolver }
+type mutationResolver struct{ *Resolver }
+type queryResolver struct{ *Resolver }
+type subscriptionResolver struct{ *Resolver }
+type userResolver struct{ *Resolver }
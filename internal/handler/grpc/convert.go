@@ -0,0 +1,262 @@
+package grpc
+
+import (
+	"strings"
+	"time"
+
+	subscriptionv1 "github.com/vasiliy-maslov/go-subscription-service/api/subscription/v1"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/model"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/repository"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// parseID разбирает обязательный UUID из поля name запроса.
+func parseID(value, name string) (uuid.UUID, error) {
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return uuid.Nil, status.Error(codes.InvalidArgument, "invalid "+name+" format")
+	}
+	return id, nil
+}
+
+// parseOptionalID разбирает необязательный UUID; пустое значение означает отсутствие.
+func parseOptionalID(value, name string) (*uuid.UUID, error) {
+	if value == "" {
+		return nil, nil
+	}
+	id, err := parseID(value, name)
+	if err != nil {
+		return nil, err
+	}
+	return &id, nil
+}
+
+// optionalDate возвращает календарный день момента ts в часовом поясе вызова loc.
+func optionalDate(ts *timestamppb.Timestamp, loc *time.Location) *model.Date {
+	if ts == nil {
+		return nil
+	}
+	d := model.DateOf(ts.AsTime(), loc)
+	return &d
+}
+
+// dateTimestamp возвращает начало календарного дня d в часовом поясе вызова loc.
+func dateTimestamp(d model.Date, loc *time.Location) *timestamppb.Timestamp {
+	return timestamppb.New(d.In(loc))
+}
+
+func optionalDateTimestamp(d *model.Date, loc *time.Location) *timestamppb.Timestamp {
+	if d == nil {
+		return nil
+	}
+	return dateTimestamp(*d, loc)
+}
+
+func optionalString(id *uuid.UUID) string {
+	if id == nil {
+		return ""
+	}
+	return id.String()
+}
+
+// toModelSubscription переводит подписку из запроса в модель. Идентификатор, статус и отметки времени
+// игнорируются, как и в REST API. Категории и метки остаются nil при пустом списке.
+// Даты - календарные дни меток времени в часовом поясе вызова loc.
+func toModelSubscription(in *subscriptionv1.Subscription, loc *time.Location) (model.Subscription, error) {
+	if in == nil {
+		return model.Subscription{}, status.Error(codes.InvalidArgument, "subscription is required")
+	}
+
+	userID, err := parseID(in.GetUserId(), "user_id")
+	if err != nil {
+		return model.Subscription{}, err
+	}
+
+	sub := model.Subscription{
+		UserID:        userID,
+		ServiceName:   in.GetServiceName(),
+		Currency:      model.Currency(strings.ToUpper(in.GetCurrency())),
+		BillingPeriod: model.BillingPeriod(in.GetBillingPeriod()),
+		EndDate:       optionalDate(in.GetEndDate(), loc),
+		TrialEndDate:  optionalDate(in.GetTrialEndDate(), loc),
+		Tags:          in.GetTags(),
+	}
+	if in.GetStartDate() != nil {
+		sub.StartDate = model.DateOf(in.GetStartDate().AsTime(), loc)
+	}
+	if in.GetPrice() != "" {
+		if sub.Price, err = model.ParseMoney(in.GetPrice()); err != nil {
+			return model.Subscription{}, status.Error(codes.InvalidArgument, "invalid price: "+err.Error())
+		}
+	}
+	if sub.OrganizationID, err = parseOptionalID(in.GetOrganizationId(), "organization_id"); err != nil {
+		return model.Subscription{}, err
+	}
+	if sub.ServiceID, err = parseOptionalID(in.GetServiceId(), "service_id"); err != nil {
+		return model.Subscription{}, err
+	}
+	if sub.PlanID, err = parseOptionalID(in.GetPlanId(), "plan_id"); err != nil {
+		return model.Subscription{}, err
+	}
+	for _, value := range in.GetCategoryIds() {
+		categoryID, err := parseID(value, "category_ids")
+		if err != nil {
+			return model.Subscription{}, err
+		}
+		sub.CategoryIDs = append(sub.CategoryIDs, categoryID)
+	}
+
+	return sub, nil
+}
+
+// toProtoSubscription переводит модель подписки в сообщение ответа. Даты передаются
+// началом дня в часовом поясе вызова loc.
+func toProtoSubscription(sub model.Subscription, loc *time.Location) *subscriptionv1.Subscription {
+	out := &subscriptionv1.Subscription{
+		Id:             sub.ID.String(),
+		UserId:         sub.UserID.String(),
+		OrganizationId: optionalString(sub.OrganizationID),
+		ServiceName:    sub.ServiceName,
+		ServiceId:      optionalString(sub.ServiceID),
+		PlanId:         optionalString(sub.PlanID),
+		Price:          sub.Price.String(),
+		Currency:       string(sub.Currency),
+		BillingPeriod:  string(sub.BillingPeriod),
+		StartDate:      dateTimestamp(sub.StartDate, loc),
+		EndDate:        optionalDateTimestamp(sub.EndDate, loc),
+		Status:         string(sub.Status),
+		TrialEndDate:   optionalDateTimestamp(sub.TrialEndDate, loc),
+		Tags:           sub.Tags,
+		CreatedAt:      timestamppb.New(sub.CreatedAt),
+		UpdatedAt:      timestamppb.New(sub.UpdatedAt),
+	}
+	for _, categoryID := range sub.CategoryIDs {
+		out.CategoryIds = append(out.CategoryIds, categoryID.String())
+	}
+	return out
+}
+
+// toFilter переводит выборку подписок из запроса. user_id обязателен, если не задана организация.
+func toFilter(in *subscriptionv1.SubscriptionFilter) (repository.SubscriptionFilter, error) {
+	var (
+		filter repository.SubscriptionFilter
+		err    error
+	)
+
+	if filter.OrganizationID, err = parseOptionalID(in.GetOrganizationId(), "organization_id"); err != nil {
+		return repository.SubscriptionFilter{}, err
+	}
+	if in.GetUserId() == "" && filter.OrganizationID == nil {
+		return repository.SubscriptionFilter{}, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+	if in.GetUserId() != "" {
+		if filter.UserID, err = parseID(in.GetUserId(), "user_id"); err != nil {
+			return repository.SubscriptionFilter{}, err
+		}
+	}
+
+	if in.ServiceName != nil {
+		filter.ServiceName = in.ServiceName
+	}
+	if filter.ServiceID, err = parseOptionalID(in.GetServiceId(), "service_id"); err != nil {
+		return repository.SubscriptionFilter{}, err
+	}
+	if filter.CategoryID, err = parseOptionalID(in.GetCategoryId(), "category_id"); err != nil {
+		return repository.SubscriptionFilter{}, err
+	}
+	if in.Tag != nil {
+		filter.Tag = in.Tag
+	}
+	if in.Currency != nil {
+		currency := model.Currency(strings.ToUpper(in.GetCurrency()))
+		if !currency.Valid() {
+			return repository.SubscriptionFilter{}, status.Error(codes.InvalidArgument, "invalid currency, use an ISO 4217 code")
+		}
+		filter.Currency = &currency
+	}
+
+	return filter, nil
+}
+
+// toCostPeriod переводит период расчета стоимости. Границы периода - календарные дни меток времени
+// в часовом поясе вызова loc; при помесячном расчете сервис расширяет период до целых месяцев.
+func toCostPeriod(in *subscriptionv1.CostPeriod, loc *time.Location) (model.CostPeriod, error) {
+	if in.GetFrom() == nil || in.GetTo() == nil {
+		return model.CostPeriod{}, status.Error(codes.InvalidArgument, "period from and to are required")
+	}
+
+	period := model.CostPeriod{
+		Proration: model.Proration(in.GetProration()),
+		From:      model.DateOf(in.GetFrom().AsTime(), loc),
+		To:        model.DateOf(in.GetTo().AsTime(), loc),
+	}
+	if period.Proration == "" {
+		period.Proration = model.ProrationMonthly
+	}
+	if !period.Proration.Valid() {
+		return model.CostPeriod{}, status.Error(codes.InvalidArgument, "proration must be monthly or daily")
+	}
+
+	return period, nil
+}
+
+// toProtoCostSummary переводит результат расчета стоимости в сообщение ответа.
+// Месяцы передаются началом первого дня в часовом поясе вызова loc.
+func toProtoCostSummary(summary model.CostSummary, loc *time.Location) *subscriptionv1.CostSummary {
+	out := &subscriptionv1.CostSummary{
+		TotalCost: summary.TotalCost.String(),
+		Currency:  string(summary.Currency),
+	}
+	for _, group := range summary.Breakdown {
+		out.Breakdown = append(out.Breakdown, &subscriptionv1.CostGroup{
+			Key:       group.Key,
+			Name:      group.Name,
+			TotalCost: group.TotalCost.String(),
+		})
+	}
+	for _, item := range summary.LineItems {
+		line := &subscriptionv1.CostLineItem{
+			Month:          dateTimestamp(item.Month, loc),
+			SubscriptionId: item.SubscriptionID.String(),
+			ServiceName:    item.ServiceName,
+			BasePrice:      item.BasePrice.String(),
+			Price:          item.Price.String(),
+			Days:           int32(item.Days),
+			PeriodDays:     int32(item.PeriodDays),
+			Cost:           item.Cost.String(),
+		}
+		if item.Discount != nil {
+			line.DiscountId = item.Discount.ID.String()
+		}
+		out.LineItems = append(out.LineItems, line)
+	}
+	return out
+}
+
+// toProtoForecast переводит прогноз расходов в сообщение ответа.
+// Месяцы передаются началом первого дня в часовом поясе вызова loc.
+func toProtoForecast(forecast model.Forecast, loc *time.Location) *subscriptionv1.SpendingForecast {
+	out := &subscriptionv1.SpendingForecast{
+		TotalCost: forecast.TotalCost.String(),
+		Currency:  string(forecast.Currency),
+	}
+	for _, month := range forecast.Months {
+		m := &subscriptionv1.ForecastMonth{
+			Month:     dateTimestamp(month.Month, loc),
+			TotalCost: month.TotalCost.String(),
+		}
+		for _, renewal := range month.Renewals {
+			m.Renewals = append(m.Renewals, &subscriptionv1.ForecastRenewal{
+				SubscriptionId: renewal.SubscriptionID.String(),
+				ServiceName:    renewal.ServiceName,
+				Cost:           renewal.Cost.String(),
+			})
+		}
+		out.Months = append(out.Months, m)
+	}
+	return out
+}
@@ -0,0 +1,84 @@
+package grpc
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/vasiliy-maslov/go-subscription-service/internal/repository"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/service"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errorDomain - домен причин отказа в деталях ошибки ErrorInfo.
+const errorDomain = "subscription-service"
+
+// toStatus переводит ошибку сервисного слоя в статус gRPC по тем же правилам, что и коды ответа REST API.
+// Отказ политики доступа дополняется деталью ErrorInfo с недостающим разрешением.
+func toStatus(err error) error {
+	var permErr *service.PermissionError
+	if errors.As(err, &permErr) {
+		st := status.New(codes.PermissionDenied, permErr.Error())
+		if detailed, detailErr := st.WithDetails(&errdetails.ErrorInfo{
+			Reason:   "PERMISSION_DENIED",
+			Domain:   errorDomain,
+			Metadata: map[string]string{"permission": string(permErr.Permission)},
+		}); detailErr == nil {
+			st = detailed
+		}
+		return st.Err()
+	}
+
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		return status.Error(codes.NotFound, "subscription not found")
+	case errors.Is(err, service.ErrValidation), errors.Is(err, repository.ErrUserNotFound):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, service.ErrDuplicateSubscription):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, repository.ErrTenantMismatch), errors.Is(err, service.ErrForbidden),
+		errors.Is(err, service.ErrOutOfScope):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, service.ErrInvalidTransition):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, service.ErrInvalidAPIKey):
+		return status.Error(codes.Unauthenticated, "invalid API key")
+	case errors.Is(err, service.ErrInvalidToken):
+		return status.Error(codes.Unauthenticated, "invalid token")
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
+
+// httpStatus возвращает код ответа HTTP, соответствующий коду gRPC, чтобы записи журнала аудита
+// от обоих API были сопоставимы.
+func httpStatus(code codes.Code) int {
+	switch code {
+	case codes.OK:
+		return http.StatusOK
+	case codes.InvalidArgument, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted, codes.FailedPrecondition:
+		return http.StatusConflict
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Canceled:
+		return 499
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,73 @@
+package grpc
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/vasiliy-maslov/go-subscription-service/internal/model"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/repository"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/service"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestToStatus(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantCode   codes.Code
+		wantStatus int
+	}{
+		{name: "not found", err: repository.ErrNotFound, wantCode: codes.NotFound, wantStatus: http.StatusNotFound},
+		{name: "validation", err: fmt.Errorf("%w: price must be positive", service.ErrValidation), wantCode: codes.InvalidArgument, wantStatus: http.StatusBadRequest},
+		{name: "unknown user", err: repository.ErrUserNotFound, wantCode: codes.InvalidArgument, wantStatus: http.StatusBadRequest},
+		{name: "duplicate", err: service.ErrDuplicateSubscription, wantCode: codes.AlreadyExists, wantStatus: http.StatusConflict},
+		{name: "tenant mismatch", err: repository.ErrTenantMismatch, wantCode: codes.PermissionDenied, wantStatus: http.StatusForbidden},
+		{name: "forbidden", err: service.ErrForbidden, wantCode: codes.PermissionDenied, wantStatus: http.StatusForbidden},
+		{name: "out of the API key scope", err: service.ErrOutOfScope, wantCode: codes.PermissionDenied, wantStatus: http.StatusForbidden},
+		{name: "invalid transition", err: service.ErrInvalidTransition, wantCode: codes.FailedPrecondition, wantStatus: http.StatusConflict},
+		{name: "invalid API key", err: service.ErrInvalidAPIKey, wantCode: codes.Unauthenticated, wantStatus: http.StatusUnauthorized},
+		{name: "invalid token", err: service.ErrInvalidToken, wantCode: codes.Unauthenticated, wantStatus: http.StatusUnauthorized},
+		{name: "unexpected", err: errors.New("connection reset"), wantCode: codes.Internal, wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := status.Convert(toStatus(fmt.Errorf("op: %w", tt.err)))
+			if st.Code() != tt.wantCode {
+				t.Errorf("toStatus() code = %s, want %s", st.Code(), tt.wantCode)
+			}
+			if got := httpStatus(st.Code()); got != tt.wantStatus {
+				t.Errorf("httpStatus(%s) = %d, want %d", st.Code(), got, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestToStatusPermission(t *testing.T) {
+	err := fmt.Errorf("op: %w", &service.PermissionError{Permission: model.PermissionSubscriptionsRead})
+
+	st := status.Convert(toStatus(err))
+	if st.Code() != codes.PermissionDenied {
+		t.Fatalf("toStatus() code = %s, want %s", st.Code(), codes.PermissionDenied)
+	}
+
+	details := st.Details()
+	if len(details) != 1 {
+		t.Fatalf("toStatus() details = %v, want one ErrorInfo", details)
+	}
+	info, ok := details[0].(*errdetails.ErrorInfo)
+	if !ok {
+		t.Fatalf("toStatus() detail = %T, want *errdetails.ErrorInfo", details[0])
+	}
+	if info.Reason != "PERMISSION_DENIED" || info.Domain != errorDomain {
+		t.Errorf("ErrorInfo = %s/%s, want PERMISSION_DENIED/%s", info.Reason, info.Domain, errorDomain)
+	}
+	if got := info.Metadata["permission"]; got != string(model.PermissionSubscriptionsRead) {
+		t.Errorf("ErrorInfo permission = %q, want %q", got, model.PermissionSubscriptionsRead)
+	}
+}
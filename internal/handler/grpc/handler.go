@@ -0,0 +1,80 @@
+package grpc
+
+import (
+	"log/slog"
+
+	subscriptionv1 "github.com/vasiliy-maslov/go-subscription-service/api/subscription/v1"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/ratelimit"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/service"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+// tracerName - имя инструментирования для серверных спанов gRPC.
+const tracerName = "github.com/vasiliy-maslov/go-subscription-service/internal/handler/grpc"
+
+// Handler реализует gRPC API сервиса подписок поверх того же сервисного слоя, что и REST API.
+type Handler struct {
+	subscriptionv1.UnimplementedSubscriptionServiceServer
+
+	service service.SubscriptionService
+	apiKeys service.APIKeyService
+	tokens  service.TokenService
+	audit   service.AuditService
+	// limiter ограничивает частоту вызовов; nil отключает ограничение.
+	limiter *ratelimit.Limiter
+	tracer  trace.Tracer
+	logger  *slog.Logger
+}
+
+// NewHandler создает новый экземпляр gRPC-обработчика.
+func NewHandler(
+	s service.SubscriptionService,
+	apiKeys service.APIKeyService,
+	tokens service.TokenService,
+	audit service.AuditService,
+	limiter *ratelimit.Limiter,
+	logger *slog.Logger,
+) *Handler {
+	return &Handler{
+		service: s,
+		apiKeys: apiKeys,
+		tokens:  tokens,
+		audit:   audit,
+		limiter: limiter,
+		tracer:  otel.Tracer(tracerName),
+		logger:  logger,
+	}
+}
+
+// InitServer создает gRPC-сервер с сервисом подписок, проверкой здоровья (grpc.health.v1)
+// и reflection. Перехватчики повторяют middleware REST API: трассировка, идентификатор запроса,
+// журнал доступа, перехват паник, часовой пояс, аудит, аутентификация и ограничение частоты.
+func (h *Handler) InitServer() *grpc.Server {
+	server := grpc.NewServer(grpc.ChainUnaryInterceptor(
+		h.trace,
+		h.requestID,
+		h.accessLog,
+		h.recovery,
+		timeZone,
+		h.recordAudit,
+		h.rateLimitIP,
+		h.authenticate,
+		h.rateLimit,
+	))
+
+	subscriptionv1.RegisterSubscriptionServiceServer(server, h)
+
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus(subscriptionv1.SubscriptionService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(server, healthServer)
+
+	reflection.Register(server)
+
+	return server
+}
@@ -0,0 +1,396 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"time"
+
+	subscriptionv1 "github.com/vasiliy-maslov/go-subscription-service/api/subscription/v1"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/logging"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/model"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/ratelimit"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/repository"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/service"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.39.0"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// Ключи метаданных повторяют заголовки REST API.
+const (
+	requestIDKey     = "x-request-id"
+	timeZoneKey      = "x-time-zone"
+	authorizationKey = "authorization"
+)
+
+// mutatingMethods - изменяющие вызовы, которые записываются в журнал аудита.
+var mutatingMethods = map[string]bool{
+	subscriptionv1.SubscriptionService_CreateSubscription_FullMethodName: true,
+	subscriptionv1.SubscriptionService_UpdateSubscription_FullMethodName: true,
+	subscriptionv1.SubscriptionService_DeleteSubscription_FullMethodName: true,
+}
+
+// metadataValue возвращает первое значение ключа метаданных вызова.
+func metadataValue(ctx context.Context, key string) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// clientIP возвращает IP-адрес клиента без порта.
+func clientIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
+
+// serverError сообщает, что код означает ошибку сервера, а не клиента.
+func serverError(code codes.Code) bool {
+	switch code {
+	case codes.Unknown, codes.DeadlineExceeded, codes.Unimplemented, codes.Internal, codes.Unavailable, codes.DataLoss:
+		return true
+	default:
+		return false
+	}
+}
+
+// withLogAttrs добавляет атрибуты к логгеру вызова и к его спану.
+func withLogAttrs(ctx context.Context, attrs ...attribute.KeyValue) context.Context {
+	trace.SpanFromContext(ctx).SetAttributes(attrs...)
+
+	args := make([]any, len(attrs))
+	for i, attr := range attrs {
+		args[i] = slog.String(string(attr.Key), attr.Value.Emit())
+	}
+	return logging.WithLogger(ctx, logging.FromContext(ctx, slog.Default()).With(args...))
+}
+
+// metadataCarrier переносит контекст трассировки через метаданные gRPC.
+type metadataCarrier metadata.MD
+
+func (c metadataCarrier) Get(key string) string {
+	if values := metadata.MD(c).Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+func (c metadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// trace открывает серверный спан OpenTelemetry на каждый вызов. Родительский контекст
+// трассировки берется из метаданных traceparent и baggage (W3C Trace Context).
+func (h *Handler) trace(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	ctx = otel.GetTextMapPropagator().Extract(ctx, metadataCarrier(md))
+
+	serviceName, method, _ := strings.Cut(strings.TrimPrefix(info.FullMethod, "/"), "/")
+	ctx, span := h.tracer.Start(ctx, info.FullMethod,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(
+			attribute.String("rpc.system", "grpc"),
+			attribute.String("rpc.service", serviceName),
+			attribute.String("rpc.method", method),
+			semconv.ClientAddress(clientIP(ctx)),
+		),
+	)
+	defer span.End()
+
+	resp, err := handler(ctx, req)
+
+	code := status.Code(err)
+	span.SetAttributes(attribute.Int("rpc.grpc.status_code", int(code)))
+	if serverError(code) {
+		span.SetStatus(otelcodes.Error, code.String())
+	}
+	return resp, err
+}
+
+// requestID присваивает вызову идентификатор из метаданных x-request-id или новый UUID,
+// возвращает его в заголовке ответа и кладет в контекст логгер с идентификатором и методом.
+func (h *Handler) requestID(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	id := metadataValue(ctx, requestIDKey)
+	if !logging.ValidRequestID(id) {
+		id = uuid.NewString()
+	}
+	_ = grpc.SetHeader(ctx, metadata.Pairs(requestIDKey, id))
+
+	log := h.logger.With(slog.String("request_id", id), slog.String("method", info.FullMethod))
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		log = log.With(slog.String("trace_id", sc.TraceID().String()))
+	}
+	ctx = logging.WithLogger(logging.WithRequestID(ctx, id), log)
+
+	return handler(ctx, req)
+}
+
+// accessLog пишет одну запись на каждый вызов: метод, код ответа, длительность и IP-адрес.
+// Ошибки сервера пишутся на уровне Error.
+func (h *Handler) accessLog(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	start := time.Now()
+
+	resp, err := handler(ctx, req)
+
+	code := status.Code(err)
+	attrs := []any{
+		slog.String("code", code.String()),
+		slog.Duration("latency", time.Since(start)),
+		slog.String("client_ip", clientIP(ctx)),
+	}
+
+	log := logging.FromContext(ctx, h.logger)
+	if serverError(code) {
+		log.Error("gRPC-вызов", append(attrs, slog.String("error", err.Error()))...)
+		return resp, err
+	}
+	log.Info("gRPC-вызов", attrs...)
+	return resp, err
+}
+
+// recovery перехватывает панику обработчика, пишет ее в лог со стеком вызовов
+// и возвращает клиенту codes.Internal.
+func (h *Handler) recovery(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+	const op = "grpc.recovery"
+
+	defer func() {
+		rec := recover()
+		if rec == nil {
+			return
+		}
+
+		logging.FromContext(ctx, h.logger).Error("Паника при обработке вызова",
+			slog.String("op", op),
+			slog.Any("panic", rec),
+			slog.String("stack", string(debug.Stack())),
+		)
+		err = status.Error(codes.Internal, "internal server error, request ID "+logging.RequestID(ctx))
+	}()
+
+	return handler(ctx, req)
+}
+
+// timeZone переносит часовой пояс пользователя из метаданных x-time-zone в контекст вызова.
+// Без метаданных даты толкуются в UTC.
+func timeZone(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	name := metadataValue(ctx, timeZoneKey)
+	if name == "" {
+		return handler(ctx, req)
+	}
+
+	// "Local" означает пояс сервера, а не пользователя, поэтому не принимается.
+	loc, err := time.LoadLocation(name)
+	if err != nil || name == "Local" {
+		return nil, status.Error(codes.InvalidArgument, "invalid "+timeZoneKey+" metadata, use an IANA time zone name")
+	}
+
+	return handler(service.WithTimeZone(ctx, loc), req)
+}
+
+type actorKey struct{}
+
+// auditActor - исполнитель вызова. Его заполняют authenticate и tenant, а читает recordAudit,
+// который стоит раньше них в цепочке и не видит их контекст.
+type auditActor struct {
+	actorType model.AuditActorType
+	id        *uuid.UUID
+}
+
+func setActor(ctx context.Context, actorType model.AuditActorType, id uuid.UUID) {
+	if actor, ok := ctx.Value(actorKey{}).(*auditActor); ok {
+		actor.actorType, actor.id = actorType, &id
+	}
+}
+
+// recordAudit записывает в журнал аудита каждый изменяющий вызов, в том числе отклоненный
+// аутентификацией или политикой доступа. Ошибка записи не меняет ответ, а пишется в лог.
+func (h *Handler) recordAudit(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	const op = "grpc.recordAudit"
+
+	if h.audit == nil || !mutatingMethods[info.FullMethod] {
+		return handler(ctx, req)
+	}
+
+	actor := &auditActor{actorType: model.AuditActorAnonymous}
+	resp, err := handler(context.WithValue(ctx, actorKey{}, actor), req)
+
+	code := status.Code(err)
+	entry := model.AuditEntry{
+		ActorType:    actor.actorType,
+		ActorID:      actor.id,
+		Action:       info.FullMethod,
+		ResourceType: "subscriptions",
+		RequestID:    logging.RequestID(ctx),
+		Outcome:      auditOutcome(code),
+		Status:       httpStatus(code),
+		SourceIP:     clientIP(ctx),
+	}
+	if r, ok := req.(interface{ GetId() string }); ok {
+		entry.ResourceID = r.GetId()
+	}
+	if created, ok := resp.(*subscriptionv1.CreateSubscriptionResponse); ok {
+		entry.ResourceID = created.GetId()
+	}
+
+	// Клиент мог отменить вызов, но запись о выполненном действии нужна все равно.
+	if _, auditErr := h.audit.Record(context.WithoutCancel(ctx), entry); auditErr != nil {
+		logging.FromContext(ctx, h.logger).Error("Не удалось записать вызов в журнал аудита",
+			slog.String("op", op), slog.String("error", auditErr.Error()))
+	}
+
+	return resp, err
+}
+
+// auditOutcome определяет результат действия по коду ответа.
+func auditOutcome(code codes.Code) model.AuditOutcome {
+	switch code {
+	case codes.OK:
+		return model.AuditOutcomeSuccess
+	case codes.Unauthenticated, codes.PermissionDenied:
+		return model.AuditOutcomeDenied
+	default:
+		return model.AuditOutcomeFailure
+	}
+}
+
+// authenticate определяет, от чьего имени выполняется вызов, по метаданным authorization так же,
+// как REST API: API-ключ (sk_...) действует с правами ключа, токен пользователя привязывает вызов
+// к пользователю из токена. Вызов сервиса подписок без метаданных отклоняется с кодом Unauthenticated;
+// проверка здоровья и reflection доступны без аутентификации.
+func (h *Handler) authenticate(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	const op = "grpc.authenticate"
+
+	if !strings.HasPrefix(info.FullMethod, "/"+subscriptionv1.SubscriptionService_ServiceDesc.ServiceName+"/") {
+		return handler(ctx, req)
+	}
+
+	raw := strings.TrimSpace(metadataValue(ctx, authorizationKey))
+	if scheme, token, ok := strings.Cut(raw, " "); ok && strings.EqualFold(scheme, "Bearer") {
+		raw = strings.TrimSpace(token)
+	}
+	if raw == "" {
+		return nil, status.Error(codes.Unauthenticated, "authentication required: send an API key or a user token in the "+authorizationKey+" metadata")
+	}
+
+	if !service.IsAPIKey(raw) {
+		userID, err := h.tokens.Authenticate(ctx, raw)
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, "invalid token")
+		}
+
+		setActor(ctx, model.AuditActorUser, userID)
+		ctx = withLogAttrs(repository.WithTenant(ctx, userID), attribute.String("user_id", userID.String()))
+		return handler(ctx, req)
+	}
+
+	key, err := h.apiKeys.Authenticate(ctx, raw)
+	if err != nil {
+		if status.Code(toStatus(err)) == codes.Unauthenticated {
+			return nil, status.Error(codes.Unauthenticated, "invalid API key")
+		}
+		logging.FromContext(ctx, h.logger).Error("Не удалось проверить API-ключ", slog.String("op", op), slog.String("error", err.Error()))
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	setActor(ctx, model.AuditActorAPIKey, key.ID)
+	ctx = withLogAttrs(service.WithAPIKey(ctx, key), attribute.String("api_key_id", key.ID.String()))
+	return handler(ctx, req)
+}
+
+// rateLimitIP ограничивает частоту вызовов с IP-адреса клиента до аутентификации той же корзиной,
+// что и REST API, поэтому перебор ключей и токенов ограничен. Проверка здоровья не ограничивается.
+func (h *Handler) rateLimitIP(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	const op = "grpc.rateLimitIP"
+
+	if h.limiter == nil || !strings.HasPrefix(info.FullMethod, "/"+subscriptionv1.SubscriptionService_ServiceDesc.ServiceName+"/") {
+		return handler(ctx, req)
+	}
+
+	decision, err := h.limiter.AllowIP(ctx, clientIP(ctx))
+	if err == nil && decision.Allowed {
+		// Метаданные повторные вызовы grpc.SetHeader не заменяют, а дополняют, поэтому
+		// для разрешенного вызова их передает только rateLimit.
+		return handler(ctx, req)
+	}
+	return h.limit(ctx, req, handler, op, "ip:"+clientIP(ctx), decision, err)
+}
+
+// rateLimit ограничивает частоту вызовов аутентифицированного клиента теми же корзинами, что и REST API:
+// по API-ключу или пользователю из токена. Ограничение маршрута задается в конфиге методом GRPC
+// и полным именем метода. Проверка здоровья не ограничивается.
+func (h *Handler) rateLimit(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	const op = "grpc.rateLimit"
+
+	if h.limiter == nil || !strings.HasPrefix(info.FullMethod, "/"+subscriptionv1.SubscriptionService_ServiceDesc.ServiceName+"/") {
+		return handler(ctx, req)
+	}
+
+	var client string
+	if key, ok := service.APIKeyFrom(ctx); ok {
+		client = "key:" + key.ID.String()
+	} else if userID, ok := repository.TenantFrom(ctx); ok {
+		client = "user:" + userID.String()
+	} else {
+		return handler(ctx, req)
+	}
+
+	decision, err := h.limiter.Allow(ctx, "GRPC", info.FullMethod, client)
+	return h.limit(ctx, req, handler, op, client, decision, err)
+}
+
+// limit применяет решение ограничителя к вызову клиента client: передает метаданные ratelimit-*,
+// а при превышении возвращает ResourceExhausted с retry-after. Ошибка хранилища счетчиков не блокирует вызов.
+func (h *Handler) limit(ctx context.Context, req any, handler grpc.UnaryHandler, op, client string, decision ratelimit.Decision, err error) (any, error) {
+	if err != nil {
+		logging.FromContext(ctx, h.logger).Error("Не удалось проверить ограничение запросов", slog.String("op", op), slog.String("error", err.Error()))
+		return handler(ctx, req)
+	}
+
+	header := metadata.Pairs(
+		"ratelimit-policy", decision.Rule.Policy(),
+		"ratelimit-limit", strconv.Itoa(decision.Rule.Burst),
+		"ratelimit-remaining", strconv.Itoa(decision.Remaining),
+		"ratelimit-reset", strconv.Itoa(int(decision.Reset.Seconds())),
+	)
+
+	if !decision.Allowed {
+		logging.FromContext(ctx, h.logger).Warn("Превышено ограничение запросов", slog.String("op", op), slog.String("client", client))
+		header.Set("retry-after", strconv.Itoa(int(decision.RetryAfter.Seconds())))
+		_ = grpc.SetHeader(ctx, header)
+		return nil, status.Error(codes.ResourceExhausted, fmt.Sprintf("rate limit of %d requests per %s exceeded, retry in %d s",
+			decision.Rule.Requests, decision.Rule.Period, int(decision.RetryAfter.Seconds())))
+	}
+
+	_ = grpc.SetHeader(ctx, header)
+	return handler(ctx, req)
+}
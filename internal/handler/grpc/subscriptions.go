@@ -0,0 +1,216 @@
+package grpc
+
+import (
+	"context"
+	"log/slog"
+
+	subscriptionv1 "github.com/vasiliy-maslov/go-subscription-service/api/subscription/v1"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/logging"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/model"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/service"
+
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// defaultForecastMonths - горизонт прогноза, если в запросе он не задан.
+const defaultForecastMonths = 12
+
+// CreateSubscription создает подписку.
+func (h *Handler) CreateSubscription(ctx context.Context, req *subscriptionv1.CreateSubscriptionRequest) (*subscriptionv1.CreateSubscriptionResponse, error) {
+	const op = "grpc.CreateSubscription"
+	log := logging.FromContext(ctx, h.logger).With(slog.String("op", op))
+
+	input, err := toModelSubscription(req.GetSubscription(), service.TimeZoneFrom(ctx))
+	if err != nil {
+		log.Warn("Некорректный запрос", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	log.Info("Запрос на создание подписки", slog.Any("input", input))
+
+	createdID, err := h.service.Create(ctx, input)
+	if err != nil {
+		log.Error("Сервис вернул ошибку при создании", slog.String("error", err.Error()))
+		return nil, toStatus(err)
+	}
+
+	return &subscriptionv1.CreateSubscriptionResponse{Id: createdID.String()}, nil
+}
+
+// GetSubscription возвращает подписку по идентификатору.
+func (h *Handler) GetSubscription(ctx context.Context, req *subscriptionv1.GetSubscriptionRequest) (*subscriptionv1.Subscription, error) {
+	const op = "grpc.GetSubscription"
+	log := logging.FromContext(ctx, h.logger).With(slog.String("op", op), slog.String("id", req.GetId()))
+
+	id, err := parseID(req.GetId(), "subscription ID")
+	if err != nil {
+		log.Warn("Некорректный формат UUID", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	log.Info("Запрос на получение подписки")
+
+	sub, err := h.service.GetByID(ctx, id)
+	if err != nil {
+		log.Error("Сервис вернул ошибку при получении", slog.String("error", err.Error()))
+		return nil, toStatus(err)
+	}
+
+	return toProtoSubscription(sub, service.TimeZoneFrom(ctx)), nil
+}
+
+// UpdateSubscription обновляет подписку. Категории и метки заменяются только при флагах
+// replace_category_ids и replace_tags, как при их отсутствии в теле REST-запроса.
+func (h *Handler) UpdateSubscription(ctx context.Context, req *subscriptionv1.UpdateSubscriptionRequest) (*emptypb.Empty, error) {
+	const op = "grpc.UpdateSubscription"
+	log := logging.FromContext(ctx, h.logger).With(slog.String("op", op), slog.String("id", req.GetId()))
+
+	id, err := parseID(req.GetId(), "subscription ID")
+	if err != nil {
+		log.Warn("Некорректный формат UUID", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	input, err := toModelSubscription(req.GetSubscription(), service.TimeZoneFrom(ctx))
+	if err != nil {
+		log.Warn("Некорректный запрос", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	switch {
+	case !req.GetReplaceCategoryIds():
+		input.CategoryIDs = nil
+	case input.CategoryIDs == nil:
+		input.CategoryIDs = []uuid.UUID{}
+	}
+	switch {
+	case !req.GetReplaceTags():
+		input.Tags = nil
+	case input.Tags == nil:
+		input.Tags = []string{}
+	}
+
+	log.Info("Запрос на обновление подписки", slog.Any("input", input))
+
+	if err := h.service.Update(ctx, id, input); err != nil {
+		log.Error("Сервис вернул ошибку при обновлении", slog.String("error", err.Error()))
+		return nil, toStatus(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// DeleteSubscription удаляет подписку.
+func (h *Handler) DeleteSubscription(ctx context.Context, req *subscriptionv1.DeleteSubscriptionRequest) (*emptypb.Empty, error) {
+	const op = "grpc.DeleteSubscription"
+	log := logging.FromContext(ctx, h.logger).With(slog.String("op", op), slog.String("id", req.GetId()))
+
+	id, err := parseID(req.GetId(), "subscription ID")
+	if err != nil {
+		log.Warn("Некорректный формат UUID", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	log.Info("Запрос на удаление подписки")
+
+	if err := h.service.Delete(ctx, id); err != nil {
+		log.Error("Сервис вернул ошибку при удалении", slog.String("error", err.Error()))
+		return nil, toStatus(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// ListSubscriptions возвращает подписки пользователя или организации.
+func (h *Handler) ListSubscriptions(ctx context.Context, req *subscriptionv1.ListSubscriptionsRequest) (*subscriptionv1.ListSubscriptionsResponse, error) {
+	const op = "grpc.ListSubscriptions"
+	log := logging.FromContext(ctx, h.logger).With(slog.String("op", op))
+
+	filter, err := toFilter(req.GetFilter())
+	if err != nil {
+		log.Warn("Некорректная выборка", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	log.Info("Запрос на получение списка подписок", slog.String("user_id", filter.UserID.String()))
+
+	subscriptions, err := h.service.List(ctx, filter)
+	if err != nil {
+		log.Error("Сервис вернул ошибку при получении списка", slog.String("error", err.Error()))
+		return nil, toStatus(err)
+	}
+
+	resp := &subscriptionv1.ListSubscriptionsResponse{Subscriptions: make([]*subscriptionv1.Subscription, 0, len(subscriptions))}
+	for _, sub := range subscriptions {
+		resp.Subscriptions = append(resp.Subscriptions, toProtoSubscription(sub, service.TimeZoneFrom(ctx)))
+	}
+	return resp, nil
+}
+
+// CalculateTotalCost рассчитывает стоимость подписок за период: итог, разбивку по группе
+// group_by или построчную детализацию.
+func (h *Handler) CalculateTotalCost(ctx context.Context, req *subscriptionv1.CalculateTotalCostRequest) (*subscriptionv1.CostSummary, error) {
+	const op = "grpc.CalculateTotalCost"
+	log := logging.FromContext(ctx, h.logger).With(slog.String("op", op))
+
+	filter, err := toFilter(req.GetFilter())
+	if err != nil {
+		log.Warn("Некорректная выборка", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	period, err := toCostPeriod(req.GetPeriod(), service.TimeZoneFrom(ctx))
+	if err != nil {
+		log.Warn("Некорректный период", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	log.Info("Запрос на расчет стоимости",
+		slog.String("user_id", filter.UserID.String()),
+		slog.String("from", period.From.String()),
+		slog.String("to", period.To.String()),
+		slog.String("proration", string(period.Proration)),
+	)
+
+	var summary model.CostSummary
+	switch {
+	case req.GetGroupBy() != "":
+		summary, err = h.service.CalculateCostBreakdown(ctx, filter, req.GetGroupBy(), period)
+	case req.GetLineItems():
+		summary, err = h.service.CalculateCostLineItems(ctx, filter, period)
+	default:
+		summary, err = h.service.CalculateTotalCost(ctx, filter, period)
+	}
+	if err != nil {
+		log.Error("Сервис вернул ошибку при расчете стоимости", slog.String("error", err.Error()))
+		return nil, toStatus(err)
+	}
+
+	return toProtoCostSummary(summary, service.TimeZoneFrom(ctx)), nil
+}
+
+// Forecast строит прогноз расходов по месяцам.
+func (h *Handler) Forecast(ctx context.Context, req *subscriptionv1.ForecastRequest) (*subscriptionv1.SpendingForecast, error) {
+	const op = "grpc.Forecast"
+	log := logging.FromContext(ctx, h.logger).With(slog.String("op", op))
+
+	filter, err := toFilter(req.GetFilter())
+	if err != nil {
+		log.Warn("Некорректная выборка", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	months := int(req.GetMonths())
+	if months == 0 {
+		months = defaultForecastMonths
+	}
+
+	forecast, err := h.service.Forecast(ctx, filter, months)
+	if err != nil {
+		log.Error("Сервис вернул ошибку при построении прогноза", slog.String("error", err.Error()))
+		return nil, toStatus(err)
+	}
+
+	return toProtoForecast(forecast, service.TimeZoneFrom(ctx)), nil
+}
@@ -0,0 +1,138 @@
+package http
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/vasiliy-maslov/go-subscription-service/internal/model"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/repository"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type CreateAPIKeyRequest struct {
+	Name        string             `json:"name"                 binding:"required"`
+	Permissions []model.Permission `json:"permissions"          binding:"required"`
+	// UserIDs ограничивает ключ подписками перечисленных пользователей.
+	UserIDs   []uuid.UUID `json:"user_ids,omitempty"`
+	ExpiresAt *time.Time  `json:"expires_at,omitempty"`
+}
+
+// ListAPIKeys godoc
+// @Summary List API keys
+// @Description Returns all API keys without their secrets. Requires the api_keys:manage permission.
+// @Tags api_keys
+// @Produce  json
+// @Security ApiKeyAuth
+// @Success 200 {array} model.APIKey
+// @Failure 401 {object} ErrorResponse "Missing or invalid API key or token"
+// @Failure 403 {object} ProblemResponse "Missing permission"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /api_keys [get]
+func (h *Handler) ListAPIKeys(c *gin.Context) {
+	const op = "handler.ListAPIKeys"
+	log := h.requestLogger(c).With(slog.String("op", op))
+
+	keys, err := h.apiKeys.List(c.Request.Context())
+	if err != nil {
+		log.Error("Сервис вернул ошибку при получении API-ключей", slog.String("error", err.Error()))
+		apiKeyError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, keys)
+}
+
+// CreateAPIKey godoc
+// @Summary Create an API key
+// @Description Issues an API key for a machine client. The key value is returned only in this response; send it as "Authorization: Bearer <key>". The key grants exactly the listed permissions, not roles; with user_ids it only sees subscriptions of those users. Requires the api_keys:manage permission. The key cannot exceed its issuer: every listed permission must be held by the issuer, a key issued by a scoped key must list a subset of its user_ids, and a user token issues keys only on its own user unless it has the users:manage permission.
+// @Tags api_keys
+// @Accept  json
+// @Produce  json
+// @Security ApiKeyAuth
+// @Param   api_key body CreateAPIKeyRequest true "API key data"
+// @Success 201 {object} model.IssuedAPIKey
+// @Failure 400 {object} ErrorResponse "Invalid request body, unknown permission or user, or expiry in the past"
+// @Failure 401 {object} ErrorResponse "Missing or invalid API key or token"
+// @Failure 403 {object} ProblemResponse "Missing permission, a permission the issuer lacks, or user_ids outside the issuer scope"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /api_keys [post]
+func (h *Handler) CreateAPIKey(c *gin.Context) {
+	const op = "handler.CreateAPIKey"
+	log := h.requestLogger(c).With(slog.String("op", op))
+
+	var input CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		log.Warn("Не удалось прочитать тело запроса", slog.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	issued, err := h.apiKeys.Create(c.Request.Context(), model.APIKey{
+		Name:        input.Name,
+		Permissions: input.Permissions,
+		UserIDs:     input.UserIDs,
+		ExpiresAt:   input.ExpiresAt,
+	})
+	if err != nil {
+		log.Error("Сервис вернул ошибку при выпуске API-ключа", slog.String("error", err.Error()))
+		apiKeyError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, issued)
+}
+
+// RevokeAPIKey godoc
+// @Summary Revoke an API key
+// @Description Revokes the key immediately; revoking an already revoked key is a no-op. Requires the api_keys:manage permission.
+// @Tags api_keys
+// @Produce  json
+// @Security ApiKeyAuth
+// @Param   id path string true "API key UUID" Format(uuid)
+// @Success 200 {object} model.APIKey
+// @Failure 400 {object} ErrorResponse "Invalid UUID format"
+// @Failure 401 {object} ErrorResponse "Missing or invalid API key or token"
+// @Failure 403 {object} ProblemResponse "Missing permission"
+// @Failure 404 {object} ErrorResponse "API key not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /api_keys/{id} [delete]
+func (h *Handler) RevokeAPIKey(c *gin.Context) {
+	const op = "handler.RevokeAPIKey"
+	log := h.requestLogger(c).With(slog.String("op", op), slog.String("id", c.Param("id")))
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid API key ID"})
+		return
+	}
+
+	key, err := h.apiKeys.Revoke(c.Request.Context(), id)
+	if err != nil {
+		log.Error("Сервис вернул ошибку при отзыве API-ключа", slog.String("error", err.Error()))
+		apiKeyError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, key)
+}
+
+// apiKeyError отправляет ответ, соответствующий ошибке сервиса API-ключей.
+func apiKeyError(c *gin.Context, err error) {
+	if permissionDenied(c, err) {
+		return
+	}
+
+	switch {
+	case errors.Is(err, repository.ErrAPIKeyNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "api key not found"})
+	case errors.Is(err, service.ErrValidation):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
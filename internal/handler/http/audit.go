@@ -0,0 +1,293 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vasiliy-maslov/go-subscription-service/internal/logging"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/model"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/repository"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// maxAuditBody - сколько байт ответа на создание ресурса читается, чтобы найти ID ресурса.
+const maxAuditBody = 4096
+
+// erasedUserKey - ключ контекста gin с ID пользователя, данные которого удалил запрос.
+const erasedUserKey = "audit.erased_user"
+
+// auditWriter запоминает начало тела ответа, не мешая его отправке.
+type auditWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *auditWriter) Write(b []byte) (int, error) {
+	w.capture(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *auditWriter) WriteString(s string) (int, error) {
+	w.capture([]byte(s))
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *auditWriter) capture(b []byte) {
+	if rest := maxAuditBody - w.body.Len(); rest > 0 {
+		w.body.Write(b[:min(len(b), rest)])
+	}
+}
+
+// recordAudit записывает в журнал аудита каждый изменяющий запрос (POST, PUT, PATCH, DELETE):
+// кто его выполнил, маршрут, ID ресурса, идентификатор запроса, результат и IP-адрес.
+// Запросы, отклоненные аутентификацией или политикой доступа, тоже записываются.
+// Ошибка записи не меняет ответ, а пишется в лог.
+func (h *Handler) recordAudit(c *gin.Context) {
+	const op = "handler.recordAudit"
+
+	switch c.Request.Method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+	default:
+		c.Next()
+		return
+	}
+	if h.audit == nil {
+		c.Next()
+		return
+	}
+
+	writer := &auditWriter{ResponseWriter: c.Writer}
+	c.Writer = writer
+	c.Next()
+
+	// Клиент мог закрыть соединение, но запись о выполненном действии нужна все равно.
+	ctx := context.WithoutCancel(c.Request.Context())
+	entry := model.AuditEntry{
+		ActorType: model.AuditActorAnonymous,
+		Action:    c.Request.Method + " " + route(c),
+		RequestID: logging.RequestID(ctx),
+		Status:    writer.Status(),
+		Outcome:   auditOutcome(writer.Status()),
+		SourceIP:  c.ClientIP(),
+	}
+	if key, ok := service.APIKeyFrom(ctx); ok {
+		entry.ActorType, entry.ActorID = model.AuditActorAPIKey, &key.ID
+	} else if userID, ok := repository.TenantFrom(ctx); ok {
+		entry.ActorType, entry.ActorID = model.AuditActorUser, &userID
+	}
+
+	// Маршрут /api/v1/<тип ресурса>/...
+	entry.ResourceType, _, _ = strings.Cut(strings.TrimPrefix(route(c), "/api/v1/"), "/")
+	for _, p := range c.Params {
+		if p.Key == "id" {
+			entry.ResourceID = p.Value
+			continue
+		}
+		if entry.Params == nil {
+			entry.Params = make(map[string]string)
+		}
+		entry.Params[p.Key] = p.Value
+	}
+	if entry.ResourceID == "" && writer.Status() == http.StatusCreated {
+		var created struct {
+			ID string `json:"id"`
+		}
+		if json.Unmarshal(writer.body.Bytes(), &created) == nil {
+			entry.ResourceID = created.ID
+		}
+	}
+
+	// Запрос удалил данные пользователя: запись о нем сохраняется так, будто ее части с ID
+	// пользователя уже удалены вместе с остальными его данными.
+	if erased, ok := c.Get(erasedUserKey); ok {
+		eraseAuditUser(&entry, erased.(uuid.UUID))
+	}
+
+	if _, err := h.audit.Record(ctx, entry); err != nil {
+		h.requestLogger(c).Error("Не удалось записать запрос в журнал аудита", slog.String("op", op), slog.String("error", err.Error()))
+	}
+}
+
+// eraseAuditUser отмечает удаленными части записи, в которых есть ID пользователя userID:
+// исполнителя с IP-адресом и ID ресурса с параметрами.
+func eraseAuditUser(e *model.AuditEntry, userID uuid.UUID) {
+	e.IdentityErased = e.ActorID != nil && *e.ActorID == userID
+	e.SubjectErased = strings.Contains(e.ResourceID, userID.String())
+	for _, v := range e.Params {
+		e.SubjectErased = e.SubjectErased || strings.Contains(v, userID.String())
+	}
+}
+
+// auditOutcome определяет результат действия по статусу ответа.
+func auditOutcome(status int) model.AuditOutcome {
+	switch {
+	case status < http.StatusBadRequest:
+		return model.AuditOutcomeSuccess
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		return model.AuditOutcomeDenied
+	default:
+		return model.AuditOutcomeFailure
+	}
+}
+
+// ListAuditLog godoc
+// @Summary List audit log entries
+// @Description Returns audit log entries of mutating API calls, newest first. Use the seq of the last entry as before to get the next page. Requires the audit:read permission.
+// @Tags audit
+// @Produce  json
+// @Security ApiKeyAuth
+// @Param   actor_id query string false "Optional: filter by user or API key UUID" Format(uuid)
+// @Param   actor_type query string false "Optional: filter by actor type" Enums(user, api_key, anonymous)
+// @Param   action query string false "Optional: filter by method and route" Example(DELETE /api/v1/subscriptions/:id)
+// @Param   resource_id query string false "Optional: filter by resource ID"
+// @Param   outcome query string false "Optional: filter by outcome" Enums(success, denied, failure)
+// @Param   request_id query string false "Optional: filter by X-Request-ID"
+// @Param   from query string false "Optional: entries at or after this time (RFC 3339)" Example(2025-01-01T00:00:00Z)
+// @Param   to query string false "Optional: entries before this time (RFC 3339)" Example(2025-02-01T00:00:00Z)
+// @Param   before query int false "Optional: entries with seq less than this value"
+// @Param   limit query int false "Optional: page size, 1 to 1000 (default 100)"
+// @Success 200 {array} model.AuditEntry
+// @Failure 400 {object} ErrorResponse "Invalid query parameters"
+// @Failure 401 {object} ErrorResponse "Missing or invalid API key or token"
+// @Failure 403 {object} ProblemResponse "Missing permission"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /audit [get]
+func (h *Handler) ListAuditLog(c *gin.Context) {
+	const op = "handler.ListAuditLog"
+	log := h.requestLogger(c).With(slog.String("op", op))
+
+	filter, ok := auditFilter(c)
+	if !ok {
+		return
+	}
+
+	entries, err := h.audit.List(c.Request.Context(), filter)
+	if err != nil {
+		log.Error("Сервис вернул ошибку при получении журнала аудита", slog.String("error", err.Error()))
+		auditError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, entries)
+}
+
+// VerifyAuditLog godoc
+// @Summary Verify the audit log hash chain
+// @Description Recomputes the hash of every audit log entry and checks that each entry references the hash of the previous one. Reports the first entry that does not match. Requires the audit:read permission.
+// @Tags audit
+// @Produce  json
+// @Security ApiKeyAuth
+// @Success 200 {object} model.AuditVerification
+// @Failure 401 {object} ErrorResponse "Missing or invalid API key or token"
+// @Failure 403 {object} ProblemResponse "Missing permission"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /audit/verify [get]
+func (h *Handler) VerifyAuditLog(c *gin.Context) {
+	const op = "handler.VerifyAuditLog"
+	log := h.requestLogger(c).With(slog.String("op", op))
+
+	result, err := h.audit.Verify(c.Request.Context())
+	if err != nil {
+		log.Error("Сервис вернул ошибку при проверке журнала аудита", slog.String("error", err.Error()))
+		auditError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// auditFilter разбирает параметры выборки журнала аудита из query.
+// При ошибке отправляет ответ 400 и возвращает false.
+func auditFilter(c *gin.Context) (repository.AuditFilter, bool) {
+	var filter repository.AuditFilter
+
+	if value, exists := c.GetQuery("actor_id"); exists {
+		actorID, err := uuid.Parse(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid actor_id format"})
+			return repository.AuditFilter{}, false
+		}
+		filter.ActorID = &actorID
+	}
+	if value, exists := c.GetQuery("actor_type"); exists {
+		actorType := model.AuditActorType(value)
+		switch actorType {
+		case model.AuditActorUser, model.AuditActorAPIKey, model.AuditActorAnonymous:
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "actor_type must be user, api_key or anonymous"})
+			return repository.AuditFilter{}, false
+		}
+		filter.ActorType = &actorType
+	}
+	if value, exists := c.GetQuery("action"); exists {
+		filter.Action = &value
+	}
+	if value, exists := c.GetQuery("resource_id"); exists {
+		filter.ResourceID = &value
+	}
+	if value, exists := c.GetQuery("outcome"); exists {
+		outcome := model.AuditOutcome(value)
+		switch outcome {
+		case model.AuditOutcomeSuccess, model.AuditOutcomeDenied, model.AuditOutcomeFailure:
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "outcome must be success, denied or failure"})
+			return repository.AuditFilter{}, false
+		}
+		filter.Outcome = &outcome
+	}
+	if value, exists := c.GetQuery("request_id"); exists {
+		filter.RequestID = &value
+	}
+	for name, target := range map[string]**time.Time{"from": &filter.From, "to": &filter.To} {
+		value, exists := c.GetQuery(name)
+		if !exists {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid " + name + " format, use RFC 3339"})
+			return repository.AuditFilter{}, false
+		}
+		*target = &parsed
+	}
+	if value, exists := c.GetQuery("before"); exists {
+		before, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "before must be an integer"})
+			return repository.AuditFilter{}, false
+		}
+		filter.Before = &before
+	}
+	if value, exists := c.GetQuery("limit"); exists {
+		limit, err := strconv.Atoi(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be an integer"})
+			return repository.AuditFilter{}, false
+		}
+		filter.Limit = limit
+	}
+
+	return filter, true
+}
+
+// auditError отправляет ответ на ошибку сервиса журнала аудита.
+func auditError(c *gin.Context, err error) {
+	if permissionDenied(c, err) {
+		return
+	}
+	if errors.Is(err, service.ErrValidation) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
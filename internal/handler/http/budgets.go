@@ -0,0 +1,239 @@
+package http
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/vasiliy-maslov/go-subscription-service/internal/model"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/repository"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ListBudgets godoc
+// @Summary List budgets of a user
+// @Tags budgets
+// @Produce  json
+// @Security ApiKeyAuth
+// @Param   user_id query string true "User UUID" Format(uuid)
+// @Success 200 {array} model.Budget
+// @Failure 400 {object} ErrorResponse "Missing or invalid user_id"
+// @Failure 401 {object} ErrorResponse "Missing or invalid API key or token"
+// @Failure 403 {object} ProblemResponse "Data of another user without the users:manage permission"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /budgets [get]
+func (h *Handler) ListBudgets(c *gin.Context) {
+	const op = "handler.ListBudgets"
+	log := h.requestLogger(c).With(slog.String("op", op))
+
+	userID, ok := queryUserID(c)
+	if !ok {
+		return
+	}
+
+	budgets, err := h.budgets.List(c.Request.Context(), userID)
+	if err != nil {
+		log.Error("Сервис вернул ошибку при получении бюджетов", slog.String("error", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, budgets)
+}
+
+// CreateBudget godoc
+// @Summary Create a monthly budget
+// @Description Creates an overall budget or, when category_id or service_id is set, a budget for that category or catalog service. Thresholds default to 80 and 100 percent.
+// @Tags budgets
+// @Accept  json
+// @Produce  json
+// @Security ApiKeyAuth
+// @Param   budget body model.Budget true "Budget data. ID, CreatedAt and UpdatedAt will be ignored."
+// @Success 201 {object} model.Budget
+// @Failure 400 {object} ErrorResponse "Invalid request body, user, category or service"
+// @Failure 401 {object} ErrorResponse "Missing or invalid API key or token"
+// @Failure 403 {object} ProblemResponse "Data of another user without the users:manage permission"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /budgets [post]
+func (h *Handler) CreateBudget(c *gin.Context) {
+	const op = "handler.CreateBudget"
+	log := h.requestLogger(c).With(slog.String("op", op))
+
+	var input model.Budget
+	if err := c.ShouldBindJSON(&input); err != nil {
+		log.Warn("Не удалось прочитать тело запроса", slog.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	budget, err := h.budgets.Create(c.Request.Context(), input)
+	if err != nil {
+		log.Error("Сервис вернул ошибку при создании бюджета", slog.String("error", err.Error()))
+		budgetError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, budget)
+}
+
+// GetBudgetStatus godoc
+// @Summary Get budget status for the current month
+// @Description Returns projected spending against the limit for every budget of the user. Spending is calculated by the same rules as total cost for the whole current month.
+// @Tags budgets
+// @Produce  json
+// @Security ApiKeyAuth
+// @Param   user_id query string true "User UUID" Format(uuid)
+// @Success 200 {array} model.BudgetStatus
+// @Failure 400 {object} ErrorResponse "Missing or invalid user_id"
+// @Failure 401 {object} ErrorResponse "Missing or invalid API key or token"
+// @Failure 403 {object} ProblemResponse "Missing permission, or data of another user without the users:manage permission"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /budgets/status [get]
+func (h *Handler) GetBudgetStatus(c *gin.Context) {
+	const op = "handler.GetBudgetStatus"
+	log := h.requestLogger(c).With(slog.String("op", op))
+
+	userID, ok := queryUserID(c)
+	if !ok {
+		return
+	}
+
+	statuses, err := h.budgets.Status(c.Request.Context(), userID)
+	if err != nil {
+		log.Error("Сервис вернул ошибку при расчете состояния бюджетов", slog.String("error", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, statuses)
+}
+
+// GetBudget godoc
+// @Summary Get a budget by ID
+// @Tags budgets
+// @Produce  json
+// @Security ApiKeyAuth
+// @Param   id path string true "Budget UUID" Format(uuid)
+// @Success 200 {object} model.Budget
+// @Failure 400 {object} ErrorResponse "Invalid UUID format"
+// @Failure 401 {object} ErrorResponse "Missing or invalid API key or token"
+// @Failure 403 {object} ProblemResponse "Data of another user without the users:manage permission"
+// @Failure 404 {object} ErrorResponse "Budget not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /budgets/{id} [get]
+func (h *Handler) GetBudget(c *gin.Context) {
+	const op = "handler.GetBudget"
+	log := h.requestLogger(c).With(slog.String("op", op), slog.String("id", c.Param("id")))
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid budget ID"})
+		return
+	}
+
+	budget, err := h.budgets.GetByID(c.Request.Context(), id)
+	if err != nil {
+		log.Error("Сервис вернул ошибку при получении бюджета", slog.String("error", err.Error()))
+		budgetError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, budget)
+}
+
+// UpdateBudget godoc
+// @Summary Update a budget
+// @Description Replaces the limit, scope and thresholds of the budget. The owner cannot be changed.
+// @Tags budgets
+// @Accept  json
+// @Produce  json
+// @Security ApiKeyAuth
+// @Param   id path string true "Budget UUID" Format(uuid)
+// @Param   budget body model.Budget true "New budget data"
+// @Success 200 {object} model.Budget
+// @Failure 400 {object} ErrorResponse "Invalid request body, category, service or UUID format"
+// @Failure 401 {object} ErrorResponse "Missing or invalid API key or token"
+// @Failure 403 {object} ProblemResponse "Data of another user without the users:manage permission"
+// @Failure 404 {object} ErrorResponse "Budget not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /budgets/{id} [put]
+func (h *Handler) UpdateBudget(c *gin.Context) {
+	const op = "handler.UpdateBudget"
+	log := h.requestLogger(c).With(slog.String("op", op), slog.String("id", c.Param("id")))
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid budget ID"})
+		return
+	}
+
+	var input model.Budget
+	if err := c.ShouldBindJSON(&input); err != nil {
+		log.Warn("Не удалось прочитать тело запроса", slog.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	budget, err := h.budgets.Update(c.Request.Context(), id, input)
+	if err != nil {
+		log.Error("Сервис вернул ошибку при обновлении бюджета", slog.String("error", err.Error()))
+		budgetError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, budget)
+}
+
+// DeleteBudget godoc
+// @Summary Delete a budget
+// @Tags budgets
+// @Produce  json
+// @Security ApiKeyAuth
+// @Param   id path string true "Budget UUID" Format(uuid)
+// @Success 204 "No Content"
+// @Failure 400 {object} ErrorResponse "Invalid UUID format"
+// @Failure 401 {object} ErrorResponse "Missing or invalid API key or token"
+// @Failure 403 {object} ProblemResponse "Data of another user without the users:manage permission"
+// @Failure 404 {object} ErrorResponse "Budget not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /budgets/{id} [delete]
+func (h *Handler) DeleteBudget(c *gin.Context) {
+	const op = "handler.DeleteBudget"
+	log := h.requestLogger(c).With(slog.String("op", op), slog.String("id", c.Param("id")))
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid budget ID"})
+		return
+	}
+
+	if err := h.budgets.Delete(c.Request.Context(), id); err != nil {
+		log.Error("Сервис вернул ошибку при удалении бюджета", slog.String("error", err.Error()))
+		budgetError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// budgetError отправляет ответ, соответствующий ошибке сервиса бюджетов.
+func budgetError(c *gin.Context, err error) {
+	if permissionDenied(c, err) {
+		return
+	}
+
+	switch {
+	case errors.Is(err, repository.ErrBudgetNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "budget not found"})
+	case errors.Is(err, repository.ErrCategoryNotFound), errors.Is(err, repository.ErrServiceNotFound),
+		errors.Is(err, repository.ErrUserNotFound):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrValidation):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,220 @@
+package http
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/vasiliy-maslov/go-subscription-service/internal/model"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/repository"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ListCatalogServices godoc
+// @Summary List catalog services
+// @Description Returns services from the catalog with their aliases and plans.
+// @Tags catalog
+// @Produce  json
+// @Security ApiKeyAuth
+// @Param   category query string false "Optional: filter by category"
+// @Success 200 {array} model.CatalogService
+// @Failure 401 {object} ErrorResponse "Missing or invalid API key or token"
+// @Failure 403 {object} ProblemResponse "Missing permission"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /services [get]
+func (h *Handler) ListCatalogServices(c *gin.Context) {
+	const op = "handler.ListCatalogServices"
+	log := h.requestLogger(c).With(slog.String("op", op))
+
+	var category *string
+	if value, exists := c.GetQuery("category"); exists {
+		category = &value
+	}
+
+	services, err := h.catalog.List(c.Request.Context(), category)
+	if err != nil {
+		log.Error("Сервис вернул ошибку при получении каталога", slog.String("error", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, services)
+}
+
+// GetCatalogService godoc
+// @Summary Get a catalog service by ID
+// @Description Retrieves a catalog service with its aliases and plans.
+// @Tags catalog
+// @Produce  json
+// @Security ApiKeyAuth
+// @Param   id path string true "Catalog service UUID" Format(uuid)
+// @Success 200 {object} model.CatalogService
+// @Failure 400 {object} ErrorResponse "Invalid UUID format"
+// @Failure 401 {object} ErrorResponse "Missing or invalid API key or token"
+// @Failure 403 {object} ProblemResponse "Missing permission"
+// @Failure 404 {object} ErrorResponse "Catalog service not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /services/{id} [get]
+func (h *Handler) GetCatalogService(c *gin.Context) {
+	const op = "handler.GetCatalogService"
+	idStr := c.Param("id")
+	log := h.requestLogger(c).With(slog.String("op", op), slog.String("id", idStr))
+
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		log.Warn("Некорректный формат UUID", slog.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid service ID"})
+		return
+	}
+
+	svc, err := h.catalog.GetByID(c.Request.Context(), id)
+	if err != nil {
+		log.Error("Сервис вернул ошибку при получении сервиса каталога", slog.String("error", err.Error()))
+		h.catalogError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, svc)
+}
+
+// CreateCatalogService godoc
+// @Summary Add a service to the catalog
+// @Description Creates a catalog entry with canonical name, aliases, category, logo and plans. Existing subscriptions whose service name matches an alias are linked to it.
+// @Tags catalog
+// @Accept  json
+// @Produce  json
+// @Security ApiKeyAuth
+// @Param   service body model.CatalogService true "Catalog service data. IDs and timestamps will be ignored."
+// @Success 201 {object} model.CatalogService
+// @Failure 400 {object} ErrorResponse "Invalid request body"
+// @Failure 401 {object} ErrorResponse "Missing or invalid API key or token"
+// @Failure 403 {object} ProblemResponse "Missing the catalog:manage permission"
+// @Failure 409 {object} ErrorResponse "Name or alias is already used by another service"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /services [post]
+func (h *Handler) CreateCatalogService(c *gin.Context) {
+	const op = "handler.CreateCatalogService"
+	log := h.requestLogger(c).With(slog.String("op", op))
+
+	var input model.CatalogService
+	if err := c.ShouldBindJSON(&input); err != nil {
+		log.Warn("Не удалось прочитать тело запроса", slog.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	log.Info("Запрос на создание сервиса каталога", slog.Any("input", input))
+
+	svc, err := h.catalog.Create(c.Request.Context(), input)
+	if err != nil {
+		log.Error("Сервис вернул ошибку при создании сервиса каталога", slog.String("error", err.Error()))
+		h.catalogError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, svc)
+}
+
+// UpdateCatalogService godoc
+// @Summary Update a catalog service
+// @Description Replaces the catalog entry, its aliases and plans. Plans keep their IDs when their names do not change.
+// @Tags catalog
+// @Accept  json
+// @Produce  json
+// @Security ApiKeyAuth
+// @Param   id path string true "Catalog service UUID" Format(uuid)
+// @Param   service body model.CatalogService true "New catalog service data. All fields must be provided."
+// @Success 200 {object} model.CatalogService
+// @Failure 400 {object} ErrorResponse "Invalid request body or UUID format"
+// @Failure 401 {object} ErrorResponse "Missing or invalid API key or token"
+// @Failure 403 {object} ProblemResponse "Missing the catalog:manage permission"
+// @Failure 404 {object} ErrorResponse "Catalog service not found"
+// @Failure 409 {object} ErrorResponse "Name or alias is already used by another service"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /services/{id} [put]
+func (h *Handler) UpdateCatalogService(c *gin.Context) {
+	const op = "handler.UpdateCatalogService"
+	idStr := c.Param("id")
+	log := h.requestLogger(c).With(slog.String("op", op), slog.String("id", idStr))
+
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		log.Warn("Некорректный формат UUID", slog.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid service ID"})
+		return
+	}
+
+	var input model.CatalogService
+	if err := c.ShouldBindJSON(&input); err != nil {
+		log.Warn("Не удалось прочитать тело запроса", slog.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	log.Info("Запрос на обновление сервиса каталога", slog.Any("input", input))
+
+	svc, err := h.catalog.Update(c.Request.Context(), id, input)
+	if err != nil {
+		log.Error("Сервис вернул ошибку при обновлении сервиса каталога", slog.String("error", err.Error()))
+		h.catalogError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, svc)
+}
+
+// DeleteCatalogService godoc
+// @Summary Delete a catalog service
+// @Description Removes a service from the catalog. Subscriptions keep their service name but lose the catalog link.
+// @Tags catalog
+// @Produce  json
+// @Security ApiKeyAuth
+// @Param   id path string true "Catalog service UUID" Format(uuid)
+// @Success 204 "No Content"
+// @Failure 400 {object} ErrorResponse "Invalid UUID format"
+// @Failure 401 {object} ErrorResponse "Missing or invalid API key or token"
+// @Failure 403 {object} ProblemResponse "Missing the catalog:manage permission"
+// @Failure 404 {object} ErrorResponse "Catalog service not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /services/{id} [delete]
+func (h *Handler) DeleteCatalogService(c *gin.Context) {
+	const op = "handler.DeleteCatalogService"
+	idStr := c.Param("id")
+	log := h.requestLogger(c).With(slog.String("op", op), slog.String("id", idStr))
+
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		log.Warn("Некорректный формат UUID", slog.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid service ID"})
+		return
+	}
+
+	if err := h.catalog.Delete(c.Request.Context(), id); err != nil {
+		log.Error("Сервис вернул ошибку при удалении сервиса каталога", slog.String("error", err.Error()))
+		h.catalogError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// catalogError отправляет ответ, соответствующий ошибке сервиса каталога.
+func (h *Handler) catalogError(c *gin.Context, err error) {
+	if permissionDenied(c, err) {
+		return
+	}
+
+	switch {
+	case errors.Is(err, repository.ErrServiceNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "catalog service not found"})
+	case errors.Is(err, repository.ErrAlreadyExists):
+		c.JSON(http.StatusConflict, gin.H{"error": "name or alias is already used by another service"})
+	case errors.Is(err, service.ErrValidation):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,338 @@
+package http
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/vasiliy-maslov/go-subscription-service/internal/model"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/repository"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type RenameRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+// ListCategories godoc
+// @Summary List categories of a user
+// @Tags categories
+// @Produce  json
+// @Security ApiKeyAuth
+// @Param   user_id query string true "User UUID" Format(uuid)
+// @Success 200 {array} model.Category
+// @Failure 400 {object} ErrorResponse "Missing or invalid user_id"
+// @Failure 401 {object} ErrorResponse "Missing or invalid API key or token"
+// @Failure 403 {object} ProblemResponse "Data of another user without the users:manage permission"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /categories [get]
+func (h *Handler) ListCategories(c *gin.Context) {
+	const op = "handler.ListCategories"
+	log := h.requestLogger(c).With(slog.String("op", op))
+
+	userID, ok := queryUserID(c)
+	if !ok {
+		return
+	}
+
+	categories, err := h.categories.ListCategories(c.Request.Context(), userID)
+	if err != nil {
+		log.Error("Сервис вернул ошибку при получении категорий", slog.String("error", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, categories)
+}
+
+// CreateCategory godoc
+// @Summary Create a category
+// @Tags categories
+// @Accept  json
+// @Produce  json
+// @Security ApiKeyAuth
+// @Param   category body model.Category true "Category data. ID and CreatedAt will be ignored."
+// @Success 201 {object} model.Category
+// @Failure 400 {object} ErrorResponse "Invalid request body or unknown user"
+// @Failure 401 {object} ErrorResponse "Missing or invalid API key or token"
+// @Failure 403 {object} ProblemResponse "Data of another user without the users:manage permission"
+// @Failure 409 {object} ErrorResponse "Category with this name already exists"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /categories [post]
+func (h *Handler) CreateCategory(c *gin.Context) {
+	const op = "handler.CreateCategory"
+	log := h.requestLogger(c).With(slog.String("op", op))
+
+	var input model.Category
+	if err := c.ShouldBindJSON(&input); err != nil {
+		log.Warn("Не удалось прочитать тело запроса", slog.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	category, err := h.categories.CreateCategory(c.Request.Context(), input)
+	if err != nil {
+		log.Error("Сервис вернул ошибку при создании категории", slog.String("error", err.Error()))
+		classificationError(c, err, "category")
+		return
+	}
+
+	c.JSON(http.StatusCreated, category)
+}
+
+// RenameCategory godoc
+// @Summary Rename a category
+// @Tags categories
+// @Accept  json
+// @Produce  json
+// @Security ApiKeyAuth
+// @Param   id path string true "Category UUID" Format(uuid)
+// @Param   category body RenameRequest true "New name"
+// @Success 200 {object} model.Category
+// @Failure 400 {object} ErrorResponse "Invalid request body or UUID format"
+// @Failure 401 {object} ErrorResponse "Missing or invalid API key or token"
+// @Failure 403 {object} ProblemResponse "Data of another user without the users:manage permission"
+// @Failure 404 {object} ErrorResponse "Category not found"
+// @Failure 409 {object} ErrorResponse "Category with this name already exists"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /categories/{id} [put]
+func (h *Handler) RenameCategory(c *gin.Context) {
+	const op = "handler.RenameCategory"
+	log := h.requestLogger(c).With(slog.String("op", op), slog.String("id", c.Param("id")))
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid category ID"})
+		return
+	}
+
+	var input RenameRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		log.Warn("Не удалось прочитать тело запроса", slog.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	category, err := h.categories.RenameCategory(c.Request.Context(), id, input.Name)
+	if err != nil {
+		log.Error("Сервис вернул ошибку при переименовании категории", slog.String("error", err.Error()))
+		classificationError(c, err, "category")
+		return
+	}
+
+	c.JSON(http.StatusOK, category)
+}
+
+// DeleteCategory godoc
+// @Summary Delete a category
+// @Description Deletes the category and detaches it from subscriptions.
+// @Tags categories
+// @Produce  json
+// @Security ApiKeyAuth
+// @Param   id path string true "Category UUID" Format(uuid)
+// @Success 204 "No Content"
+// @Failure 400 {object} ErrorResponse "Invalid UUID format"
+// @Failure 401 {object} ErrorResponse "Missing or invalid API key or token"
+// @Failure 403 {object} ProblemResponse "Data of another user without the users:manage permission"
+// @Failure 404 {object} ErrorResponse "Category not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /categories/{id} [delete]
+func (h *Handler) DeleteCategory(c *gin.Context) {
+	const op = "handler.DeleteCategory"
+	log := h.requestLogger(c).With(slog.String("op", op), slog.String("id", c.Param("id")))
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid category ID"})
+		return
+	}
+
+	if err := h.categories.DeleteCategory(c.Request.Context(), id); err != nil {
+		log.Error("Сервис вернул ошибку при удалении категории", slog.String("error", err.Error()))
+		classificationError(c, err, "category")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListTags godoc
+// @Summary List tags of a user
+// @Tags tags
+// @Produce  json
+// @Security ApiKeyAuth
+// @Param   user_id query string true "User UUID" Format(uuid)
+// @Success 200 {array} model.Tag
+// @Failure 400 {object} ErrorResponse "Missing or invalid user_id"
+// @Failure 401 {object} ErrorResponse "Missing or invalid API key or token"
+// @Failure 403 {object} ProblemResponse "Data of another user without the users:manage permission"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /tags [get]
+func (h *Handler) ListTags(c *gin.Context) {
+	const op = "handler.ListTags"
+	log := h.requestLogger(c).With(slog.String("op", op))
+
+	userID, ok := queryUserID(c)
+	if !ok {
+		return
+	}
+
+	tags, err := h.categories.ListTags(c.Request.Context(), userID)
+	if err != nil {
+		log.Error("Сервис вернул ошибку при получении меток", slog.String("error", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, tags)
+}
+
+// CreateTag godoc
+// @Summary Create a tag
+// @Description Tags are also created automatically when assigned to a subscription.
+// @Tags tags
+// @Accept  json
+// @Produce  json
+// @Security ApiKeyAuth
+// @Param   tag body model.Tag true "Tag data. ID and CreatedAt will be ignored."
+// @Success 201 {object} model.Tag
+// @Failure 400 {object} ErrorResponse "Invalid request body or unknown user"
+// @Failure 401 {object} ErrorResponse "Missing or invalid API key or token"
+// @Failure 403 {object} ProblemResponse "Data of another user without the users:manage permission"
+// @Failure 409 {object} ErrorResponse "Tag with this name already exists"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /tags [post]
+func (h *Handler) CreateTag(c *gin.Context) {
+	const op = "handler.CreateTag"
+	log := h.requestLogger(c).With(slog.String("op", op))
+
+	var input model.Tag
+	if err := c.ShouldBindJSON(&input); err != nil {
+		log.Warn("Не удалось прочитать тело запроса", slog.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tag, err := h.categories.CreateTag(c.Request.Context(), input)
+	if err != nil {
+		log.Error("Сервис вернул ошибку при создании метки", slog.String("error", err.Error()))
+		classificationError(c, err, "tag")
+		return
+	}
+
+	c.JSON(http.StatusCreated, tag)
+}
+
+// RenameTag godoc
+// @Summary Rename a tag
+// @Tags tags
+// @Accept  json
+// @Produce  json
+// @Security ApiKeyAuth
+// @Param   id path string true "Tag UUID" Format(uuid)
+// @Param   tag body RenameRequest true "New name"
+// @Success 200 {object} model.Tag
+// @Failure 400 {object} ErrorResponse "Invalid request body or UUID format"
+// @Failure 401 {object} ErrorResponse "Missing or invalid API key or token"
+// @Failure 403 {object} ProblemResponse "Data of another user without the users:manage permission"
+// @Failure 404 {object} ErrorResponse "Tag not found"
+// @Failure 409 {object} ErrorResponse "Tag with this name already exists"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /tags/{id} [put]
+func (h *Handler) RenameTag(c *gin.Context) {
+	const op = "handler.RenameTag"
+	log := h.requestLogger(c).With(slog.String("op", op), slog.String("id", c.Param("id")))
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid tag ID"})
+		return
+	}
+
+	var input RenameRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		log.Warn("Не удалось прочитать тело запроса", slog.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tag, err := h.categories.RenameTag(c.Request.Context(), id, input.Name)
+	if err != nil {
+		log.Error("Сервис вернул ошибку при переименовании метки", slog.String("error", err.Error()))
+		classificationError(c, err, "tag")
+		return
+	}
+
+	c.JSON(http.StatusOK, tag)
+}
+
+// DeleteTag godoc
+// @Summary Delete a tag
+// @Description Deletes the tag and detaches it from subscriptions.
+// @Tags tags
+// @Produce  json
+// @Security ApiKeyAuth
+// @Param   id path string true "Tag UUID" Format(uuid)
+// @Success 204 "No Content"
+// @Failure 400 {object} ErrorResponse "Invalid UUID format"
+// @Failure 401 {object} ErrorResponse "Missing or invalid API key or token"
+// @Failure 403 {object} ProblemResponse "Data of another user without the users:manage permission"
+// @Failure 404 {object} ErrorResponse "Tag not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /tags/{id} [delete]
+func (h *Handler) DeleteTag(c *gin.Context) {
+	const op = "handler.DeleteTag"
+	log := h.requestLogger(c).With(slog.String("op", op), slog.String("id", c.Param("id")))
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid tag ID"})
+		return
+	}
+
+	if err := h.categories.DeleteTag(c.Request.Context(), id); err != nil {
+		log.Error("Сервис вернул ошибку при удалении метки", slog.String("error", err.Error()))
+		classificationError(c, err, "tag")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// queryUserID разбирает обязательный параметр user_id. При ошибке отправляет ответ 400.
+func queryUserID(c *gin.Context) (uuid.UUID, bool) {
+	userIDStr, ok := c.GetQuery("user_id")
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "user_id is required"})
+		return uuid.Nil, false
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user_id format"})
+		return uuid.Nil, false
+	}
+
+	return userID, true
+}
+
+// classificationError отправляет ответ, соответствующий ошибке сервиса категорий и меток.
+func classificationError(c *gin.Context, err error, entity string) {
+	if permissionDenied(c, err) {
+		return
+	}
+
+	switch {
+	case errors.Is(err, repository.ErrCategoryNotFound), errors.Is(err, repository.ErrTagNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": entity + " not found"})
+	case errors.Is(err, repository.ErrAlreadyExists):
+		c.JSON(http.StatusConflict, gin.H{"error": entity + " with this name already exists"})
+	case errors.Is(err, service.ErrValidation), errors.Is(err, repository.ErrUserNotFound):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
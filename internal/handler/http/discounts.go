@@ -0,0 +1,158 @@
+package http
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/vasiliy-maslov/go-subscription-service/internal/model"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/repository"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ListDiscounts godoc
+// @Summary List subscription discounts
+// @Description Returns discounts, promo periods and coupons attached to the subscription.
+// @Tags discounts
+// @Produce  json
+// @Security ApiKeyAuth
+// @Param   id path string true "Subscription UUID" Format(uuid)
+// @Success 200 {array} model.Discount
+// @Failure 400 {object} ErrorResponse "Invalid UUID format"
+// @Failure 401 {object} ErrorResponse "Missing or invalid API key or token"
+// @Failure 403 {object} ProblemResponse "Missing permission"
+// @Failure 404 {object} ErrorResponse "Subscription not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /subscriptions/{id}/discounts [get]
+func (h *Handler) ListDiscounts(c *gin.Context) {
+	const op = "handler.ListDiscounts"
+	idStr := c.Param("id")
+	log := h.requestLogger(c).With(slog.String("op", op), slog.String("id", idStr))
+
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		log.Warn("Некорректный формат UUID", slog.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid subscription ID"})
+		return
+	}
+
+	discounts, err := h.service.ListDiscounts(c.Request.Context(), id)
+	if err != nil {
+		log.Error("Сервис вернул ошибку при получении скидок", slog.String("error", err.Error()))
+		if permissionDenied(c, err) {
+			return
+		}
+		if errors.Is(err, repository.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "subscription not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, discounts)
+}
+
+// AddDiscount godoc
+// @Summary Add a discount to a subscription
+// @Description Adds a percent or fixed discount limited to the first cycles billing periods and/or a date range. When several discounts apply to a month, the best one is used.
+// @Tags discounts
+// @Accept  json
+// @Produce  json
+// @Security ApiKeyAuth
+// @Param   id path string true "Subscription UUID" Format(uuid)
+// @Param   discount body model.Discount true "Discount. ID, SubscriptionID, CreatedAt will be ignored."
+// @Success 201 {object} model.Discount
+// @Failure 400 {object} ErrorResponse "Invalid request body or UUID format"
+// @Failure 401 {object} ErrorResponse "Missing or invalid API key or token"
+// @Failure 403 {object} ProblemResponse "Missing permission"
+// @Failure 404 {object} ErrorResponse "Subscription not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /subscriptions/{id}/discounts [post]
+func (h *Handler) AddDiscount(c *gin.Context) {
+	const op = "handler.AddDiscount"
+	idStr := c.Param("id")
+	log := h.requestLogger(c).With(slog.String("op", op), slog.String("id", idStr))
+
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		log.Warn("Некорректный формат UUID", slog.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid subscription ID"})
+		return
+	}
+
+	var input model.Discount
+	if err := c.ShouldBindJSON(&input); err != nil {
+		log.Warn("Не удалось прочитать тело запроса", slog.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	discount, err := h.service.AddDiscount(c.Request.Context(), id, input)
+	if err != nil {
+		log.Error("Сервис вернул ошибку при добавлении скидки", slog.String("error", err.Error()))
+		if permissionDenied(c, err) {
+			return
+		}
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "subscription not found"})
+		case errors.Is(err, service.ErrValidation):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, discount)
+}
+
+// DeleteDiscount godoc
+// @Summary Delete a subscription discount
+// @Tags discounts
+// @Produce  json
+// @Security ApiKeyAuth
+// @Param   id path string true "Subscription UUID" Format(uuid)
+// @Param   discount_id path string true "Discount UUID" Format(uuid)
+// @Success 204 "No Content"
+// @Failure 400 {object} ErrorResponse "Invalid UUID format"
+// @Failure 401 {object} ErrorResponse "Missing or invalid API key or token"
+// @Failure 403 {object} ProblemResponse "Missing permission"
+// @Failure 404 {object} ErrorResponse "Discount not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /subscriptions/{id}/discounts/{discount_id} [delete]
+func (h *Handler) DeleteDiscount(c *gin.Context) {
+	const op = "handler.DeleteDiscount"
+	log := h.requestLogger(c).With(slog.String("op", op), slog.String("id", c.Param("id")), slog.String("discount_id", c.Param("discount_id")))
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid subscription ID"})
+		return
+	}
+
+	changeID, err := uuid.Parse(c.Param("discount_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid discount ID"})
+		return
+	}
+
+	if err := h.service.DeleteDiscount(c.Request.Context(), id, changeID); err != nil {
+		log.Error("Сервис вернул ошибку при удалении скидки", slog.String("error", err.Error()))
+		if permissionDenied(c, err) {
+			return
+		}
+		if errors.Is(err, repository.ErrDiscountNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "discount not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
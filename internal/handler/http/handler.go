@@ -4,14 +4,18 @@ import (
 	"errors"
 	"log/slog"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/vasiliy-maslov/go-subscription-service/internal/model"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/ratelimit"
 	"github.com/vasiliy-maslov/go-subscription-service/internal/repository"
 	"github.com/vasiliy-maslov/go-subscription-service/internal/service"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type ErrorResponse struct {
@@ -26,21 +30,67 @@ type StatusResponse struct {
 	Status string `json:"status"`
 }
 
-type TotalCostResponse struct {
-	TotalCost int `json:"total_cost"`
-}
+// tracerName - имя инструментирования для серверных спанов HTTP.
+const tracerName = "github.com/vasiliy-maslov/go-subscription-service/internal/handler/http"
 
 // Handler - это слой, который связывает HTTP-запросы с бизнес-логикой.
 type Handler struct {
-	service service.SubscriptionService
+	service       service.SubscriptionService
+	catalog       service.CatalogService
+	categories    service.CategoryService
+	budgets       service.BudgetService
+	users         service.UserService
+	organizations service.OrganizationService
+	roles         service.RoleService
+	apiKeys       service.APIKeyService
+	tokens        service.TokenService
+	privacy       service.PrivacyService
+	audit         service.AuditService
+	// graphql обслуживает /graphql; nil отключает эндпоинт.
+	graphql http.Handler
+	// stream обслуживает поток изменений /subscriptions/stream; nil отключает эндпоинт.
+	stream service.StreamService
+	// limiter ограничивает частоту запросов; nil отключает ограничение.
+	limiter *ratelimit.Limiter
+	tracer  trace.Tracer
 	logger  *slog.Logger
 }
 
 // NewHandler создает новый экземпляр обработчика.
-func NewHandler(s service.SubscriptionService, logger *slog.Logger) *Handler {
+func NewHandler(
+	s service.SubscriptionService,
+	catalog service.CatalogService,
+	categories service.CategoryService,
+	budgets service.BudgetService,
+	users service.UserService,
+	organizations service.OrganizationService,
+	roles service.RoleService,
+	apiKeys service.APIKeyService,
+	tokens service.TokenService,
+	privacy service.PrivacyService,
+	audit service.AuditService,
+	graphql http.Handler,
+	stream service.StreamService,
+	limiter *ratelimit.Limiter,
+	logger *slog.Logger,
+) *Handler {
 	return &Handler{
-		service: s,
-		logger:  logger,
+		service:       s,
+		catalog:       catalog,
+		categories:    categories,
+		budgets:       budgets,
+		users:         users,
+		organizations: organizations,
+		roles:         roles,
+		apiKeys:       apiKeys,
+		tokens:        tokens,
+		privacy:       privacy,
+		audit:         audit,
+		graphql:       graphql,
+		stream:        stream,
+		limiter:       limiter,
+		tracer:        otel.Tracer(tracerName),
+		logger:        logger,
 	}
 }
 
@@ -49,16 +99,19 @@ func NewHandler(s service.SubscriptionService, logger *slog.Logger) *Handler {
 // @Description Retrieves full details of a subscription by its UUID.
 // @Tags subscriptions
 // @Produce  json
+// @Security ApiKeyAuth
 // @Param   id path string true "Subscription UUID" Format(uuid)
 // @Success 200 {object} model.Subscription
 // @Failure 400 {object} ErrorResponse "Invalid UUID format"
+// @Failure 401 {object} ErrorResponse "Missing or invalid API key or token"
+// @Failure 403 {object} ProblemResponse "Missing permission"
 // @Failure 404 {object} ErrorResponse "Subscription not found"
 // @Failure 500 {object} ErrorResponse "Internal server error"
 // @Router /subscriptions/{id} [get]
 func (h *Handler) GetSubscriptionByID(c *gin.Context) {
 	const op = "handler.GetSubscriptionByID"
 	idStr := c.Param("id")
-	log := h.logger.With(slog.String("op", op), slog.String("id", idStr))
+	log := h.requestLogger(c).With(slog.String("op", op), slog.String("id", idStr))
 
 	id, err := uuid.Parse(idStr)
 	if err != nil {
@@ -72,6 +125,9 @@ func (h *Handler) GetSubscriptionByID(c *gin.Context) {
 	sub, err := h.service.GetByID(c.Request.Context(), id)
 	if err != nil {
 		log.Error("Сервис вернул ошибку при получении", slog.String("error", err.Error()))
+		if permissionDenied(c, err) {
+			return
+		}
 		if errors.Is(err, repository.ErrNotFound) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "subscription not found"})
 			return
@@ -89,14 +145,18 @@ func (h *Handler) GetSubscriptionByID(c *gin.Context) {
 // @Tags subscriptions
 // @Accept  json
 // @Produce  json
-// @Param   subscription body model.Subscription true "Subscription data to create. ID, CreatedAt, UpdatedAt will be ignored."
+// @Security ApiKeyAuth
+// @Param   subscription body model.Subscription true "Subscription data to create. ID, Status, CreatedAt, UpdatedAt will be ignored. The user must exist; currency defaults to the user's default currency. With organization_id the user must be a member of the organization and currency defaults to the organization's default currency. The service name is matched against catalog aliases; with plan_id and zero price the plan price is used. Categories must belong to the owner, unknown tags are created."
 // @Success 201 {object} CreateResponse
-// @Failure 400 {object} ErrorResponse "Invalid request body"
+// @Failure 400 {object} ErrorResponse "Invalid request body, unknown user or unknown catalog service or plan"
+// @Failure 401 {object} ErrorResponse "Missing or invalid API key or token"
+// @Failure 403 {object} ProblemResponse "Missing permission, or the subscription is created for a user other than the authenticated user (plain error body)"
+// @Failure 409 {object} ErrorResponse "Overlapping subscription to the same service exists (strict mode)"
 // @Failure 500 {object} ErrorResponse "Internal server error"
 // @Router /subscriptions [post]
 func (h *Handler) CreateSubscription(c *gin.Context) {
 	const op = "handler.CreateSubscription"
-	log := h.logger.With(slog.String("op", op))
+	log := h.requestLogger(c).With(slog.String("op", op))
 
 	var input model.Subscription
 	if err := c.ShouldBindJSON(&input); err != nil {
@@ -110,7 +170,19 @@ func (h *Handler) CreateSubscription(c *gin.Context) {
 	createdID, err := h.service.Create(c.Request.Context(), input)
 	if err != nil {
 		log.Error("Сервис вернул ошибку при создании", slog.String("error", err.Error()))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		if permissionDenied(c, err) {
+			return
+		}
+		switch {
+		case errors.Is(err, service.ErrValidation), errors.Is(err, repository.ErrUserNotFound):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrDuplicateSubscription):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case errors.Is(err, repository.ErrTenantMismatch):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
 		return
 	}
 
@@ -123,17 +195,21 @@ func (h *Handler) CreateSubscription(c *gin.Context) {
 // @Tags subscriptions
 // @Accept  json
 // @Produce  json
+// @Security ApiKeyAuth
 // @Param   id path string true "Subscription UUID" Format(uuid)
-// @Param   subscription body model.Subscription true "New subscription data. All fields must be provided."
+// @Param   subscription body model.Subscription true "New subscription data. All fields must be provided, except category_ids and tags: when omitted they are left unchanged."
 // @Success 200 {object} StatusResponse
 // @Failure 400 {object} ErrorResponse "Invalid request body or UUID format"
+// @Failure 401 {object} ErrorResponse "Missing or invalid API key or token"
+// @Failure 403 {object} ProblemResponse "Missing permission"
 // @Failure 404 {object} ErrorResponse "Subscription not found"
+// @Failure 409 {object} ErrorResponse "Overlapping subscription to the same service exists (strict mode)"
 // @Failure 500 {object} ErrorResponse "Internal server error"
 // @Router /subscriptions/{id} [put]
 func (h *Handler) UpdateSubscription(c *gin.Context) {
 	const op = "handler.UpdateSubscription"
 	idStr := c.Param("id")
-	log := h.logger.With(slog.String("op", op), slog.String("id", idStr))
+	log := h.requestLogger(c).With(slog.String("op", op), slog.String("id", idStr))
 
 	id, err := uuid.Parse(idStr)
 	if err != nil {
@@ -154,10 +230,21 @@ func (h *Handler) UpdateSubscription(c *gin.Context) {
 	err = h.service.Update(c.Request.Context(), id, input)
 	if err != nil {
 		log.Error("Сервис вернул ошибку при обновлении", slog.String("error", err.Error()))
+		if permissionDenied(c, err) {
+			return
+		}
 		if errors.Is(err, repository.ErrNotFound) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "subscription not found"})
 			return
 		}
+		if errors.Is(err, service.ErrValidation) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, service.ErrDuplicateSubscription) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -170,16 +257,19 @@ func (h *Handler) UpdateSubscription(c *gin.Context) {
 // @Description Deletes a subscription by its UUID.
 // @Tags subscriptions
 // @Produce  json
+// @Security ApiKeyAuth
 // @Param   id path string true "Subscription UUID" Format(uuid)
 // @Success 204 "No Content"
 // @Failure 400 {object} ErrorResponse "Invalid UUID format"
+// @Failure 401 {object} ErrorResponse "Missing or invalid API key or token"
+// @Failure 403 {object} ProblemResponse "Missing permission"
 // @Failure 404 {object} ErrorResponse "Subscription not found"
 // @Failure 500 {object} ErrorResponse "Internal server error"
 // @Router /subscriptions/{id} [delete]
 func (h *Handler) DeleteSubscription(c *gin.Context) {
 	const op = "handler.DeleteSubscription"
 	idStr := c.Param("id")
-	log := h.logger.With(slog.String("op", op), slog.String("id", idStr))
+	log := h.requestLogger(c).With(slog.String("op", op), slog.String("id", idStr))
 
 	id, err := uuid.Parse(idStr)
 	if err != nil {
@@ -193,6 +283,9 @@ func (h *Handler) DeleteSubscription(c *gin.Context) {
 	err = h.service.Delete(c.Request.Context(), id)
 	if err != nil {
 		log.Error("Сервис вернул ошибку при удалении", slog.String("error", err.Error()))
+		if permissionDenied(c, err) {
+			return
+		}
 		if errors.Is(err, repository.ErrNotFound) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "subscription not found"})
 			return
@@ -204,75 +297,352 @@ func (h *Handler) DeleteSubscription(c *gin.Context) {
 	c.Status(http.StatusNoContent)
 }
 
+// PauseSubscription godoc
+// @Summary Pause a subscription
+// @Description Pauses an active or trialing subscription starting today. Paused months are not charged.
+// @Tags subscriptions
+// @Produce  json
+// @Security ApiKeyAuth
+// @Param   id path string true "Subscription UUID" Format(uuid)
+// @Success 200 {object} model.Subscription
+// @Failure 400 {object} ErrorResponse "Invalid UUID format"
+// @Failure 401 {object} ErrorResponse "Missing or invalid API key or token"
+// @Failure 403 {object} ProblemResponse "Missing permission"
+// @Failure 404 {object} ErrorResponse "Subscription not found"
+// @Failure 409 {object} ErrorResponse "Transition is not allowed from the current status"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /subscriptions/{id}/pause [post]
+func (h *Handler) PauseSubscription(c *gin.Context) {
+	h.changeStatus(c, "handler.PauseSubscription", func(id uuid.UUID) (model.Subscription, error) {
+		return h.service.Pause(c.Request.Context(), id)
+	})
+}
+
+// ResumeSubscription godoc
+// @Summary Resume a subscription
+// @Description Resumes a paused subscription or withdraws a scheduled cancellation, restoring the end date in effect before it.
+// @Tags subscriptions
+// @Produce  json
+// @Security ApiKeyAuth
+// @Param   id path string true "Subscription UUID" Format(uuid)
+// @Success 200 {object} model.Subscription
+// @Failure 400 {object} ErrorResponse "Invalid UUID format"
+// @Failure 401 {object} ErrorResponse "Missing or invalid API key or token"
+// @Failure 403 {object} ProblemResponse "Missing permission"
+// @Failure 404 {object} ErrorResponse "Subscription not found"
+// @Failure 409 {object} ErrorResponse "Transition is not allowed from the current status"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /subscriptions/{id}/resume [post]
+func (h *Handler) ResumeSubscription(c *gin.Context) {
+	h.changeStatus(c, "handler.ResumeSubscription", func(id uuid.UUID) (model.Subscription, error) {
+		return h.service.Resume(c.Request.Context(), id)
+	})
+}
+
+// CancelSubscription godoc
+// @Summary Cancel a subscription
+// @Description Schedules cancellation at the end of the current period (month or trial). With immediate=true the subscription ends today.
+// @Tags subscriptions
+// @Produce  json
+// @Security ApiKeyAuth
+// @Param   id path string true "Subscription UUID" Format(uuid)
+// @Param   immediate query bool false "End the subscription today instead of at period end"
+// @Success 200 {object} model.Subscription
+// @Failure 400 {object} ErrorResponse "Invalid UUID format"
+// @Failure 401 {object} ErrorResponse "Missing or invalid API key or token"
+// @Failure 403 {object} ProblemResponse "Missing permission"
+// @Failure 404 {object} ErrorResponse "Subscription not found"
+// @Failure 409 {object} ErrorResponse "Transition is not allowed from the current status"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /subscriptions/{id}/cancel [post]
+func (h *Handler) CancelSubscription(c *gin.Context) {
+	immediate := c.Query("immediate") == "true"
+	h.changeStatus(c, "handler.CancelSubscription", func(id uuid.UUID) (model.Subscription, error) {
+		return h.service.Cancel(c.Request.Context(), id, immediate)
+	})
+}
+
+// changeStatus разбирает ID подписки, выполняет переход статуса и отправляет ответ.
+func (h *Handler) changeStatus(c *gin.Context, op string, change func(id uuid.UUID) (model.Subscription, error)) {
+	idStr := c.Param("id")
+	log := h.requestLogger(c).With(slog.String("op", op), slog.String("id", idStr))
+
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		log.Warn("Некорректный формат UUID", slog.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid subscription ID"})
+		return
+	}
+
+	log.Info("Запрос на изменение статуса подписки")
+
+	sub, err := change(id)
+	if err != nil {
+		log.Error("Сервис вернул ошибку при изменении статуса", slog.String("error", err.Error()))
+		if permissionDenied(c, err) {
+			return
+		}
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "subscription not found"})
+		case errors.Is(err, service.ErrInvalidTransition):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, sub)
+}
+
+// RestoreSubscription godoc
+// @Summary Restore an ended subscription
+// @Description Makes an ended subscription active again. The end date in effect before the cancellation is restored if it has not passed yet, otherwise the end date is cleared. Requires the subscriptions:restore permission.
+// @Tags subscriptions
+// @Produce  json
+// @Security ApiKeyAuth
+// @Param   id path string true "Subscription UUID" Format(uuid)
+// @Success 200 {object} model.Subscription
+// @Failure 400 {object} ErrorResponse "Invalid UUID format"
+// @Failure 401 {object} ErrorResponse "Missing or invalid API key or token"
+// @Failure 403 {object} ProblemResponse "Missing permission"
+// @Failure 404 {object} ErrorResponse "Subscription not found"
+// @Failure 409 {object} ErrorResponse "Subscription has not ended"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /subscriptions/{id}/restore [post]
+func (h *Handler) RestoreSubscription(c *gin.Context) {
+	h.changeStatus(c, "handler.RestoreSubscription", func(id uuid.UUID) (model.Subscription, error) {
+		return h.service.Restore(c.Request.Context(), id)
+	})
+}
+
 // CalculateTotalCost godoc
 // @Summary Calculate total subscription cost
-// @Description Calculates the total cost of subscriptions for a user over a specified period.
+// @Description Calculates the total cost of subscriptions for a user over a specified period. Trial and paused months are not charged; for shared subscriptions only the user's share is counted. With proration=daily the period is a date range and each billing period is charged by the share of its billable days inside the range, using actual month and year lengths. Dates are calendar dates and do not depend on time zones.
 // @Tags subscriptions
 // @Produce  json
+// @Security ApiKeyAuth
 // @Param   user_id query string true "User UUID" Format(uuid)
-// @Param   start_period query string true "Start period in YYYY-MM format" Example("2024-01")
-// @Param   end_period query string true "End period in YYYY-MM format" Example("2024-12")
-// @Param   service_name query string false "Optional: filter by service name"
-// @Success 200 {object} TotalCostResponse
-// @Failure 400 {object} ErrorResponse "Missing or invalid query parameters"
+// @Param   start_period query string false "Start period in YYYY-MM format; required unless proration=daily" Example("2024-01")
+// @Param   end_period query string false "End period in YYYY-MM format; required unless proration=daily" Example("2024-12")
+// @Param   proration query string false "Optional: monthly (default) charges any partial month in full, daily prorates each billing period by the days covered" Enums(monthly, daily)
+// @Param   from query string false "First day in YYYY-MM-DD format; required when proration=daily" Example("2024-01-15")
+// @Param   to query string false "Last day in YYYY-MM-DD format, inclusive; required when proration=daily" Example("2024-02-02")
+// @Param   service_name query string false "Optional: filter by service name or any of its catalog aliases"
+// @Param   service_id query string false "Optional: filter by catalog service UUID" Format(uuid)
+// @Param   category_id query string false "Optional: filter by category UUID" Format(uuid)
+// @Param   tag query string false "Optional: filter by tag (case-insensitive)"
+// @Param   group_by query string false "Optional: add a breakdown by category, tag or member (the user who manages each subscription); cannot be combined with details" Enums(category, tag, member)
+// @Param   details query bool false "Optional: add per-month line items showing base price and applied discount; cannot be combined with group_by"
+// @Param   currency query string false "Optional: filter by ISO 4217 currency code; required when subscriptions use different currencies" Example(RUB)
+// @Success 200 {object} model.CostSummary
+// @Failure 400 {object} ErrorResponse "Missing or invalid query parameters, group_by together with details, or subscriptions in different currencies"
+// @Failure 401 {object} ErrorResponse "Missing or invalid API key or token"
+// @Failure 403 {object} ProblemResponse "Missing permission"
+// @Failure 429 {object} ProblemResponse "Rate limit exceeded, see the Retry-After header"
 // @Failure 500 {object} ErrorResponse "Internal server error"
 // @Router /subscriptions/total_cost [get]
 func (h *Handler) CalculateTotalCost(c *gin.Context) {
 	const op = "handler.CalculateTotalCost"
-	log := h.logger.With(slog.String("op", op))
+	log := h.requestLogger(c).With(slog.String("op", op))
 
-	userIDStr, ok := c.GetQuery("user_id")
+	filter, ok := h.subscriptionFilter(c)
 	if !ok {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "user_id is required"})
 		return
 	}
-	userID, err := uuid.Parse(userIDStr)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user_id format"})
+
+	period, ok := costPeriod(c)
+	if !ok {
 		return
 	}
 
-	startPeriodStr, ok := c.GetQuery("start_period")
-	if !ok {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "start_period is required"})
+	// Разбивка и построчная детализация - разные формы отчета, поэтому вместе не запрашиваются.
+	groupBy, grouped := c.GetQuery("group_by")
+	details := c.Query("details") == "true"
+	if grouped && details {
+		log.Warn("Запрошены разбивка и детализация одновременно")
+		c.JSON(http.StatusBadRequest, gin.H{"error": "group_by cannot be combined with details=true"})
 		return
 	}
-	startPeriod, err := time.Parse("2006-01", startPeriodStr)
+
+	log.Info("Запрос на расчет стоимости",
+		slog.String("user_id", filter.UserID.String()),
+		slog.String("from", period.From.String()),
+		slog.String("to", period.To.String()),
+		slog.String("proration", string(period.Proration)),
+	)
+
+	var (
+		summary model.CostSummary
+		err     error
+	)
+	if grouped {
+		summary, err = h.service.CalculateCostBreakdown(c.Request.Context(), filter, groupBy, period)
+	} else if details {
+		summary, err = h.service.CalculateCostLineItems(c.Request.Context(), filter, period)
+	} else {
+		summary, err = h.service.CalculateTotalCost(c.Request.Context(), filter, period)
+	}
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid start_period format, use YYYY-MM"})
+		if permissionDenied(c, err) {
+			return
+		}
+		if errors.Is(err, service.ErrValidation) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	endPeriodStr, ok := c.GetQuery("end_period")
+	c.JSON(http.StatusOK, summary)
+}
+
+// ListSubscriptions godoc
+// @Summary List subscriptions of a user
+// @Description Returns subscriptions the user owns or shares, optionally filtered by service, category or tag.
+// @Tags subscriptions
+// @Produce  json
+// @Security ApiKeyAuth
+// @Param   user_id query string true "User UUID" Format(uuid)
+// @Param   service_name query string false "Optional: filter by service name or any of its catalog aliases"
+// @Param   service_id query string false "Optional: filter by catalog service UUID" Format(uuid)
+// @Param   category_id query string false "Optional: filter by category UUID" Format(uuid)
+// @Param   tag query string false "Optional: filter by tag (case-insensitive)"
+// @Param   currency query string false "Optional: filter by ISO 4217 currency code" Example(RUB)
+// @Success 200 {array} model.Subscription
+// @Failure 400 {object} ErrorResponse "Missing or invalid query parameters"
+// @Failure 401 {object} ErrorResponse "Missing or invalid API key or token"
+// @Failure 403 {object} ProblemResponse "Missing permission"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /subscriptions [get]
+func (h *Handler) ListSubscriptions(c *gin.Context) {
+	const op = "handler.ListSubscriptions"
+	log := h.requestLogger(c).With(slog.String("op", op))
+
+	filter, ok := h.subscriptionFilter(c)
 	if !ok {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "end_period is required"})
 		return
 	}
-	endPeriod, err := time.Parse("2006-01", endPeriodStr)
+
+	log.Info("Запрос на получение списка подписок", slog.String("user_id", filter.UserID.String()))
+
+	subscriptions, err := h.service.List(c.Request.Context(), filter)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid end_period format, use YYYY-MM"})
+		log.Error("Сервис вернул ошибку при получении списка", slog.String("error", err.Error()))
+		if permissionDenied(c, err) {
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	log.Info("Запрос на расчет стоимости",
-		slog.String("user_id", userIDStr),
-		slog.String("start_period", startPeriodStr),
-		slog.String("end_period", endPeriodStr),
-	)
+	if subscriptions == nil {
+		subscriptions = []model.Subscription{}
+	}
 
-	// Делаем конец периода последним днем месяца
-	endPeriod = endPeriod.AddDate(0, 1, -1)
+	c.JSON(http.StatusOK, subscriptions)
+}
 
-	var serviceName *string
-	if name, exists := c.GetQuery("service_name"); exists {
-		serviceName = &name
+// costPeriod читает период расчета стоимости из query-параметров. При помесячном расчете
+// период задается месяцами start_period и end_period, при посуточном - датами from и to.
+// Месяцы и даты календарные; сервис расширяет помесячный период до целых месяцев.
+// При ошибке отправляет ответ 400 и возвращает false.
+func costPeriod(c *gin.Context) (model.CostPeriod, bool) {
+	period := model.CostPeriod{Proration: model.Proration(c.DefaultQuery("proration", string(model.ProrationMonthly)))}
+
+	var ok bool
+	switch period.Proration {
+	case model.ProrationMonthly:
+		if period.From, ok = queryDate(c, "start_period", "2006-01", "YYYY-MM"); !ok {
+			return model.CostPeriod{}, false
+		}
+		if period.To, ok = queryDate(c, "end_period", "2006-01", "YYYY-MM"); !ok {
+			return model.CostPeriod{}, false
+		}
+	case model.ProrationDaily:
+		if period.From, ok = queryDate(c, "from", time.DateOnly, "YYYY-MM-DD"); !ok {
+			return model.CostPeriod{}, false
+		}
+		if period.To, ok = queryDate(c, "to", time.DateOnly, "YYYY-MM-DD"); !ok {
+			return model.CostPeriod{}, false
+		}
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "proration must be monthly or daily"})
+		return model.CostPeriod{}, false
 	}
 
-	totalCost, err := h.service.CalculateTotalCost(c.Request.Context(), userID, serviceName, startPeriod, endPeriod)
+	return period, true
+}
+
+// queryDate читает обязательный query-параметр name в формате layout как календарную дату.
+// При ошибке отправляет ответ 400 с подсказкой формата hint и возвращает false.
+func queryDate(c *gin.Context, name, layout, hint string) (model.Date, bool) {
+	value, ok := c.GetQuery(name)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": name + " is required"})
+		return model.Date{}, false
+	}
+
+	parsed, err := time.Parse(layout, value)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid " + name + " format, use " + hint})
+		return model.Date{}, false
+	}
+
+	return model.NewDate(parsed.Year(), parsed.Month(), parsed.Day()), true
+}
+
+// subscriptionFilter разбирает общие параметры выборки подписок из query.
+// При ошибке отправляет ответ 400 и возвращает false.
+func (h *Handler) subscriptionFilter(c *gin.Context) (repository.SubscriptionFilter, bool) {
+	userID, ok := queryUserID(c)
+	if !ok {
+		return repository.SubscriptionFilter{}, false
+	}
+
+	filter := repository.SubscriptionFilter{UserID: userID}
+	if !filterOptions(c, &filter) {
+		return repository.SubscriptionFilter{}, false
+	}
+
+	return filter, true
+}
+
+// filterOptions разбирает необязательные фильтры по сервису, категории, метке и валюте из query.
+// При ошибке отправляет ответ 400 и возвращает false.
+func filterOptions(c *gin.Context, filter *repository.SubscriptionFilter) bool {
+	if name, exists := c.GetQuery("service_name"); exists {
+		filter.ServiceName = &name
+	}
+	if serviceIDStr, exists := c.GetQuery("service_id"); exists {
+		serviceID, err := uuid.Parse(serviceIDStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid service_id format"})
+			return false
+		}
+		filter.ServiceID = &serviceID
+	}
+	if categoryIDStr, exists := c.GetQuery("category_id"); exists {
+		categoryID, err := uuid.Parse(categoryIDStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid category_id format"})
+			return false
+		}
+		filter.CategoryID = &categoryID
+	}
+	if tag, exists := c.GetQuery("tag"); exists {
+		filter.Tag = &tag
+	}
+	if currencyStr, exists := c.GetQuery("currency"); exists {
+		currency := model.Currency(strings.ToUpper(currencyStr))
+		if !currency.Valid() {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid currency, use an ISO 4217 code"})
+			return false
+		}
+		filter.Currency = &currency
 	}
 
-	c.JSON(http.StatusOK, gin.H{"total_cost": totalCost})
+	return true
 }
@@ -0,0 +1,47 @@
+package http
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// FindDuplicates godoc
+// @Summary Find duplicate subscriptions
+// @Description Finds pairs of the user's subscriptions to the same service (compared by normalized name) whose date ranges overlap, and suggests how to merge them.
+// @Tags insights
+// @Produce  json
+// @Security ApiKeyAuth
+// @Param   id path string true "User UUID" Format(uuid)
+// @Success 200 {array} model.SubscriptionOverlap
+// @Failure 400 {object} ErrorResponse "Invalid UUID format"
+// @Failure 401 {object} ErrorResponse "Missing or invalid API key or token"
+// @Failure 403 {object} ProblemResponse "Missing permission"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /users/{id}/insights/duplicates [get]
+func (h *Handler) FindDuplicates(c *gin.Context) {
+	const op = "handler.FindDuplicates"
+	idStr := c.Param("id")
+	log := h.requestLogger(c).With(slog.String("op", op), slog.String("id", idStr))
+
+	userID, err := uuid.Parse(idStr)
+	if err != nil {
+		log.Warn("Некорректный формат UUID", slog.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return
+	}
+
+	overlaps, err := h.service.FindDuplicates(c.Request.Context(), userID)
+	if err != nil {
+		log.Error("Сервис вернул ошибку при поиске дубликатов", slog.String("error", err.Error()))
+		if permissionDenied(c, err) {
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, overlaps)
+}
@@ -0,0 +1,179 @@
+package http
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/vasiliy-maslov/go-subscription-service/internal/model"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/repository"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ListMembers godoc
+// @Summary List subscription members
+// @Description Returns current and former members of a shared subscription.
+// @Tags members
+// @Produce  json
+// @Security ApiKeyAuth
+// @Param   id path string true "Subscription UUID" Format(uuid)
+// @Success 200 {array} model.SubscriptionMember
+// @Failure 400 {object} ErrorResponse "Invalid UUID format"
+// @Failure 401 {object} ErrorResponse "Missing or invalid API key or token"
+// @Failure 403 {object} ProblemResponse "Missing permission"
+// @Failure 404 {object} ErrorResponse "Subscription not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /subscriptions/{id}/members [get]
+func (h *Handler) ListMembers(c *gin.Context) {
+	const op = "handler.ListMembers"
+	idStr := c.Param("id")
+	log := h.requestLogger(c).With(slog.String("op", op), slog.String("id", idStr))
+
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		log.Warn("Некорректный формат UUID", slog.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid subscription ID"})
+		return
+	}
+
+	members, err := h.service.ListMembers(c.Request.Context(), id)
+	if err != nil {
+		log.Error("Сервис вернул ошибку при получении участников", slog.String("error", err.Error()))
+		if permissionDenied(c, err) {
+			return
+		}
+		if errors.Is(err, repository.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "subscription not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, members)
+}
+
+// AddMember godoc
+// @Summary Add a member to a shared subscription
+// @Description Adds a user who shares the subscription cost, either by weight (the owner has weight 1 unless listed) or by a fixed monthly amount. joined_at defaults to today.
+// @Tags members
+// @Accept  json
+// @Produce  json
+// @Security ApiKeyAuth
+// @Param   id path string true "Subscription UUID" Format(uuid)
+// @Param   member body model.SubscriptionMember true "Member data. ID, SubscriptionID, CreatedAt will be ignored."
+// @Success 201 {object} model.SubscriptionMember
+// @Failure 400 {object} ErrorResponse "Invalid request body or UUID format, or unknown user"
+// @Failure 401 {object} ErrorResponse "Missing or invalid API key or token"
+// @Failure 403 {object} ProblemResponse "Missing permission"
+// @Failure 404 {object} ErrorResponse "Subscription not found"
+// @Failure 409 {object} ErrorResponse "User is already an active member"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /subscriptions/{id}/members [post]
+func (h *Handler) AddMember(c *gin.Context) {
+	const op = "handler.AddMember"
+	idStr := c.Param("id")
+	log := h.requestLogger(c).With(slog.String("op", op), slog.String("id", idStr))
+
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		log.Warn("Некорректный формат UUID", slog.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid subscription ID"})
+		return
+	}
+
+	var input model.SubscriptionMember
+	if err := c.ShouldBindJSON(&input); err != nil {
+		log.Warn("Не удалось прочитать тело запроса", slog.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	log.Info("Запрос на добавление участника", slog.Any("input", input))
+
+	member, err := h.service.AddMember(c.Request.Context(), id, input)
+	if err != nil {
+		log.Error("Сервис вернул ошибку при добавлении участника", slog.String("error", err.Error()))
+		if permissionDenied(c, err) {
+			return
+		}
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "subscription not found"})
+		case errors.Is(err, repository.ErrAlreadyExists):
+			c.JSON(http.StatusConflict, gin.H{"error": "user is already an active member"})
+		case errors.Is(err, service.ErrValidation), errors.Is(err, repository.ErrUserNotFound):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, member)
+}
+
+// RemoveMember godoc
+// @Summary Remove a member from a shared subscription
+// @Description Ends the user's membership. The member is still charged for the month they leave in.
+// @Tags members
+// @Produce  json
+// @Security ApiKeyAuth
+// @Param   id path string true "Subscription UUID" Format(uuid)
+// @Param   user_id path string true "Member user UUID" Format(uuid)
+// @Param   left_at query string false "Last day of membership in YYYY-MM-DD format, defaults to today" Example("2024-06-15")
+// @Success 204 "No Content"
+// @Failure 400 {object} ErrorResponse "Invalid UUID or date format"
+// @Failure 401 {object} ErrorResponse "Missing or invalid API key or token"
+// @Failure 403 {object} ProblemResponse "Missing permission"
+// @Failure 404 {object} ErrorResponse "Active member not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /subscriptions/{id}/members/{user_id} [delete]
+func (h *Handler) RemoveMember(c *gin.Context) {
+	const op = "handler.RemoveMember"
+	idStr := c.Param("id")
+	log := h.requestLogger(c).With(slog.String("op", op), slog.String("id", idStr))
+
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		log.Warn("Некорректный формат UUID", slog.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid subscription ID"})
+		return
+	}
+
+	userID, err := uuid.Parse(c.Param("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user_id format"})
+		return
+	}
+
+	var leftAt model.Date
+	if leftAtStr, ok := c.GetQuery("left_at"); ok {
+		leftAt, err = model.ParseDate(leftAtStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid left_at format, use YYYY-MM-DD"})
+			return
+		}
+	}
+
+	log.Info("Запрос на удаление участника", slog.String("user_id", userID.String()))
+
+	err = h.service.RemoveMember(c.Request.Context(), id, userID, leftAt)
+	if err != nil {
+		log.Error("Сервис вернул ошибку при удалении участника", slog.String("error", err.Error()))
+		if permissionDenied(c, err) {
+			return
+		}
+		if errors.Is(err, repository.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "active member not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,308 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vasiliy-maslov/go-subscription-service/internal/logging"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/ratelimit"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/repository"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.39.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// trace открывает серверный спан OpenTelemetry на каждый запрос. Родительский контекст
+// трассировки берется из заголовков traceparent и baggage (W3C Trace Context).
+func (h *Handler) trace(c *gin.Context) {
+	ctx := otel.GetTextMapPropagator().Extract(c.Request.Context(), propagation.HeaderCarrier(c.Request.Header))
+	ctx, span := h.tracer.Start(ctx, c.Request.Method+" "+route(c),
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(
+			semconv.HTTPRequestMethodKey.String(c.Request.Method),
+			semconv.HTTPRoute(route(c)),
+			semconv.URLPath(c.Request.URL.Path),
+			semconv.ClientAddress(c.ClientIP()),
+		),
+	)
+	defer span.End()
+
+	c.Request = c.Request.WithContext(ctx)
+	c.Next()
+
+	// Пользователь известен только после authenticate.
+	ctx = c.Request.Context()
+	if key, ok := service.APIKeyFrom(ctx); ok {
+		span.SetAttributes(attribute.String("api_key_id", key.ID.String()))
+	} else if userID, ok := repository.TenantFrom(ctx); ok {
+		span.SetAttributes(attribute.String("user_id", userID.String()))
+	}
+
+	status := c.Writer.Status()
+	span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+	if status >= http.StatusInternalServerError {
+		span.SetStatus(codes.Error, http.StatusText(status))
+	}
+}
+
+// requestIDHeader - заголовок с идентификатором запроса. Клиент может передать свой идентификатор,
+// иначе он создается сервером; в обоих случаях он возвращается в ответе.
+const requestIDHeader = "X-Request-ID"
+
+// requestID присваивает запросу идентификатор из заголовка X-Request-ID или новый UUID
+// и кладет в контекст логгер с этим идентификатором, методом, маршрутом и идентификатором трассировки.
+// IP-адрес клиента тоже попадает в контекст, чтобы обработчики вне gin (GraphQL) определяли его
+// так же, как REST API, с учетом доверенных прокси.
+func (h *Handler) requestID(c *gin.Context) {
+	id := c.GetHeader(requestIDHeader)
+	if !logging.ValidRequestID(id) {
+		id = uuid.NewString()
+	}
+	c.Header(requestIDHeader, id)
+
+	log := h.logger.With(
+		slog.String("request_id", id),
+		slog.String("method", c.Request.Method),
+		slog.String("route", route(c)),
+	)
+	if sc := trace.SpanContextFromContext(c.Request.Context()); sc.IsValid() {
+		log = log.With(slog.String("trace_id", sc.TraceID().String()))
+	}
+	ctx := logging.WithClientIP(logging.WithRequestID(c.Request.Context(), id), c.ClientIP())
+	c.Request = c.Request.WithContext(logging.WithLogger(ctx, log))
+	c.Next()
+}
+
+// route возвращает шаблон маршрута запроса, а для неизвестного маршрута - путь.
+func route(c *gin.Context) string {
+	if path := c.FullPath(); path != "" {
+		return path
+	}
+	return c.Request.URL.Path
+}
+
+// requestLogger возвращает логгер запроса, созданный middleware requestID.
+func (h *Handler) requestLogger(c *gin.Context) *slog.Logger {
+	return logging.FromContext(c.Request.Context(), h.logger)
+}
+
+// withLogAttrs добавляет атрибуты к логгеру запроса, чтобы их получили записи сервисов и репозиториев.
+func withLogAttrs(c *gin.Context, attrs ...any) {
+	ctx := c.Request.Context()
+	c.Request = c.Request.WithContext(logging.WithLogger(ctx, logging.FromContext(ctx, slog.Default()).With(attrs...)))
+}
+
+// recovery перехватывает панику обработчика, пишет ее в лог со стеком вызовов
+// и отвечает 500 в формате application/problem+json.
+func (h *Handler) recovery(c *gin.Context) {
+	const op = "handler.recovery"
+
+	defer func() {
+		rec := recover()
+		if rec == nil {
+			return
+		}
+		// Клиент закрыл соединение - паника net/http, отвечать уже некому.
+		if rec == http.ErrAbortHandler {
+			panic(rec)
+		}
+
+		h.requestLogger(c).Error("Паника при обработке запроса",
+			slog.String("op", op),
+			slog.Any("panic", rec),
+			slog.String("stack", string(debug.Stack())),
+		)
+
+		if c.Writer.Written() {
+			c.Abort()
+			return
+		}
+		c.Header("Content-Type", "application/problem+json")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, ProblemResponse{
+			Type:   "about:blank",
+			Title:  http.StatusText(http.StatusInternalServerError),
+			Status: http.StatusInternalServerError,
+			Detail: "internal server error, request ID " + logging.RequestID(c.Request.Context()),
+		})
+	}()
+
+	c.Next()
+}
+
+// accessLog пишет одну запись на каждый запрос: метод, маршрут, статус, длительность,
+// размер ответа, IP-адрес и пользователя. Ответы 5xx пишутся на уровне Error.
+func (h *Handler) accessLog(c *gin.Context) {
+	start := time.Now()
+
+	c.Next()
+
+	attrs := []any{
+		slog.String("path", c.Request.URL.Path),
+		slog.Int("status", c.Writer.Status()),
+		slog.Duration("latency", time.Since(start)),
+		slog.Int("bytes", max(c.Writer.Size(), 0)),
+		slog.String("client_ip", c.ClientIP()),
+	}
+
+	// Логгер запроса уже содержит пользователя или API-ключ, если их установили tenant и authenticate.
+	log := h.requestLogger(c)
+	if c.Writer.Status() >= http.StatusInternalServerError {
+		log.Error("HTTP-запрос", attrs...)
+		return
+	}
+	log.Info("HTTP-запрос", attrs...)
+}
+
+// timeZoneHeader - заголовок с часовым поясом пользователя в формате IANA, например Asia/Vladivostok.
+const timeZoneHeader = "X-Time-Zone"
+
+// timeZone переносит часовой пояс пользователя из заголовка X-Time-Zone в контекст запроса.
+// Без заголовка даты толкуются в UTC.
+func timeZone(c *gin.Context) {
+	name := c.GetHeader(timeZoneHeader)
+	if name == "" {
+		c.Next()
+		return
+	}
+
+	// "Local" означает пояс сервера, а не пользователя, поэтому не принимается.
+	loc, err := time.LoadLocation(name)
+	if err != nil || name == "Local" {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid " + timeZoneHeader + " header, use an IANA time zone name"})
+		return
+	}
+
+	c.Request = c.Request.WithContext(service.WithTimeZone(c.Request.Context(), loc))
+	c.Next()
+}
+
+// authenticate определяет, от чьего имени выполняется запрос, по заголовку Authorization
+// ("Bearer <значение>" или само значение). API-ключ (sk_...) действует с правами ключа. Токен пользователя
+// привязывает запрос к пользователю из токена: подписки других пользователей и чужих организаций
+// для такого запроса не существуют. Запрос без заголовка отклоняется с ответом 401.
+func (h *Handler) authenticate(c *gin.Context) {
+	const op = "handler.authenticate"
+
+	raw := strings.TrimSpace(c.GetHeader("Authorization"))
+	if scheme, token, ok := strings.Cut(raw, " "); ok && strings.EqualFold(scheme, "Bearer") {
+		raw = strings.TrimSpace(token)
+	}
+	if raw == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "authentication required: send an API key or a user token in the Authorization header"})
+		return
+	}
+
+	if !service.IsAPIKey(raw) {
+		userID, err := h.tokens.Authenticate(c.Request.Context(), raw)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
+			return
+		}
+
+		c.Request = c.Request.WithContext(repository.WithTenant(c.Request.Context(), userID))
+		withLogAttrs(c, slog.String("user_id", userID.String()))
+		c.Next()
+		return
+	}
+
+	key, err := h.apiKeys.Authenticate(c.Request.Context(), raw)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidAPIKey) {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid API key"})
+			return
+		}
+		h.requestLogger(c).Error("Не удалось проверить API-ключ", slog.String("op", op), slog.String("error", err.Error()))
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Request = c.Request.WithContext(service.WithAPIKey(c.Request.Context(), key))
+	withLogAttrs(c, slog.String("api_key_id", key.ID.String()))
+	c.Next()
+}
+
+// rateLimitIP ограничивает частоту запросов с IP-адреса клиента до аутентификации, поэтому
+// запросы с неверным или отсутствующим ключом или токеном тоже расходуют корзину и перебор
+// ключей и токенов ограничен.
+func (h *Handler) rateLimitIP(c *gin.Context) {
+	const op = "handler.rateLimitIP"
+
+	if h.limiter == nil {
+		c.Next()
+		return
+	}
+
+	client := "ip:" + c.ClientIP()
+	decision, err := h.limiter.AllowIP(c.Request.Context(), c.ClientIP())
+	h.limit(c, op, client, decision, err)
+}
+
+// rateLimit ограничивает частоту запросов аутентифицированного клиента: API-ключа или пользователя
+// из токена. Ставится после authenticate; запросы до аутентификации ограничивает rateLimitIP.
+func (h *Handler) rateLimit(c *gin.Context) {
+	const op = "handler.rateLimit"
+
+	if h.limiter == nil {
+		c.Next()
+		return
+	}
+
+	ctx := c.Request.Context()
+	var client string
+	if key, ok := service.APIKeyFrom(ctx); ok {
+		client = "key:" + key.ID.String()
+	} else if userID, ok := repository.TenantFrom(ctx); ok {
+		client = "user:" + userID.String()
+	} else {
+		c.Next()
+		return
+	}
+
+	decision, err := h.limiter.Allow(ctx, c.Request.Method, c.FullPath(), client)
+	h.limit(c, op, client, decision, err)
+}
+
+// limit применяет решение ограничителя к запросу клиента client. Ответ содержит заголовки
+// RateLimit-Policy, RateLimit-Limit, RateLimit-Remaining и RateLimit-Reset, а при превышении -
+// 429 application/problem+json с Retry-After. Ошибка хранилища счетчиков не блокирует запрос.
+func (h *Handler) limit(c *gin.Context, op, client string, decision ratelimit.Decision, err error) {
+	if err != nil {
+		h.requestLogger(c).Error("Не удалось проверить ограничение запросов", slog.String("op", op), slog.String("error", err.Error()))
+		c.Next()
+		return
+	}
+
+	c.Header("RateLimit-Policy", decision.Rule.Policy())
+	c.Header("RateLimit-Limit", strconv.Itoa(decision.Rule.Burst))
+	c.Header("RateLimit-Remaining", strconv.Itoa(decision.Remaining))
+	c.Header("RateLimit-Reset", strconv.Itoa(int(decision.Reset.Seconds())))
+
+	if !decision.Allowed {
+		h.requestLogger(c).Warn("Превышено ограничение запросов", slog.String("op", op), slog.String("client", client), slog.String("path", c.FullPath()))
+		c.Header("Retry-After", strconv.Itoa(int(decision.RetryAfter.Seconds())))
+		c.Header("Content-Type", "application/problem+json")
+		c.AbortWithStatusJSON(http.StatusTooManyRequests, ProblemResponse{
+			Type:   "about:blank",
+			Title:  http.StatusText(http.StatusTooManyRequests),
+			Status: http.StatusTooManyRequests,
+			Detail: fmt.Sprintf("rate limit of %d requests per %s exceeded, retry in %d s",
+				decision.Rule.Requests, decision.Rule.Period, int(decision.RetryAfter.Seconds())),
+		})
+		return
+	}
+
+	c.Next()
+}
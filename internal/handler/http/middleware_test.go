@@ -0,0 +1,119 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/vasiliy-maslov/go-subscription-service/internal/logging"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// logRecord - запись JSON-лога с полями, которые проверяют тесты.
+type logRecord struct {
+	Level     string `json:"level"`
+	Msg       string `json:"msg"`
+	RequestID string `json:"request_id"`
+	Route     string `json:"route"`
+	Status    int    `json:"status"`
+}
+
+func TestRequestIDAndAccessLog(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name          string
+		path          string
+		requestID     string
+		wantRequestID string
+		wantStatus    int
+		wantLevel     string
+	}{
+		{name: "request ID from the client", path: "/ok", requestID: "req-42", wantRequestID: "req-42", wantStatus: http.StatusOK, wantLevel: "INFO"},
+		{name: "no request ID", path: "/ok", wantStatus: http.StatusOK, wantLevel: "INFO"},
+		{name: "request ID with a space", path: "/ok", requestID: "req 42", wantStatus: http.StatusOK, wantLevel: "INFO"},
+		{name: "too long request ID", path: "/ok", requestID: strings.Repeat("a", 129), wantStatus: http.StatusOK, wantLevel: "INFO"},
+		{name: "panic", path: "/panic", requestID: "req-43", wantRequestID: "req-43", wantStatus: http.StatusInternalServerError, wantLevel: "ERROR"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				logs     bytes.Buffer
+				clientIP string
+			)
+			h := &Handler{logger: slog.New(slog.NewJSONHandler(&logs, nil))}
+
+			router := gin.New()
+			router.Use(h.requestID, h.accessLog, h.recovery)
+			router.GET("/ok", func(c *gin.Context) {
+				clientIP = logging.ClientIP(c.Request.Context())
+				logging.FromContext(c.Request.Context(), nil).Info("handler")
+				c.Status(http.StatusOK)
+			})
+			router.GET("/panic", func(*gin.Context) {
+				panic("boom")
+			})
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			req.RemoteAddr = "10.0.0.1:1234"
+			req.Header.Set("X-Forwarded-For", "203.0.113.7")
+			if tt.requestID != "" {
+				req.Header.Set(requestIDHeader, tt.requestID)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			id := w.Header().Get(requestIDHeader)
+			if tt.wantRequestID != "" && id != tt.wantRequestID {
+				t.Errorf("%s = %q, want %q", requestIDHeader, id, tt.wantRequestID)
+			}
+			if tt.wantRequestID == "" && uuid.Validate(id) != nil {
+				t.Errorf("%s = %q, want a generated UUID", requestIDHeader, id)
+			}
+
+			if tt.wantStatus == http.StatusOK && clientIP != "203.0.113.7" {
+				t.Errorf("client IP in the request context = %q, want the address from X-Forwarded-For", clientIP)
+			}
+
+			if tt.wantStatus == http.StatusInternalServerError {
+				var problem ProblemResponse
+				if err := json.Unmarshal(w.Body.Bytes(), &problem); err != nil {
+					t.Fatalf("decode problem: %v", err)
+				}
+				if !strings.HasSuffix(problem.Detail, id) {
+					t.Errorf("problem detail = %q, want the request ID %s", problem.Detail, id)
+				}
+			}
+
+			var accessLog *logRecord
+			for _, line := range bytes.Split(bytes.TrimSpace(logs.Bytes()), []byte("\n")) {
+				var record logRecord
+				if err := json.Unmarshal(line, &record); err != nil {
+					t.Fatalf("decode log record %q: %v", line, err)
+				}
+				if record.RequestID != id || record.Route != tt.path {
+					t.Errorf("log record %q has request_id %q and route %q, want %q and %q", record.Msg, record.RequestID, record.Route, id, tt.path)
+				}
+				if record.Msg == "HTTP-запрос" {
+					accessLog = &record
+				}
+			}
+			if accessLog == nil {
+				t.Fatal("no access log record")
+			}
+			if accessLog.Status != tt.wantStatus || accessLog.Level != tt.wantLevel {
+				t.Errorf("access log = %s %d, want %s %d", accessLog.Level, accessLog.Status, tt.wantLevel, tt.wantStatus)
+			}
+		})
+	}
+}
@@ -0,0 +1,403 @@
+package http
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/vasiliy-maslov/go-subscription-service/internal/model"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/repository"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type CreateOrganizationRequest struct {
+	Name            string         `json:"name"             binding:"required"`
+	DefaultCurrency model.Currency `json:"default_currency"`
+	// OwnerID - первый владелец организации; для пользователя из токена должен с ним совпадать.
+	OwnerID uuid.UUID `json:"owner_id" binding:"required"`
+}
+
+type OrganizationMemberRequest struct {
+	Role model.OrganizationRole `json:"role" enums:"owner,admin,member"`
+}
+
+// ListOrganizations godoc
+// @Summary List organizations of a user
+// @Tags organizations
+// @Produce  json
+// @Param   user_id query string true "User UUID" Format(uuid)
+// @Success 200 {array} model.Organization
+// @Failure 400 {object} ErrorResponse "Missing or invalid user_id"
+// @Failure 403 {object} ErrorResponse "user_id differs from the authenticated user"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /organizations [get]
+func (h *Handler) ListOrganizations(c *gin.Context) {
+	const op = "handler.ListOrganizations"
+	log := h.requestLogger(c).With(slog.String("op", op))
+
+	userID, ok := queryUserID(c)
+	if !ok {
+		return
+	}
+
+	organizations, err := h.organizations.ListForUser(c.Request.Context(), userID)
+	if err != nil {
+		log.Error("Сервис вернул ошибку при получении организаций", slog.String("error", err.Error()))
+		organizationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, organizations)
+}
+
+// CreateOrganization godoc
+// @Summary Create an organization
+// @Description Creates an organization (team workspace) with the given user as its owner. default_currency defaults to RUB and is used for business subscriptions without a currency.
+// @Tags organizations
+// @Accept  json
+// @Produce  json
+// @Param   organization body CreateOrganizationRequest true "Organization data"
+// @Success 201 {object} model.Organization
+// @Failure 400 {object} ErrorResponse "Invalid request body or unknown owner"
+// @Failure 403 {object} ErrorResponse "owner_id differs from the authenticated user"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /organizations [post]
+func (h *Handler) CreateOrganization(c *gin.Context) {
+	const op = "handler.CreateOrganization"
+	log := h.requestLogger(c).With(slog.String("op", op))
+
+	var input CreateOrganizationRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		log.Warn("Не удалось прочитать тело запроса", slog.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	org, err := h.organizations.Create(c.Request.Context(),
+		model.Organization{Name: input.Name, DefaultCurrency: input.DefaultCurrency}, input.OwnerID)
+	if err != nil {
+		log.Error("Сервис вернул ошибку при создании организации", slog.String("error", err.Error()))
+		organizationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, org)
+}
+
+// GetOrganization godoc
+// @Summary Get an organization by ID
+// @Tags organizations
+// @Produce  json
+// @Param   id path string true "Organization UUID" Format(uuid)
+// @Success 200 {object} model.Organization
+// @Failure 400 {object} ErrorResponse "Invalid UUID format"
+// @Failure 404 {object} ErrorResponse "Organization not found or the authenticated user is not a member"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /organizations/{id} [get]
+func (h *Handler) GetOrganization(c *gin.Context) {
+	const op = "handler.GetOrganization"
+	log := h.requestLogger(c).With(slog.String("op", op), slog.String("id", c.Param("id")))
+
+	id, ok := organizationID(c)
+	if !ok {
+		return
+	}
+
+	org, err := h.organizations.GetByID(c.Request.Context(), id)
+	if err != nil {
+		log.Error("Сервис вернул ошибку при получении организации", slog.String("error", err.Error()))
+		organizationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, org)
+}
+
+// UpdateOrganization godoc
+// @Summary Update an organization
+// @Description Replaces the name and default currency. Requires the admin or owner role of the token user.
+// @Tags organizations
+// @Accept  json
+// @Produce  json
+// @Param   id path string true "Organization UUID" Format(uuid)
+// @Param   organization body model.Organization true "New organization data. ID, CreatedAt and UpdatedAt will be ignored."
+// @Success 200 {object} model.Organization
+// @Failure 400 {object} ErrorResponse "Invalid request body or UUID format"
+// @Failure 403 {object} ErrorResponse "Insufficient organization role"
+// @Failure 404 {object} ErrorResponse "Organization not found or the authenticated user is not a member"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /organizations/{id} [put]
+func (h *Handler) UpdateOrganization(c *gin.Context) {
+	const op = "handler.UpdateOrganization"
+	log := h.requestLogger(c).With(slog.String("op", op), slog.String("id", c.Param("id")))
+
+	id, ok := organizationID(c)
+	if !ok {
+		return
+	}
+
+	var input model.Organization
+	if err := c.ShouldBindJSON(&input); err != nil {
+		log.Warn("Не удалось прочитать тело запроса", slog.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	org, err := h.organizations.Update(c.Request.Context(), id, input)
+	if err != nil {
+		log.Error("Сервис вернул ошибку при обновлении организации", slog.String("error", err.Error()))
+		organizationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, org)
+}
+
+// DeleteOrganization godoc
+// @Summary Delete an organization
+// @Description Deletes the organization together with its business subscriptions. Requires the owner role of the token user.
+// @Tags organizations
+// @Produce  json
+// @Param   id path string true "Organization UUID" Format(uuid)
+// @Success 204 "No Content"
+// @Failure 400 {object} ErrorResponse "Invalid UUID format"
+// @Failure 403 {object} ErrorResponse "Insufficient organization role"
+// @Failure 404 {object} ErrorResponse "Organization not found or the authenticated user is not a member"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /organizations/{id} [delete]
+func (h *Handler) DeleteOrganization(c *gin.Context) {
+	const op = "handler.DeleteOrganization"
+	log := h.requestLogger(c).With(slog.String("op", op), slog.String("id", c.Param("id")))
+
+	id, ok := organizationID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.organizations.Delete(c.Request.Context(), id); err != nil {
+		log.Error("Сервис вернул ошибку при удалении организации", slog.String("error", err.Error()))
+		organizationError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListOrganizationMembers godoc
+// @Summary List members of an organization
+// @Tags organizations
+// @Produce  json
+// @Param   id path string true "Organization UUID" Format(uuid)
+// @Success 200 {array} model.OrganizationMember
+// @Failure 400 {object} ErrorResponse "Invalid UUID format"
+// @Failure 404 {object} ErrorResponse "Organization not found or the authenticated user is not a member"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /organizations/{id}/members [get]
+func (h *Handler) ListOrganizationMembers(c *gin.Context) {
+	const op = "handler.ListOrganizationMembers"
+	log := h.requestLogger(c).With(slog.String("op", op), slog.String("id", c.Param("id")))
+
+	id, ok := organizationID(c)
+	if !ok {
+		return
+	}
+
+	members, err := h.organizations.ListMembers(c.Request.Context(), id)
+	if err != nil {
+		log.Error("Сервис вернул ошибку при получении участников организации", slog.String("error", err.Error()))
+		organizationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, members)
+}
+
+// SetOrganizationMember godoc
+// @Summary Add a member to an organization or change the member's role
+// @Description Role defaults to member. Requires the admin or owner role of the token user; only owners can grant or revoke the owner role. The last owner cannot be demoted.
+// @Tags organizations
+// @Accept  json
+// @Produce  json
+// @Param   id path string true "Organization UUID" Format(uuid)
+// @Param   user_id path string true "User UUID" Format(uuid)
+// @Param   member body OrganizationMemberRequest true "Member role"
+// @Success 200 {object} model.OrganizationMember
+// @Failure 400 {object} ErrorResponse "Invalid request body, UUID format, unknown user or last owner"
+// @Failure 403 {object} ErrorResponse "Insufficient organization role"
+// @Failure 404 {object} ErrorResponse "Organization not found or the authenticated user is not a member"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /organizations/{id}/members/{user_id} [put]
+func (h *Handler) SetOrganizationMember(c *gin.Context) {
+	const op = "handler.SetOrganizationMember"
+	log := h.requestLogger(c).With(slog.String("op", op), slog.String("id", c.Param("id")), slog.String("user_id", c.Param("user_id")))
+
+	id, ok := organizationID(c)
+	if !ok {
+		return
+	}
+
+	userID, err := uuid.Parse(c.Param("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return
+	}
+
+	var input OrganizationMemberRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		log.Warn("Не удалось прочитать тело запроса", slog.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	member, err := h.organizations.SetMember(c.Request.Context(), model.OrganizationMember{
+		OrganizationID: id,
+		UserID:         userID,
+		Role:           input.Role,
+	})
+	if err != nil {
+		log.Error("Сервис вернул ошибку при изменении участника организации", slog.String("error", err.Error()))
+		organizationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, member)
+}
+
+// RemoveOrganizationMember godoc
+// @Summary Remove a member from an organization
+// @Description Requires the admin or owner role of the token user, except for members leaving on their own; only owners can remove owners. The last owner cannot leave. Business subscriptions of the member stay with the organization.
+// @Tags organizations
+// @Produce  json
+// @Param   id path string true "Organization UUID" Format(uuid)
+// @Param   user_id path string true "User UUID" Format(uuid)
+// @Success 204 "No Content"
+// @Failure 400 {object} ErrorResponse "Invalid UUID format or last owner"
+// @Failure 403 {object} ErrorResponse "Insufficient organization role"
+// @Failure 404 {object} ErrorResponse "Organization or member not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /organizations/{id}/members/{user_id} [delete]
+func (h *Handler) RemoveOrganizationMember(c *gin.Context) {
+	const op = "handler.RemoveOrganizationMember"
+	log := h.requestLogger(c).With(slog.String("op", op), slog.String("id", c.Param("id")), slog.String("user_id", c.Param("user_id")))
+
+	id, ok := organizationID(c)
+	if !ok {
+		return
+	}
+
+	userID, err := uuid.Parse(c.Param("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return
+	}
+
+	if err := h.organizations.RemoveMember(c.Request.Context(), id, userID); err != nil {
+		log.Error("Сервис вернул ошибку при исключении участника организации", slog.String("error", err.Error()))
+		organizationError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// CalculateOrganizationCost godoc
+// @Summary Calculate the cost of an organization's business subscriptions
+// @Description Aggregates business subscriptions of all members. Each subscription contributes the share of the member who manages it, by the same rules as the user total cost. Use group_by=member for a per-member breakdown.
+// @Tags organizations
+// @Produce  json
+// @Security ApiKeyAuth
+// @Param   id path string true "Organization UUID" Format(uuid)
+// @Param   start_period query string false "Start period in YYYY-MM format; required unless proration=daily" Example("2024-01")
+// @Param   end_period query string false "End period in YYYY-MM format; required unless proration=daily" Example("2024-12")
+// @Param   proration query string false "Optional: monthly (default) or daily" Enums(monthly, daily)
+// @Param   from query string false "First day in YYYY-MM-DD format; required when proration=daily" Example("2024-01-15")
+// @Param   to query string false "Last day in YYYY-MM-DD format, inclusive; required when proration=daily" Example("2024-02-02")
+// @Param   service_name query string false "Optional: filter by service name or any of its catalog aliases"
+// @Param   service_id query string false "Optional: filter by catalog service UUID" Format(uuid)
+// @Param   category_id query string false "Optional: filter by category UUID" Format(uuid)
+// @Param   tag query string false "Optional: filter by tag (case-insensitive)"
+// @Param   group_by query string false "Optional: add a breakdown by member, category or tag" Enums(member, category, tag)
+// @Param   currency query string false "Optional: filter by ISO 4217 currency code; required when subscriptions use different currencies" Example(RUB)
+// @Success 200 {object} model.CostSummary
+// @Failure 400 {object} ErrorResponse "Missing or invalid query parameters, or subscriptions in different currencies"
+// @Failure 401 {object} ErrorResponse "Missing or invalid API key or token"
+// @Failure 403 {object} ProblemResponse "Missing permission"
+// @Failure 404 {object} ErrorResponse "Organization not found or the authenticated user is not a member"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /organizations/{id}/total_cost [get]
+func (h *Handler) CalculateOrganizationCost(c *gin.Context) {
+	const op = "handler.CalculateOrganizationCost"
+	log := h.requestLogger(c).With(slog.String("op", op), slog.String("id", c.Param("id")))
+
+	id, ok := organizationID(c)
+	if !ok {
+		return
+	}
+
+	filter := repository.SubscriptionFilter{OrganizationID: &id}
+	if !filterOptions(c, &filter) {
+		return
+	}
+
+	period, ok := costPeriod(c)
+	if !ok {
+		return
+	}
+
+	// Проверяет существование организации и участие в ней пользователя запроса.
+	if _, err := h.organizations.GetByID(c.Request.Context(), id); err != nil {
+		log.Error("Сервис вернул ошибку при получении организации", slog.String("error", err.Error()))
+		organizationError(c, err)
+		return
+	}
+
+	var (
+		summary model.CostSummary
+		err     error
+	)
+	if groupBy, exists := c.GetQuery("group_by"); exists {
+		summary, err = h.service.CalculateCostBreakdown(c.Request.Context(), filter, groupBy, period)
+	} else {
+		summary, err = h.service.CalculateTotalCost(c.Request.Context(), filter, period)
+	}
+	if err != nil {
+		log.Error("Сервис вернул ошибку при расчете стоимости организации", slog.String("error", err.Error()))
+		organizationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
+
+// organizationID разбирает ID организации из пути. При ошибке отправляет ответ 400 и возвращает false.
+func organizationID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid organization ID"})
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// organizationError отправляет ответ, соответствующий ошибке сервиса организаций.
+func organizationError(c *gin.Context, err error) {
+	if permissionDenied(c, err) {
+		return
+	}
+
+	switch {
+	case errors.Is(err, repository.ErrOrganizationNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "organization not found"})
+	case errors.Is(err, repository.ErrOrganizationMemberNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "organization member not found"})
+	case errors.Is(err, service.ErrOrganizationForbidden), errors.Is(err, repository.ErrTenantMismatch):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrValidation), errors.Is(err, repository.ErrUserNotFound):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,158 @@
+package http
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/vasiliy-maslov/go-subscription-service/internal/model"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/repository"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ListPriceChanges godoc
+// @Summary List subscription price changes
+// @Description Returns past and scheduled price changes ordered by effective date.
+// @Tags prices
+// @Produce  json
+// @Security ApiKeyAuth
+// @Param   id path string true "Subscription UUID" Format(uuid)
+// @Success 200 {array} model.PriceChange
+// @Failure 400 {object} ErrorResponse "Invalid UUID format"
+// @Failure 401 {object} ErrorResponse "Missing or invalid API key or token"
+// @Failure 403 {object} ProblemResponse "Missing permission"
+// @Failure 404 {object} ErrorResponse "Subscription not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /subscriptions/{id}/price_changes [get]
+func (h *Handler) ListPriceChanges(c *gin.Context) {
+	const op = "handler.ListPriceChanges"
+	idStr := c.Param("id")
+	log := h.requestLogger(c).With(slog.String("op", op), slog.String("id", idStr))
+
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		log.Warn("Некорректный формат UUID", slog.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid subscription ID"})
+		return
+	}
+
+	changes, err := h.service.ListPriceChanges(c.Request.Context(), id)
+	if err != nil {
+		log.Error("Сервис вернул ошибку при получении изменений цены", slog.String("error", err.Error()))
+		if permissionDenied(c, err) {
+			return
+		}
+		if errors.Is(err, repository.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "subscription not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, changes)
+}
+
+// AddPriceChange godoc
+// @Summary Schedule a subscription price change
+// @Description Sets a new price starting from the month of effective_date. A change with the same effective date is replaced.
+// @Tags prices
+// @Accept  json
+// @Produce  json
+// @Security ApiKeyAuth
+// @Param   id path string true "Subscription UUID" Format(uuid)
+// @Param   change body model.PriceChange true "Price change. ID, SubscriptionID, CreatedAt will be ignored."
+// @Success 201 {object} model.PriceChange
+// @Failure 400 {object} ErrorResponse "Invalid request body or UUID format"
+// @Failure 401 {object} ErrorResponse "Missing or invalid API key or token"
+// @Failure 403 {object} ProblemResponse "Missing permission"
+// @Failure 404 {object} ErrorResponse "Subscription not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /subscriptions/{id}/price_changes [post]
+func (h *Handler) AddPriceChange(c *gin.Context) {
+	const op = "handler.AddPriceChange"
+	idStr := c.Param("id")
+	log := h.requestLogger(c).With(slog.String("op", op), slog.String("id", idStr))
+
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		log.Warn("Некорректный формат UUID", slog.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid subscription ID"})
+		return
+	}
+
+	var input model.PriceChange
+	if err := c.ShouldBindJSON(&input); err != nil {
+		log.Warn("Не удалось прочитать тело запроса", slog.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	change, err := h.service.AddPriceChange(c.Request.Context(), id, input)
+	if err != nil {
+		log.Error("Сервис вернул ошибку при изменении цены", slog.String("error", err.Error()))
+		if permissionDenied(c, err) {
+			return
+		}
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "subscription not found"})
+		case errors.Is(err, service.ErrValidation):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, change)
+}
+
+// DeletePriceChange godoc
+// @Summary Delete a subscription price change
+// @Tags prices
+// @Produce  json
+// @Security ApiKeyAuth
+// @Param   id path string true "Subscription UUID" Format(uuid)
+// @Param   change_id path string true "Price change UUID" Format(uuid)
+// @Success 204 "No Content"
+// @Failure 400 {object} ErrorResponse "Invalid UUID format"
+// @Failure 401 {object} ErrorResponse "Missing or invalid API key or token"
+// @Failure 403 {object} ProblemResponse "Missing permission"
+// @Failure 404 {object} ErrorResponse "Price change not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /subscriptions/{id}/price_changes/{change_id} [delete]
+func (h *Handler) DeletePriceChange(c *gin.Context) {
+	const op = "handler.DeletePriceChange"
+	log := h.requestLogger(c).With(slog.String("op", op), slog.String("id", c.Param("id")), slog.String("change_id", c.Param("change_id")))
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid subscription ID"})
+		return
+	}
+
+	changeID, err := uuid.Parse(c.Param("change_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid price change ID"})
+		return
+	}
+
+	if err := h.service.DeletePriceChange(c.Request.Context(), id, changeID); err != nil {
+		log.Error("Сервис вернул ошибку при удалении изменения цены", slog.String("error", err.Error()))
+		if permissionDenied(c, err) {
+			return
+		}
+		if errors.Is(err, repository.ErrPriceChangeNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "price change not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
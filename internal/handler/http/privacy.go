@@ -0,0 +1,144 @@
+package http
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/vasiliy-maslov/go-subscription-service/internal/model"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/repository"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ExportUserData godoc
+// @Summary Export all data of a user
+// @Description Returns a ZIP archive with JSON files: user.json (profile and settings), subscriptions.json (owned and shared subscriptions with status, member, price and discount history), categories.json, tags.json, budgets.json and notifications.json (domain events and alerts mentioning the user).
+// @Tags users
+// @Produce  application/zip
+// @Security ApiKeyAuth
+// @Param   id path string true "User UUID" Format(uuid)
+// @Success 200 {file} file "ZIP archive"
+// @Failure 400 {object} ErrorResponse "Invalid UUID format"
+// @Failure 401 {object} ErrorResponse "Missing or invalid API key or token"
+// @Failure 403 {object} ProblemResponse "Another user without the privacy:manage permission"
+// @Failure 404 {object} ErrorResponse "User not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /users/{id}/data-export [get]
+func (h *Handler) ExportUserData(c *gin.Context) {
+	const op = "handler.ExportUserData"
+	log := h.requestLogger(c).With(slog.String("op", op), slog.String("id", c.Param("id")))
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return
+	}
+
+	export, err := h.privacy.Export(c.Request.Context(), id)
+	if err != nil {
+		log.Error("Сервис вернул ошибку при выгрузке данных пользователя", slog.String("error", err.Error()))
+		privacyError(c, err)
+		return
+	}
+
+	archive, err := exportArchive(export)
+	if err != nil {
+		log.Error("Не удалось упаковать выгрузку", slog.String("error", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="user-%s-export.zip"`, id))
+	c.Data(http.StatusOK, "application/zip", archive)
+}
+
+// EraseUserData godoc
+// @Summary Erase all data of a user
+// @Description Deletes the user with owned subscriptions, memberships, categories, tags and budgets, and replaces the user ID with a tombstone in the event log. The erasure fails and is rolled back if any reference to the user remains. The returned record is kept in the erasure journal without the user ID.
+// @Tags users
+// @Produce  json
+// @Security ApiKeyAuth
+// @Param   id path string true "User UUID" Format(uuid)
+// @Success 200 {object} model.Erasure
+// @Failure 400 {object} ErrorResponse "Invalid UUID format"
+// @Failure 401 {object} ErrorResponse "Missing or invalid API key or token"
+// @Failure 403 {object} ProblemResponse "Another user without the privacy:manage permission"
+// @Failure 404 {object} ErrorResponse "User not found"
+// @Failure 500 {object} ErrorResponse "Internal server error or incomplete erasure"
+// @Router /users/{id}/data [delete]
+func (h *Handler) EraseUserData(c *gin.Context) {
+	const op = "handler.EraseUserData"
+	log := h.requestLogger(c).With(slog.String("op", op), slog.String("id", c.Param("id")))
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return
+	}
+
+	erasure, err := h.privacy.Erase(c.Request.Context(), id)
+	if err != nil {
+		log.Error("Сервис вернул ошибку при удалении данных пользователя", slog.String("error", err.Error()))
+		privacyError(c, err)
+		return
+	}
+
+	c.Set(erasedUserKey, id)
+	c.JSON(http.StatusOK, erasure)
+}
+
+// exportArchive упаковывает выгрузку в ZIP-архив, по одному JSON-файлу на раздел.
+func exportArchive(export model.UserDataExport) ([]byte, error) {
+	files := []struct {
+		name string
+		data any
+	}{
+		{"user.json", gin.H{"exported_at": export.ExportedAt, "user": export.User}},
+		{"subscriptions.json", export.Subscriptions},
+		{"categories.json", export.Categories},
+		{"tags.json", export.Tags},
+		{"budgets.json", export.Budgets},
+		{"notifications.json", export.Notifications},
+	}
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for _, file := range files {
+		w, err := archive.CreateHeader(&zip.FileHeader{Name: file.name, Method: zip.Deflate, Modified: export.ExportedAt})
+		if err != nil {
+			return nil, err
+		}
+
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(file.data); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// privacyError отправляет ответ, соответствующий ошибке сервиса запросов субъектов данных.
+func privacyError(c *gin.Context, err error) {
+	if permissionDenied(c, err) {
+		return
+	}
+
+	switch {
+	case errors.Is(err, repository.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,73 @@
+package http
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/vasiliy-maslov/go-subscription-service/internal/config"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/ratelimit"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRateLimitUnauthenticated(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	limiter, err := ratelimit.NewLimiter(config.RateLimitConfig{
+		Default: config.RateLimitRule{Requests: 100, Period: time.Minute},
+		PerIP:   config.RateLimitRule{Requests: 2, Period: time.Minute},
+	}, ratelimit.NewMemoryStore(), logger)
+	if err != nil {
+		t.Fatalf("NewLimiter() error = %v", err)
+	}
+	tokens, err := service.NewTokenService("test-token-secret-of-at-least-32-bytes", logger)
+	if err != nil {
+		t.Fatalf("NewTokenService() error = %v", err)
+	}
+	router := NewHandler(nil, nil, nil, nil, nil, nil, nil, nil, tokens, nil, nil, nil, nil, limiter, logger).InitRoutes()
+
+	tests := []struct {
+		name          string
+		authorization string
+		want          int
+	}{
+		{name: "invalid token", authorization: "Bearer guess-1", want: http.StatusUnauthorized},
+		{name: "missing token", want: http.StatusUnauthorized},
+		{name: "guessing is limited", authorization: "Bearer guess-2", want: http.StatusTooManyRequests},
+		{name: "API key guessing is limited", authorization: "sk_abcd1234_guess", want: http.StatusTooManyRequests},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/subscriptions/", nil)
+			req.RemoteAddr = "192.0.2.1:1234"
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.want)
+			}
+			if tt.want == http.StatusTooManyRequests && rec.Header().Get("Retry-After") == "" {
+				t.Error("429 response has no Retry-After header")
+			}
+		})
+	}
+
+	// Другой адрес расходует свою корзину.
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/subscriptions/", nil)
+	req.RemoteAddr = "192.0.2.2:1234"
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("status from another address = %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+}
@@ -0,0 +1,70 @@
+package http
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/vasiliy-maslov/go-subscription-service/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultForecastMonths - горизонт прогноза, если параметр months не указан.
+const defaultForecastMonths = 12
+
+// Forecast godoc
+// @Summary Forecast spending for upcoming months
+// @Description Projects month-by-month spending starting from the current month, taking into account billing periods, scheduled price changes, cancellations and pauses. Months with annual renewals list them in renewals.
+// @Tags reports
+// @Produce  json
+// @Security ApiKeyAuth
+// @Param   user_id query string true "User UUID" Format(uuid)
+// @Param   months query int false "Number of months to forecast (1-60, default 12)"
+// @Param   service_name query string false "Optional: filter by service name or any of its catalog aliases"
+// @Param   service_id query string false "Optional: filter by catalog service UUID" Format(uuid)
+// @Param   category_id query string false "Optional: filter by category UUID" Format(uuid)
+// @Param   tag query string false "Optional: filter by tag (case-insensitive)"
+// @Param   currency query string false "Optional: filter by ISO 4217 currency code; required when subscriptions use different currencies" Example(RUB)
+// @Success 200 {object} model.Forecast
+// @Failure 400 {object} ErrorResponse "Missing or invalid query parameters"
+// @Failure 401 {object} ErrorResponse "Missing or invalid API key or token"
+// @Failure 403 {object} ProblemResponse "Missing permission"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /reports/forecast [get]
+func (h *Handler) Forecast(c *gin.Context) {
+	const op = "handler.Forecast"
+	log := h.requestLogger(c).With(slog.String("op", op))
+
+	filter, ok := h.subscriptionFilter(c)
+	if !ok {
+		return
+	}
+
+	months := defaultForecastMonths
+	if monthsStr, exists := c.GetQuery("months"); exists {
+		var err error
+		months, err = strconv.Atoi(monthsStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid months format"})
+			return
+		}
+	}
+
+	forecast, err := h.service.Forecast(c.Request.Context(), filter, months)
+	if err != nil {
+		log.Error("Сервис вернул ошибку при построении прогноза", slog.String("error", err.Error()))
+		if permissionDenied(c, err) {
+			return
+		}
+		if errors.Is(err, service.ErrValidation) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, forecast)
+}
@@ -0,0 +1,182 @@
+package http
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/vasiliy-maslov/go-subscription-service/internal/model"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/repository"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ProblemResponse - ответ об ошибке в формате RFC 9457 (application/problem+json).
+type ProblemResponse struct {
+	Type   string `json:"type"             example:"about:blank"`
+	Title  string `json:"title"            example:"Forbidden"`
+	Status int    `json:"status"           example:"403"`
+	Detail string `json:"detail"           example:"permission denied: subscriptions:delete is required"`
+	// Permission - право, которого не хватило пользователю или API-ключу.
+	Permission model.Permission `json:"permission,omitempty" example:"subscriptions:delete"`
+}
+
+// ListRoles godoc
+// @Summary List roles
+// @Description Returns all roles with the permissions they grant.
+// @Tags roles
+// @Produce  json
+// @Success 200 {array} model.Role
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /roles [get]
+func (h *Handler) ListRoles(c *gin.Context) {
+	const op = "handler.ListRoles"
+	log := h.requestLogger(c).With(slog.String("op", op))
+
+	roles, err := h.roles.ListRoles(c.Request.Context())
+	if err != nil {
+		log.Error("Сервис вернул ошибку при получении ролей", slog.String("error", err.Error()))
+		roleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, roles)
+}
+
+// ListRoleBindings godoc
+// @Summary List roles of a user
+// @Description Users can always list their own roles; listing roles of another user requires the roles:manage permission.
+// @Tags roles
+// @Produce  json
+// @Param   id path string true "User UUID" Format(uuid)
+// @Success 200 {array} model.RoleBinding
+// @Failure 400 {object} ErrorResponse "Invalid UUID format"
+// @Failure 403 {object} ProblemResponse "Missing permission"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /users/{id}/roles [get]
+func (h *Handler) ListRoleBindings(c *gin.Context) {
+	const op = "handler.ListRoleBindings"
+	log := h.requestLogger(c).With(slog.String("op", op), slog.String("id", c.Param("id")))
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return
+	}
+
+	bindings, err := h.roles.ListBindings(c.Request.Context(), id)
+	if err != nil {
+		log.Error("Сервис вернул ошибку при получении ролей пользователя", slog.String("error", err.Error()))
+		roleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, bindings)
+}
+
+// BindRole godoc
+// @Summary Grant a role to a user
+// @Description Grants the role; granting a role the user already has is a no-op. Requires the roles:manage permission.
+// @Tags roles
+// @Produce  json
+// @Param   id path string true "User UUID" Format(uuid)
+// @Param   role path string true "Role name" Example(editor)
+// @Success 200 {object} model.RoleBinding
+// @Failure 400 {object} ErrorResponse "Invalid UUID format, unknown user or unknown role"
+// @Failure 403 {object} ProblemResponse "Missing permission"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /users/{id}/roles/{role} [put]
+func (h *Handler) BindRole(c *gin.Context) {
+	const op = "handler.BindRole"
+	log := h.requestLogger(c).With(slog.String("op", op), slog.String("id", c.Param("id")), slog.String("role", c.Param("role")))
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return
+	}
+
+	binding, err := h.roles.Bind(c.Request.Context(), id, c.Param("role"))
+	if err != nil {
+		log.Error("Сервис вернул ошибку при выдаче роли", slog.String("error", err.Error()))
+		roleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, binding)
+}
+
+// UnbindRole godoc
+// @Summary Revoke a role from a user
+// @Description Requires the roles:manage permission.
+// @Tags roles
+// @Produce  json
+// @Param   id path string true "User UUID" Format(uuid)
+// @Param   role path string true "Role name" Example(editor)
+// @Success 204 "No Content"
+// @Failure 400 {object} ErrorResponse "Invalid UUID format"
+// @Failure 403 {object} ProblemResponse "Missing permission"
+// @Failure 404 {object} ErrorResponse "The user does not have the role"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /users/{id}/roles/{role} [delete]
+func (h *Handler) UnbindRole(c *gin.Context) {
+	const op = "handler.UnbindRole"
+	log := h.requestLogger(c).With(slog.String("op", op), slog.String("id", c.Param("id")), slog.String("role", c.Param("role")))
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return
+	}
+
+	if err := h.roles.Unbind(c.Request.Context(), id, c.Param("role")); err != nil {
+		log.Error("Сервис вернул ошибку при отзыве роли", slog.String("error", err.Error()))
+		roleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// roleError отправляет ответ, соответствующий ошибке сервиса ролей.
+func roleError(c *gin.Context, err error) {
+	if permissionDenied(c, err) {
+		return
+	}
+
+	switch {
+	case errors.Is(err, repository.ErrRoleBindingNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "role binding not found"})
+	case errors.Is(err, repository.ErrRoleNotFound), errors.Is(err, repository.ErrUserNotFound),
+		errors.Is(err, service.ErrValidation):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// permissionDenied отправляет ответ 403 application/problem+json с недостающим правом,
+// если сервис отказал в доступе. Возвращает false для остальных ошибок.
+func permissionDenied(c *gin.Context, err error) bool {
+	problem := ProblemResponse{
+		Type:   "about:blank",
+		Title:  http.StatusText(http.StatusForbidden),
+		Status: http.StatusForbidden,
+	}
+
+	var permErr *service.PermissionError
+	switch {
+	case errors.As(err, &permErr):
+		problem.Detail, problem.Permission = permErr.Error(), permErr.Permission
+	case errors.Is(err, service.ErrForbidden), errors.Is(err, service.ErrOutOfScope):
+		problem.Detail = err.Error()
+	default:
+		return false
+	}
+
+	c.Header("Content-Type", "application/problem+json")
+	c.JSON(http.StatusForbidden, problem)
+	return true
+}
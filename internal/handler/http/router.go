@@ -11,18 +11,132 @@ import (
 // InitRoutes инициализирует роуты и связывает их с методами обработчика.
 func (h *Handler) InitRoutes() *gin.Engine {
 	router := gin.New()
+	// recovery стоит после trace и accessLog, чтобы запрос с паникой попал в спан и лог со статусом 500.
+	router.Use(h.trace, h.requestID, h.accessLog, h.recovery)
 
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
-	api := router.Group("/api/v1")
+	// GraphQL-запросы проходят те же проверки, что и REST API, но мутации записываются в журнал аудита
+	// самим обработчиком GraphQL: один POST-запрос может содержать несколько мутаций или только чтение.
+	if h.graphql != nil {
+		graphql := router.Group("/graphql", timeZone, h.rateLimitIP, h.authenticate, h.rateLimit)
+		graphql.GET("", gin.WrapH(h.graphql))
+		graphql.POST("", gin.WrapH(h.graphql))
+	}
+
+	// recordAudit стоит перед authenticate, чтобы в журнал попадали и отклоненные ключи и токены,
+	// а rateLimitIP - чтобы перебор ключей и токенов с одного адреса ограничивался до их проверки.
+	api := router.Group("/api/v1", timeZone, h.recordAudit, h.rateLimitIP, h.authenticate, h.rateLimit)
 	{
 		subscriptions := api.Group("/subscriptions")
 		{
+			subscriptions.GET("/", h.ListSubscriptions)
 			subscriptions.POST("/", h.CreateSubscription)
 			subscriptions.GET("/:id", h.GetSubscriptionByID)
 			subscriptions.PUT("/:id", h.UpdateSubscription)
 			subscriptions.DELETE("/:id", h.DeleteSubscription)
+			subscriptions.POST("/:id/pause", h.PauseSubscription)
+			subscriptions.POST("/:id/resume", h.ResumeSubscription)
+			subscriptions.POST("/:id/cancel", h.CancelSubscription)
+			subscriptions.POST("/:id/restore", h.RestoreSubscription)
+			subscriptions.GET("/:id/members", h.ListMembers)
+			subscriptions.POST("/:id/members", h.AddMember)
+			subscriptions.DELETE("/:id/members/:user_id", h.RemoveMember)
+			subscriptions.GET("/:id/price_changes", h.ListPriceChanges)
+			subscriptions.POST("/:id/price_changes", h.AddPriceChange)
+			subscriptions.DELETE("/:id/price_changes/:change_id", h.DeletePriceChange)
+			subscriptions.GET("/:id/discounts", h.ListDiscounts)
+			subscriptions.POST("/:id/discounts", h.AddDiscount)
+			subscriptions.DELETE("/:id/discounts/:discount_id", h.DeleteDiscount)
 			subscriptions.GET("/total_cost", h.CalculateTotalCost)
+			if h.stream != nil {
+				subscriptions.GET("/stream", h.StreamSubscriptions)
+			}
+		}
+
+		services := api.Group("/services")
+		{
+			services.GET("/", h.ListCatalogServices)
+			services.POST("/", h.CreateCatalogService)
+			services.GET("/:id", h.GetCatalogService)
+			services.PUT("/:id", h.UpdateCatalogService)
+			services.DELETE("/:id", h.DeleteCatalogService)
+		}
+
+		categories := api.Group("/categories")
+		{
+			categories.GET("/", h.ListCategories)
+			categories.POST("/", h.CreateCategory)
+			categories.PUT("/:id", h.RenameCategory)
+			categories.DELETE("/:id", h.DeleteCategory)
+		}
+
+		tags := api.Group("/tags")
+		{
+			tags.GET("/", h.ListTags)
+			tags.POST("/", h.CreateTag)
+			tags.PUT("/:id", h.RenameTag)
+			tags.DELETE("/:id", h.DeleteTag)
+		}
+
+		users := api.Group("/users")
+		{
+			users.POST("/", h.CreateUser)
+			users.GET("/:id", h.GetUser)
+			users.PUT("/:id", h.UpdateUser)
+			users.DELETE("/:id", h.DeleteUser)
+			users.GET("/:id/insights/duplicates", h.FindDuplicates)
+			users.GET("/:id/data-export", h.ExportUserData)
+			users.DELETE("/:id/data", h.EraseUserData)
+			users.GET("/:id/roles", h.ListRoleBindings)
+			users.PUT("/:id/roles/:role", h.BindRole)
+			users.DELETE("/:id/roles/:role", h.UnbindRole)
+		}
+
+		organizations := api.Group("/organizations")
+		{
+			organizations.GET("/", h.ListOrganizations)
+			organizations.POST("/", h.CreateOrganization)
+			organizations.GET("/:id", h.GetOrganization)
+			organizations.PUT("/:id", h.UpdateOrganization)
+			organizations.DELETE("/:id", h.DeleteOrganization)
+			organizations.GET("/:id/members", h.ListOrganizationMembers)
+			organizations.PUT("/:id/members/:user_id", h.SetOrganizationMember)
+			organizations.DELETE("/:id/members/:user_id", h.RemoveOrganizationMember)
+			organizations.GET("/:id/total_cost", h.CalculateOrganizationCost)
+		}
+
+		roles := api.Group("/roles")
+		{
+			roles.GET("/", h.ListRoles)
+		}
+
+		apiKeys := api.Group("/api_keys")
+		{
+			apiKeys.GET("/", h.ListAPIKeys)
+			apiKeys.POST("/", h.CreateAPIKey)
+			apiKeys.DELETE("/:id", h.RevokeAPIKey)
+		}
+
+		audit := api.Group("/audit")
+		{
+			audit.GET("/", h.ListAuditLog)
+			audit.GET("/verify", h.VerifyAuditLog)
+		}
+
+		reports := api.Group("/reports")
+		{
+			reports.GET("/forecast", h.Forecast)
+		}
+
+		budgets := api.Group("/budgets")
+		{
+			budgets.GET("/", h.ListBudgets)
+			budgets.POST("/", h.CreateBudget)
+			budgets.GET("/status", h.GetBudgetStatus)
+			budgets.GET("/:id", h.GetBudget)
+			budgets.PUT("/:id", h.UpdateBudget)
+			budgets.DELETE("/:id", h.DeleteBudget)
 		}
 	}
 
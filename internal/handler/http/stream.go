@@ -0,0 +1,143 @@
+package http
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/vasiliy-maslov/go-subscription-service/internal/model"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/repository"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/service"
+
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+)
+
+// streamHeartbeat - период комментариев, которые не дают прокси закрыть простаивающий поток.
+const streamHeartbeat = 15 * time.Second
+
+// StreamResetEvent - данные события reset: изменения после Last-Event-ID уже недоступны.
+type StreamResetEvent struct {
+	LastEventID string `json:"last_event_id"`
+}
+
+// StreamSubscriptions godoc
+// @Summary Stream subscription changes of a user
+// @Description Server-Sent Events stream of changes to subscriptions the user owns. Each event is named created, updated or deleted (status changes are updates), carries the change as JSON data and an ID that is the same on every replica. After a reconnect, send the last received ID in the Last-Event-ID header (browsers do it automatically) to get the changes made meanwhile from a bounded buffer of recent changes. If they are no longer in the buffer, the stream starts with a reset event and the client should reload the subscriptions. A comment line is sent every 15 seconds to keep the connection open.
+// @Tags subscriptions
+// @Produce  text/event-stream
+// @Security ApiKeyAuth
+// @Param   user_id query string false "User UUID; required for API keys, defaults to the user of the token" Format(uuid)
+// @Param   Last-Event-ID header string false "ID of the last received event"
+// @Param   last_event_id query string false "Same as Last-Event-ID, for clients that cannot set headers"
+// @Success 200 {object} model.SubscriptionChange "Stream of events, each with a change as data"
+// @Failure 400 {object} ErrorResponse "Missing or invalid user_id or Last-Event-ID"
+// @Failure 401 {object} ErrorResponse "Missing or invalid API key or token"
+// @Failure 403 {object} ProblemResponse "Missing permission, or user_id is not visible to the caller (plain error body)"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /subscriptions/stream [get]
+func (h *Handler) StreamSubscriptions(c *gin.Context) {
+	const op = "handler.StreamSubscriptions"
+	log := h.requestLogger(c).With(slog.String("op", op))
+
+	// Пользователь токена получает свои изменения; чужой user_id отклоняет сервис.
+	userID, authenticated := repository.TenantFrom(c.Request.Context())
+	if _, set := c.GetQuery("user_id"); set || !authenticated {
+		var ok bool
+		if userID, ok = queryUserID(c); !ok {
+			return
+		}
+	}
+	lastEventID, ok := streamLastEventID(c)
+	if !ok {
+		return
+	}
+
+	log.Info("Запрос на поток изменений подписок", slog.String("user_id", userID.String()))
+
+	ctx := c.Request.Context()
+	sub, err := h.stream.Subscribe(ctx, userID, lastEventID)
+	if err != nil {
+		log.Error("Сервис вернул ошибку при подписке на изменения", slog.String("error", err.Error()))
+		if permissionDenied(c, err) {
+			return
+		}
+		switch {
+		case errors.Is(err, service.ErrValidation):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, repository.ErrTenantMismatch):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+	defer sub.Close()
+
+	// Отключаем буферизацию ответа в nginx, иначе события приходят клиенту пачками.
+	c.Header("X-Accel-Buffering", "no")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Content-Type", "text/event-stream")
+	c.Status(http.StatusOK)
+
+	if sub.Reset {
+		c.Render(-1, sse.Event{Event: "reset", Data: StreamResetEvent{LastEventID: strconv.FormatInt(*lastEventID, 10)}})
+	}
+	for _, change := range sub.Replay {
+		c.Render(-1, changeEvent(change))
+	}
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info("Клиент закрыл поток изменений")
+			return
+		case change, ok := <-sub.Changes():
+			if !ok {
+				log.Info("Поток изменений закрыт сервером")
+				return
+			}
+			c.Render(-1, changeEvent(change))
+		case <-heartbeat.C:
+			if _, err := c.Writer.WriteString(": heartbeat\n\n"); err != nil {
+				return
+			}
+		}
+		c.Writer.Flush()
+	}
+}
+
+// changeEvent возвращает событие SSE для изменения подписки.
+func changeEvent(change model.SubscriptionChange) sse.Event {
+	return sse.Event{
+		Event: change.Type,
+		Id:    strconv.FormatInt(change.Seq, 10),
+		Data:  change,
+	}
+}
+
+// streamLastEventID читает идентификатор последнего полученного события из заголовка Last-Event-ID
+// или query-параметра last_event_id. Без них возвращает nil. При ошибке отправляет ответ 400 и возвращает false.
+func streamLastEventID(c *gin.Context) (*int64, bool) {
+	value := c.GetHeader("Last-Event-ID")
+	if value == "" {
+		value = c.Query("last_event_id")
+	}
+	if value == "" {
+		return nil, true
+	}
+
+	id, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid Last-Event-ID"})
+		return nil, false
+	}
+
+	return &id, true
+}
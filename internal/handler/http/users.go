@@ -0,0 +1,178 @@
+package http
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/vasiliy-maslov/go-subscription-service/internal/model"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/repository"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// CreateUser godoc
+// @Summary Create a user
+// @Description Creates a user profile. default_currency defaults to RUB, time_zone to UTC and budget alerts are enabled unless disabled explicitly. A user token without the users:manage permission registers its own profile under the user ID from the token.
+// @Tags users
+// @Accept  json
+// @Produce  json
+// @Security ApiKeyAuth
+// @Param   user body model.User true "User data. ID, CreatedAt and UpdatedAt will be ignored."
+// @Success 201 {object} model.User
+// @Failure 400 {object} ErrorResponse "Invalid request body"
+// @Failure 401 {object} ErrorResponse "Missing or invalid API key or token"
+// @Failure 403 {object} ProblemResponse "API key without the users:manage permission"
+// @Failure 409 {object} ErrorResponse "User with this ID or email already exists"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /users [post]
+func (h *Handler) CreateUser(c *gin.Context) {
+	const op = "handler.CreateUser"
+	log := h.requestLogger(c).With(slog.String("op", op))
+
+	input := model.User{Notifications: model.DefaultNotificationPreferences}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		log.Warn("Не удалось прочитать тело запроса", slog.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err := h.users.Create(c.Request.Context(), input)
+	if err != nil {
+		log.Error("Сервис вернул ошибку при создании пользователя", slog.String("error", err.Error()))
+		userError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, user)
+}
+
+// GetUser godoc
+// @Summary Get a user by ID
+// @Tags users
+// @Produce  json
+// @Security ApiKeyAuth
+// @Param   id path string true "User UUID" Format(uuid)
+// @Success 200 {object} model.User
+// @Failure 400 {object} ErrorResponse "Invalid UUID format"
+// @Failure 401 {object} ErrorResponse "Missing or invalid API key or token"
+// @Failure 403 {object} ProblemResponse "Another user without the users:manage permission"
+// @Failure 404 {object} ErrorResponse "User not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /users/{id} [get]
+func (h *Handler) GetUser(c *gin.Context) {
+	const op = "handler.GetUser"
+	log := h.requestLogger(c).With(slog.String("op", op), slog.String("id", c.Param("id")))
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return
+	}
+
+	user, err := h.users.GetByID(c.Request.Context(), id)
+	if err != nil {
+		log.Error("Сервис вернул ошибку при получении пользователя", slog.String("error", err.Error()))
+		userError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+// UpdateUser godoc
+// @Summary Update a user
+// @Description Replaces the profile and preferences of the user. Omitted notification preferences are disabled.
+// @Tags users
+// @Accept  json
+// @Produce  json
+// @Security ApiKeyAuth
+// @Param   id path string true "User UUID" Format(uuid)
+// @Param   user body model.User true "New user data"
+// @Success 200 {object} model.User
+// @Failure 400 {object} ErrorResponse "Invalid request body or UUID format"
+// @Failure 401 {object} ErrorResponse "Missing or invalid API key or token"
+// @Failure 403 {object} ProblemResponse "Another user without the users:manage permission"
+// @Failure 404 {object} ErrorResponse "User not found"
+// @Failure 409 {object} ErrorResponse "User with this email already exists"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /users/{id} [put]
+func (h *Handler) UpdateUser(c *gin.Context) {
+	const op = "handler.UpdateUser"
+	log := h.requestLogger(c).With(slog.String("op", op), slog.String("id", c.Param("id")))
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return
+	}
+
+	var input model.User
+	if err := c.ShouldBindJSON(&input); err != nil {
+		log.Warn("Не удалось прочитать тело запроса", slog.String("error", err.Error()))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err := h.users.Update(c.Request.Context(), id, input)
+	if err != nil {
+		log.Error("Сервис вернул ошибку при обновлении пользователя", slog.String("error", err.Error()))
+		userError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+// DeleteUser godoc
+// @Summary Delete a user
+// @Description Deletes the user together with owned subscriptions, memberships, categories, tags and budgets.
+// @Tags users
+// @Produce  json
+// @Security ApiKeyAuth
+// @Param   id path string true "User UUID" Format(uuid)
+// @Success 204 "No Content"
+// @Failure 400 {object} ErrorResponse "Invalid UUID format"
+// @Failure 401 {object} ErrorResponse "Missing or invalid API key or token"
+// @Failure 403 {object} ProblemResponse "Another user without the users:manage permission"
+// @Failure 404 {object} ErrorResponse "User not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /users/{id} [delete]
+func (h *Handler) DeleteUser(c *gin.Context) {
+	const op = "handler.DeleteUser"
+	log := h.requestLogger(c).With(slog.String("op", op), slog.String("id", c.Param("id")))
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return
+	}
+
+	if err := h.users.Delete(c.Request.Context(), id); err != nil {
+		log.Error("Сервис вернул ошибку при удалении пользователя", slog.String("error", err.Error()))
+		userError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// userError отправляет ответ, соответствующий ошибке сервиса пользователей.
+func userError(c *gin.Context, err error) {
+	if permissionDenied(c, err) {
+		return
+	}
+
+	switch {
+	case errors.Is(err, repository.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+	case errors.Is(err, repository.ErrAlreadyExists):
+		c.JSON(http.StatusConflict, gin.H{"error": "user with this ID or email already exists"})
+	case errors.Is(err, service.ErrValidation):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,66 @@
+// Package logging переносит логгер, идентификатор запроса и IP-адрес клиента через context.Context,
+// чтобы записи обработчика, сервиса и репозитория одного запроса можно было связать.
+package logging
+
+import (
+	"context"
+	"log/slog"
+)
+
+type loggerKey struct{}
+
+type requestIDKey struct{}
+
+type clientIPKey struct{}
+
+// WithLogger возвращает контекст с логгером запроса.
+func WithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// FromContext возвращает логгер запроса, а если его нет - fallback.
+func FromContext(ctx context.Context, fallback *slog.Logger) *slog.Logger {
+	if logger, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok && logger != nil {
+		return logger
+	}
+	return fallback
+}
+
+// WithRequestID возвращает контекст с идентификатором запроса.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID возвращает идентификатор запроса или пустую строку.
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// WithClientIP возвращает контекст с IP-адресом клиента.
+func WithClientIP(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, clientIPKey{}, ip)
+}
+
+// ClientIP возвращает IP-адрес клиента или пустую строку.
+func ClientIP(ctx context.Context) string {
+	ip, _ := ctx.Value(clientIPKey{}).(string)
+	return ip
+}
+
+// maxRequestIDLength ограничивает длину идентификатора запроса, переданного клиентом.
+const maxRequestIDLength = 128
+
+// ValidRequestID допускает непустые идентификаторы из печатных ASCII-символов без пробелов,
+// чтобы значение клиента нельзя было использовать для подделки записей лога.
+func ValidRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,41 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Типы доменных событий по API-ключам.
+const (
+	EventAPIKeyCreated = "api_key.created"
+	EventAPIKeyRevoked = "api_key.revoked"
+)
+
+// APIKey - ключ доступа машинного клиента. Секрет ключа не хранится и показывается один раз при выпуске.
+type APIKey struct {
+	ID   uuid.UUID `db:"id"           json:"id"`
+	Name string    `db:"name"         json:"name"`
+	// Prefix - открытая часть ключа, по которой он ищется и узнается в списке.
+	Prefix string `db:"prefix"       json:"prefix"`
+	// Permissions - права ключа. Роли к ключам не применяются.
+	Permissions []Permission `db:"permissions"  json:"permissions"`
+	// UserIDs ограничивает ключ подписками перечисленных пользователей; пустой список не ограничивает.
+	UserIDs    []uuid.UUID `db:"user_ids"     json:"user_ids"`
+	ExpiresAt  *time.Time  `db:"expires_at"   json:"expires_at,omitempty"`
+	LastUsedAt *time.Time  `db:"last_used_at" json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time  `db:"revoked_at"   json:"revoked_at,omitempty"`
+	CreatedAt  time.Time   `db:"created_at"   json:"created_at"`
+}
+
+// IssuedAPIKey - только что выпущенный ключ вместе с его полным значением.
+type IssuedAPIKey struct {
+	APIKey
+	// Key - значение для заголовка Authorization. Повторно получить его нельзя.
+	Key string `json:"key"`
+}
+
+// ActiveAt сообщает, что ключ не отозван и не истек к моменту now.
+func (k APIKey) ActiveAt(now time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || now.Before(*k.ExpiresAt))
+}
@@ -0,0 +1,228 @@
+package model
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuditActorType - кто выполнил действие.
+type AuditActorType string
+
+const (
+	AuditActorUser      AuditActorType = "user"
+	AuditActorAPIKey    AuditActorType = "api_key"
+	AuditActorAnonymous AuditActorType = "anonymous"
+)
+
+// AuditOutcome - результат действия.
+type AuditOutcome string
+
+const (
+	AuditOutcomeSuccess AuditOutcome = "success"
+	// AuditOutcomeDenied - запрос отклонен аутентификацией или политикой доступа.
+	AuditOutcomeDenied  AuditOutcome = "denied"
+	AuditOutcomeFailure AuditOutcome = "failure"
+)
+
+// AuditEntry - запись журнала аудита об изменяющем вызове API. Записи образуют цепочку:
+// каждая хранит хеш предыдущей, поэтому изменение или удаление записи обнаруживается проверкой.
+type AuditEntry struct {
+	// Seq - порядковый номер записи в журнале.
+	Seq        int64          `db:"seq"           json:"seq"`
+	OccurredAt time.Time      `db:"occurred_at"   json:"occurred_at"`
+	ActorType  AuditActorType `db:"actor_type"    json:"actor_type"   example:"user"`
+	// ActorID - пользователь из токена или API-ключ; у анонимного запроса и после удаления данных
+	// пользователя отсутствует.
+	ActorID *uuid.UUID `db:"actor_id"      json:"actor_id,omitempty"`
+	// Action - метод и маршрут запроса.
+	Action       string `db:"action"        json:"action"        example:"DELETE /api/v1/subscriptions/:id"`
+	ResourceType string `db:"resource_type" json:"resource_type" example:"subscriptions"`
+	// ResourceID - ID ресурса; после удаления данных пользователя, которого касается запись, пуст.
+	ResourceID string `db:"resource_id"   json:"resource_id,omitempty"`
+	// Params - параметры пути запроса, например user_id участника или имя роли. Удаляются вместе
+	// с ResourceID.
+	Params    map[string]string `db:"params"        json:"params,omitempty"`
+	RequestID string            `db:"request_id"    json:"request_id"`
+	Outcome   AuditOutcome      `db:"outcome"       json:"outcome"       example:"success"`
+	// Status - HTTP-статус ответа.
+	Status int `db:"status"        json:"status"        example:"204"`
+	// SourceIP - IP-адрес клиента; после удаления данных пользователя пуст.
+	SourceIP string `db:"source_ip"     json:"source_ip"`
+	// IdentityHash - SHA-256 от соли, ActorID и SourceIP. Исполнитель и IP-адрес хранятся отдельно
+	// от журнала и удаляются вместе с данными пользователя, а в цепочку входит только этот хеш.
+	// Пуст у записей, добавленных до переноса исполнителя из журнала.
+	IdentityHash string `db:"identity_hash" json:"identity_hash,omitempty"`
+	// IdentitySalt - случайная соль IdentityHash, удаляется вместе с исполнителем.
+	IdentitySalt string `db:"-"             json:"-"`
+	// IdentityErased сообщает, что исполнитель и IP-адрес записи удалены вместе с данными пользователя.
+	IdentityErased bool `db:"-"             json:"identity_erased,omitempty"`
+	// SubjectHash - SHA-256 от соли, ResourceID и Params. Они хранятся отдельно от журнала так же,
+	// как исполнитель, и удаляются вместе с данными пользователя, которого касается запись.
+	// Пуст у записей, добавленных до переноса ID ресурса из журнала.
+	SubjectHash string `db:"subject_hash"  json:"subject_hash,omitempty"`
+	// SubjectSalt - случайная соль SubjectHash, удаляется вместе с ID ресурса и параметрами.
+	SubjectSalt string `db:"-"             json:"-"`
+	// SubjectErased сообщает, что ID ресурса и параметры записи удалены вместе с данными пользователя.
+	SubjectErased bool `db:"-"             json:"subject_erased,omitempty"`
+	// PrevHash - хеш предыдущей записи; у первой записи пуст.
+	PrevHash string `db:"prev_hash"     json:"prev_hash"`
+	// Hash - SHA-256 от PrevHash и полей записи, кроме Seq.
+	Hash string `db:"hash"          json:"hash"`
+}
+
+// ComputeIdentityHash вычисляет IdentityHash записи по соли, исполнителю и IP-адресу.
+func (e AuditEntry) ComputeIdentityHash() string {
+	canonical, _ := json.Marshal(struct {
+		Salt     string     `json:"salt"`
+		ActorID  *uuid.UUID `json:"actor_id"`
+		SourceIP string     `json:"source_ip"`
+	}{
+		Salt:     e.IdentitySalt,
+		ActorID:  e.ActorID,
+		SourceIP: e.SourceIP,
+	})
+	sum := sha256.Sum256(canonical)
+	return hex.EncodeToString(sum[:])
+}
+
+// ComputeSubjectHash вычисляет SubjectHash записи по соли, ID ресурса и параметрам.
+func (e AuditEntry) ComputeSubjectHash() string {
+	canonical, _ := json.Marshal(struct {
+		Salt       string            `json:"salt"`
+		ResourceID string            `json:"resource_id"`
+		Params     map[string]string `json:"params"`
+	}{
+		Salt:       e.SubjectSalt,
+		ResourceID: e.ResourceID,
+		Params:     e.params(),
+	})
+	sum := sha256.Sum256(canonical)
+	return hex.EncodeToString(sum[:])
+}
+
+// params возвращает параметры записи; пустые параметры хранятся как пустой объект JSONB
+// и читаются непустой картой, поэтому в хешах они всегда nil.
+func (e AuditEntry) params() map[string]string {
+	if len(e.Params) == 0 {
+		return nil
+	}
+	return e.Params
+}
+
+// ComputeHash вычисляет хеш записи с учетом PrevHash. Время округляется до микросекунд,
+// с которыми его хранит Postgres, чтобы хеш совпадал при проверке прочитанной записи.
+// В хеш входят IdentityHash и SubjectHash, а у записей без них - сами исполнитель, IP-адрес,
+// ID ресурса и параметры.
+func (e AuditEntry) ComputeHash() string {
+	params := e.params()
+	occurredAt := e.OccurredAt.UTC().Truncate(time.Microsecond).Format(time.RFC3339Nano)
+
+	if e.SubjectHash != "" {
+		canonical, _ := json.Marshal(struct {
+			PrevHash     string         `json:"prev_hash"`
+			OccurredAt   string         `json:"occurred_at"`
+			ActorType    AuditActorType `json:"actor_type"`
+			IdentityHash string         `json:"identity_hash"`
+			Action       string         `json:"action"`
+			ResourceType string         `json:"resource_type"`
+			SubjectHash  string         `json:"subject_hash"`
+			RequestID    string         `json:"request_id"`
+			Outcome      AuditOutcome   `json:"outcome"`
+			Status       int            `json:"status"`
+		}{
+			PrevHash:     e.PrevHash,
+			OccurredAt:   occurredAt,
+			ActorType:    e.ActorType,
+			IdentityHash: e.IdentityHash,
+			Action:       e.Action,
+			ResourceType: e.ResourceType,
+			SubjectHash:  e.SubjectHash,
+			RequestID:    e.RequestID,
+			Outcome:      e.Outcome,
+			Status:       e.Status,
+		})
+		sum := sha256.Sum256(canonical)
+		return hex.EncodeToString(sum[:])
+	}
+
+	if e.IdentityHash != "" {
+		canonical, _ := json.Marshal(struct {
+			PrevHash     string            `json:"prev_hash"`
+			OccurredAt   string            `json:"occurred_at"`
+			ActorType    AuditActorType    `json:"actor_type"`
+			IdentityHash string            `json:"identity_hash"`
+			Action       string            `json:"action"`
+			ResourceType string            `json:"resource_type"`
+			ResourceID   string            `json:"resource_id"`
+			Params       map[string]string `json:"params"`
+			RequestID    string            `json:"request_id"`
+			Outcome      AuditOutcome      `json:"outcome"`
+			Status       int               `json:"status"`
+		}{
+			PrevHash:     e.PrevHash,
+			OccurredAt:   occurredAt,
+			ActorType:    e.ActorType,
+			IdentityHash: e.IdentityHash,
+			Action:       e.Action,
+			ResourceType: e.ResourceType,
+			ResourceID:   e.ResourceID,
+			Params:       params,
+			RequestID:    e.RequestID,
+			Outcome:      e.Outcome,
+			Status:       e.Status,
+		})
+		sum := sha256.Sum256(canonical)
+		return hex.EncodeToString(sum[:])
+	}
+
+	canonical, _ := json.Marshal(struct {
+		PrevHash     string            `json:"prev_hash"`
+		OccurredAt   string            `json:"occurred_at"`
+		ActorType    AuditActorType    `json:"actor_type"`
+		ActorID      *uuid.UUID        `json:"actor_id"`
+		Action       string            `json:"action"`
+		ResourceType string            `json:"resource_type"`
+		ResourceID   string            `json:"resource_id"`
+		Params       map[string]string `json:"params"`
+		RequestID    string            `json:"request_id"`
+		Outcome      AuditOutcome      `json:"outcome"`
+		Status       int               `json:"status"`
+		SourceIP     string            `json:"source_ip"`
+	}{
+		PrevHash:     e.PrevHash,
+		OccurredAt:   occurredAt,
+		ActorType:    e.ActorType,
+		ActorID:      e.ActorID,
+		Action:       e.Action,
+		ResourceType: e.ResourceType,
+		ResourceID:   e.ResourceID,
+		Params:       params,
+		RequestID:    e.RequestID,
+		Outcome:      e.Outcome,
+		Status:       e.Status,
+		SourceIP:     e.SourceIP,
+	})
+	sum := sha256.Sum256(canonical)
+	return hex.EncodeToString(sum[:])
+}
+
+// Verifiable сообщает, что хеш записи можно пересчитать. У записей, добавленных до переноса
+// исполнителя или ID ресурса из журнала, хеш вычислен от самих значений, и после их удаления
+// проверить можно только место записи в цепочке.
+func (e AuditEntry) Verifiable() bool {
+	return !(e.IdentityErased && e.IdentityHash == "") && !(e.SubjectErased && e.SubjectHash == "")
+}
+
+// AuditVerification - результат проверки цепочки хешей журнала аудита.
+type AuditVerification struct {
+	Valid bool `json:"valid"`
+	// Checked - число проверенных записей.
+	Checked int64 `json:"checked"`
+	// BrokenAt - номер первой записи, которая не сходится с цепочкой.
+	BrokenAt *int64 `json:"broken_at,omitempty"`
+	Reason   string `json:"reason,omitempty" example:"hash mismatch"`
+}
@@ -0,0 +1,95 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestAuditEntryComputeHash(t *testing.T) {
+	actorID := uuid.New()
+	entry := AuditEntry{
+		OccurredAt:   time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC),
+		ActorType:    AuditActorUser,
+		ActorID:      &actorID,
+		Action:       "DELETE /api/v1/subscriptions/:id",
+		ResourceType: "subscriptions",
+		ResourceID:   uuid.NewString(),
+		RequestID:    "req-1",
+		Outcome:      AuditOutcomeSuccess,
+		Status:       204,
+		SourceIP:     "192.0.2.1",
+		IdentitySalt: "salt",
+	}
+	entry.IdentityHash = entry.ComputeIdentityHash()
+	hash := entry.ComputeHash()
+
+	erased := entry
+	erased.ActorID, erased.SourceIP, erased.IdentitySalt = nil, "", ""
+	if got := erased.ComputeHash(); got != hash {
+		t.Errorf("ComputeHash() after erasing the identity = %s, want %s", got, hash)
+	}
+
+	otherSalt := entry
+	otherSalt.IdentitySalt = "other"
+	if otherSalt.ComputeIdentityHash() == entry.IdentityHash {
+		t.Error("ComputeIdentityHash() does not depend on the salt")
+	}
+
+	otherActor := entry
+	otherActorID := uuid.New()
+	otherActor.ActorID = &otherActorID
+	if otherActor.ComputeIdentityHash() == entry.IdentityHash {
+		t.Error("ComputeIdentityHash() does not depend on the actor")
+	}
+
+	legacy := entry
+	legacy.IdentityHash = ""
+	legacyErased := legacy
+	legacyErased.ActorID, legacyErased.SourceIP = nil, ""
+	if legacy.ComputeHash() == legacyErased.ComputeHash() {
+		t.Error("ComputeHash() of an entry without identity_hash does not depend on the actor and IP")
+	}
+}
+
+func TestAuditEntrySubjectHash(t *testing.T) {
+	userID := uuid.New()
+	entry := AuditEntry{
+		OccurredAt:   time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC),
+		ActorType:    AuditActorAPIKey,
+		Action:       "DELETE /api/v1/subscriptions/:id/members/:user_id",
+		ResourceType: "subscriptions",
+		ResourceID:   uuid.NewString(),
+		Params:       map[string]string{"user_id": userID.String()},
+		RequestID:    "req-2",
+		Outcome:      AuditOutcomeSuccess,
+		Status:       204,
+		IdentitySalt: "identity-salt",
+		SubjectSalt:  "subject-salt",
+	}
+	entry.IdentityHash = entry.ComputeIdentityHash()
+	entry.SubjectHash = entry.ComputeSubjectHash()
+	hash := entry.ComputeHash()
+
+	erased := entry
+	erased.ResourceID, erased.Params, erased.SubjectSalt, erased.SubjectErased = "", nil, "", true
+	if got := erased.ComputeHash(); got != hash {
+		t.Errorf("ComputeHash() after erasing the subject = %s, want %s", got, hash)
+	}
+	if !erased.Verifiable() {
+		t.Error("Verifiable() = false for an entry with subject_hash")
+	}
+
+	otherUser := entry
+	otherUser.Params = map[string]string{"user_id": uuid.NewString()}
+	if otherUser.ComputeSubjectHash() == entry.SubjectHash {
+		t.Error("ComputeSubjectHash() does not depend on the params")
+	}
+
+	legacy := entry
+	legacy.SubjectHash, legacy.ResourceID, legacy.Params, legacy.SubjectErased = "", "", nil, true
+	if legacy.Verifiable() {
+		t.Error("Verifiable() = true for an old entry with an erased subject")
+	}
+}
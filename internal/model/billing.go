@@ -0,0 +1,96 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BillingPeriod - периодичность списания оплаты за подписку.
+type BillingPeriod string
+
+const (
+	// BillingMonthly - цена списывается каждый месяц.
+	BillingMonthly BillingPeriod = "monthly"
+	// BillingYearly - цена списывается раз в год в месяц начала подписки.
+	BillingYearly BillingPeriod = "yearly"
+)
+
+// Valid сообщает, известна ли периодичность оплаты.
+func (p BillingPeriod) Valid() bool {
+	return p == BillingMonthly || p == BillingYearly
+}
+
+// ChargedIn сообщает, списывается ли оплата подписки в месяце month.
+// Для годовых подписок это месяцы продления - годовщины месяца начала.
+func (s Subscription) ChargedIn(month Date) bool {
+	if s.BillingPeriod == BillingYearly {
+		return month.Month() == s.StartDate.Month()
+	}
+	return true
+}
+
+// Proration - способ учета неполных периодов оплаты при расчете стоимости.
+type Proration string
+
+const (
+	// ProrationMonthly - неполный месяц оплачивается целиком.
+	ProrationMonthly Proration = "monthly"
+	// ProrationDaily - цена периода оплаты делится пропорционально оплачиваемым дням,
+	// попавшим в расчет. Учитывается фактическая длина месяца (28-31 день) и года (365-366 дней).
+	ProrationDaily Proration = "daily"
+)
+
+// Valid сообщает, известен ли способ учета неполных периодов.
+func (p Proration) Valid() bool {
+	return p == ProrationMonthly || p == ProrationDaily
+}
+
+// CostPeriod - период расчета стоимости: закрытый диапазон календарных дат [From, To].
+// Даты, переданные как моменты времени, сервис переводит в календарные дни в часовом поясе пользователя.
+type CostPeriod struct {
+	From      Date
+	To        Date
+	Proration Proration
+}
+
+// PriceChange - запланированное или уже вступившее в силу изменение цены подписки.
+// Новая цена действует начиная с месяца, в который попадает EffectiveDate.
+type PriceChange struct {
+	ID             uuid.UUID `db:"id"              json:"id"`
+	SubscriptionID uuid.UUID `db:"subscription_id" json:"subscription_id"`
+	EffectiveDate  Date      `db:"effective_date"  json:"effective_date" swaggertype:"string" format:"date" example:"2024-06-01"`
+	Price          Money     `db:"price"           json:"price" swaggertype:"string" example:"9.99"`
+	CreatedAt      time.Time `db:"created_at"      json:"created_at"`
+}
+
+// CostSummary - суммарная стоимость подписок пользователя за период.
+// Currency пуста, если за период нет ни одной подписки.
+type CostSummary struct {
+	TotalCost Money          `json:"total_cost" swaggertype:"string" example:"9.99"`
+	Currency  Currency       `json:"currency,omitempty"`
+	Breakdown []CostGroup    `json:"breakdown,omitempty"`
+	LineItems []CostLineItem `json:"line_items,omitempty"`
+}
+
+// Forecast - прогноз расходов пользователя по месяцам.
+type Forecast struct {
+	Months    []ForecastMonth `json:"months"`
+	TotalCost Money           `json:"total_cost" swaggertype:"string" example:"9.99"`
+	Currency  Currency        `json:"currency,omitempty"`
+}
+
+// ForecastMonth - прогноз расходов за один месяц.
+type ForecastMonth struct {
+	Month     Date  `json:"month" swaggertype:"string" format:"date" example:"2024-01-01"`
+	TotalCost Money `json:"total_cost" swaggertype:"string" example:"9.99"`
+	// Renewals - годовые подписки, продление которых приходится на этот месяц.
+	Renewals []ForecastRenewal `json:"renewals"`
+}
+
+// ForecastRenewal - продление годовой подписки.
+type ForecastRenewal struct {
+	SubscriptionID uuid.UUID `json:"subscription_id"`
+	ServiceName    string    `json:"service_name"`
+	Cost           Money     `json:"cost" swaggertype:"string" example:"9.99"`
+}
@@ -0,0 +1,78 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EventBudgetThresholdCrossed - прогноз расходов за месяц достиг порога бюджета.
+const EventBudgetThresholdCrossed = "budget.threshold_crossed"
+
+// DefaultBudgetThresholds - пороги оповещения в процентах от лимита, если они не заданы.
+var DefaultBudgetThresholds = []int{80, 100}
+
+// Budget - месячный лимит расходов пользователя. Без категории и сервиса бюджет общий,
+// иначе учитываются только подписки указанной категории или сервиса каталога.
+// В расходы по бюджету входят только подписки в валюте бюджета.
+type Budget struct {
+	ID         uuid.UUID  `db:"id"          json:"id"`
+	UserID     uuid.UUID  `db:"user_id"     json:"user_id"`
+	CategoryID *uuid.UUID `db:"category_id" json:"category_id,omitempty"`
+	ServiceID  *uuid.UUID `db:"service_id"  json:"service_id,omitempty"`
+	Amount     Money      `db:"amount"      json:"amount" swaggertype:"string" example:"100.00"`
+	Currency   Currency   `db:"currency"    json:"currency"`
+	// Thresholds - пороги оповещения в процентах от Amount по возрастанию.
+	Thresholds []int     `db:"thresholds"  json:"thresholds"`
+	CreatedAt  time.Time `db:"created_at"  json:"created_at"`
+	UpdatedAt  time.Time `db:"updated_at"  json:"updated_at"`
+}
+
+// BudgetStatus - состояние бюджета за месяц.
+// Spent - прогноз расходов за весь месяц по правилам расчета суммарной стоимости.
+type BudgetStatus struct {
+	Budget    Budget `json:"budget"`
+	Period    Date   `json:"period" swaggertype:"string" format:"date" example:"2024-01-01"`
+	Spent     Money  `json:"spent" swaggertype:"string" example:"9.99"`
+	Remaining Money  `json:"remaining" swaggertype:"string" example:"90.01"`
+	Percent   int    `json:"percent"`
+	// Crossed - достигнутые пороги.
+	Crossed []int `json:"crossed"`
+}
+
+// NewBudgetStatus вычисляет состояние бюджета по сумме расходов за месяц period.
+func NewBudgetStatus(b Budget, period Date, spent Money) (BudgetStatus, error) {
+	remaining, err := b.Amount.Sub(spent)
+	if err != nil {
+		return BudgetStatus{}, err
+	}
+
+	status := BudgetStatus{
+		Budget:    b,
+		Period:    period,
+		Spent:     spent,
+		Remaining: remaining,
+		Percent:   spent.PercentOf(b.Amount),
+		Crossed:   []int{},
+	}
+
+	// Порог в целых процентах достигнут тогда же, когда его достигает целая часть доли расходов.
+	for _, threshold := range b.Thresholds {
+		if status.Percent >= threshold {
+			status.Crossed = append(status.Crossed, threshold)
+		}
+	}
+
+	return status, nil
+}
+
+// BudgetAlert - оповещение о достижении порога бюджета, публикуемое как доменное событие.
+type BudgetAlert struct {
+	BudgetID  uuid.UUID `json:"budget_id"`
+	UserID    uuid.UUID `json:"user_id"`
+	Period    Date      `json:"period" swaggertype:"string" format:"date" example:"2024-01-01"`
+	Threshold int       `json:"threshold"`
+	Amount    Money     `json:"amount" swaggertype:"string" example:"100.00"`
+	Spent     Money     `json:"spent" swaggertype:"string" example:"9.99"`
+	Currency  Currency  `json:"currency"`
+}
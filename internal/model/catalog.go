@@ -0,0 +1,35 @@
+package model
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CatalogService - запись каталога сервисов с каноническим названием и известными тарифами.
+type CatalogService struct {
+	ID        uuid.UUID     `db:"id"         json:"id"`
+	Name      string        `db:"name"       json:"name"`
+	Aliases   []string      `db:"-"          json:"aliases"`
+	Category  string        `db:"category"   json:"category,omitempty"`
+	LogoURL   string        `db:"logo_url"   json:"logo_url,omitempty"`
+	Plans     []ServicePlan `db:"-"          json:"plans"`
+	CreatedAt time.Time     `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time     `db:"updated_at" json:"updated_at"`
+}
+
+// ServicePlan - тариф сервиса с ценой по умолчанию.
+type ServicePlan struct {
+	ID        uuid.UUID `db:"id"         json:"id"`
+	ServiceID uuid.UUID `db:"service_id" json:"service_id"`
+	Name      string    `db:"name"       json:"name"`
+	Price     Money     `db:"price"      json:"price" swaggertype:"string" example:"9.99"`
+	Currency  Currency  `db:"currency"   json:"currency"`
+}
+
+// NormalizeServiceName приводит название сервиса к виду для сравнения:
+// нижний регистр и одиночные пробелы без пробелов по краям.
+func NormalizeServiceName(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
@@ -0,0 +1,39 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Category - пользовательская категория подписок (например, "Развлечения" или "Работа").
+type Category struct {
+	ID        uuid.UUID `db:"id"         json:"id"`
+	UserID    uuid.UUID `db:"user_id"    json:"user_id"`
+	Name      string    `db:"name"       json:"name"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
+// Tag - произвольная пользовательская метка подписки.
+type Tag struct {
+	ID        uuid.UUID `db:"id"         json:"id"`
+	UserID    uuid.UUID `db:"user_id"    json:"user_id"`
+	Name      string    `db:"name"       json:"name"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
+// Группировки суммарной стоимости.
+const (
+	GroupByCategory = "category"
+	GroupByTag      = "tag"
+	// GroupByMember группирует подписки по пользователям, которые ими управляют.
+	GroupByMember = "member"
+)
+
+// CostGroup - стоимость подписок, относящихся к одной категории или метке.
+// Key пуст для подписок без категории (метки).
+type CostGroup struct {
+	Key       string `json:"key"`
+	Name      string `json:"name"`
+	TotalCost Money  `json:"total_cost" swaggertype:"string" example:"9.99"`
+}
@@ -0,0 +1,204 @@
+package model
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// ErrDateUnresolved возвращается при сохранении даты, переданной как момент времени,
+// до того, как для нее определен календарный день в часовом поясе пользователя.
+var ErrDateUnresolved = errors.New("date is not resolved to a calendar day")
+
+// Date - календарная дата без времени суток и часового пояса. Так хранятся все даты подписок
+// (колонки DATE), а в JSON они передаются строкой YYYY-MM-DD.
+//
+// В запросе дату можно передать и как момент времени (RFC 3339). Такая дата остается
+// неразрешенной, пока Resolve не определит ее календарный день в часовом поясе пользователя.
+// Нулевое значение - 0001-01-01.
+type Date struct {
+	// day - полночь UTC календарного дня.
+	day time.Time
+	// moment - момент времени из запроса, для которого еще не определен календарный день.
+	moment time.Time
+}
+
+// NewDate возвращает календарную дату. Значения вне диапазона нормализуются, как в time.Date:
+// NewDate(2024, time.February, 30) - это 1 марта.
+func NewDate(year int, month time.Month, day int) Date {
+	return Date{day: time.Date(year, month, day, 0, 0, 0, 0, time.UTC)}
+}
+
+// DateOf возвращает календарный день, на который приходится момент t в часовом поясе loc.
+func DateOf(t time.Time, loc *time.Location) Date {
+	local := t.In(loc)
+	return NewDate(local.Year(), local.Month(), local.Day())
+}
+
+// MomentDate возвращает дату, переданную как момент времени t. Календарный день
+// определяется позже вызовом Resolve в часовом поясе пользователя.
+func MomentDate(t time.Time) Date {
+	return Date{moment: t}
+}
+
+// ParseDate разбирает дату в формате YYYY-MM-DD.
+func ParseDate(s string) (Date, error) {
+	t, err := time.Parse(time.DateOnly, s)
+	if err != nil {
+		return Date{}, fmt.Errorf("invalid date %q, use YYYY-MM-DD", s)
+	}
+	return NewDate(t.Year(), t.Month(), t.Day()), nil
+}
+
+// Resolve определяет календарный день даты, переданной как момент времени, в часовом поясе loc.
+// Календарные даты возвращаются без изменений.
+func (d Date) Resolve(loc *time.Location) Date {
+	if d.moment.IsZero() {
+		return d
+	}
+	return DateOf(d.moment, loc)
+}
+
+// Resolved сообщает, известен ли календарный день даты.
+func (d Date) Resolved() bool {
+	return d.moment.IsZero()
+}
+
+// Year возвращает год даты.
+func (d Date) Year() int { return d.day.Year() }
+
+// Month возвращает месяц даты.
+func (d Date) Month() time.Month { return d.day.Month() }
+
+// Day возвращает день месяца.
+func (d Date) Day() int { return d.day.Day() }
+
+// IsZero сообщает, является ли дата нулевым значением.
+func (d Date) IsZero() bool {
+	return d.Resolved() && d.day.IsZero()
+}
+
+// Before сообщает, предшествует ли d дате o.
+func (d Date) Before(o Date) bool { return d.day.Before(o.day) }
+
+// After сообщает, следует ли d за датой o.
+func (d Date) After(o Date) bool { return d.day.After(o.day) }
+
+// Equal сообщает, совпадают ли даты.
+func (d Date) Equal(o Date) bool { return d.day.Equal(o.day) }
+
+// Compare возвращает -1, 0 или 1, если d раньше, совпадает или позже o.
+func (d Date) Compare(o Date) int { return d.day.Compare(o.day) }
+
+// AddDate прибавляет к дате годы, месяцы и дни с нормализацией, как time.Time.AddDate.
+func (d Date) AddDate(years, months, days int) Date {
+	return Date{day: d.day.AddDate(years, months, days)}
+}
+
+// Sub возвращает число дней от o до d.
+func (d Date) Sub(o Date) int {
+	return int(d.day.Sub(o.day).Hours() / 24)
+}
+
+// StartOfMonth возвращает первый день месяца даты.
+func (d Date) StartOfMonth() Date {
+	return NewDate(d.Year(), d.Month(), 1)
+}
+
+// EndOfMonth возвращает последний день месяца даты.
+func (d Date) EndOfMonth() Date {
+	return NewDate(d.Year(), d.Month()+1, 0)
+}
+
+// In возвращает начало календарного дня в часовом поясе loc. Если полночь в этот день
+// пропущена при переходе на летнее время, началом дня считается момент перехода.
+func (d Date) In(loc *time.Location) time.Time {
+	t := time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, loc)
+	if DateOf(t, loc).Before(d) {
+		// time.Date вернул момент до перехода, то есть конец предыдущего дня.
+		_, t = t.ZoneBounds()
+	}
+	return t
+}
+
+// Time возвращает полночь UTC календарного дня.
+func (d Date) Time() time.Time {
+	return d.day
+}
+
+// String возвращает дату в формате YYYY-MM-DD.
+func (d Date) String() string {
+	if !d.Resolved() {
+		return d.moment.Format(time.RFC3339)
+	}
+	return d.day.Format(time.DateOnly)
+}
+
+// MarshalJSON кодирует дату строкой YYYY-MM-DD.
+func (d Date) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(d.String())), nil
+}
+
+// UnmarshalJSON принимает дату строкой YYYY-MM-DD или моментом времени в формате RFC 3339.
+func (d *Date) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		return nil
+	}
+	unquoted, err := strconv.Unquote(s)
+	if err != nil {
+		return fmt.Errorf("date must be a string, got %s", s)
+	}
+
+	parsed, err := ParseDateOrMoment(unquoted)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// ParseDateOrMoment разбирает календарную дату YYYY-MM-DD или момент времени в формате RFC 3339.
+func ParseDateOrMoment(s string) (Date, error) {
+	if len(s) == len(time.DateOnly) {
+		return ParseDate(s)
+	}
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return Date{}, fmt.Errorf("invalid date %q, use YYYY-MM-DD or RFC 3339", s)
+	}
+	return MomentDate(t), nil
+}
+
+// Scan читает дату из колонки DATE.
+func (d *Date) Scan(src any) error {
+	switch v := src.(type) {
+	case time.Time:
+		*d = NewDate(v.Year(), v.Month(), v.Day())
+		return nil
+	case string:
+		return d.scanString(v)
+	case []byte:
+		return d.scanString(string(v))
+	}
+	return fmt.Errorf("cannot scan %T into Date", src)
+}
+
+func (d *Date) scanString(s string) error {
+	parsed, err := ParseDate(s)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// Value передает дату в базу строкой YYYY-MM-DD. Неразрешенную дату сохранить нельзя.
+func (d Date) Value() (driver.Value, error) {
+	if !d.Resolved() {
+		return nil, ErrDateUnresolved
+	}
+	return d.String(), nil
+}
@@ -0,0 +1,139 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func loadLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatalf("LoadLocation(%q) error = %v", name, err)
+	}
+	return loc
+}
+
+func TestDateResolve(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		zone  string
+		want  string
+	}{
+		{name: "calendar date ignores the zone", input: "2024-01-31", zone: "Asia/Vladivostok", want: "2024-01-31"},
+		{name: "moment in UTC", input: "2024-01-31T23:30:00Z", zone: "UTC", want: "2024-01-31"},
+		{name: "moment is the next day east of UTC", input: "2024-01-31T23:30:00-05:00", zone: "Asia/Vladivostok", want: "2024-02-01"},
+		{name: "moment is the previous day west of UTC", input: "2024-02-01T02:00:00Z", zone: "America/New_York", want: "2024-01-31"},
+		{name: "before spring forward", input: "2024-03-10T04:30:00Z", zone: "America/New_York", want: "2024-03-09"},
+		{name: "night of spring forward", input: "2024-03-10T06:30:00Z", zone: "America/New_York", want: "2024-03-10"},
+		{name: "first 01:30 of fall back", input: "2024-11-03T05:30:00Z", zone: "America/New_York", want: "2024-11-03"},
+		{name: "second 01:30 of fall back", input: "2024-11-03T06:30:00Z", zone: "America/New_York", want: "2024-11-03"},
+		{name: "last hour of the 25-hour day", input: "2024-11-04T04:30:00Z", zone: "America/New_York", want: "2024-11-03"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := ParseDateOrMoment(tt.input)
+			if err != nil {
+				t.Fatalf("ParseDateOrMoment(%q) error = %v", tt.input, err)
+			}
+			got := d.Resolve(loadLocation(t, tt.zone))
+			if !got.Resolved() {
+				t.Fatalf("Resolve() left the date unresolved")
+			}
+			if got.String() != tt.want {
+				t.Errorf("Resolve(%s) = %s, want %s", tt.zone, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDateIn(t *testing.T) {
+	tests := []struct {
+		name string
+		date Date
+		zone string
+		want string
+		// hours - длина суток в часах.
+		hours float64
+	}{
+		{name: "ordinary day", date: NewDate(2024, time.January, 15), zone: "America/New_York", want: "2024-01-15T05:00:00Z", hours: 24},
+		{name: "spring forward day is 23 hours", date: NewDate(2024, time.March, 10), zone: "America/New_York", want: "2024-03-10T05:00:00Z", hours: 23},
+		{name: "fall back day is 25 hours", date: NewDate(2024, time.November, 3), zone: "America/New_York", want: "2024-11-03T04:00:00Z", hours: 25},
+		{name: "skipped midnight starts the day at the transition", date: NewDate(2024, time.September, 8), zone: "America/Santiago", want: "2024-09-08T04:00:00Z", hours: 23},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loc := loadLocation(t, tt.zone)
+			start := tt.date.In(loc)
+			if got := start.UTC().Format(time.RFC3339); got != tt.want {
+				t.Errorf("In(%s) = %s, want %s", tt.zone, got, tt.want)
+			}
+			if got := DateOf(start, loc); !got.Equal(tt.date) {
+				t.Errorf("DateOf(In()) = %s, want %s", got, tt.date)
+			}
+			if got := tt.date.AddDate(0, 0, 1).In(loc).Sub(start).Hours(); got != tt.hours {
+				t.Errorf("day length = %vh, want %vh", got, tt.hours)
+			}
+		})
+	}
+}
+
+func TestDateMonths(t *testing.T) {
+	d := NewDate(2024, time.February, 10)
+	if got := d.StartOfMonth().String(); got != "2024-02-01" {
+		t.Errorf("StartOfMonth() = %s, want 2024-02-01", got)
+	}
+	if got := d.EndOfMonth().String(); got != "2024-02-29" {
+		t.Errorf("EndOfMonth() = %s, want 2024-02-29", got)
+	}
+	if got := NewDate(2024, time.March, 31).Sub(NewDate(2024, time.March, 1)); got != 30 {
+		t.Errorf("Sub() across spring forward = %d, want 30", got)
+	}
+}
+
+func TestDateJSON(t *testing.T) {
+	var v struct {
+		Date *Date `json:"date"`
+	}
+
+	if err := json.Unmarshal([]byte(`{"date":"2024-02-29"}`), &v); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	out, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if string(out) != `{"date":"2024-02-29"}` {
+		t.Errorf("Marshal() = %s, want the input back", out)
+	}
+
+	for _, input := range []string{`{"date":"2024-02-30"}`, `{"date":"29.02.2024"}`, `{"date":20240229}`} {
+		if err := json.Unmarshal([]byte(input), &v); err == nil {
+			t.Errorf("Unmarshal(%s) error = nil, want an error", input)
+		}
+	}
+}
+
+func TestDateDatabase(t *testing.T) {
+	var d Date
+	if err := d.Scan(time.Date(2024, time.March, 10, 0, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	value, err := d.Value()
+	if err != nil {
+		t.Fatalf("Value() error = %v", err)
+	}
+	if value != "2024-03-10" {
+		t.Errorf("Value() = %v, want 2024-03-10", value)
+	}
+
+	moment := MomentDate(time.Date(2024, time.March, 10, 6, 30, 0, 0, time.UTC))
+	if _, err := moment.Value(); !errors.Is(err, ErrDateUnresolved) {
+		t.Errorf("Value() of an unresolved date error = %v, want %v", err, ErrDateUnresolved)
+	}
+}
@@ -0,0 +1,89 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DiscountKind - способ расчета скидки.
+type DiscountKind string
+
+const (
+	// DiscountPercent - скидка в процентах от цены.
+	DiscountPercent DiscountKind = "percent"
+	// DiscountFixed - скидка фиксированной суммой.
+	DiscountFixed DiscountKind = "fixed"
+)
+
+// Discount - скидка, промо-период или купон подписки.
+// Скидка действует первые Cycles оплачиваемых периодов подписки и (или) в месяцы
+// диапазона [StartDate, EndDate]. Без ограничений скидка бессрочная.
+type Discount struct {
+	ID             uuid.UUID    `db:"id"              json:"id"`
+	SubscriptionID uuid.UUID    `db:"subscription_id" json:"subscription_id"`
+	Kind           DiscountKind `db:"kind"            json:"kind"`
+	// Value - процент (целое число от 1 до 100) для percent или сумма в валюте подписки для fixed.
+	Value       Money     `db:"value"           json:"value" swaggertype:"string" example:"10"`
+	Cycles      *int      `db:"cycles"          json:"cycles,omitempty"`
+	StartDate   *Date     `db:"start_date"      json:"start_date,omitempty" swaggertype:"string" format:"date"`
+	EndDate     *Date     `db:"end_date"        json:"end_date,omitempty" swaggertype:"string" format:"date"`
+	Description string    `db:"description"     json:"description"`
+	CreatedAt   time.Time `db:"created_at"      json:"created_at"`
+}
+
+// ActiveIn сообщает, действует ли скидка для списания в месяце month, которое является
+// cycle-м по счету (с единицы) оплачиваемым периодом подписки.
+func (d Discount) ActiveIn(month Date, cycle int) bool {
+	if d.Cycles != nil && cycle > *d.Cycles {
+		return false
+	}
+	if d.StartDate != nil && d.StartDate.After(month.EndOfMonth()) {
+		return false
+	}
+	if d.EndDate != nil && d.EndDate.Before(month) {
+		return false
+	}
+	return true
+}
+
+// Apply возвращает цену после применения скидки. Цена не бывает отрицательной.
+// Процентная скидка округляется до минимальных единиц цены по правилу "половина от нуля".
+func (d Discount) Apply(price Money) (Money, error) {
+	switch d.Kind {
+	case DiscountPercent:
+		discount, err := price.MulDivRound(d.Value.Minor(), 100)
+		if err != nil {
+			return Money{}, err
+		}
+		return price.Sub(discount)
+	case DiscountFixed:
+		discounted, err := price.Sub(d.Value)
+		if err != nil {
+			return Money{}, err
+		}
+		if discounted.Sign() < 0 {
+			return price.Sub(price)
+		}
+		return discounted, nil
+	}
+	return price, nil
+}
+
+// CostLineItem - списание за подписку в одном месяце с пояснением расчета.
+type CostLineItem struct {
+	Month          Date      `json:"month" swaggertype:"string" format:"date" example:"2024-01-01"`
+	SubscriptionID uuid.UUID `json:"subscription_id"`
+	ServiceName    string    `json:"service_name"`
+	// BasePrice - цена до скидки, Price - после.
+	BasePrice Money `json:"base_price" swaggertype:"string" example:"9.99"`
+	Price     Money `json:"price" swaggertype:"string" example:"9.99"`
+	// Discount - примененная скидка, если была.
+	Discount *Discount `json:"discount,omitempty"`
+	// Days и PeriodDays - оплачиваемые дни периода, попавшие в расчет, и длина периода
+	// при посуточном расчете. Price уже уменьшена пропорционально Days.
+	Days       int `json:"days,omitempty"`
+	PeriodDays int `json:"period_days,omitempty"`
+	// Cost - часть цены, приходящаяся на пользователя.
+	Cost Money `json:"cost" swaggertype:"string" example:"9.99"`
+}
@@ -0,0 +1,32 @@
+package model
+
+import (
+	"github.com/google/uuid"
+)
+
+// Варианты объединения пересекающихся подписок.
+const (
+	// MergeDelete - подписки совпадают, лишнюю можно удалить.
+	MergeDelete = "delete"
+	// MergeSetEndDate - у более ранней подписки не указана или поздно указана дата окончания.
+	MergeSetEndDate = "set_end_date"
+)
+
+// SubscriptionOverlap - пара подписок пользователя на один сервис с пересекающимися периодами.
+// First начинается не позже Second. OverlapEnd не задан, если пересечение бессрочное.
+type SubscriptionOverlap struct {
+	ServiceName  string          `json:"service_name"`
+	First        Subscription    `json:"first"`
+	Second       Subscription    `json:"second"`
+	OverlapStart Date            `json:"overlap_start" swaggertype:"string" format:"date"`
+	OverlapEnd   *Date           `json:"overlap_end,omitempty" swaggertype:"string" format:"date"`
+	Suggestion   MergeSuggestion `json:"suggestion"`
+}
+
+// MergeSuggestion - предлагаемое исправление пересечения.
+type MergeSuggestion struct {
+	// Action - delete или set_end_date.
+	Action         string    `json:"action"`
+	SubscriptionID uuid.UUID `json:"subscription_id"`
+	EndDate        *Date     `json:"end_date,omitempty" swaggertype:"string" format:"date"`
+}
@@ -0,0 +1,63 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Типы доменных событий по подпискам.
+const (
+	EventSubscriptionCreated = "subscription.created"
+	EventSubscriptionUpdated = "subscription.updated"
+	EventSubscriptionDeleted = "subscription.deleted"
+	EventStatusChanged       = "subscription.status_changed"
+	EventMemberAdded         = "subscription.member_added"
+	EventMemberRemoved       = "subscription.member_removed"
+	EventPriceChanged        = "subscription.price_changed"
+)
+
+// Event представляет доменное событие, сохраненное в outbox.
+// ID уникален для события и используется потребителями для дедупликации.
+type Event struct {
+	ID          uuid.UUID       `json:"id"`
+	AggregateID uuid.UUID       `json:"aggregate_id"`
+	Type        string          `json:"type"`
+	Payload     json.RawMessage `json:"payload"`
+	CreatedAt   time.Time       `json:"created_at"`
+}
+
+// Типы изменений подписки в потоке /subscriptions/stream.
+const (
+	ChangeCreated = "created"
+	ChangeUpdated = "updated"
+	ChangeDeleted = "deleted"
+)
+
+// SubscriptionChange - изменение подписки, доставляемое клиентам потока событий.
+// Seq - номер события в outbox, одинаковый на всех репликах; он служит идентификатором события SSE.
+type SubscriptionChange struct {
+	Seq  int64  `json:"-"`
+	Type string `json:"type" enums:"created,updated,deleted"`
+	// Event - тип доменного события, из которого получено изменение, например subscription.status_changed.
+	Event          string    `json:"event"`
+	SubscriptionID uuid.UUID `json:"subscription_id"`
+	UserID         uuid.UUID `json:"user_id"`
+	// Subscription - подписка после изменения; для удаленной подписки - ее последнее состояние.
+	Subscription Subscription `json:"subscription"`
+	OccurredAt   time.Time    `json:"occurred_at"`
+}
+
+// ChangeType возвращает тип изменения подписки для доменного события eventType.
+// Смена статуса считается обновлением подписки.
+func ChangeType(eventType string) string {
+	switch eventType {
+	case EventSubscriptionCreated:
+		return ChangeCreated
+	case EventSubscriptionDeleted:
+		return ChangeDeleted
+	default:
+		return ChangeUpdated
+	}
+}
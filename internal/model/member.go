@@ -0,0 +1,39 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ShareType определяет, как участник совместной подписки оплачивает свою долю.
+type ShareType string
+
+const (
+	// ShareWeight - доля пропорциональна весу участника. Владелец по умолчанию имеет вес 1.
+	ShareWeight ShareType = "weight"
+	// ShareFixed - участник платит фиксированную сумму в месяц.
+	ShareFixed ShareType = "fixed"
+)
+
+// SubscriptionMember - участник совместной (семейной) подписки.
+// Участие действует с JoinedAt по LeftAt включительно.
+type SubscriptionMember struct {
+	ID             uuid.UUID `db:"id"              json:"id"`
+	SubscriptionID uuid.UUID `db:"subscription_id" json:"subscription_id"`
+	UserID         uuid.UUID `db:"user_id"         json:"user_id"`
+	ShareType      ShareType `db:"share_type"      json:"share_type"`
+	Weight         int       `db:"weight"          json:"weight,omitempty"`
+	Amount         Money     `db:"amount"          json:"amount" swaggertype:"string" example:"9.99"`
+	JoinedAt       Date      `db:"joined_at"       json:"joined_at" swaggertype:"string" format:"date"`
+	LeftAt         *Date     `db:"left_at"         json:"left_at,omitempty" swaggertype:"string" format:"date"`
+	CreatedAt      time.Time `db:"created_at"      json:"created_at"`
+}
+
+// ActiveBetween сообщает, состоял ли участник в подписке хотя бы один день в периоде [from, to].
+func (m SubscriptionMember) ActiveBetween(from, to Date) bool {
+	if m.JoinedAt.After(to) {
+		return false
+	}
+	return m.LeftAt == nil || !m.LeftAt.Before(from)
+}
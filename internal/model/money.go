@@ -0,0 +1,317 @@
+package model
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// ErrMoneyOverflow возвращается, если результат операции не помещается в int64 минимальных единиц.
+var ErrMoneyOverflow = errors.New("money amount overflow")
+
+// ErrMoneyPrecision возвращается, если сумма содержит больше знаков после запятой, чем допускает валюта.
+var ErrMoneyPrecision = errors.New("money amount has too many decimal places")
+
+// maxMoneyScale ограничивает число знаков после запятой в разбираемых суммах.
+const maxMoneyScale = 6
+
+// DefaultCurrency - валюта сумм, для которых она не указана явно.
+const DefaultCurrency Currency = "RUB"
+
+// Currency - код валюты ISO 4217.
+type Currency string
+
+// currencyExponents - валюты, число знаков после запятой у которых отличается от двух.
+var currencyExponents = map[Currency]int{
+	"BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "ISK": 0, "JPY": 0, "KMF": 0, "KRW": 0,
+	"PYG": 0, "RWF": 0, "UGX": 0, "VND": 0, "VUV": 0, "XAF": 0, "XOF": 0, "XPF": 0,
+	"BHD": 3, "IQD": 3, "JOD": 3, "KWD": 3, "LYD": 3, "OMR": 3, "TND": 3,
+}
+
+// Valid сообщает, похож ли код на код валюты ISO 4217: три заглавные латинские буквы.
+func (c Currency) Valid() bool {
+	if len(c) != 3 {
+		return false
+	}
+	for _, r := range c {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// Exponent возвращает число знаков после запятой в суммах валюты (2 для большинства валют).
+func (c Currency) Exponent() int {
+	if exp, ok := currencyExponents[c]; ok {
+		return exp
+	}
+	return 2
+}
+
+// Money - денежная сумма, хранимая в минимальных единицах: minor / 10^scale.
+// Валюта суммы задается сущностью, которой она принадлежит, и определяет scale.
+// Нулевое значение - ноль. Арифметика проверяет переполнение и не теряет точность:
+// суммы с разным scale приводятся к большему из них.
+// В JSON сумма кодируется десятичной строкой ("9.99"), в базе хранится как NUMERIC.
+type Money struct {
+	minor int64
+	scale int
+}
+
+// NewMoney создает сумму из минимальных единиц: NewMoney(999, 2) - это 9.99.
+func NewMoney(minor int64, scale int) Money {
+	return Money{minor: minor, scale: scale}
+}
+
+// ParseMoney разбирает десятичную запись суммы ("9.99", "-10", "100.5").
+// Число знаков после запятой в записи становится scale суммы.
+func ParseMoney(s string) (Money, error) {
+	s = strings.TrimSpace(s)
+	intPart, fracPart, hasDot := strings.Cut(s, ".")
+
+	sign := ""
+	if strings.HasPrefix(intPart, "-") || strings.HasPrefix(intPart, "+") {
+		sign, intPart = intPart[:1], intPart[1:]
+	}
+
+	if (intPart == "" && fracPart == "") || (hasDot && fracPart == "") || !isDigits(intPart) || !isDigits(fracPart) {
+		return Money{}, fmt.Errorf("invalid money amount %q", s)
+	}
+	if len(fracPart) > maxMoneyScale {
+		return Money{}, fmt.Errorf("%w: %q", ErrMoneyPrecision, s)
+	}
+
+	minor, err := strconv.ParseInt(sign+intPart+fracPart, 10, 64)
+	if err != nil {
+		if errors.Is(err, strconv.ErrRange) {
+			return Money{}, fmt.Errorf("%w: %q", ErrMoneyOverflow, s)
+		}
+		return Money{}, fmt.Errorf("invalid money amount %q", s)
+	}
+
+	return Money{minor: minor, scale: len(fracPart)}, nil
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Minor возвращает сумму в минимальных единицах.
+func (m Money) Minor() int64 {
+	return m.minor
+}
+
+// Scale возвращает число знаков после запятой.
+func (m Money) Scale() int {
+	return m.scale
+}
+
+// IsZero сообщает, равна ли сумма нулю.
+func (m Money) IsZero() bool {
+	return m.minor == 0
+}
+
+// Sign возвращает -1, 0 или 1 в зависимости от знака суммы.
+func (m Money) Sign() int {
+	switch {
+	case m.minor < 0:
+		return -1
+	case m.minor > 0:
+		return 1
+	}
+	return 0
+}
+
+// String возвращает десятичную запись суммы с scale знаками после запятой.
+func (m Money) String() string {
+	digits := strconv.FormatInt(m.minor, 10)
+	sign := ""
+	if m.minor < 0 {
+		sign, digits = "-", digits[1:]
+	}
+	if m.scale == 0 {
+		return sign + digits
+	}
+	if len(digits) <= m.scale {
+		digits = strings.Repeat("0", m.scale-len(digits)+1) + digits
+	}
+	return sign + digits[:len(digits)-m.scale] + "." + digits[len(digits)-m.scale:]
+}
+
+// Cmp сравнивает суммы: -1, если m < o, 0, если равны, 1, если m > o.
+func (m Money) Cmp(o Money) int {
+	return m.big(max(m.scale, o.scale)).Cmp(o.big(max(m.scale, o.scale)))
+}
+
+// Add возвращает m + o.
+func (m Money) Add(o Money) (Money, error) {
+	scale := max(m.scale, o.scale)
+	return fromBig(new(big.Int).Add(m.big(scale), o.big(scale)), scale)
+}
+
+// Sub возвращает m - o.
+func (m Money) Sub(o Money) (Money, error) {
+	scale := max(m.scale, o.scale)
+	return fromBig(new(big.Int).Sub(m.big(scale), o.big(scale)), scale)
+}
+
+// Mul возвращает m * n.
+func (m Money) Mul(n int64) (Money, error) {
+	return fromBig(new(big.Int).Mul(big.NewInt(m.minor), big.NewInt(n)), m.scale)
+}
+
+// MulDiv возвращает m * num / den с отбрасыванием остатка в минимальных единицах (к нулю).
+// Используется при делении суммы на доли, когда остаток распределяется отдельно.
+func (m Money) MulDiv(num, den int64) (Money, error) {
+	if den == 0 {
+		return Money{}, errors.New("money division by zero")
+	}
+	product := new(big.Int).Mul(big.NewInt(m.minor), big.NewInt(num))
+	return fromBig(product.Quo(product, big.NewInt(den)), m.scale)
+}
+
+// MulDivRound возвращает m * num / den, округленное до минимальных единиц по правилу
+// "половина от нуля" (коммерческое округление): 0.005 -> 0.01, -0.005 -> -0.01.
+func (m Money) MulDivRound(num, den int64) (Money, error) {
+	if den == 0 {
+		return Money{}, errors.New("money division by zero")
+	}
+	product := new(big.Int).Mul(big.NewInt(m.minor), big.NewInt(num))
+	return fromBig(quoRoundHalfAway(product, big.NewInt(den)), m.scale)
+}
+
+// Rescale приводит сумму к scale знакам после запятой без потери точности.
+// Если отбрасываемые знаки не нулевые, возвращается ErrMoneyPrecision.
+func (m Money) Rescale(scale int) (Money, error) {
+	if scale >= m.scale {
+		return fromBig(m.big(scale), scale)
+	}
+	divisor := pow10(m.scale - scale)
+	q, r := new(big.Int).QuoRem(big.NewInt(m.minor), divisor, new(big.Int))
+	if r.Sign() != 0 {
+		return Money{}, fmt.Errorf("%w: %s", ErrMoneyPrecision, m)
+	}
+	return fromBig(q, scale)
+}
+
+// Round округляет сумму до scale знаков после запятой по правилу "половина от нуля".
+func (m Money) Round(scale int) (Money, error) {
+	if scale >= m.scale {
+		return m.Rescale(scale)
+	}
+	return fromBig(quoRoundHalfAway(big.NewInt(m.minor), pow10(m.scale-scale)), scale)
+}
+
+// PercentOf возвращает долю m от total в целых процентах с отбрасыванием дробной части.
+func (m Money) PercentOf(total Money) int {
+	if total.IsZero() {
+		return 0
+	}
+	scale := max(m.scale, total.scale)
+	num := new(big.Int).Mul(m.big(scale), big.NewInt(100))
+	return int(num.Quo(num, total.big(scale)).Int64())
+}
+
+// MinMoney возвращает меньшую из сумм.
+func MinMoney(a, b Money) Money {
+	if a.Cmp(b) <= 0 {
+		return a
+	}
+	return b
+}
+
+// MarshalJSON кодирует сумму десятичной строкой.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(m.String())), nil
+}
+
+// UnmarshalJSON принимает сумму десятичной строкой ("9.99") или числом (9.99, 500).
+func (m *Money) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		return nil
+	}
+	if unquoted, err := strconv.Unquote(s); err == nil {
+		s = unquoted
+	}
+
+	parsed, err := ParseMoney(s)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// Scan читает сумму из колонки NUMERIC.
+func (m *Money) Scan(src any) error {
+	switch v := src.(type) {
+	case string:
+		return m.scanString(v)
+	case []byte:
+		return m.scanString(string(v))
+	case int64:
+		*m = Money{minor: v}
+		return nil
+	case nil:
+		*m = Money{}
+		return nil
+	}
+	return fmt.Errorf("cannot scan %T into Money", src)
+}
+
+func (m *Money) scanString(s string) error {
+	parsed, err := ParseMoney(s)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// Value передает сумму в базу десятичной строкой.
+func (m Money) Value() (driver.Value, error) {
+	return m.String(), nil
+}
+
+// big возвращает сумму в минимальных единицах для scale знаков после запятой (scale >= m.scale).
+func (m Money) big(scale int) *big.Int {
+	v := big.NewInt(m.minor)
+	if scale > m.scale {
+		v.Mul(v, pow10(scale-m.scale))
+	}
+	return v
+}
+
+func fromBig(v *big.Int, scale int) (Money, error) {
+	if !v.IsInt64() {
+		return Money{}, ErrMoneyOverflow
+	}
+	return Money{minor: v.Int64(), scale: scale}, nil
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+// quoRoundHalfAway делит a на положительное b с округлением половины от нуля.
+func quoRoundHalfAway(a, b *big.Int) *big.Int {
+	q, r := new(big.Int).QuoRem(a, b, new(big.Int))
+	if new(big.Int).Mul(new(big.Int).Abs(r), big.NewInt(2)).Cmp(b) >= 0 {
+		if a.Sign() < 0 {
+			q.Sub(q, big.NewInt(1))
+		} else {
+			q.Add(q, big.NewInt(1))
+		}
+	}
+	return q
+}
@@ -0,0 +1,248 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+)
+
+func mustMoney(t *testing.T, s string) Money {
+	t.Helper()
+	m, err := ParseMoney(s)
+	if err != nil {
+		t.Fatalf("ParseMoney(%q) error = %v", s, err)
+	}
+	return m
+}
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		input     string
+		wantMinor int64
+		wantScale int
+		wantErr   error
+		invalid   bool
+	}{
+		{input: "9.99", wantMinor: 999, wantScale: 2},
+		{input: "-10", wantMinor: -10, wantScale: 0},
+		{input: "+1.5", wantMinor: 15, wantScale: 1},
+		{input: ".5", wantMinor: 5, wantScale: 1},
+		{input: " 100.50 ", wantMinor: 10050, wantScale: 2},
+		{input: "0.000001", wantMinor: 1, wantScale: 6},
+		{input: "9223372036854775807", wantMinor: math.MaxInt64, wantScale: 0},
+		{input: "-9223372036854775808", wantMinor: math.MinInt64, wantScale: 0},
+		{input: "92233720368547758.07", wantMinor: math.MaxInt64, wantScale: 2},
+		{input: "9223372036854775808", wantErr: ErrMoneyOverflow},
+		{input: "92233720368547758.08", wantErr: ErrMoneyOverflow},
+		{input: "1.0000001", wantErr: ErrMoneyPrecision},
+		{input: "", invalid: true},
+		{input: "1.", invalid: true},
+		{input: "-", invalid: true},
+		{input: "1e3", invalid: true},
+		{input: "1.-5", invalid: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseMoney(tt.input)
+			switch {
+			case tt.invalid:
+				if err == nil {
+					t.Fatalf("ParseMoney(%q) = %s, want an error", tt.input, got)
+				}
+				return
+			case !errors.Is(err, tt.wantErr):
+				t.Fatalf("ParseMoney(%q) error = %v, want %v", tt.input, err, tt.wantErr)
+			case tt.wantErr != nil:
+				return
+			}
+			if got.Minor() != tt.wantMinor || got.Scale() != tt.wantScale {
+				t.Errorf("ParseMoney(%q) = %d/10^%d, want %d/10^%d", tt.input, got.Minor(), got.Scale(), tt.wantMinor, tt.wantScale)
+			}
+		})
+	}
+}
+
+func TestMoneyString(t *testing.T) {
+	tests := []struct {
+		money Money
+		want  string
+	}{
+		{money: NewMoney(999, 2), want: "9.99"},
+		{money: NewMoney(5, 2), want: "0.05"},
+		{money: NewMoney(-5, 2), want: "-0.05"},
+		{money: NewMoney(-500, 0), want: "-500"},
+		{money: Money{}, want: "0"},
+		{money: NewMoney(math.MaxInt64, 2), want: "92233720368547758.07"},
+		{money: NewMoney(math.MinInt64, 2), want: "-92233720368547758.08"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := tt.money.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoneyArithmetic(t *testing.T) {
+	maxMoney, minMoney := NewMoney(math.MaxInt64, 0), NewMoney(math.MinInt64, 0)
+
+	tests := []struct {
+		name       string
+		op         func() (Money, error)
+		want       string
+		wantErr    error
+		wantAnyErr bool
+	}{
+		{name: "add across scales", op: func() (Money, error) { return mustMoney(t, "1.5").Add(mustMoney(t, "0.25")) }, want: "1.75"},
+		{name: "sub across scales", op: func() (Money, error) { return mustMoney(t, "1").Sub(mustMoney(t, "0.001")) }, want: "0.999"},
+		{name: "add up to the maximum", op: func() (Money, error) { return NewMoney(math.MaxInt64-1, 0).Add(NewMoney(1, 0)) }, want: "9223372036854775807"},
+		{name: "add past the maximum", op: func() (Money, error) { return maxMoney.Add(NewMoney(1, 0)) }, wantErr: ErrMoneyOverflow},
+		{name: "add past the minimum", op: func() (Money, error) { return minMoney.Add(NewMoney(-1, 0)) }, wantErr: ErrMoneyOverflow},
+		{name: "sub past the minimum", op: func() (Money, error) { return minMoney.Sub(NewMoney(1, 0)) }, wantErr: ErrMoneyOverflow},
+		{name: "rescaling to a common scale overflows", op: func() (Money, error) { return maxMoney.Add(mustMoney(t, "0.01")) }, wantErr: ErrMoneyOverflow},
+		{name: "mul keeps the scale", op: func() (Money, error) { return mustMoney(t, "9.99").Mul(3) }, want: "29.97"},
+		{name: "mul past the maximum", op: func() (Money, error) { return NewMoney(math.MaxInt64/2+1, 0).Mul(2) }, wantErr: ErrMoneyOverflow},
+		{name: "negating the minimum", op: func() (Money, error) { return minMoney.Mul(-1) }, wantErr: ErrMoneyOverflow},
+		{name: "muldiv truncates toward zero", op: func() (Money, error) { return mustMoney(t, "10.00").MulDiv(1, 3) }, want: "3.33"},
+		{name: "muldiv truncates negative toward zero", op: func() (Money, error) { return mustMoney(t, "-10.00").MulDiv(2, 3) }, want: "-6.66"},
+		{name: "muldiv with an intermediate overflow", op: func() (Money, error) { return maxMoney.MulDiv(2, 2) }, want: "9223372036854775807"},
+		{name: "muldiv by zero", op: func() (Money, error) { return maxMoney.MulDiv(1, 0) }, wantAnyErr: true},
+		{name: "muldivround rounds half away from zero", op: func() (Money, error) { return mustMoney(t, "0.01").MulDivRound(1, 2) }, want: "0.01"},
+		{name: "muldivround rounds negative half away from zero", op: func() (Money, error) { return mustMoney(t, "-0.01").MulDivRound(1, 2) }, want: "-0.01"},
+		{name: "muldivround rounds down below half", op: func() (Money, error) { return mustMoney(t, "0.01").MulDivRound(1, 3) }, want: "0.00"},
+		{name: "muldivround with an intermediate overflow", op: func() (Money, error) { return minMoney.MulDivRound(3, 3) }, want: "-9223372036854775808"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.op()
+			switch {
+			case tt.wantAnyErr:
+				if err == nil {
+					t.Fatalf("= %s, want an error", got)
+				}
+				return
+			case tt.wantErr != nil:
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error = %v", err)
+			}
+			if got.String() != tt.want {
+				t.Errorf("= %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoneyRescaleAndRound(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		scale     int
+		round     bool
+		want      string
+		wantErr   error
+		maxAmount bool
+	}{
+		{name: "rescale up", input: "1.5", scale: 2, want: "1.50"},
+		{name: "rescale down without a remainder", input: "1.50", scale: 1, want: "1.5"},
+		{name: "rescale down with a remainder", input: "1.55", scale: 1, wantErr: ErrMoneyPrecision},
+		{name: "rescale up past the maximum", maxAmount: true, scale: 1, wantErr: ErrMoneyOverflow},
+		{name: "round half up", input: "1.555", scale: 2, round: true, want: "1.56"},
+		{name: "round negative half away from zero", input: "-1.555", scale: 2, round: true, want: "-1.56"},
+		{name: "round below half", input: "1.554", scale: 2, round: true, want: "1.55"},
+		{name: "round to units", input: "2.5", scale: 0, round: true, want: "3"},
+		{name: "round to a larger scale", input: "2.5", scale: 2, round: true, want: "2.50"},
+		{name: "round the maximum", maxAmount: true, scale: 0, round: true, want: "9223372036854775807"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMoney(math.MaxInt64, 0)
+			if !tt.maxAmount {
+				m = mustMoney(t, tt.input)
+			}
+
+			var (
+				got Money
+				err error
+			)
+			if tt.round {
+				got, err = m.Round(tt.scale)
+			} else {
+				got, err = m.Rescale(tt.scale)
+			}
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && (got.String() != tt.want || got.Scale() != tt.scale) {
+				t.Errorf("= %s (scale %d), want %s (scale %d)", got, got.Scale(), tt.want, tt.scale)
+			}
+		})
+	}
+}
+
+func TestMoneyCmp(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{a: "1.5", b: "1.50", want: 0},
+		{a: "1.5", b: "1.49", want: 1},
+		{a: "-0.001", b: "0", want: -1},
+		{a: "92233720368547758.07", b: "9223372036854775807", want: -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+" vs "+tt.b, func(t *testing.T) {
+			if got := mustMoney(t, tt.a).Cmp(mustMoney(t, tt.b)); got != tt.want {
+				t.Errorf("Cmp() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoneyJSON(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{input: `"9.99"`, want: "9.99"},
+		{input: `9.99`, want: "9.99"},
+		{input: `500`, want: "500"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			var m Money
+			if err := json.Unmarshal([]byte(tt.input), &m); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+			if m.String() != tt.want {
+				t.Errorf("Unmarshal() = %s, want %s", m, tt.want)
+			}
+
+			data, err := json.Marshal(m)
+			if err != nil {
+				t.Fatalf("Marshal() error = %v", err)
+			}
+			if string(data) != `"`+tt.want+`"` {
+				t.Errorf("Marshal() = %s, want %q", data, tt.want)
+			}
+		})
+	}
+
+	var m Money
+	if err := json.Unmarshal([]byte(`"1e3"`), &m); err == nil {
+		t.Error("Unmarshal() accepted an exponent")
+	}
+}
@@ -0,0 +1,59 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Типы доменных событий по организациям.
+const (
+	EventOrganizationCreated = "organization.created"
+	EventOrganizationUpdated = "organization.updated"
+	EventOrganizationDeleted = "organization.deleted"
+)
+
+// Organization - организация (рабочее пространство команды), которой принадлежат рабочие подписки участников.
+type Organization struct {
+	ID   uuid.UUID `db:"id"               json:"id"`
+	Name string    `db:"name"             json:"name"`
+	// DefaultCurrency - валюта рабочих подписок, для которых она не указана.
+	DefaultCurrency Currency  `db:"default_currency" json:"default_currency"`
+	CreatedAt       time.Time `db:"created_at"       json:"created_at"`
+	UpdatedAt       time.Time `db:"updated_at"       json:"updated_at"`
+}
+
+// OrganizationRole - роль участника организации.
+type OrganizationRole string
+
+const (
+	// OrganizationRoleOwner управляет организацией, включая ее удаление и назначение владельцев.
+	OrganizationRoleOwner OrganizationRole = "owner"
+	// OrganizationRoleAdmin меняет профиль организации и состав участников.
+	OrganizationRoleAdmin OrganizationRole = "admin"
+	// OrganizationRoleMember заводит рабочие подписки и видит подписки и отчеты организации.
+	OrganizationRoleMember OrganizationRole = "member"
+)
+
+// Valid сообщает, что роль входит в число известных.
+func (r OrganizationRole) Valid() bool {
+	switch r {
+	case OrganizationRoleOwner, OrganizationRoleAdmin, OrganizationRoleMember:
+		return true
+	default:
+		return false
+	}
+}
+
+// CanManage сообщает, что роль позволяет менять профиль и участников организации.
+func (r OrganizationRole) CanManage() bool {
+	return r == OrganizationRoleOwner || r == OrganizationRoleAdmin
+}
+
+// OrganizationMember - участие пользователя в организации.
+type OrganizationMember struct {
+	OrganizationID uuid.UUID        `db:"organization_id" json:"organization_id"`
+	UserID         uuid.UUID        `db:"user_id"         json:"user_id"`
+	Role           OrganizationRole `db:"role"            json:"role"`
+	CreatedAt      time.Time        `db:"created_at"      json:"created_at"`
+}
@@ -0,0 +1,45 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EventUserErased - событие об удалении данных пользователя по его запросу.
+// Агрегатом события служит tombstone, а не ID пользователя.
+const EventUserErased = "user.erased"
+
+// UserDataExport - все данные пользователя, выгружаемые по запросу субъекта данных.
+type UserDataExport struct {
+	ExportedAt time.Time `json:"exported_at"`
+	User       User      `json:"user"`
+	// Subscriptions - собственные подписки пользователя и подписки, в которых он участвует.
+	Subscriptions []SubscriptionHistory `json:"subscriptions"`
+	Categories    []Category            `json:"categories"`
+	Tags          []Tag                 `json:"tags"`
+	Budgets       []Budget              `json:"budgets"`
+	// Notifications - доменные события и оповещения, в которых упоминается пользователь.
+	Notifications []Event `json:"notifications"`
+}
+
+// SubscriptionHistory - подписка вместе с историей статусов, участников, цен и скидок.
+type SubscriptionHistory struct {
+	Subscription Subscription         `json:"subscription"`
+	Transitions  []StatusTransition   `json:"transitions"`
+	Members      []SubscriptionMember `json:"members"`
+	PriceChanges []PriceChange        `json:"price_changes"`
+	Discounts    []Discount           `json:"discounts"`
+}
+
+// Erasure - запись журнала об удалении данных пользователя. Журнал не хранит ID пользователя:
+// во всех оставшихся записях он заменен на TombstoneID.
+type Erasure struct {
+	ID          uuid.UUID `json:"id"`
+	TombstoneID uuid.UUID `json:"tombstone_id"`
+	// Subscriptions - число удаленных собственных подписок пользователя.
+	Subscriptions int `json:"subscriptions"`
+	// RedactedEvents - число событий outbox, в которых пользователь заменен на tombstone.
+	RedactedEvents int64     `json:"redacted_events"`
+	ErasedAt       time.Time `json:"erased_at"`
+}
@@ -0,0 +1,73 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Типы доменных событий по ролям пользователей.
+const (
+	EventRoleBound   = "role.bound"
+	EventRoleUnbound = "role.unbound"
+)
+
+// Permission - право на действие. Права выдаются только через роли.
+type Permission string
+
+const (
+	// PermissionSubscriptionsRead разрешает читать подписки, их историю и отчеты по собственным подпискам.
+	PermissionSubscriptionsRead Permission = "subscriptions:read"
+	// PermissionSubscriptionsWrite разрешает создавать, изменять, приостанавливать, возобновлять и отменять подписки.
+	PermissionSubscriptionsWrite Permission = "subscriptions:write"
+	// PermissionSubscriptionsDelete разрешает удалять подписки.
+	PermissionSubscriptionsDelete Permission = "subscriptions:delete"
+	// PermissionSubscriptionsRestore разрешает восстанавливать завершенные подписки.
+	PermissionSubscriptionsRestore Permission = "subscriptions:restore"
+	// PermissionReportsReadAll разрешает строить отчеты о расходах любого пользователя.
+	PermissionReportsReadAll Permission = "reports:read_all"
+	// PermissionRolesManage разрешает выдавать и отзывать роли.
+	PermissionRolesManage Permission = "roles:manage"
+	// PermissionAPIKeysManage разрешает выпускать и отзывать API-ключи.
+	PermissionAPIKeysManage Permission = "api_keys:manage"
+	// PermissionAuditRead разрешает читать журнал аудита и проверять его целостность.
+	PermissionAuditRead Permission = "audit:read"
+	// PermissionUsersManage разрешает читать и изменять профили, бюджеты, категории и метки других
+	// пользователей и удалять их. Своими данными пользователь распоряжается без этого права.
+	PermissionUsersManage Permission = "users:manage"
+	// PermissionCatalogManage разрешает добавлять, изменять и удалять сервисы каталога.
+	PermissionCatalogManage Permission = "catalog:manage"
+	// PermissionPrivacyManage разрешает выгружать и удалять данные других пользователей по запросам
+	// субъектов данных. Свои данные пользователь выгружает и удаляет без этого права.
+	PermissionPrivacyManage Permission = "privacy:manage"
+	// PermissionOrganizationsManage разрешает читать, изменять и удалять организации и их состав
+	// без членства в них. Участник организации действует по своей роли в ней без этого права.
+	PermissionOrganizationsManage Permission = "organizations:manage"
+)
+
+// Role - именованный набор прав. Роли и их права хранятся в базе и заводятся миграциями.
+type Role struct {
+	Name        string       `db:"name"        json:"name"`
+	Description string       `db:"description" json:"description"`
+	Permissions []Permission `db:"-"           json:"permissions"`
+}
+
+// RoleBinding - выдача роли пользователю.
+type RoleBinding struct {
+	UserID    uuid.UUID `db:"user_id"    json:"user_id"`
+	Role      string    `db:"role"       json:"role"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
+// Valid сообщает, что право входит в число известных.
+func (p Permission) Valid() bool {
+	switch p {
+	case PermissionSubscriptionsRead, PermissionSubscriptionsWrite, PermissionSubscriptionsDelete,
+		PermissionSubscriptionsRestore, PermissionReportsReadAll, PermissionRolesManage, PermissionAPIKeysManage,
+		PermissionAuditRead, PermissionUsersManage, PermissionCatalogManage,
+		PermissionPrivacyManage, PermissionOrganizationsManage:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,50 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SubscriptionStatus - состояние подписки в ее жизненном цикле.
+type SubscriptionStatus string
+
+const (
+	StatusTrialing        SubscriptionStatus = "trialing"
+	StatusActive          SubscriptionStatus = "active"
+	StatusPaused          SubscriptionStatus = "paused"
+	StatusCancelScheduled SubscriptionStatus = "cancel_scheduled"
+	StatusEnded           SubscriptionStatus = "ended"
+)
+
+// allowedTransitions описывает конечный автомат статусов подписки.
+var allowedTransitions = map[SubscriptionStatus][]SubscriptionStatus{
+	StatusTrialing:        {StatusActive, StatusPaused, StatusCancelScheduled, StatusEnded},
+	StatusActive:          {StatusPaused, StatusCancelScheduled, StatusEnded},
+	StatusPaused:          {StatusActive, StatusTrialing, StatusCancelScheduled, StatusEnded},
+	StatusCancelScheduled: {StatusActive, StatusEnded},
+	StatusEnded:           {StatusActive},
+}
+
+// CanTransitionTo сообщает, допустим ли переход из текущего статуса в статус to.
+func (s SubscriptionStatus) CanTransitionTo(to SubscriptionStatus) bool {
+	for _, allowed := range allowedTransitions[s] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// StatusTransition - сохраненный переход подписки между статусами.
+// FromStatus пуст для начального статуса, присвоенного при создании.
+// PreviousEndDate - дата окончания подписки до перехода, по ней отмена возвращается назад.
+type StatusTransition struct {
+	ID              uuid.UUID          `db:"id"                json:"id"`
+	SubscriptionID  uuid.UUID          `db:"subscription_id"   json:"subscription_id"`
+	FromStatus      SubscriptionStatus `db:"from_status"       json:"from_status,omitempty"`
+	ToStatus        SubscriptionStatus `db:"to_status"         json:"to_status"`
+	EffectiveDate   Date               `db:"effective_date"    json:"effective_date" swaggertype:"string" format:"date"`
+	PreviousEndDate *Date              `db:"previous_end_date" json:"previous_end_date,omitempty" swaggertype:"string" format:"date"`
+	CreatedAt       time.Time          `db:"created_at"        json:"created_at"`
+}
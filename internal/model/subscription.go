@@ -7,12 +7,35 @@ import (
 
 // Subscription представляет одну запись о подписке
 type Subscription struct {
-	ID          uuid.UUID  `db:"id"           json:"id"`
-	UserID      uuid.UUID  `db:"user_id"      json:"user_id"`
-	ServiceName string     `db:"service_name" json:"service_name"`
-	Price       int        `db:"price"        json:"price"`
-	StartDate   time.Time  `db:"start_date"   json:"start_date"`
-	EndDate     *time.Time `db:"end_date"     json:"end_date,omitempty"`
-	CreatedAt   time.Time  `db:"created_at"   json:"created_at"`
-	UpdatedAt   time.Time  `db:"updated_at"   json:"updated_at"`
+	ID     uuid.UUID `db:"id"              json:"id"`
+	UserID uuid.UUID `db:"user_id"         json:"user_id"`
+	// OrganizationID - организация, которой принадлежит рабочая подписка. У личных подписок не задан,
+	// у рабочих UserID - участник организации, который ими управляет.
+	OrganizationID *uuid.UUID `db:"organization_id" json:"organization_id,omitempty"`
+	ServiceName    string     `db:"service_name"    json:"service_name"`
+	ServiceID      *uuid.UUID `db:"service_id"      json:"service_id,omitempty"`
+	PlanID         *uuid.UUID `db:"plan_id"         json:"plan_id,omitempty"`
+	Price          Money      `db:"price"           json:"price" swaggertype:"string" example:"9.99"`
+	Currency       Currency   `db:"currency"        json:"currency"`
+	// BillingPeriod - периодичность оплаты; по умолчанию monthly.
+	BillingPeriod BillingPeriod      `db:"billing_period"  json:"billing_period"`
+	StartDate     Date               `db:"start_date"      json:"start_date" swaggertype:"string" format:"date" example:"2024-01-01"`
+	EndDate       *Date              `db:"end_date"        json:"end_date,omitempty" swaggertype:"string" format:"date" example:"2024-12-31"`
+	Status        SubscriptionStatus `db:"status"          json:"status"`
+	TrialEndDate  *Date              `db:"trial_end_date"  json:"trial_end_date,omitempty" swaggertype:"string" format:"date" example:"2024-01-15"`
+	CategoryIDs   []uuid.UUID        `db:"-"               json:"category_ids"`
+	Tags          []string           `db:"-"               json:"tags"`
+	CreatedAt     time.Time          `db:"created_at"      json:"created_at"`
+	UpdatedAt     time.Time          `db:"updated_at"      json:"updated_at"`
+}
+
+// StatusAt возвращает фактический статус подписки на дату day с учетом окончания пробного периода и даты завершения.
+func (s Subscription) StatusAt(day Date) SubscriptionStatus {
+	if s.EndDate != nil && s.EndDate.Before(day) {
+		return StatusEnded
+	}
+	if s.Status == StatusTrialing && s.TrialEndDate != nil && !s.TrialEndDate.After(day) {
+		return StatusActive
+	}
+	return s.Status
 }
@@ -0,0 +1,42 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Типы доменных событий по пользователям.
+const (
+	EventUserCreated = "user.created"
+	EventUserUpdated = "user.updated"
+	EventUserDeleted = "user.deleted"
+)
+
+// User - пользователь сервиса: владелец подписок, категорий, меток и бюджетов.
+type User struct {
+	ID          uuid.UUID `db:"id"                       json:"id"`
+	DisplayName string    `db:"display_name"             json:"display_name"`
+	Email       string    `db:"email"                    json:"email,omitempty"`
+	// DefaultCurrency - валюта новых подписок и бюджетов, для которых она не указана.
+	DefaultCurrency Currency `db:"default_currency"         json:"default_currency"`
+	// TimeZone - часовой пояс IANA, в котором определяется текущая дата пользователя,
+	// если запрос не задает пояс заголовком X-Time-Zone.
+	TimeZone      string                  `db:"time_zone"                json:"time_zone"`
+	Notifications NotificationPreferences `db:"notification_preferences" json:"notification_preferences"`
+	CreatedAt     time.Time               `db:"created_at"               json:"created_at"`
+	UpdatedAt     time.Time               `db:"updated_at"               json:"updated_at"`
+}
+
+// NotificationPreferences - оповещения, которые пользователь согласен получать.
+// Хранятся в JSONB, поэтому новые настройки добавляются без миграции схемы.
+type NotificationPreferences struct {
+	// BudgetAlerts - события о достижении порогов бюджета.
+	BudgetAlerts bool `json:"budget_alerts"`
+}
+
+// DefaultNotificationPreferences - настройки оповещений нового пользователя.
+var DefaultNotificationPreferences = NotificationPreferences{BudgetAlerts: true}
+
+// DefaultTimeZone - часовой пояс пользователя, для которого он не указан.
+const DefaultTimeZone = "UTC"
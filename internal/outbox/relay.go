@@ -0,0 +1,139 @@
+package outbox
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/vasiliy-maslov/go-subscription-service/internal/events"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/model"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// Options задает параметры разбора outbox.
+type Options struct {
+	// Interval - период опроса outbox.
+	Interval  time.Duration
+	BatchSize int
+	// ClaimTimeout - на сколько захватывается пачка событий. Должен превышать время ее публикации,
+	// иначе события будут выданы другой реплике повторно.
+	ClaimTimeout time.Duration
+	// MaxAttempts - после стольких неудачных попыток событие переводится в dead letter.
+	MaxAttempts int
+}
+
+// Relay периодически забирает события из outbox и передает их издателю.
+type Relay struct {
+	repo      repository.OutboxRepository
+	publisher events.EventPublisher
+	logger    *slog.Logger
+	opts      Options
+}
+
+// NewRelay создает новый экземпляр ретранслятора outbox.
+func NewRelay(repo repository.OutboxRepository, publisher events.EventPublisher, logger *slog.Logger, opts Options) *Relay {
+	return &Relay{
+		repo:      repo,
+		publisher: publisher,
+		logger:    logger,
+		opts:      opts,
+	}
+}
+
+// Run разбирает outbox, пока не будет отменен контекст.
+func (r *Relay) Run(ctx context.Context) {
+	const op = "outbox.Run"
+	log := r.logger.With(slog.String("op", op))
+
+	log.Info("Запущен ретранслятор outbox", slog.Duration("interval", r.opts.Interval))
+
+	// Ретранслятор публикует события всех пользователей, поэтому работает как системный вызов.
+	ctx = repository.WithSystem(ctx)
+
+	ticker := time.NewTicker(r.opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info("Ретранслятор outbox остановлен")
+			return
+		case <-ticker.C:
+			if err := r.Dispatch(ctx); err != nil {
+				log.Error("Не удалось разобрать outbox", slog.String("error", err.Error()))
+			}
+		}
+	}
+}
+
+// Dispatch захватывает пачку событий, публикует ее вне транзакции и записывает результат:
+// опубликованные события отмечаются, неудачные получают попытку, пропущенные освобождаются.
+func (r *Relay) Dispatch(ctx context.Context) error {
+	batch, err := r.repo.Claim(ctx, r.opts.BatchSize, r.opts.ClaimTimeout)
+	if err != nil {
+		return err
+	}
+	if len(batch) == 0 {
+		return nil
+	}
+
+	published, failed, skipped := r.publish(ctx, batch)
+
+	if len(published) > 0 {
+		if err := r.repo.MarkPublished(ctx, published); err != nil {
+			return err
+		}
+	}
+	if len(failed) > 0 {
+		dead, err := r.repo.MarkFailed(ctx, failed, r.opts.MaxAttempts)
+		for _, id := range dead {
+			r.logger.Error("Событие переведено в dead letter после исчерпания попыток",
+				slog.String("event_id", id.String()),
+				slog.Int("max_attempts", r.opts.MaxAttempts),
+				slog.String("error", failed[id].Error()),
+			)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if len(skipped) > 0 {
+		if err := r.repo.Release(ctx, skipped); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// publish отправляет события по порядку. После первой ошибки по подписке
+// ее последующие события в пачке пропускаются, чтобы не нарушить порядок доставки.
+func (r *Relay) publish(ctx context.Context, batch []model.Event) (published []uuid.UUID, failed map[uuid.UUID]error, skipped []uuid.UUID) {
+	published = make([]uuid.UUID, 0, len(batch))
+	failed = make(map[uuid.UUID]error)
+	blocked := make(map[uuid.UUID]struct{})
+
+	for _, event := range batch {
+		if _, ok := blocked[event.AggregateID]; ok {
+			skipped = append(skipped, event.ID)
+			continue
+		}
+
+		if err := r.publisher.Publish(ctx, event); err != nil {
+			r.logger.Warn("Не удалось опубликовать событие",
+				slog.String("event_id", event.ID.String()),
+				slog.String("event_type", event.Type),
+				slog.String("error", err.Error()),
+			)
+			failed[event.ID] = err
+			blocked[event.AggregateID] = struct{}{}
+			continue
+		}
+
+		published = append(published, event.ID)
+	}
+
+	return published, failed, skipped
+}
@@ -0,0 +1,261 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/vasiliy-maslov/go-subscription-service/internal/events"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/model"
+
+	"github.com/google/uuid"
+)
+
+// fakeOutbox - outbox в памяти: события выдаются в порядке записи, пока не опубликованы
+// и не переведены в dead letter.
+type fakeOutbox struct {
+	events    []model.Event
+	published map[uuid.UUID]bool
+	attempts  map[uuid.UUID]int
+	dead      map[uuid.UUID]bool
+	released  []uuid.UUID
+}
+
+func newFakeOutbox(events ...model.Event) *fakeOutbox {
+	return &fakeOutbox{
+		events:    events,
+		published: make(map[uuid.UUID]bool),
+		attempts:  make(map[uuid.UUID]int),
+		dead:      make(map[uuid.UUID]bool),
+	}
+}
+
+func (f *fakeOutbox) Add(context.Context, string, uuid.UUID, any) error {
+	return errors.New("not implemented")
+}
+
+func (f *fakeOutbox) Claim(_ context.Context, limit int, _ time.Duration) ([]model.Event, error) {
+	var batch []model.Event
+	for _, e := range f.events {
+		if len(batch) == limit {
+			break
+		}
+		if !f.published[e.ID] && !f.dead[e.ID] {
+			batch = append(batch, e)
+		}
+	}
+	return batch, nil
+}
+
+func (f *fakeOutbox) MarkPublished(_ context.Context, ids []uuid.UUID) error {
+	for _, id := range ids {
+		f.published[id] = true
+	}
+	return nil
+}
+
+func (f *fakeOutbox) MarkFailed(_ context.Context, failed map[uuid.UUID]error, maxAttempts int) ([]uuid.UUID, error) {
+	var dead []uuid.UUID
+	for id := range failed {
+		f.attempts[id]++
+		if f.attempts[id] >= maxAttempts {
+			f.dead[id] = true
+			dead = append(dead, id)
+		}
+	}
+	return dead, nil
+}
+
+func (f *fakeOutbox) Release(_ context.Context, ids []uuid.UUID) error {
+	f.released = append(f.released, ids...)
+	return nil
+}
+
+// flakyBroker отклоняет сообщения с ID из reject, остальные передает брокеру в памяти.
+type flakyBroker struct {
+	*events.MemoryBroker
+	reject map[string]bool
+}
+
+func (b *flakyBroker) Publish(ctx context.Context, subject string, msg events.Message) error {
+	if b.reject[msg.ID] {
+		return errors.New("broker unavailable")
+	}
+	return b.MemoryBroker.Publish(ctx, subject, msg)
+}
+
+func newEvent(aggregateID uuid.UUID, eventType string) model.Event {
+	return model.Event{
+		ID:          uuid.New(),
+		AggregateID: aggregateID,
+		Type:        eventType,
+		Payload:     json.RawMessage(`{}`),
+		CreatedAt:   time.Now(),
+	}
+}
+
+func newTestRelay(repo *fakeOutbox, broker events.Broker, maxAttempts int) *Relay {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	return NewRelay(repo, events.NewBrokerPublisher(broker, "subscriptions"), logger, Options{
+		Interval:     time.Second,
+		BatchSize:    100,
+		ClaimTimeout: time.Minute,
+		MaxAttempts:  maxAttempts,
+	})
+}
+
+// messageIDs возвращает ID сообщений subject в порядке публикации.
+func messageIDs(broker *events.MemoryBroker, subject string) []string {
+	var ids []string
+	for _, msg := range broker.Messages(subject) {
+		ids = append(ids, msg.ID)
+	}
+	return ids
+}
+
+func TestRelayDispatch(t *testing.T) {
+	first, second := uuid.New(), uuid.New()
+
+	tests := []struct {
+		name        string
+		events      []model.Event
+		reject      []int // индексы событий, которые брокер отклоняет
+		maxAttempts int
+		runs        int
+		published   []int
+		dead        []int
+		released    []int
+	}{
+		{
+			name: "events are published in order with their IDs",
+			events: []model.Event{
+				newEvent(first, model.EventSubscriptionCreated),
+				newEvent(second, model.EventSubscriptionCreated),
+				newEvent(first, model.EventSubscriptionUpdated),
+			},
+			maxAttempts: 3,
+			runs:        1,
+			published:   []int{0, 1, 2},
+		},
+		{
+			name: "failed event holds back later events of its subscription only",
+			events: []model.Event{
+				newEvent(first, model.EventSubscriptionCreated),
+				newEvent(second, model.EventSubscriptionCreated),
+				newEvent(first, model.EventSubscriptionUpdated),
+			},
+			reject:      []int{0},
+			maxAttempts: 3,
+			runs:        1,
+			published:   []int{1},
+			released:    []int{2},
+		},
+		{
+			name: "poison event goes to dead letter and unblocks its subscription",
+			events: []model.Event{
+				newEvent(first, model.EventSubscriptionCreated),
+				newEvent(first, model.EventSubscriptionUpdated),
+			},
+			reject:      []int{0},
+			maxAttempts: 2,
+			runs:        3,
+			published:   []int{1},
+			dead:        []int{0},
+			released:    []int{1, 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeOutbox(tt.events...)
+			broker := &flakyBroker{MemoryBroker: events.NewMemoryBroker(), reject: make(map[string]bool)}
+			for _, i := range tt.reject {
+				broker.reject[tt.events[i].ID.String()] = true
+			}
+			relay := newTestRelay(repo, broker, tt.maxAttempts)
+
+			for range tt.runs {
+				if err := relay.Dispatch(context.Background()); err != nil {
+					t.Fatalf("Dispatch() error = %v", err)
+				}
+			}
+
+			var got []string
+			for _, eventType := range []string{model.EventSubscriptionCreated, model.EventSubscriptionUpdated} {
+				got = append(got, messageIDs(broker.MemoryBroker, "subscriptions."+eventType)...)
+			}
+			var want []string
+			for _, i := range tt.published {
+				want = append(want, tt.events[i].ID.String())
+			}
+			slices.Sort(got)
+			slices.Sort(want)
+			if !slices.Equal(got, want) {
+				t.Errorf("published = %v, want %v", got, want)
+			}
+
+			for _, i := range tt.dead {
+				if !repo.dead[tt.events[i].ID] {
+					t.Errorf("event %d is not in dead letter", i)
+				}
+			}
+			if len(repo.dead) != len(tt.dead) {
+				t.Errorf("dead letters = %d, want %d", len(repo.dead), len(tt.dead))
+			}
+
+			var wantReleased []uuid.UUID
+			for _, i := range tt.released {
+				wantReleased = append(wantReleased, tt.events[i].ID)
+			}
+			if !slices.Equal(repo.released, wantReleased) {
+				t.Errorf("released = %v, want %v", repo.released, wantReleased)
+			}
+		})
+	}
+}
+
+func TestRelayPreservesOrderPerSubscription(t *testing.T) {
+	subscription := uuid.New()
+	created := newEvent(subscription, model.EventSubscriptionCreated)
+	updated := newEvent(subscription, model.EventSubscriptionCreated)
+
+	repo := newFakeOutbox(created, updated)
+	broker := &flakyBroker{MemoryBroker: events.NewMemoryBroker(), reject: map[string]bool{created.ID.String(): true}}
+	relay := newTestRelay(repo, broker, 10)
+
+	if err := relay.Dispatch(context.Background()); err != nil {
+		t.Fatalf("Dispatch() error = %v", err)
+	}
+	if got := messageIDs(broker.MemoryBroker, "subscriptions."+model.EventSubscriptionCreated); len(got) != 0 {
+		t.Fatalf("published %v before the failed event", got)
+	}
+
+	// Брокер восстановился: события доставляются в порядке записи, ключом служит ID подписки.
+	delete(broker.reject, created.ID.String())
+	if err := relay.Dispatch(context.Background()); err != nil {
+		t.Fatalf("Dispatch() error = %v", err)
+	}
+
+	messages := broker.Messages("subscriptions." + model.EventSubscriptionCreated)
+	if len(messages) != 2 || messages[0].ID != created.ID.String() || messages[1].ID != updated.ID.String() {
+		t.Fatalf("messages = %+v, want created then updated", messages)
+	}
+	for _, msg := range messages {
+		if msg.Key != subscription.String() {
+			t.Errorf("message key = %q, want subscription ID %s", msg.Key, subscription)
+		}
+	}
+
+	// Повторная публикация того же события отбрасывается брокером по ID.
+	if err := events.NewBrokerPublisher(broker, "subscriptions").Publish(context.Background(), created); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	if got := len(broker.Messages("subscriptions." + model.EventSubscriptionCreated)); got != 2 {
+		t.Errorf("messages after duplicate = %d, want 2", got)
+	}
+}
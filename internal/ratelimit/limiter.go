@@ -0,0 +1,184 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/vasiliy-maslov/go-subscription-service/internal/config"
+)
+
+// Store хранит корзины токенов клиентов.
+type Store interface {
+	// Take пополняет корзину key до capacity со скоростью rate токенов в секунду и забирает из нее токен.
+	// Возвращает, был ли токен, и сколько токенов осталось.
+	Take(ctx context.Context, key string, capacity, rate float64) (allowed bool, tokens float64, err error)
+	// Sweep удаляет корзины, к которым не обращались дольше idle.
+	Sweep(ctx context.Context, idle time.Duration) error
+}
+
+// Rule - ограничение в терминах token bucket: корзина на Burst токенов пополняется
+// на Requests токенов за Period.
+type Rule struct {
+	Requests int
+	Period   time.Duration
+	Burst    int
+}
+
+// rate возвращает скорость пополнения в токенах в секунду.
+func (r Rule) rate() float64 {
+	return float64(r.Requests) / r.Period.Seconds()
+}
+
+// fillTime возвращает время пополнения пустой корзины до Burst токенов.
+func (r Rule) fillTime() time.Duration {
+	return time.Duration(float64(r.Burst) * float64(r.Period) / float64(r.Requests))
+}
+
+// Policy возвращает ограничение в формате заголовка RateLimit-Policy: "<burst>;w=<окно в секундах>".
+func (r Rule) Policy() string {
+	return fmt.Sprintf("%d;w=%d", r.Burst, int(math.Ceil(r.Period.Seconds())))
+}
+
+// Decision - результат проверки запроса.
+type Decision struct {
+	Allowed bool
+	Rule    Rule
+	// Remaining - число запросов, которые можно выполнить сразу.
+	Remaining int
+	// Reset - время до полного пополнения корзины.
+	Reset time.Duration
+	// RetryAfter - время до появления следующего токена; для разрешенного запроса нулевое.
+	RetryAfter time.Duration
+}
+
+// Limiter ограничивает частоту запросов клиентов по правилам маршрутов.
+type Limiter struct {
+	store    Store
+	fallback Rule
+	perIP    Rule
+	routes   map[string]Rule
+	sweep    time.Duration
+	logger   *slog.Logger
+}
+
+// NewLimiter создает ограничитель по конфигурации. Правила без Burst получают Burst = Requests.
+func NewLimiter(cfg config.RateLimitConfig, store Store, logger *slog.Logger) (*Limiter, error) {
+	fallback, err := newRule(cfg.Default)
+	if err != nil {
+		return nil, fmt.Errorf("default: %w", err)
+	}
+	perIP, err := newRule(cfg.PerIP)
+	if err != nil {
+		return nil, fmt.Errorf("per_ip: %w", err)
+	}
+
+	routes := make(map[string]Rule, len(cfg.Routes))
+	for _, route := range cfg.Routes {
+		rule, err := newRule(route.RateLimitRule)
+		if err != nil {
+			return nil, fmt.Errorf("%s %s: %w", route.Method, route.Path, err)
+		}
+		routes[routeKey(route.Method, route.Path)] = rule
+	}
+
+	return &Limiter{
+		store:    store,
+		fallback: fallback,
+		perIP:    perIP,
+		routes:   routes,
+		sweep:    cfg.SweepInterval,
+		logger:   logger,
+	}, nil
+}
+
+func newRule(cfg config.RateLimitRule) (Rule, error) {
+	rule := Rule{Requests: cfg.Requests, Period: cfg.Period, Burst: cfg.Burst}
+	if rule.Burst == 0 {
+		rule.Burst = rule.Requests
+	}
+	if rule.Requests <= 0 || rule.Period <= 0 || rule.Burst <= 0 {
+		return Rule{}, fmt.Errorf("requests, period and burst must be positive")
+	}
+	return rule, nil
+}
+
+func routeKey(method, path string) string {
+	return strings.ToUpper(method) + " " + path
+}
+
+// Allow проверяет запрос клиента client к маршруту method path. У маршрутов с собственным правилом
+// отдельная корзина, остальные маршруты расходуют общую корзину клиента.
+func (l *Limiter) Allow(ctx context.Context, method, path, client string) (Decision, error) {
+	key := routeKey(method, path)
+	rule, ok := l.routes[key]
+	if !ok {
+		rule, key = l.fallback, "*"
+	}
+
+	return l.take(ctx, rule, key+"|"+client)
+}
+
+// AllowIP проверяет запрос с IP-адреса ip до аутентификации по правилу PerIP. Корзина IP-адреса
+// общая для всех маршрутов и не зависит от корзин аутентифицированных клиентов.
+func (l *Limiter) AllowIP(ctx context.Context, ip string) (Decision, error) {
+	return l.take(ctx, l.perIP, "ip|"+ip)
+}
+
+// take забирает токен из корзины key по правилу rule.
+func (l *Limiter) take(ctx context.Context, rule Rule, key string) (Decision, error) {
+	rate := rule.rate()
+	allowed, tokens, err := l.store.Take(ctx, key, float64(rule.Burst), rate)
+	if err != nil {
+		return Decision{}, err
+	}
+
+	d := Decision{
+		Allowed:   allowed,
+		Rule:      rule,
+		Remaining: int(math.Floor(tokens)),
+		Reset:     seconds((float64(rule.Burst) - tokens) / rate),
+	}
+	if !allowed {
+		d.RetryAfter = seconds((1 - tokens) / rate)
+	}
+	return d, nil
+}
+
+// seconds переводит число секунд в длительность, округляя вверх до целой секунды.
+func seconds(s float64) time.Duration {
+	return time.Duration(math.Ceil(math.Max(s, 0))) * time.Second
+}
+
+// Run периодически удаляет корзины клиентов, которые давно не обращались к API, пока не будет отменен контекст.
+// Корзина, к которой не обращались дольше времени пополнения самого медленного правила
+// (Burst×Period/Requests), уже полна, и ее удаление ничего не меняет.
+func (l *Limiter) Run(ctx context.Context) {
+	const op = "ratelimit.Run"
+	log := l.logger.With(slog.String("op", op))
+
+	idle := max(l.fallback.fillTime(), l.perIP.fillTime())
+	for _, rule := range l.routes {
+		idle = max(idle, rule.fillTime())
+	}
+
+	log.Info("Запущена очистка счетчиков ограничения запросов", slog.Duration("interval", l.sweep))
+
+	ticker := time.NewTicker(l.sweep)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info("Очистка счетчиков ограничения запросов остановлена")
+			return
+		case <-ticker.C:
+			if err := l.store.Sweep(ctx, idle); err != nil {
+				log.Error("Не удалось удалить неиспользуемые счетчики", slog.String("error", err.Error()))
+			}
+		}
+	}
+}
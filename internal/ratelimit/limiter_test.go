@@ -0,0 +1,200 @@
+package ratelimit
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/vasiliy-maslov/go-subscription-service/internal/config"
+)
+
+func newTestLimiter(t *testing.T, cfg config.RateLimitConfig) (*Limiter, *MemoryStore) {
+	t.Helper()
+	store := NewMemoryStore()
+	limiter, err := NewLimiter(cfg, store, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("NewLimiter() error = %v", err)
+	}
+	return limiter, store
+}
+
+// rewind сдвигает время последнего обращения ко всем корзинам на d назад, как будто прошло d.
+func rewind(store *MemoryStore, d time.Duration) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	for _, b := range store.buckets {
+		b.updatedAt = b.updatedAt.Add(-d)
+	}
+}
+
+func TestLimiterBurst(t *testing.T) {
+	limiter, _ := newTestLimiter(t, config.RateLimitConfig{
+		Default: config.RateLimitRule{Requests: 60, Period: time.Minute, Burst: 3},
+		PerIP:   config.RateLimitRule{Requests: 60, Period: time.Minute},
+	})
+	ctx := context.Background()
+
+	tests := []struct {
+		name           string
+		wantAllowed    bool
+		wantRemaining  int
+		wantRetryAfter time.Duration
+	}{
+		{name: "first request", wantAllowed: true, wantRemaining: 2},
+		{name: "second request", wantAllowed: true, wantRemaining: 1},
+		{name: "last request of the burst", wantAllowed: true, wantRemaining: 0},
+		{name: "request over the burst", wantAllowed: false, wantRemaining: 0, wantRetryAfter: time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := limiter.Allow(ctx, "GET", "/api/v1/subscriptions/", "user-1")
+			if err != nil {
+				t.Fatalf("Allow() error = %v", err)
+			}
+			if d.Allowed != tt.wantAllowed || d.Remaining != tt.wantRemaining || d.RetryAfter != tt.wantRetryAfter {
+				t.Errorf("Allow() = {Allowed: %v, Remaining: %d, RetryAfter: %s}, want {Allowed: %v, Remaining: %d, RetryAfter: %s}",
+					d.Allowed, d.Remaining, d.RetryAfter, tt.wantAllowed, tt.wantRemaining, tt.wantRetryAfter)
+			}
+		})
+	}
+}
+
+func TestLimiterRefill(t *testing.T) {
+	tests := []struct {
+		name        string
+		elapsed     time.Duration
+		wantAllowed int
+	}{
+		{name: "no time passed", elapsed: 0, wantAllowed: 0},
+		{name: "less than one token", elapsed: 500 * time.Millisecond, wantAllowed: 0},
+		{name: "two tokens", elapsed: 2 * time.Second, wantAllowed: 2},
+		{name: "refill stops at the burst", elapsed: time.Hour, wantAllowed: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter, store := newTestLimiter(t, config.RateLimitConfig{
+				Default: config.RateLimitRule{Requests: 60, Period: time.Minute, Burst: 3},
+				PerIP:   config.RateLimitRule{Requests: 60, Period: time.Minute},
+			})
+			ctx := context.Background()
+
+			for range 3 {
+				if d, _ := limiter.Allow(ctx, "GET", "/", "user-1"); !d.Allowed {
+					t.Fatal("Allow() denied a request within the burst")
+				}
+			}
+			rewind(store, tt.elapsed)
+
+			allowed := 0
+			for range 5 {
+				d, err := limiter.Allow(ctx, "GET", "/", "user-1")
+				if err != nil {
+					t.Fatalf("Allow() error = %v", err)
+				}
+				if !d.Allowed {
+					break
+				}
+				allowed++
+			}
+			if allowed != tt.wantAllowed {
+				t.Errorf("Allow() allowed %d requests after %s, want %d", allowed, tt.elapsed, tt.wantAllowed)
+			}
+		})
+	}
+}
+
+func TestLimiterBuckets(t *testing.T) {
+	limiter, _ := newTestLimiter(t, config.RateLimitConfig{
+		Default: config.RateLimitRule{Requests: 1, Period: time.Minute},
+		PerIP:   config.RateLimitRule{Requests: 1, Period: time.Minute},
+		Routes: []config.RouteRateLimit{{
+			Method:        "get",
+			Path:          "/api/v1/subscriptions/total_cost",
+			RateLimitRule: config.RateLimitRule{Requests: 1, Period: time.Minute},
+		}},
+	})
+	ctx := context.Background()
+
+	if d, _ := limiter.Allow(ctx, "GET", "/api/v1/subscriptions/", "user-1"); !d.Allowed {
+		t.Fatal("Allow() denied the first request")
+	}
+
+	tests := []struct {
+		name        string
+		allow       func() (Decision, error)
+		wantAllowed bool
+	}{
+		{
+			name:  "another route shares the default bucket",
+			allow: func() (Decision, error) { return limiter.Allow(ctx, "DELETE", "/api/v1/subscriptions/:id", "user-1") },
+		},
+		{
+			name: "route with its own rule",
+			allow: func() (Decision, error) {
+				return limiter.Allow(ctx, "GET", "/api/v1/subscriptions/total_cost", "user-1")
+			},
+			wantAllowed: true,
+		},
+		{
+			name:        "another client",
+			allow:       func() (Decision, error) { return limiter.Allow(ctx, "GET", "/api/v1/subscriptions/", "user-2") },
+			wantAllowed: true,
+		},
+		{
+			name:        "IP bucket named like the client",
+			allow:       func() (Decision, error) { return limiter.AllowIP(ctx, "user-1") },
+			wantAllowed: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := tt.allow()
+			if err != nil {
+				t.Fatalf("error = %v", err)
+			}
+			if d.Allowed != tt.wantAllowed {
+				t.Errorf("Allowed = %v, want %v", d.Allowed, tt.wantAllowed)
+			}
+		})
+	}
+}
+
+func TestNewLimiterRules(t *testing.T) {
+	valid := config.RateLimitRule{Requests: 10, Period: time.Minute}
+
+	tests := []struct {
+		name    string
+		cfg     config.RateLimitConfig
+		wantErr bool
+	}{
+		{name: "burst defaults to requests", cfg: config.RateLimitConfig{Default: valid, PerIP: valid}},
+		{name: "no requests", cfg: config.RateLimitConfig{Default: config.RateLimitRule{Period: time.Minute}, PerIP: valid}, wantErr: true},
+		{name: "no period", cfg: config.RateLimitConfig{Default: valid, PerIP: config.RateLimitRule{Requests: 10}}, wantErr: true},
+		{
+			name: "negative burst of a route",
+			cfg: config.RateLimitConfig{Default: valid, PerIP: valid, Routes: []config.RouteRateLimit{{
+				Method:        "GET",
+				Path:          "/",
+				RateLimitRule: config.RateLimitRule{Requests: 10, Period: time.Minute, Burst: -1},
+			}}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter, err := NewLimiter(tt.cfg, NewMemoryStore(), slog.New(slog.NewTextHandler(io.Discard, nil)))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewLimiter() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && limiter.fallback.Burst != valid.Requests {
+				t.Errorf("Burst = %d, want %d", limiter.fallback.Burst, valid.Requests)
+			}
+		})
+	}
+}
@@ -0,0 +1,173 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/vasiliy-maslov/go-subscription-service/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const apiKeyColumns = `id, name, prefix, permissions, user_ids, expires_at, last_used_at, revoked_at, created_at`
+
+var _ APIKeyRepository = (*APIKeyRepo)(nil)
+
+type APIKeyRepo struct {
+	db *pgxpool.Pool
+}
+
+// NewAPIKeyRepo создает новый экземпляр репозитория API-ключей.
+func NewAPIKeyRepo(db *pgxpool.Pool) *APIKeyRepo {
+	return &APIKeyRepo{db: db}
+}
+
+// Create сохраняет новый ключ с хешем его секрета.
+func (r *APIKeyRepo) Create(ctx context.Context, key model.APIKey, secretHash string) (model.APIKey, error) {
+	query := `
+		INSERT INTO api_keys (id, name, prefix, secret_hash, permissions, user_ids, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NOW())
+		RETURNING ` + apiKeyColumns
+
+	created, err := scanAPIKey(conn(ctx, r.db).QueryRow(ctx, query,
+		uuid.New(), key.Name, key.Prefix, secretHash, permissionNames(key.Permissions), key.UserIDs, key.ExpiresAt))
+	if err != nil {
+		if isUniqueViolation(err) {
+			return model.APIKey{}, ErrAlreadyExists
+		}
+		return model.APIKey{}, err
+	}
+
+	return created, nil
+}
+
+// GetByPrefix возвращает ключ и хеш его секрета по открытой части ключа.
+func (r *APIKeyRepo) GetByPrefix(ctx context.Context, prefix string) (model.APIKey, string, error) {
+	query := `SELECT ` + apiKeyColumns + `, secret_hash FROM api_keys WHERE prefix = $1`
+
+	var (
+		key         model.APIKey
+		permissions []string
+		secretHash  string
+	)
+	err := conn(ctx, r.db).QueryRow(ctx, query, prefix).Scan(&key.ID, &key.Name, &key.Prefix, &permissions, &key.UserIDs,
+		&key.ExpiresAt, &key.LastUsedAt, &key.RevokedAt, &key.CreatedAt, &secretHash)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.APIKey{}, "", ErrAPIKeyNotFound
+		}
+		return model.APIKey{}, "", err
+	}
+	key.Permissions = toPermissions(permissions)
+
+	return key, secretHash, nil
+}
+
+// List возвращает все ключи, начиная с новых.
+func (r *APIKeyRepo) List(ctx context.Context) ([]model.APIKey, error) {
+	rows, err := conn(ctx, r.db).Query(ctx, `SELECT `+apiKeyColumns+` FROM api_keys ORDER BY created_at DESC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := make([]model.APIKey, 0)
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, rows.Err()
+}
+
+// Revoke отзывает ключ. Повторный отзыв сохраняет дату первого.
+func (r *APIKeyRepo) Revoke(ctx context.Context, id uuid.UUID) (model.APIKey, error) {
+	query := `
+		UPDATE api_keys
+		SET revoked_at = COALESCE(revoked_at, NOW())
+		WHERE id = $1
+		RETURNING ` + apiKeyColumns
+
+	key, err := scanAPIKey(conn(ctx, r.db).QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.APIKey{}, ErrAPIKeyNotFound
+		}
+		return model.APIKey{}, err
+	}
+
+	return key, nil
+}
+
+// RemoveUser исключает пользователя из списка пользователей ключей и возвращает измененные ключи.
+// Пустой список снимает ограничение по пользователям, поэтому ключ, у которого не осталось
+// пользователей, отзывается.
+func (r *APIKeyRepo) RemoveUser(ctx context.Context, userID uuid.UUID) ([]model.APIKey, error) {
+	query := `
+		UPDATE api_keys
+		SET user_ids = array_remove(user_ids, $1::uuid),
+			revoked_at = CASE
+				WHEN cardinality(array_remove(user_ids, $1::uuid)) = 0 THEN COALESCE(revoked_at, NOW())
+				ELSE revoked_at
+			END
+		WHERE $1::uuid = ANY(user_ids)
+		RETURNING ` + apiKeyColumns
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := make([]model.APIKey, 0)
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, rows.Err()
+}
+
+// Touch отмечает использование ключа. Чтобы частые запросы не создавали запись на каждый вызов,
+// время обновляется не чаще раза в минуту.
+func (r *APIKeyRepo) Touch(ctx context.Context, id uuid.UUID) error {
+	_, err := conn(ctx, r.db).Exec(ctx, `
+		UPDATE api_keys SET last_used_at = NOW()
+		WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < NOW() - INTERVAL '1 minute')`, id)
+	return err
+}
+
+func scanAPIKey(row pgx.Row) (model.APIKey, error) {
+	var (
+		key         model.APIKey
+		permissions []string
+	)
+	err := row.Scan(&key.ID, &key.Name, &key.Prefix, &permissions, &key.UserIDs,
+		&key.ExpiresAt, &key.LastUsedAt, &key.RevokedAt, &key.CreatedAt)
+	key.Permissions = toPermissions(permissions)
+	return key, err
+}
+
+func permissionNames(permissions []model.Permission) []string {
+	names := make([]string, len(permissions))
+	for i, p := range permissions {
+		names[i] = string(p)
+	}
+	return names
+}
+
+func toPermissions(names []string) []model.Permission {
+	permissions := make([]model.Permission, len(names))
+	for i, name := range names {
+		permissions[i] = model.Permission(name)
+	}
+	return permissions
+}
@@ -0,0 +1,201 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/vasiliy-maslov/go-subscription-service/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// auditColumns выбирает запись журнала вместе с исполнителем и IP-адресом из audit_identities
+// и ID ресурса и параметрами из audit_subjects. После удаления данных пользователя они пусты,
+// а identity_erased и subject_erased истинны.
+const auditColumns = `a.seq, a.occurred_at, a.actor_type, i.actor_id, a.action, a.resource_type,
+	COALESCE(s.resource_id, ''), COALESCE(s.params, '{}'), a.request_id, a.outcome, a.status, COALESCE(i.source_ip, ''),
+	a.identity_hash, COALESCE(i.salt, ''), i.seq IS NULL, a.subject_hash, COALESCE(s.salt, ''), s.seq IS NULL,
+	a.prev_hash, a.hash`
+
+const auditFrom = ` FROM audit_log a
+	LEFT JOIN audit_identities i ON i.seq = a.seq
+	LEFT JOIN audit_subjects s ON s.seq = a.seq`
+
+// auditChainLock - ключ advisory-блокировки, под которой добавляются записи журнала аудита.
+const auditChainLock = 0x61756469 // "audi"
+
+var _ AuditRepository = (*AuditRepo)(nil)
+
+type AuditRepo struct {
+	db *pgxpool.Pool
+}
+
+// NewAuditRepo создает новый экземпляр репозитория журнала аудита.
+func NewAuditRepo(db *pgxpool.Pool) *AuditRepo {
+	return &AuditRepo{db: db}
+}
+
+func scanAuditEntry(row pgx.Row) (model.AuditEntry, error) {
+	var e model.AuditEntry
+	err := row.Scan(&e.Seq, &e.OccurredAt, &e.ActorType, &e.ActorID, &e.Action, &e.ResourceType, &e.ResourceID, &e.Params,
+		&e.RequestID, &e.Outcome, &e.Status, &e.SourceIP, &e.IdentityHash, &e.IdentitySalt, &e.IdentityErased,
+		&e.SubjectHash, &e.SubjectSalt, &e.SubjectErased, &e.PrevHash, &e.Hash)
+	return e, err
+}
+
+// LockChain берет транзакционную advisory-блокировку цепочки. Вызывается внутри транзакции.
+func (r *AuditRepo) LockChain(ctx context.Context) error {
+	_, err := conn(ctx, r.db).Exec(ctx, `SELECT pg_advisory_xact_lock($1)`, int64(auditChainLock))
+	return err
+}
+
+// LastHash возвращает хеш последней записи или пустую строку для пустого журнала.
+func (r *AuditRepo) LastHash(ctx context.Context) (string, error) {
+	var hash string
+	err := conn(ctx, r.db).QueryRow(ctx, `SELECT hash FROM audit_log ORDER BY seq DESC LIMIT 1`).Scan(&hash)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", nil
+	}
+	return hash, err
+}
+
+// Append добавляет запись с уже вычисленным хешем, исполнителя и IP-адрес - в audit_identities,
+// а ID ресурса и параметры - в audit_subjects, и возвращает запись с номером. Части, отмеченные
+// удаленными (IdentityErased, SubjectErased), не сохраняются: запись выглядит так, будто их удалили
+// сразу после добавления. Вызывается внутри транзакции.
+func (r *AuditRepo) Append(ctx context.Context, e model.AuditEntry) (model.AuditEntry, error) {
+	query := `
+		INSERT INTO audit_log (occurred_at, actor_type, action, resource_type, request_id, outcome, status,
+			identity_hash, subject_hash, prev_hash, hash)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING seq`
+
+	db := conn(ctx, r.db)
+	err := db.QueryRow(ctx, query,
+		e.OccurredAt, e.ActorType, e.Action, e.ResourceType, e.RequestID, e.Outcome, e.Status,
+		e.IdentityHash, e.SubjectHash, e.PrevHash, e.Hash).Scan(&e.Seq)
+	if err != nil {
+		return model.AuditEntry{}, err
+	}
+
+	if e.IdentityErased {
+		e.ActorID, e.SourceIP, e.IdentitySalt = nil, "", ""
+	} else {
+		_, err = db.Exec(ctx, `INSERT INTO audit_identities (seq, actor_id, source_ip, salt) VALUES ($1, $2, $3, $4)`,
+			e.Seq, e.ActorID, e.SourceIP, e.IdentitySalt)
+		if err != nil {
+			return model.AuditEntry{}, err
+		}
+	}
+
+	if e.SubjectErased {
+		e.ResourceID, e.Params, e.SubjectSalt = "", nil, ""
+	} else {
+		params := e.Params
+		if params == nil {
+			params = map[string]string{}
+		}
+		_, err = db.Exec(ctx, `INSERT INTO audit_subjects (seq, resource_id, params, salt) VALUES ($1, $2, $3, $4)`,
+			e.Seq, e.ResourceID, params, e.SubjectSalt)
+		if err != nil {
+			return model.AuditEntry{}, err
+		}
+	}
+
+	return e, nil
+}
+
+// EraseUser удаляет из журнала все упоминания пользователя userID: исполнителя и IP-адрес его записей,
+// а также ID ресурса и параметры записей, в которых встречается его ID. Возвращает число записей,
+// из которых что-то удалено. Сами записи и их хеши остаются в цепочке.
+func (r *AuditRepo) EraseUser(ctx context.Context, userID uuid.UUID) (int64, error) {
+	query := `
+		WITH identities AS (
+			DELETE FROM audit_identities WHERE actor_id = $1 RETURNING seq
+		), subjects AS (
+			DELETE FROM audit_subjects
+			WHERE resource_id = $1::text OR strpos(params::text, $1::text) > 0
+			RETURNING seq
+		)
+		SELECT count(*) FROM (SELECT seq FROM identities UNION SELECT seq FROM subjects) erased`
+
+	var erased int64
+	err := conn(ctx, r.db).QueryRow(ctx, query, userID).Scan(&erased)
+	return erased, err
+}
+
+// List возвращает записи журнала по фильтру, начиная с новых.
+func (r *AuditRepo) List(ctx context.Context, filter AuditFilter) ([]model.AuditEntry, error) {
+	query := `SELECT ` + auditColumns + auditFrom + ` WHERE TRUE`
+	args := []any{}
+
+	if filter.ActorID != nil {
+		args = append(args, *filter.ActorID)
+		query += fmt.Sprintf(" AND i.actor_id = $%d", len(args))
+	}
+	if filter.ActorType != nil {
+		args = append(args, *filter.ActorType)
+		query += fmt.Sprintf(" AND a.actor_type = $%d", len(args))
+	}
+	if filter.Action != nil {
+		args = append(args, *filter.Action)
+		query += fmt.Sprintf(" AND a.action = $%d", len(args))
+	}
+	if filter.ResourceID != nil {
+		args = append(args, *filter.ResourceID)
+		query += fmt.Sprintf(" AND s.resource_id = $%d", len(args))
+	}
+	if filter.Outcome != nil {
+		args = append(args, *filter.Outcome)
+		query += fmt.Sprintf(" AND a.outcome = $%d", len(args))
+	}
+	if filter.RequestID != nil {
+		args = append(args, *filter.RequestID)
+		query += fmt.Sprintf(" AND a.request_id = $%d", len(args))
+	}
+	if filter.From != nil {
+		args = append(args, *filter.From)
+		query += fmt.Sprintf(" AND a.occurred_at >= $%d", len(args))
+	}
+	if filter.To != nil {
+		args = append(args, *filter.To)
+		query += fmt.Sprintf(" AND a.occurred_at < $%d", len(args))
+	}
+	if filter.Before != nil {
+		args = append(args, *filter.Before)
+		query += fmt.Sprintf(" AND a.seq < $%d", len(args))
+	}
+
+	args = append(args, filter.Limit)
+	query += fmt.Sprintf(" ORDER BY a.seq DESC LIMIT $%d", len(args))
+
+	return r.query(ctx, query, args...)
+}
+
+// Chain возвращает записи в порядке цепочки для проверки хешей.
+func (r *AuditRepo) Chain(ctx context.Context, afterSeq int64, limit int) ([]model.AuditEntry, error) {
+	query := `SELECT ` + auditColumns + auditFrom + ` WHERE a.seq > $1 ORDER BY a.seq LIMIT $2`
+	return r.query(ctx, query, afterSeq, limit)
+}
+
+func (r *AuditRepo) query(ctx context.Context, query string, args ...any) ([]model.AuditEntry, error) {
+	rows, err := conn(ctx, r.db).Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := make([]model.AuditEntry, 0)
+	for rows.Next() {
+		e, err := scanAuditEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+
+	return entries, rows.Err()
+}
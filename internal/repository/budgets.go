@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/vasiliy-maslov/go-subscription-service/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const budgetColumns = `id, user_id, category_id, service_id, amount, currency, thresholds, created_at, updated_at`
+
+var _ BudgetRepository = (*BudgetRepo)(nil)
+
+type BudgetRepo struct {
+	db *pgxpool.Pool
+}
+
+// NewBudgetRepo создает новый экземпляр репозитория бюджетов.
+func NewBudgetRepo(db *pgxpool.Pool) *BudgetRepo {
+	return &BudgetRepo{db: db}
+}
+
+// Create сохраняет новый бюджет.
+func (r *BudgetRepo) Create(ctx context.Context, b model.Budget) (model.Budget, error) {
+	query := `
+		INSERT INTO budgets (id, user_id, category_id, service_id, amount, currency, thresholds, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NOW(), NOW())
+		RETURNING ` + budgetColumns
+
+	created, err := scanBudget(conn(ctx, r.db).QueryRow(ctx, query,
+		uuid.New(), b.UserID, b.CategoryID, b.ServiceID, b.Amount, b.Currency, b.Thresholds))
+	if err != nil {
+		if isUserViolation(err) {
+			return model.Budget{}, ErrUserNotFound
+		}
+		return model.Budget{}, err
+	}
+
+	return created, nil
+}
+
+// GetByID получает бюджет по ID.
+func (r *BudgetRepo) GetByID(ctx context.Context, id uuid.UUID) (model.Budget, error) {
+	query := `SELECT ` + budgetColumns + ` FROM budgets WHERE id = $1`
+
+	b, err := scanBudget(conn(ctx, r.db).QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.Budget{}, ErrBudgetNotFound
+		}
+		return model.Budget{}, err
+	}
+
+	return b, nil
+}
+
+// ListByUserID возвращает бюджеты пользователя.
+func (r *BudgetRepo) ListByUserID(ctx context.Context, userID uuid.UUID) ([]model.Budget, error) {
+	query := `SELECT ` + budgetColumns + ` FROM budgets WHERE user_id = $1 ORDER BY created_at`
+	return r.query(ctx, query, userID)
+}
+
+// ListAll возвращает все бюджеты. Используется фоновой проверкой порогов.
+func (r *BudgetRepo) ListAll(ctx context.Context) ([]model.Budget, error) {
+	query := `SELECT ` + budgetColumns + ` FROM budgets ORDER BY user_id, created_at`
+	return r.query(ctx, query)
+}
+
+// Update заменяет лимит, область и пороги бюджета.
+func (r *BudgetRepo) Update(ctx context.Context, id uuid.UUID, b model.Budget) (model.Budget, error) {
+	query := `
+		UPDATE budgets
+		SET category_id = $1, service_id = $2, amount = $3, currency = $4, thresholds = $5, updated_at = NOW()
+		WHERE id = $6
+		RETURNING ` + budgetColumns
+
+	updated, err := scanBudget(conn(ctx, r.db).QueryRow(ctx, query,
+		b.CategoryID, b.ServiceID, b.Amount, b.Currency, b.Thresholds, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.Budget{}, ErrBudgetNotFound
+		}
+		return model.Budget{}, err
+	}
+
+	return updated, nil
+}
+
+// Delete удаляет бюджет вместе с историей оповещений.
+func (r *BudgetRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	res, err := conn(ctx, r.db).Exec(ctx, `DELETE FROM budgets WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return ErrBudgetNotFound
+	}
+
+	return nil
+}
+
+// RecordAlert отмечает оповещение о пороге за месяц. Уникальный ключ таблицы не дает
+// отправить оповещение повторно, даже если проверку одновременно выполняют несколько реплик.
+func (r *BudgetRepo) RecordAlert(ctx context.Context, budgetID uuid.UUID, period model.Date, threshold int) (bool, error) {
+	query := `
+		INSERT INTO budget_alerts (budget_id, period, threshold, created_at)
+		VALUES ($1, $2, $3, NOW())
+		ON CONFLICT DO NOTHING`
+
+	res, err := conn(ctx, r.db).Exec(ctx, query, budgetID, period, threshold)
+	if err != nil {
+		return false, err
+	}
+
+	return res.RowsAffected() > 0, nil
+}
+
+func (r *BudgetRepo) query(ctx context.Context, query string, args ...any) ([]model.Budget, error) {
+	rows, err := conn(ctx, r.db).Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	budgets := []model.Budget{}
+	for rows.Next() {
+		b, err := scanBudget(rows)
+		if err != nil {
+			return nil, err
+		}
+		budgets = append(budgets, b)
+	}
+
+	return budgets, rows.Err()
+}
+
+func scanBudget(row pgx.Row) (model.Budget, error) {
+	var b model.Budget
+	err := row.Scan(&b.ID, &b.UserID, &b.CategoryID, &b.ServiceID, &b.Amount, &b.Currency, &b.Thresholds, &b.CreatedAt, &b.UpdatedAt)
+	return b, err
+}
@@ -0,0 +1,297 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/vasiliy-maslov/go-subscription-service/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var _ CatalogRepository = (*CatalogRepo)(nil)
+
+type CatalogRepo struct {
+	db *pgxpool.Pool
+}
+
+// NewCatalogRepo создает новый экземпляр репозитория каталога сервисов.
+func NewCatalogRepo(db *pgxpool.Pool) *CatalogRepo {
+	return &CatalogRepo{db: db}
+}
+
+// Create сохраняет сервис вместе с псевдонимами и тарифами.
+// Вызывается внутри транзакции, чтобы запись каталога не сохранилась частично.
+func (r *CatalogRepo) Create(ctx context.Context, svc model.CatalogService) (uuid.UUID, error) {
+	svc.ID = uuid.New()
+
+	query := `
+		INSERT INTO services (id, name, category, logo_url, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, NOW(), NOW())`
+
+	if _, err := conn(ctx, r.db).Exec(ctx, query, svc.ID, svc.Name, svc.Category, svc.LogoURL); err != nil {
+		if isUniqueViolation(err) {
+			return uuid.Nil, ErrAlreadyExists
+		}
+		return uuid.Nil, err
+	}
+
+	if err := r.replaceAliases(ctx, svc.ID, svc.Name, svc.Aliases); err != nil {
+		return uuid.Nil, err
+	}
+
+	if err := r.replacePlans(ctx, svc.ID, svc.Plans); err != nil {
+		return uuid.Nil, err
+	}
+
+	return svc.ID, nil
+}
+
+// GetByID получает сервис каталога по ID.
+func (r *CatalogRepo) GetByID(ctx context.Context, id uuid.UUID) (model.CatalogService, error) {
+	query := `SELECT id, name, category, logo_url, created_at, updated_at FROM services WHERE id = $1`
+
+	var svc model.CatalogService
+	err := conn(ctx, r.db).QueryRow(ctx, query, id).Scan(
+		&svc.ID, &svc.Name, &svc.Category, &svc.LogoURL, &svc.CreatedAt, &svc.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.CatalogService{}, ErrServiceNotFound
+		}
+		return model.CatalogService{}, err
+	}
+
+	services := []model.CatalogService{svc}
+	if err := r.loadDetails(ctx, services); err != nil {
+		return model.CatalogService{}, err
+	}
+
+	return services[0], nil
+}
+
+// FindByAlias ищет сервис по нормализованному названию или псевдониму.
+func (r *CatalogRepo) FindByAlias(ctx context.Context, normalized string) (model.CatalogService, error) {
+	var id uuid.UUID
+	err := conn(ctx, r.db).QueryRow(ctx,
+		`SELECT service_id FROM service_aliases WHERE alias_normalized = $1`, normalized).Scan(&id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.CatalogService{}, ErrServiceNotFound
+		}
+		return model.CatalogService{}, err
+	}
+
+	return r.GetByID(ctx, id)
+}
+
+// List возвращает сервисы каталога, опционально отфильтрованные по категории.
+func (r *CatalogRepo) List(ctx context.Context, category *string) ([]model.CatalogService, error) {
+	query := `SELECT id, name, category, logo_url, created_at, updated_at FROM services`
+
+	var args []any
+	if category != nil {
+		query += " WHERE category = $1"
+		args = append(args, *category)
+	}
+
+	query += " ORDER BY name"
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	services := []model.CatalogService{}
+	for rows.Next() {
+		var svc model.CatalogService
+		if err := rows.Scan(&svc.ID, &svc.Name, &svc.Category, &svc.LogoURL, &svc.CreatedAt, &svc.UpdatedAt); err != nil {
+			return nil, err
+		}
+		services = append(services, svc)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := r.loadDetails(ctx, services); err != nil {
+		return nil, err
+	}
+
+	return services, nil
+}
+
+// Update заменяет данные сервиса, его псевдонимы и тарифы.
+// Тарифы с прежними названиями сохраняют свои ID, чтобы не терять ссылки из подписок.
+func (r *CatalogRepo) Update(ctx context.Context, id uuid.UUID, svc model.CatalogService) error {
+	query := `
+		UPDATE services
+		SET name = $1, category = $2, logo_url = $3, updated_at = NOW()
+		WHERE id = $4`
+
+	res, err := conn(ctx, r.db).Exec(ctx, query, svc.Name, svc.Category, svc.LogoURL, id)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return ErrAlreadyExists
+		}
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return ErrServiceNotFound
+	}
+
+	if err := r.replaceAliases(ctx, id, svc.Name, svc.Aliases); err != nil {
+		return err
+	}
+
+	return r.replacePlans(ctx, id, svc.Plans)
+}
+
+// Delete удаляет сервис из каталога. Подписки сохраняют строковое название, но теряют ссылку на каталог.
+func (r *CatalogRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	res, err := conn(ctx, r.db).Exec(ctx, `DELETE FROM services WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return ErrServiceNotFound
+	}
+
+	return nil
+}
+
+// LinkSubscriptions привязывает к сервису подписки без ссылки на каталог, чье название совпадает
+// с одним из псевдонимов, и приводит их название к каноническому. Возвращает число привязанных подписок.
+func (r *CatalogRepo) LinkSubscriptions(ctx context.Context, id uuid.UUID) (int64, error) {
+	query := `
+		UPDATE subscriptions s
+		SET service_id = a.service_id, service_name = sv.name, updated_at = NOW()
+		FROM service_aliases a
+		JOIN services sv ON sv.id = a.service_id
+		WHERE a.service_id = $1
+		  AND s.service_id IS NULL
+		  AND lower(regexp_replace(btrim(s.service_name), '\s+', ' ', 'g')) = a.alias_normalized`
+
+	res, err := conn(ctx, r.db).Exec(ctx, query, id)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected(), nil
+}
+
+func (r *CatalogRepo) replaceAliases(ctx context.Context, id uuid.UUID, name string, aliases []string) error {
+	if _, err := conn(ctx, r.db).Exec(ctx, `DELETE FROM service_aliases WHERE service_id = $1`, id); err != nil {
+		return err
+	}
+
+	seen := make(map[string]struct{})
+	for _, alias := range append([]string{name}, aliases...) {
+		normalized := model.NormalizeServiceName(alias)
+		if _, ok := seen[normalized]; ok || normalized == "" {
+			continue
+		}
+		seen[normalized] = struct{}{}
+
+		_, err := conn(ctx, r.db).Exec(ctx,
+			`INSERT INTO service_aliases (alias_normalized, alias, service_id) VALUES ($1, $2, $3)`,
+			normalized, alias, id)
+		if err != nil {
+			if isUniqueViolation(err) {
+				return ErrAlreadyExists
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *CatalogRepo) replacePlans(ctx context.Context, id uuid.UUID, plans []model.ServicePlan) error {
+	names := make([]string, 0, len(plans))
+	for _, plan := range plans {
+		names = append(names, plan.Name)
+	}
+
+	_, err := conn(ctx, r.db).Exec(ctx,
+		`DELETE FROM service_plans WHERE service_id = $1 AND NOT (name = ANY($2))`, id, names)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO service_plans (id, service_id, name, price, currency)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (service_id, name) DO UPDATE SET price = EXCLUDED.price, currency = EXCLUDED.currency`
+
+	for _, plan := range plans {
+		if _, err := conn(ctx, r.db).Exec(ctx, query, uuid.New(), id, plan.Name, plan.Price, plan.Currency); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// loadDetails дозагружает псевдонимы и тарифы для списка сервисов двумя запросами.
+func (r *CatalogRepo) loadDetails(ctx context.Context, services []model.CatalogService) error {
+	if len(services) == 0 {
+		return nil
+	}
+
+	ids := make([]uuid.UUID, 0, len(services))
+	index := make(map[uuid.UUID]int, len(services))
+	for i := range services {
+		ids = append(ids, services[i].ID)
+		index[services[i].ID] = i
+		services[i].Aliases = []string{}
+		services[i].Plans = []model.ServicePlan{}
+	}
+
+	rows, err := conn(ctx, r.db).Query(ctx,
+		`SELECT service_id, alias FROM service_aliases WHERE service_id = ANY($1) ORDER BY alias`, ids)
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+		var (
+			id    uuid.UUID
+			alias string
+		)
+		if err := rows.Scan(&id, &alias); err != nil {
+			rows.Close()
+			return err
+		}
+		svc := &services[index[id]]
+		if alias != svc.Name {
+			svc.Aliases = append(svc.Aliases, alias)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	rows, err = conn(ctx, r.db).Query(ctx,
+		`SELECT id, service_id, name, price, currency FROM service_plans WHERE service_id = ANY($1) ORDER BY price, name`, ids)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var plan model.ServicePlan
+		if err := rows.Scan(&plan.ID, &plan.ServiceID, &plan.Name, &plan.Price, &plan.Currency); err != nil {
+			return err
+		}
+		svc := &services[index[plan.ServiceID]]
+		svc.Plans = append(svc.Plans, plan)
+	}
+
+	return rows.Err()
+}
@@ -0,0 +1,322 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/vasiliy-maslov/go-subscription-service/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var _ CategoryRepository = (*CategoryRepo)(nil)
+
+type CategoryRepo struct {
+	db *pgxpool.Pool
+}
+
+// NewCategoryRepo создает новый экземпляр репозитория категорий и меток.
+func NewCategoryRepo(db *pgxpool.Pool) *CategoryRepo {
+	return &CategoryRepo{db: db}
+}
+
+// CreateCategory создает категорию пользователя.
+func (r *CategoryRepo) CreateCategory(ctx context.Context, category model.Category) (model.Category, error) {
+	query := `
+		INSERT INTO categories (id, user_id, name, created_at)
+		VALUES ($1, $2, $3, NOW())
+		RETURNING id, user_id, name, created_at`
+
+	var created model.Category
+	err := conn(ctx, r.db).QueryRow(ctx, query, uuid.New(), category.UserID, category.Name).Scan(
+		&created.ID, &created.UserID, &created.Name, &created.CreatedAt)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return model.Category{}, ErrAlreadyExists
+		}
+		if isUserViolation(err) {
+			return model.Category{}, ErrUserNotFound
+		}
+		return model.Category{}, err
+	}
+
+	return created, nil
+}
+
+// GetCategories возвращает категории по списку ID.
+func (r *CategoryRepo) GetCategories(ctx context.Context, ids []uuid.UUID) ([]model.Category, error) {
+	query := `SELECT id, user_id, name, created_at FROM categories WHERE id = ANY($1) ORDER BY name`
+	return r.queryCategories(ctx, query, ids)
+}
+
+// ListCategories возвращает категории пользователя.
+func (r *CategoryRepo) ListCategories(ctx context.Context, userID uuid.UUID) ([]model.Category, error) {
+	query := `SELECT id, user_id, name, created_at FROM categories WHERE user_id = $1 ORDER BY name`
+	return r.queryCategories(ctx, query, userID)
+}
+
+// RenameCategory меняет название категории.
+func (r *CategoryRepo) RenameCategory(ctx context.Context, id uuid.UUID, name string) (model.Category, error) {
+	query := `
+		UPDATE categories SET name = $1 WHERE id = $2
+		RETURNING id, user_id, name, created_at`
+
+	var updated model.Category
+	err := conn(ctx, r.db).QueryRow(ctx, query, name, id).Scan(
+		&updated.ID, &updated.UserID, &updated.Name, &updated.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.Category{}, ErrCategoryNotFound
+		}
+		if isUniqueViolation(err) {
+			return model.Category{}, ErrAlreadyExists
+		}
+		return model.Category{}, err
+	}
+
+	return updated, nil
+}
+
+// DeleteCategory удаляет категорию. Подписки остаются, теряя только привязку к ней.
+func (r *CategoryRepo) DeleteCategory(ctx context.Context, id uuid.UUID) error {
+	res, err := conn(ctx, r.db).Exec(ctx, `DELETE FROM categories WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return ErrCategoryNotFound
+	}
+
+	return nil
+}
+
+// CreateTag создает метку пользователя.
+func (r *CategoryRepo) CreateTag(ctx context.Context, tag model.Tag) (model.Tag, error) {
+	query := `
+		INSERT INTO tags (id, user_id, name, created_at)
+		VALUES ($1, $2, $3, NOW())
+		RETURNING id, user_id, name, created_at`
+
+	var created model.Tag
+	err := conn(ctx, r.db).QueryRow(ctx, query, uuid.New(), tag.UserID, tag.Name).Scan(
+		&created.ID, &created.UserID, &created.Name, &created.CreatedAt)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return model.Tag{}, ErrAlreadyExists
+		}
+		if isUserViolation(err) {
+			return model.Tag{}, ErrUserNotFound
+		}
+		return model.Tag{}, err
+	}
+
+	return created, nil
+}
+
+// ListTags возвращает метки пользователя.
+func (r *CategoryRepo) ListTags(ctx context.Context, userID uuid.UUID) ([]model.Tag, error) {
+	query := `SELECT id, user_id, name, created_at FROM tags WHERE user_id = $1 ORDER BY name`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tags := []model.Tag{}
+	for rows.Next() {
+		var tag model.Tag
+		if err := rows.Scan(&tag.ID, &tag.UserID, &tag.Name, &tag.CreatedAt); err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+
+	return tags, rows.Err()
+}
+
+// GetTag возвращает метку по ID.
+func (r *CategoryRepo) GetTag(ctx context.Context, id uuid.UUID) (model.Tag, error) {
+	query := `SELECT id, user_id, name, created_at FROM tags WHERE id = $1`
+
+	var tag model.Tag
+	err := conn(ctx, r.db).QueryRow(ctx, query, id).Scan(&tag.ID, &tag.UserID, &tag.Name, &tag.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.Tag{}, ErrTagNotFound
+		}
+		return model.Tag{}, err
+	}
+
+	return tag, nil
+}
+
+// RenameTag меняет название метки.
+func (r *CategoryRepo) RenameTag(ctx context.Context, id uuid.UUID, name string) (model.Tag, error) {
+	query := `
+		UPDATE tags SET name = $1 WHERE id = $2
+		RETURNING id, user_id, name, created_at`
+
+	var updated model.Tag
+	err := conn(ctx, r.db).QueryRow(ctx, query, name, id).Scan(
+		&updated.ID, &updated.UserID, &updated.Name, &updated.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.Tag{}, ErrTagNotFound
+		}
+		if isUniqueViolation(err) {
+			return model.Tag{}, ErrAlreadyExists
+		}
+		return model.Tag{}, err
+	}
+
+	return updated, nil
+}
+
+// DeleteTag удаляет метку вместе с ее привязками к подпискам.
+func (r *CategoryRepo) DeleteTag(ctx context.Context, id uuid.UUID) error {
+	res, err := conn(ctx, r.db).Exec(ctx, `DELETE FROM tags WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return ErrTagNotFound
+	}
+
+	return nil
+}
+
+// SetSubscriptionCategories заменяет набор категорий подписки.
+func (r *CategoryRepo) SetSubscriptionCategories(ctx context.Context, subscriptionID uuid.UUID, categoryIDs []uuid.UUID) error {
+	if _, err := conn(ctx, r.db).Exec(ctx,
+		`DELETE FROM subscription_categories WHERE subscription_id = $1`, subscriptionID); err != nil {
+		return err
+	}
+
+	if len(categoryIDs) == 0 {
+		return nil
+	}
+
+	_, err := conn(ctx, r.db).Exec(ctx, `
+		INSERT INTO subscription_categories (subscription_id, category_id)
+		SELECT $1, unnest($2::uuid[])
+		ON CONFLICT DO NOTHING`, subscriptionID, categoryIDs)
+
+	return err
+}
+
+// SetSubscriptionTags заменяет набор меток подписки. Отсутствующие у пользователя метки создаются.
+func (r *CategoryRepo) SetSubscriptionTags(ctx context.Context, subscriptionID, userID uuid.UUID, names []string) error {
+	if _, err := conn(ctx, r.db).Exec(ctx,
+		`DELETE FROM subscription_tags WHERE subscription_id = $1`, subscriptionID); err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		if _, err := conn(ctx, r.db).Exec(ctx, `
+			INSERT INTO tags (id, user_id, name, created_at)
+			VALUES ($1, $2, $3, NOW())
+			ON CONFLICT (user_id, lower(name)) DO NOTHING`, uuid.New(), userID, name); err != nil {
+			return err
+		}
+
+		_, err := conn(ctx, r.db).Exec(ctx, `
+			INSERT INTO subscription_tags (subscription_id, tag_id)
+			SELECT $1, id FROM tags WHERE user_id = $2 AND lower(name) = lower($3)
+			ON CONFLICT DO NOTHING`, subscriptionID, userID, name)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ListSubscriptionCategories возвращает категории набора подписок, сгруппированные по ID подписки.
+// Подписки, не видимые в контексте, пропускаются.
+func (r *CategoryRepo) ListSubscriptionCategories(ctx context.Context, subscriptionIDs []uuid.UUID) (map[uuid.UUID][]model.Category, error) {
+	query := `
+		SELECT sc.subscription_id, c.id, c.user_id, c.name, c.created_at
+		FROM subscription_categories sc
+		JOIN categories c ON c.id = sc.category_id
+		WHERE sc.subscription_id = ANY($1)`
+
+	args := []any{subscriptionIDs}
+	query += tenantScope(ctx, "sc.subscription_id", &args) + ` ORDER BY c.name`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make(map[uuid.UUID][]model.Category)
+	for rows.Next() {
+		var (
+			subscriptionID uuid.UUID
+			category       model.Category
+		)
+		if err := rows.Scan(&subscriptionID, &category.ID, &category.UserID, &category.Name, &category.CreatedAt); err != nil {
+			return nil, err
+		}
+		result[subscriptionID] = append(result[subscriptionID], category)
+	}
+
+	return result, rows.Err()
+}
+
+// ListSubscriptionTags возвращает метки набора подписок, сгруппированные по ID подписки.
+// Подписки, не видимые в контексте, пропускаются.
+func (r *CategoryRepo) ListSubscriptionTags(ctx context.Context, subscriptionIDs []uuid.UUID) (map[uuid.UUID][]model.Tag, error) {
+	query := `
+		SELECT st.subscription_id, t.id, t.user_id, t.name, t.created_at
+		FROM subscription_tags st
+		JOIN tags t ON t.id = st.tag_id
+		WHERE st.subscription_id = ANY($1)`
+
+	args := []any{subscriptionIDs}
+	query += tenantScope(ctx, "st.subscription_id", &args) + ` ORDER BY t.name`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make(map[uuid.UUID][]model.Tag)
+	for rows.Next() {
+		var (
+			subscriptionID uuid.UUID
+			tag            model.Tag
+		)
+		if err := rows.Scan(&subscriptionID, &tag.ID, &tag.UserID, &tag.Name, &tag.CreatedAt); err != nil {
+			return nil, err
+		}
+		result[subscriptionID] = append(result[subscriptionID], tag)
+	}
+
+	return result, rows.Err()
+}
+
+func (r *CategoryRepo) queryCategories(ctx context.Context, query string, args ...any) ([]model.Category, error) {
+	rows, err := conn(ctx, r.db).Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	categories := []model.Category{}
+	for rows.Next() {
+		var category model.Category
+		if err := rows.Scan(&category.ID, &category.UserID, &category.Name, &category.CreatedAt); err != nil {
+			return nil, err
+		}
+		categories = append(categories, category)
+	}
+
+	return categories, rows.Err()
+}
@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/vasiliy-maslov/go-subscription-service/internal/model"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// changesChannel - канал NOTIFY, в который триггер outbox отправляет seq изменений подписок.
+const changesChannel = "subscription_changes"
+
+// changeColumns выбирает событие outbox для scanChange. Условие changeEvents совпадает
+// с условием триггера outbox_notify_subscription_change и частичного индекса по нему.
+const (
+	changeColumns = `seq, aggregate_id, event_type, payload, created_at`
+	changeEvents  = `event_type IN ('subscription.created', 'subscription.updated', 'subscription.status_changed', 'subscription.deleted')`
+)
+
+var _ ChangeRepository = (*ChangeRepo)(nil)
+
+type ChangeRepo struct {
+	db *pgxpool.Pool
+}
+
+// NewChangeRepo создает новый экземпляр репозитория изменений подписок.
+func NewChangeRepo(db *pgxpool.Pool) *ChangeRepo {
+	return &ChangeRepo{db: db}
+}
+
+// scanChange читает событие outbox, выбранное с колонками changeColumns.
+// Содержимое событий о подписках - сама подписка после изменения.
+func scanChange(row pgx.Row) (model.SubscriptionChange, error) {
+	var (
+		change  model.SubscriptionChange
+		payload []byte
+	)
+	if err := row.Scan(&change.Seq, &change.SubscriptionID, &change.Event, &payload, &change.OccurredAt); err != nil {
+		return model.SubscriptionChange{}, err
+	}
+
+	if err := json.Unmarshal(payload, &change.Subscription); err != nil {
+		return model.SubscriptionChange{}, fmt.Errorf("event %d: %w", change.Seq, err)
+	}
+	change.Type = model.ChangeType(change.Event)
+	change.UserID = change.Subscription.UserID
+
+	return change, nil
+}
+
+// Recent возвращает последние limit изменений подписок в порядке их записи.
+func (r *ChangeRepo) Recent(ctx context.Context, limit int) ([]model.SubscriptionChange, error) {
+	query := `
+		SELECT ` + changeColumns + ` FROM (
+			SELECT ` + changeColumns + ` FROM outbox WHERE ` + changeEvents + `
+			ORDER BY seq DESC
+			LIMIT $1
+		) recent
+		ORDER BY seq`
+
+	return r.list(ctx, query, limit)
+}
+
+// After возвращает до limit изменений подписок, записанных после события с номером after.
+func (r *ChangeRepo) After(ctx context.Context, after int64, limit int) ([]model.SubscriptionChange, error) {
+	query := `
+		SELECT ` + changeColumns + ` FROM outbox
+		WHERE seq > $1 AND ` + changeEvents + `
+		ORDER BY seq
+		LIMIT $2`
+
+	return r.list(ctx, query, after, limit)
+}
+
+func (r *ChangeRepo) list(ctx context.Context, query string, args ...any) ([]model.SubscriptionChange, error) {
+	rows, err := conn(ctx, r.db).Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var changes []model.SubscriptionChange
+	for rows.Next() {
+		change, err := scanChange(rows)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, change)
+	}
+
+	return changes, rows.Err()
+}
+
+// Get возвращает изменение подписки с номером seq.
+func (r *ChangeRepo) Get(ctx context.Context, seq int64) (model.SubscriptionChange, error) {
+	query := `SELECT ` + changeColumns + ` FROM outbox WHERE seq = $1 AND ` + changeEvents
+
+	change, err := scanChange(conn(ctx, r.db).QueryRow(ctx, query, seq))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.SubscriptionChange{}, ErrChangeNotFound
+		}
+		return model.SubscriptionChange{}, err
+	}
+
+	return change, nil
+}
+
+// Listen выполняет LISTEN на отдельном соединении, которое забирается из пула и закрывается
+// при выходе: вернуть в пул соединение с активной подпиской нельзя. Уведомления, пришедшие
+// во время listening, не теряются - они ждут в соединении и обрабатываются после него.
+func (r *ChangeRepo) Listen(
+	ctx context.Context,
+	listening func(ctx context.Context) error,
+	notify func(ctx context.Context, seq int64) error,
+) error {
+	pooled, err := r.db.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	listener := pooled.Hijack()
+	defer listener.Close(context.Background()) //nolint:errcheck
+
+	if _, err := listener.Exec(ctx, "LISTEN "+changesChannel); err != nil {
+		return err
+	}
+
+	if err := listening(ctx); err != nil {
+		return err
+	}
+
+	for {
+		notification, err := listener.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+
+		seq, err := strconv.ParseInt(notification.Payload, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid notification payload %q: %w", notification.Payload, err)
+		}
+
+		if err := notify(ctx, seq); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/vasiliy-maslov/go-subscription-service/internal/model"
+
+	"github.com/google/uuid"
+)
+
+const discountColumns = `id, subscription_id, kind, value, cycles, start_date, end_date, description, created_at`
+
+// AddDiscount сохраняет скидку подписки.
+func (r *SubscriptionRepo) AddDiscount(ctx context.Context, d model.Discount) (model.Discount, error) {
+	if err := r.checkTenant(ctx, d.SubscriptionID); err != nil {
+		return model.Discount{}, err
+	}
+
+	query := `
+		INSERT INTO subscription_discounts (id, subscription_id, kind, value, cycles, start_date, end_date, description, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW())
+		RETURNING ` + discountColumns
+
+	var created model.Discount
+	err := conn(ctx, r.db).QueryRow(ctx, query,
+		uuid.New(), d.SubscriptionID, d.Kind, d.Value, d.Cycles, d.StartDate, d.EndDate, d.Description).Scan(
+		&created.ID, &created.SubscriptionID, &created.Kind, &created.Value, &created.Cycles,
+		&created.StartDate, &created.EndDate, &created.Description, &created.CreatedAt)
+	if err != nil {
+		return model.Discount{}, err
+	}
+
+	return created, nil
+}
+
+// DeleteDiscount удаляет скидку подписки.
+func (r *SubscriptionRepo) DeleteDiscount(ctx context.Context, subscriptionID, id uuid.UUID) error {
+	args := []any{id, subscriptionID}
+	query := `DELETE FROM subscription_discounts WHERE id = $1 AND subscription_id = $2` + tenantScope(ctx, "subscription_id", &args)
+
+	res, err := conn(ctx, r.db).Exec(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return ErrDiscountNotFound
+	}
+
+	return nil
+}
+
+// ListDiscounts возвращает скидки набора подписок, сгруппированные по ID подписки.
+func (r *SubscriptionRepo) ListDiscounts(ctx context.Context, subscriptionIDs []uuid.UUID) (map[uuid.UUID][]model.Discount, error) {
+	args := []any{subscriptionIDs}
+	query := `
+		SELECT ` + discountColumns + `
+		FROM subscription_discounts
+		WHERE subscription_id = ANY($1)` + tenantScope(ctx, "subscription_id", &args) + `
+		ORDER BY created_at`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make(map[uuid.UUID][]model.Discount)
+	for rows.Next() {
+		var d model.Discount
+		if err := rows.Scan(&d.ID, &d.SubscriptionID, &d.Kind, &d.Value, &d.Cycles,
+			&d.StartDate, &d.EndDate, &d.Description, &d.CreatedAt); err != nil {
+			return nil, err
+		}
+		result[d.SubscriptionID] = append(result[d.SubscriptionID], d)
+	}
+
+	return result, rows.Err()
+}
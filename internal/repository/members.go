@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/vasiliy-maslov/go-subscription-service/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// uniqueViolationCode - SQLSTATE нарушения уникального ограничения.
+const uniqueViolationCode = "23505"
+
+// foreignKeyViolationCode - SQLSTATE нарушения внешнего ключа.
+const foreignKeyViolationCode = "23503"
+
+const memberColumns = `id, subscription_id, user_id, share_type, weight, amount, joined_at, left_at, created_at`
+
+// AddMember добавляет участника в совместную подписку.
+func (r *SubscriptionRepo) AddMember(ctx context.Context, m model.SubscriptionMember) (model.SubscriptionMember, error) {
+	if err := r.checkTenant(ctx, m.SubscriptionID); err != nil {
+		return model.SubscriptionMember{}, err
+	}
+
+	query := `
+		INSERT INTO subscription_members (id, subscription_id, user_id, share_type, weight, amount, joined_at, left_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW())
+		RETURNING ` + memberColumns
+
+	var created model.SubscriptionMember
+	err := conn(ctx, r.db).QueryRow(ctx, query,
+		uuid.New(), m.SubscriptionID, m.UserID, m.ShareType, m.Weight, m.Amount, m.JoinedAt, m.LeftAt,
+	).Scan(
+		&created.ID, &created.SubscriptionID, &created.UserID, &created.ShareType, &created.Weight,
+		&created.Amount, &created.JoinedAt, &created.LeftAt, &created.CreatedAt)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return model.SubscriptionMember{}, ErrAlreadyExists
+		}
+		if isUserViolation(err) {
+			return model.SubscriptionMember{}, ErrUserNotFound
+		}
+		return model.SubscriptionMember{}, err
+	}
+
+	return created, nil
+}
+
+// RemoveMember завершает участие пользователя в подписке датой leftAt.
+func (r *SubscriptionRepo) RemoveMember(ctx context.Context, subscriptionID, userID uuid.UUID, leftAt model.Date) error {
+	args := []any{leftAt, subscriptionID, userID}
+	query := `
+		UPDATE subscription_members
+		SET left_at = $1
+		WHERE subscription_id = $2 AND user_id = $3 AND left_at IS NULL` + tenantScope(ctx, "subscription_id", &args)
+
+	res, err := conn(ctx, r.db).Exec(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// ListMembers возвращает участников (включая бывших) для набора подписок, сгруппированных по ID подписки.
+func (r *SubscriptionRepo) ListMembers(ctx context.Context, subscriptionIDs []uuid.UUID) (map[uuid.UUID][]model.SubscriptionMember, error) {
+	result := make(map[uuid.UUID][]model.SubscriptionMember)
+	if len(subscriptionIDs) == 0 {
+		return result, nil
+	}
+
+	args := []any{subscriptionIDs}
+	query := `
+		SELECT ` + memberColumns + `
+		FROM subscription_members
+		WHERE subscription_id = ANY($1)` + tenantScope(ctx, "subscription_id", &args) + `
+		ORDER BY joined_at, created_at`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var m model.SubscriptionMember
+		if err := rows.Scan(
+			&m.ID, &m.SubscriptionID, &m.UserID, &m.ShareType, &m.Weight,
+			&m.Amount, &m.JoinedAt, &m.LeftAt, &m.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		result[m.SubscriptionID] = append(result[m.SubscriptionID], m)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode
+}
+
+// isUserViolation сообщает, что запись ссылается на несуществующего пользователя.
+// Внешние ключи на users называются <таблица>_user_id_fkey.
+func isUserViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == foreignKeyViolationCode && strings.HasSuffix(pgErr.ConstraintName, "_user_id_fkey")
+}
@@ -0,0 +1,207 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/vasiliy-maslov/go-subscription-service/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	organizationColumns       = `id, name, default_currency, created_at, updated_at`
+	organizationMemberColumns = `organization_id, user_id, role, created_at`
+)
+
+var _ OrganizationRepository = (*OrganizationRepo)(nil)
+
+type OrganizationRepo struct {
+	db *pgxpool.Pool
+}
+
+// NewOrganizationRepo создает новый экземпляр репозитория организаций.
+func NewOrganizationRepo(db *pgxpool.Pool) *OrganizationRepo {
+	return &OrganizationRepo{db: db}
+}
+
+// Create сохраняет новую организацию.
+func (r *OrganizationRepo) Create(ctx context.Context, org model.Organization) (model.Organization, error) {
+	query := `
+		INSERT INTO organizations (id, name, default_currency, created_at, updated_at)
+		VALUES ($1, $2, $3, NOW(), NOW())
+		RETURNING ` + organizationColumns
+
+	return scanOrganization(conn(ctx, r.db).QueryRow(ctx, query, uuid.New(), org.Name, org.DefaultCurrency))
+}
+
+// GetByID получает организацию по ID.
+func (r *OrganizationRepo) GetByID(ctx context.Context, id uuid.UUID) (model.Organization, error) {
+	return r.get(ctx, `SELECT `+organizationColumns+` FROM organizations WHERE id = $1`, id)
+}
+
+// GetByIDForUpdate получает организацию и блокирует ее строку до конца транзакции.
+// Вызывается только внутри TxManager.WithinTx.
+func (r *OrganizationRepo) GetByIDForUpdate(ctx context.Context, id uuid.UUID) (model.Organization, error) {
+	return r.get(ctx, `SELECT `+organizationColumns+` FROM organizations WHERE id = $1 FOR UPDATE`, id)
+}
+
+// ListByUserID возвращает организации, в которых состоит пользователь.
+func (r *OrganizationRepo) ListByUserID(ctx context.Context, userID uuid.UUID) ([]model.Organization, error) {
+	query := `
+		SELECT ` + organizationColumns + `
+		FROM organizations
+		WHERE id IN (SELECT organization_id FROM organization_members WHERE user_id = $1)
+		ORDER BY name`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	organizations := make([]model.Organization, 0)
+	for rows.Next() {
+		org, err := scanOrganization(rows)
+		if err != nil {
+			return nil, err
+		}
+		organizations = append(organizations, org)
+	}
+
+	return organizations, rows.Err()
+}
+
+// Update меняет название и валюту по умолчанию организации.
+func (r *OrganizationRepo) Update(ctx context.Context, id uuid.UUID, org model.Organization) (model.Organization, error) {
+	query := `
+		UPDATE organizations
+		SET name = $1, default_currency = $2, updated_at = NOW()
+		WHERE id = $3
+		RETURNING ` + organizationColumns
+
+	updated, err := scanOrganization(conn(ctx, r.db).QueryRow(ctx, query, org.Name, org.DefaultCurrency, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.Organization{}, ErrOrganizationNotFound
+		}
+		return model.Organization{}, err
+	}
+
+	return updated, nil
+}
+
+// Delete удаляет организацию. Участие в ней и ее подписки удаляются каскадно внешними ключами.
+func (r *OrganizationRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	res, err := conn(ctx, r.db).Exec(ctx, `DELETE FROM organizations WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return ErrOrganizationNotFound
+	}
+
+	return nil
+}
+
+// SetMember добавляет пользователя в организацию или меняет его роль.
+func (r *OrganizationRepo) SetMember(ctx context.Context, m model.OrganizationMember) (model.OrganizationMember, error) {
+	query := `
+		INSERT INTO organization_members (organization_id, user_id, role, created_at)
+		VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (organization_id, user_id) DO UPDATE SET role = EXCLUDED.role
+		RETURNING ` + organizationMemberColumns
+
+	member, err := scanOrganizationMember(conn(ctx, r.db).QueryRow(ctx, query, m.OrganizationID, m.UserID, m.Role))
+	if err != nil {
+		if isUserViolation(err) {
+			return model.OrganizationMember{}, ErrUserNotFound
+		}
+		return model.OrganizationMember{}, err
+	}
+
+	return member, nil
+}
+
+// GetMember возвращает участие пользователя в организации.
+func (r *OrganizationRepo) GetMember(ctx context.Context, organizationID, userID uuid.UUID) (model.OrganizationMember, error) {
+	query := `SELECT ` + organizationMemberColumns + ` FROM organization_members WHERE organization_id = $1 AND user_id = $2`
+
+	member, err := scanOrganizationMember(conn(ctx, r.db).QueryRow(ctx, query, organizationID, userID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.OrganizationMember{}, ErrOrganizationMemberNotFound
+		}
+		return model.OrganizationMember{}, err
+	}
+
+	return member, nil
+}
+
+// ListMembers возвращает участников организации.
+func (r *OrganizationRepo) ListMembers(ctx context.Context, organizationID uuid.UUID) ([]model.OrganizationMember, error) {
+	query := `
+		SELECT ` + organizationMemberColumns + `
+		FROM organization_members
+		WHERE organization_id = $1
+		ORDER BY created_at`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	members := make([]model.OrganizationMember, 0)
+	for rows.Next() {
+		member, err := scanOrganizationMember(rows)
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, member)
+	}
+
+	return members, rows.Err()
+}
+
+// RemoveMember исключает пользователя из организации.
+func (r *OrganizationRepo) RemoveMember(ctx context.Context, organizationID, userID uuid.UUID) error {
+	res, err := conn(ctx, r.db).Exec(ctx,
+		`DELETE FROM organization_members WHERE organization_id = $1 AND user_id = $2`, organizationID, userID)
+	if err != nil {
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return ErrOrganizationMemberNotFound
+	}
+
+	return nil
+}
+
+func (r *OrganizationRepo) get(ctx context.Context, query string, id uuid.UUID) (model.Organization, error) {
+	org, err := scanOrganization(conn(ctx, r.db).QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.Organization{}, ErrOrganizationNotFound
+		}
+		return model.Organization{}, err
+	}
+
+	return org, nil
+}
+
+func scanOrganization(row pgx.Row) (model.Organization, error) {
+	var org model.Organization
+	err := row.Scan(&org.ID, &org.Name, &org.DefaultCurrency, &org.CreatedAt, &org.UpdatedAt)
+	return org, err
+}
+
+func scanOrganizationMember(row pgx.Row) (model.OrganizationMember, error) {
+	var m model.OrganizationMember
+	err := row.Scan(&m.OrganizationID, &m.UserID, &m.Role, &m.CreatedAt)
+	return m, err
+}
@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"slices"
+	"time"
+
+	"github.com/vasiliy-maslov/go-subscription-service/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// outboxLockKey - ключ advisory-блокировки, под которой реплики по очереди захватывают события outbox.
+const outboxLockKey = 7_260_026
+
+var _ OutboxRepository = (*OutboxRepo)(nil)
+
+type OutboxRepo struct {
+	db *pgxpool.Pool
+}
+
+// NewOutboxRepo создает новый экземпляр репозитория outbox.
+func NewOutboxRepo(db *pgxpool.Pool) *OutboxRepo {
+	return &OutboxRepo{db: db}
+}
+
+// Add записывает доменное событие в outbox. Чтобы событие не потерялось,
+// метод вызывается в той же транзакции, что и изменение подписки.
+func (r *OutboxRepo) Add(ctx context.Context, eventType string, aggregateID uuid.UUID, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO outbox (id, aggregate_id, event_type, payload, created_at)
+		VALUES ($1, $2, $3, $4, NOW())`
+
+	_, err = conn(ctx, r.db).Exec(ctx, query, uuid.New(), aggregateID, eventType, data)
+	return err
+}
+
+// Claim захватывает пачку событий в короткой транзакции, поэтому публикация не держит ни транзакцию,
+// ни соединение. Захват выполняется под advisory-блокировкой, а событие не выдается, пока более
+// раннее событие его подписки захвачено другой репликой: так сохраняется порядок публикации
+// событий одной подписки. Захват с истекшим lease (реплика остановилась) считается снятым.
+func (r *OutboxRepo) Claim(ctx context.Context, limit int, lease time.Duration) ([]model.Event, error) {
+	tx, err := conn(ctx, r.db).Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck
+
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock($1)`, outboxLockKey); err != nil {
+		return nil, err
+	}
+
+	query := `
+		UPDATE outbox SET claimed_until = NOW() + make_interval(secs => $2)
+		WHERE seq IN (
+			SELECT o.seq FROM outbox o
+			WHERE o.published_at IS NULL AND o.dead_at IS NULL
+			  AND (o.claimed_until IS NULL OR o.claimed_until < NOW())
+			  AND NOT EXISTS (
+			      SELECT 1 FROM outbox e
+			      WHERE e.aggregate_id = o.aggregate_id AND e.seq < o.seq
+			        AND e.published_at IS NULL AND e.dead_at IS NULL AND e.claimed_until >= NOW())
+			ORDER BY o.seq
+			LIMIT $1)
+		RETURNING seq, id, aggregate_id, event_type, payload, created_at`
+
+	rows, err := tx.Query(ctx, query, limit, lease.Seconds())
+	if err != nil {
+		return nil, err
+	}
+
+	type claimed struct {
+		seq   int64
+		event model.Event
+	}
+	var batch []claimed
+	for rows.Next() {
+		var c claimed
+		if err := rows.Scan(&c.seq, &c.event.ID, &c.event.AggregateID, &c.event.Type, &c.event.Payload, &c.event.CreatedAt); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		batch = append(batch, c)
+	}
+	rows.Close()
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	// RETURNING не гарантирует порядок строк.
+	slices.SortFunc(batch, func(a, b claimed) int { return cmp.Compare(a.seq, b.seq) })
+	events := make([]model.Event, len(batch))
+	for i, c := range batch {
+		events[i] = c.event
+	}
+
+	return events, nil
+}
+
+// MarkPublished отмечает события опубликованными.
+func (r *OutboxRepo) MarkPublished(ctx context.Context, ids []uuid.UUID) error {
+	_, err := conn(ctx, r.db).Exec(ctx,
+		`UPDATE outbox SET published_at = NOW(), claimed_until = NULL WHERE id = ANY($1)`, ids)
+	return err
+}
+
+// MarkFailed записывает причину неудачи, увеличивает счетчик попыток и освобождает события.
+// События, исчерпавшие maxAttempts попыток, получают dead_at.
+func (r *OutboxRepo) MarkFailed(ctx context.Context, failed map[uuid.UUID]error, maxAttempts int) ([]uuid.UUID, error) {
+	var dead []uuid.UUID
+	for id, reason := range failed {
+		var isDead bool
+		err := conn(ctx, r.db).QueryRow(ctx, `
+			UPDATE outbox
+			SET attempts = attempts + 1,
+			    last_error = $1,
+			    claimed_until = NULL,
+			    dead_at = CASE WHEN attempts + 1 >= $3 THEN NOW() END
+			WHERE id = $2
+			RETURNING dead_at IS NOT NULL`,
+			reason.Error(), id, maxAttempts).Scan(&isDead)
+		if err != nil {
+			return dead, err
+		}
+		if isDead {
+			dead = append(dead, id)
+		}
+	}
+
+	return dead, nil
+}
+
+// Release освобождает события без изменения счетчика попыток.
+func (r *OutboxRepo) Release(ctx context.Context, ids []uuid.UUID) error {
+	_, err := conn(ctx, r.db).Exec(ctx, `UPDATE outbox SET claimed_until = NULL WHERE id = ANY($1)`, ids)
+	return err
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"errors"
+	"fmt"
 
 	"github.com/vasiliy-maslov/go-subscription-service/internal/model" // Проверь имя модуля
 
@@ -13,6 +14,9 @@ import (
 
 var _ SubscriptionRepository = (*SubscriptionRepo)(nil)
 
+// subscriptionColumns - список колонок подписки в порядке, который ожидает scanSubscription.
+const subscriptionColumns = `id, user_id, organization_id, service_name, service_id, plan_id, price, currency, billing_period, start_date, end_date, status, trial_end_date, created_at, updated_at`
+
 type SubscriptionRepo struct {
 	db *pgxpool.Pool
 }
@@ -22,18 +26,35 @@ func NewSubscriptionRepo(db *pgxpool.Pool) *SubscriptionRepo {
 	return &SubscriptionRepo{db: db}
 }
 
+// scanSubscription читает строку, выбранную с колонками subscriptionColumns.
+func scanSubscription(row pgx.Row) (model.Subscription, error) {
+	var sub model.Subscription
+	err := row.Scan(
+		&sub.ID, &sub.UserID, &sub.OrganizationID, &sub.ServiceName, &sub.ServiceID, &sub.PlanID, &sub.Price, &sub.Currency, &sub.BillingPeriod, &sub.StartDate, &sub.EndDate,
+		&sub.Status, &sub.TrialEndDate, &sub.CreatedAt, &sub.UpdatedAt)
+	return sub, err
+}
+
 // Create создает новую запись о подписке в базе данных.
+// В ограниченном контексте подписку можно создать только пользователю, подписки которого в нем видны.
 func (r *SubscriptionRepo) Create(ctx context.Context, sub model.Subscription) (uuid.UUID, error) {
+	if !InScope(ctx, sub.UserID) {
+		return uuid.Nil, ErrTenantMismatch
+	}
+
 	sub.ID = uuid.New()
 
 	query := `
-		INSERT INTO subscriptions (id, user_id, service_name, price, start_date, end_date, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, NOW(), NOW())`
+		INSERT INTO subscriptions (id, user_id, organization_id, service_name, service_id, plan_id, price, currency, billing_period, start_date, end_date, status, trial_end_date, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, NOW(), NOW())`
 
-	_, err := r.db.Exec(ctx, query,
-		sub.ID, sub.UserID, sub.ServiceName, sub.Price, sub.StartDate, sub.EndDate)
+	_, err := conn(ctx, r.db).Exec(ctx, query,
+		sub.ID, sub.UserID, sub.OrganizationID, sub.ServiceName, sub.ServiceID, sub.PlanID, sub.Price, sub.Currency, sub.BillingPeriod, sub.StartDate, sub.EndDate, sub.Status, sub.TrialEndDate)
 
 	if err != nil {
+		if isUserViolation(err) {
+			return uuid.Nil, ErrUserNotFound
+		}
 		return uuid.Nil, err
 	}
 
@@ -42,16 +63,27 @@ func (r *SubscriptionRepo) Create(ctx context.Context, sub model.Subscription) (
 
 // GetByID получает подписку по ее ID.
 func (r *SubscriptionRepo) GetByID(ctx context.Context, id uuid.UUID) (model.Subscription, error) {
-	var sub model.Subscription
+	args := []any{id}
+	query := `SELECT ` + subscriptionColumns + ` FROM subscriptions WHERE id = $1` + tenantScope(ctx, "id", &args)
 
-	query := `
-		SELECT id, user_id, service_name, price, start_date, end_date, created_at, updated_at
-		FROM subscriptions
-		WHERE id = $1`
+	sub, err := scanSubscription(conn(ctx, r.db).QueryRow(ctx, query, args...))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.Subscription{}, ErrNotFound
+		}
+		return model.Subscription{}, err
+	}
+
+	return sub, nil
+}
 
-	err := r.db.QueryRow(ctx, query, id).Scan(
-		&sub.ID, &sub.UserID, &sub.ServiceName, &sub.Price, &sub.StartDate, &sub.EndDate, &sub.CreatedAt, &sub.UpdatedAt)
+// GetByIDForUpdate получает подписку и блокирует ее строку до конца транзакции.
+// Вызывается только внутри TxManager.WithinTx.
+func (r *SubscriptionRepo) GetByIDForUpdate(ctx context.Context, id uuid.UUID) (model.Subscription, error) {
+	args := []any{id}
+	query := `SELECT ` + subscriptionColumns + ` FROM subscriptions WHERE id = $1` + tenantScope(ctx, "id", &args) + ` FOR UPDATE`
 
+	sub, err := scanSubscription(conn(ctx, r.db).QueryRow(ctx, query, args...))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return model.Subscription{}, ErrNotFound
@@ -63,12 +95,41 @@ func (r *SubscriptionRepo) GetByID(ctx context.Context, id uuid.UUID) (model.Sub
 }
 
 func (r *SubscriptionRepo) Update(ctx context.Context, id uuid.UUID, sub model.Subscription) error {
+	args := []any{sub.OrganizationID, sub.ServiceName, sub.ServiceID, sub.PlanID, sub.Price, sub.Currency, sub.BillingPeriod,
+		sub.StartDate, sub.EndDate, sub.TrialEndDate, id}
 	query := `
 		UPDATE subscriptions
-		SET service_name = $1, price = $2, start_date = $3, end_date = $4, updated_at = NOW()
-		WHERE id = $5`
+		SET organization_id = $1, service_name = $2, service_id = $3, plan_id = $4, price = $5, currency = $6,
+		    billing_period = $7, start_date = $8, end_date = $9, trial_end_date = $10, updated_at = NOW()
+		WHERE id = $11` + tenantScope(ctx, "id", &args)
 
-	res, err := r.db.Exec(ctx, query, sub.ServiceName, sub.Price, sub.StartDate, sub.EndDate, id)
+	res, err := conn(ctx, r.db).Exec(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// LockUser берет advisory-блокировку по ID пользователя до конца текущей транзакции.
+func (r *SubscriptionRepo) LockUser(ctx context.Context, userID uuid.UUID) error {
+	_, err := conn(ctx, r.db).Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext($1))`, userID.String())
+	return err
+}
+
+// SetStatus меняет статус подписки и дату ее окончания.
+func (r *SubscriptionRepo) SetStatus(ctx context.Context, id uuid.UUID, status model.SubscriptionStatus, endDate *model.Date) error {
+	args := []any{status, endDate, id}
+	query := `
+		UPDATE subscriptions
+		SET status = $1, end_date = $2, updated_at = NOW()
+		WHERE id = $3` + tenantScope(ctx, "id", &args)
+
+	res, err := conn(ctx, r.db).Exec(ctx, query, args...)
 	if err != nil {
 		return err
 	}
@@ -82,9 +143,10 @@ func (r *SubscriptionRepo) Update(ctx context.Context, id uuid.UUID, sub model.S
 
 // Delete удаляет подписку по ID.
 func (r *SubscriptionRepo) Delete(ctx context.Context, id uuid.UUID) error {
-	query := `DELETE FROM subscriptions WHERE id = $1`
+	args := []any{id}
+	query := `DELETE FROM subscriptions WHERE id = $1` + tenantScope(ctx, "id", &args)
 
-	res, err := r.db.Exec(ctx, query, id)
+	res, err := conn(ctx, r.db).Exec(ctx, query, args...)
 	if err != nil {
 		return err
 	}
@@ -96,22 +158,55 @@ func (r *SubscriptionRepo) Delete(ctx context.Context, id uuid.UUID) error {
 	return nil
 }
 
-func (r *SubscriptionRepo) ListByUserID(ctx context.Context, userID uuid.UUID, serviceName *string) ([]model.Subscription, error) {
+// List возвращает подписки, которыми пользователь владеет или в которых когда-либо участвовал,
+// либо, если задан filter.OrganizationID, подписки организации, с учетом фильтров.
+func (r *SubscriptionRepo) List(ctx context.Context, filter SubscriptionFilter) ([]model.Subscription, error) {
 	query := `
-		SELECT id, user_id, service_name, price, start_date, end_date, created_at, updated_at
+		SELECT ` + subscriptionColumns + `
+		FROM subscriptions
+		WHERE (user_id = $1 OR id IN (SELECT subscription_id FROM subscription_members WHERE user_id = $1))`
+
+	args := []any{filter.UserID} // Начинаем собирать аргументы для запроса
+
+	if filter.OrganizationID != nil {
+		query = `
+		SELECT ` + subscriptionColumns + `
 		FROM subscriptions
-		WHERE user_id = $1`
+		WHERE organization_id = $1`
+		args = []any{*filter.OrganizationID}
+	}
+
+	if filter.ServiceID != nil {
+		args = append(args, *filter.ServiceID)
+		query += fmt.Sprintf(" AND service_id = $%d", len(args))
+	}
+
+	if filter.ServiceName != nil {
+		args = append(args, model.NormalizeServiceName(*filter.ServiceName))
+		query += fmt.Sprintf(" AND lower(regexp_replace(btrim(service_name), '\\s+', ' ', 'g')) = $%d", len(args))
+	}
 
-	args := []any{userID} // Начинаем собирать аргументы для запроса
+	if filter.CategoryID != nil {
+		args = append(args, *filter.CategoryID)
+		query += fmt.Sprintf(" AND id IN (SELECT subscription_id FROM subscription_categories WHERE category_id = $%d)", len(args))
+	}
 
-	if serviceName != nil {
-		query += " AND service_name = $2"
-		args = append(args, *serviceName)
+	if filter.Tag != nil {
+		args = append(args, *filter.Tag)
+		query += fmt.Sprintf(` AND id IN (
+			SELECT st.subscription_id FROM subscription_tags st JOIN tags t ON t.id = st.tag_id
+			WHERE lower(t.name) = lower($%d))`, len(args))
 	}
 
+	if filter.Currency != nil {
+		args = append(args, *filter.Currency)
+		query += fmt.Sprintf(" AND currency = $%d", len(args))
+	}
+
+	query += tenantScope(ctx, "id", &args)
 	query += " ORDER BY start_date DESC"
 
-	rows, err := r.db.Query(ctx, query, args...)
+	rows, err := conn(ctx, r.db).Query(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -119,11 +214,8 @@ func (r *SubscriptionRepo) ListByUserID(ctx context.Context, userID uuid.UUID, s
 
 	var subscriptions []model.Subscription
 	for rows.Next() {
-		var sub model.Subscription
-		if err := rows.Scan(
-			&sub.ID, &sub.UserID, &sub.ServiceName, &sub.Price,
-			&sub.StartDate, &sub.EndDate, &sub.CreatedAt, &sub.UpdatedAt,
-		); err != nil {
+		sub, err := scanSubscription(rows)
+		if err != nil {
 			return nil, err
 		}
 		subscriptions = append(subscriptions, sub)
@@ -135,3 +227,55 @@ func (r *SubscriptionRepo) ListByUserID(ctx context.Context, userID uuid.UUID, s
 
 	return subscriptions, nil
 }
+
+// AddTransition сохраняет переход подписки между статусами.
+func (r *SubscriptionRepo) AddTransition(ctx context.Context, t model.StatusTransition) error {
+	if err := r.checkTenant(ctx, t.SubscriptionID); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO subscription_status_transitions (id, subscription_id, from_status, to_status, effective_date, previous_end_date, created_at)
+		VALUES ($1, $2, NULLIF($3, ''), $4, $5, $6, NOW())`
+
+	_, err := conn(ctx, r.db).Exec(ctx, query,
+		uuid.New(), t.SubscriptionID, string(t.FromStatus), t.ToStatus, t.EffectiveDate, t.PreviousEndDate)
+
+	return err
+}
+
+// ListTransitions возвращает переходы статусов для набора подписок, сгруппированные по ID подписки
+// и упорядоченные по дате вступления в силу.
+func (r *SubscriptionRepo) ListTransitions(ctx context.Context, subscriptionIDs []uuid.UUID) (map[uuid.UUID][]model.StatusTransition, error) {
+	result := make(map[uuid.UUID][]model.StatusTransition)
+	if len(subscriptionIDs) == 0 {
+		return result, nil
+	}
+
+	args := []any{subscriptionIDs}
+	query := `
+		SELECT id, subscription_id, COALESCE(from_status, ''), to_status, effective_date, previous_end_date, created_at
+		FROM subscription_status_transitions
+		WHERE subscription_id = ANY($1)` + tenantScope(ctx, "subscription_id", &args) + `
+		ORDER BY effective_date, created_at`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var t model.StatusTransition
+		if err := rows.Scan(&t.ID, &t.SubscriptionID, &t.FromStatus, &t.ToStatus, &t.EffectiveDate, &t.PreviousEndDate, &t.CreatedAt); err != nil {
+			return nil, err
+		}
+		result[t.SubscriptionID] = append(result[t.SubscriptionID], t)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
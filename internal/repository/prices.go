@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/vasiliy-maslov/go-subscription-service/internal/model"
+
+	"github.com/google/uuid"
+)
+
+// AddPriceChange сохраняет изменение цены подписки. Изменение на ту же дату заменяет прежнее.
+func (r *SubscriptionRepo) AddPriceChange(ctx context.Context, change model.PriceChange) (model.PriceChange, error) {
+	if err := r.checkTenant(ctx, change.SubscriptionID); err != nil {
+		return model.PriceChange{}, err
+	}
+
+	query := `
+		INSERT INTO subscription_price_changes (id, subscription_id, effective_date, price, created_at)
+		VALUES ($1, $2, $3, $4, NOW())
+		ON CONFLICT (subscription_id, effective_date) DO UPDATE SET price = EXCLUDED.price
+		RETURNING id, subscription_id, effective_date, price, created_at`
+
+	var created model.PriceChange
+	err := conn(ctx, r.db).QueryRow(ctx, query, uuid.New(), change.SubscriptionID, change.EffectiveDate, change.Price).Scan(
+		&created.ID, &created.SubscriptionID, &created.EffectiveDate, &created.Price, &created.CreatedAt)
+	if err != nil {
+		return model.PriceChange{}, err
+	}
+
+	return created, nil
+}
+
+// DeletePriceChange удаляет изменение цены подписки.
+func (r *SubscriptionRepo) DeletePriceChange(ctx context.Context, subscriptionID, id uuid.UUID) error {
+	args := []any{id, subscriptionID}
+	query := `DELETE FROM subscription_price_changes WHERE id = $1 AND subscription_id = $2` + tenantScope(ctx, "subscription_id", &args)
+
+	res, err := conn(ctx, r.db).Exec(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return ErrPriceChangeNotFound
+	}
+
+	return nil
+}
+
+// ListPriceChanges возвращает изменения цен набора подписок, сгруппированные по ID подписки
+// и упорядоченные по дате вступления в силу.
+func (r *SubscriptionRepo) ListPriceChanges(ctx context.Context, subscriptionIDs []uuid.UUID) (map[uuid.UUID][]model.PriceChange, error) {
+	args := []any{subscriptionIDs}
+	query := `
+		SELECT id, subscription_id, effective_date, price, created_at
+		FROM subscription_price_changes
+		WHERE subscription_id = ANY($1)` + tenantScope(ctx, "subscription_id", &args) + `
+		ORDER BY effective_date`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make(map[uuid.UUID][]model.PriceChange)
+	for rows.Next() {
+		var change model.PriceChange
+		if err := rows.Scan(&change.ID, &change.SubscriptionID, &change.EffectiveDate, &change.Price, &change.CreatedAt); err != nil {
+			return nil, err
+		}
+		result[change.SubscriptionID] = append(result[change.SubscriptionID], change)
+	}
+
+	return result, rows.Err()
+}
@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/vasiliy-maslov/go-subscription-service/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var _ PrivacyRepository = (*PrivacyRepo)(nil)
+
+type PrivacyRepo struct {
+	db *pgxpool.Pool
+}
+
+// NewPrivacyRepo создает новый экземпляр репозитория запросов субъектов данных.
+func NewPrivacyRepo(db *pgxpool.Pool) *PrivacyRepo {
+	return &PrivacyRepo{db: db}
+}
+
+// ListEvents возвращает события outbox, агрегатом которых является пользователь
+// или в содержимом которых встречается его ID, в порядке их записи.
+func (r *PrivacyRepo) ListEvents(ctx context.Context, userID uuid.UUID) ([]model.Event, error) {
+	query := `
+		SELECT id, aggregate_id, event_type, payload, created_at
+		FROM outbox
+		WHERE aggregate_id = $1 OR strpos(payload::text, $1::text) > 0
+		ORDER BY seq`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := make([]model.Event, 0)
+	for rows.Next() {
+		var event model.Event
+		if err := rows.Scan(&event.ID, &event.AggregateID, &event.Type, &event.Payload, &event.CreatedAt); err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
+
+// RedactEvents заменяет пользователя в событиях outbox на tombstone. Содержимое событий о самом
+// пользователе и об удаленных агрегатах aggregateIDs (его подписках и бюджетах) заменяется целиком,
+// в остальных событиях заменяется только ID пользователя. Возвращает число измененных событий.
+func (r *PrivacyRepo) RedactEvents(ctx context.Context, userID, tombstoneID uuid.UUID, aggregateIDs []uuid.UUID) (int64, error) {
+	query := `
+		UPDATE outbox
+		SET aggregate_id = CASE WHEN aggregate_id = $1 THEN $2 ELSE aggregate_id END,
+		    payload = CASE
+		        WHEN aggregate_id = $1 OR aggregate_id = ANY($3)
+		            THEN jsonb_build_object('user_id', $2::text, 'erased', true)
+		        ELSE replace(payload::text, $1::text, $2::text)::jsonb
+		    END
+		WHERE aggregate_id = $1 OR aggregate_id = ANY($3) OR strpos(payload::text, $1::text) > 0`
+
+	res, err := conn(ctx, r.db).Exec(ctx, query, userID, tombstoneID, aggregateIDs)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected(), nil
+}
+
+// CountReferences возвращает число оставшихся записей, ссылающихся на пользователя, по таблицам.
+// Таблицы без таких записей в результат не попадают.
+func (r *PrivacyRepo) CountReferences(ctx context.Context, userID uuid.UUID) (map[string]int64, error) {
+	query := `
+		SELECT name, count FROM (
+			SELECT 'users' AS name, count(*) AS count FROM users WHERE id = $1
+			UNION ALL SELECT 'subscriptions', count(*) FROM subscriptions WHERE user_id = $1
+			UNION ALL SELECT 'subscription_members', count(*) FROM subscription_members WHERE user_id = $1
+			UNION ALL SELECT 'categories', count(*) FROM categories WHERE user_id = $1
+			UNION ALL SELECT 'tags', count(*) FROM tags WHERE user_id = $1
+			UNION ALL SELECT 'budgets', count(*) FROM budgets WHERE user_id = $1
+			UNION ALL SELECT 'organization_members', count(*) FROM organization_members WHERE user_id = $1
+			UNION ALL SELECT 'role_bindings', count(*) FROM role_bindings WHERE user_id = $1
+			UNION ALL SELECT 'outbox', count(*) FROM outbox WHERE aggregate_id = $1 OR strpos(payload::text, $1::text) > 0
+			UNION ALL SELECT 'audit_identities', count(*) FROM audit_identities WHERE actor_id = $1
+			UNION ALL SELECT 'audit_subjects', count(*) FROM audit_subjects
+				WHERE resource_id = $1::text OR strpos(params::text, $1::text) > 0
+			UNION ALL SELECT 'api_keys', count(*) FROM api_keys WHERE $1 = ANY(user_ids)
+		) refs
+		WHERE count > 0`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	refs := make(map[string]int64)
+	for rows.Next() {
+		var (
+			name  string
+			count int64
+		)
+		if err := rows.Scan(&name, &count); err != nil {
+			return nil, err
+		}
+		refs[name] = count
+	}
+
+	return refs, rows.Err()
+}
+
+// RecordErasure сохраняет запись об удалении данных пользователя в журнал.
+func (r *PrivacyRepo) RecordErasure(ctx context.Context, e model.Erasure) (model.Erasure, error) {
+	query := `
+		INSERT INTO user_erasures (id, tombstone_id, subscriptions, redacted_events, erased_at)
+		VALUES ($1, $2, $3, $4, NOW())
+		RETURNING id, tombstone_id, subscriptions, redacted_events, erased_at`
+
+	var recorded model.Erasure
+	err := conn(ctx, r.db).QueryRow(ctx, query, uuid.New(), e.TombstoneID, e.Subscriptions, e.RedactedEvents).
+		Scan(&recorded.ID, &recorded.TombstoneID, &recorded.Subscriptions, &recorded.RedactedEvents, &recorded.ErasedAt)
+	return recorded, err
+}
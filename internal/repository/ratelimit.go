@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// RateLimitRepo хранит корзины ограничения запросов в Postgres, чтобы ограничения действовали
+// на все реплики сервиса. Время берется из базы, поэтому расхождение часов реплик не влияет на счет.
+type RateLimitRepo struct {
+	db *pgxpool.Pool
+}
+
+// NewRateLimitRepo создает новый экземпляр репозитория корзин ограничения запросов.
+func NewRateLimitRepo(db *pgxpool.Pool) *RateLimitRepo {
+	return &RateLimitRepo{db: db}
+}
+
+// Take пополняет корзину key и забирает из нее токен одним атомарным запросом.
+// Если токена нет, корзина не меняется и строка не возвращается.
+func (r *RateLimitRepo) Take(ctx context.Context, key string, capacity, rate float64) (bool, float64, error) {
+	const refilled = `LEAST($2::double precision, b.tokens + EXTRACT(EPOCH FROM (NOW() - b.updated_at)) * $3::double precision)`
+
+	query := `
+		INSERT INTO rate_limit_buckets AS b (key, tokens, updated_at)
+		VALUES ($1, $2::double precision - 1, NOW())
+		ON CONFLICT (key) DO UPDATE
+		SET tokens = ` + refilled + ` - 1, updated_at = NOW()
+		WHERE ` + refilled + ` >= 1
+		RETURNING tokens`
+
+	var tokens float64
+	err := conn(ctx, r.db).QueryRow(ctx, query, key, capacity, rate).Scan(&tokens)
+	if err == nil {
+		return true, tokens, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return false, 0, err
+	}
+
+	query = `SELECT ` + refilled + ` FROM rate_limit_buckets b WHERE key = $1`
+	if err := conn(ctx, r.db).QueryRow(ctx, query, key, capacity, rate).Scan(&tokens); err != nil {
+		return false, 0, err
+	}
+	return false, tokens, nil
+}
+
+// Sweep удаляет корзины, к которым не обращались дольше idle.
+func (r *RateLimitRepo) Sweep(ctx context.Context, idle time.Duration) error {
+	_, err := conn(ctx, r.db).Exec(ctx, `DELETE FROM rate_limit_buckets WHERE updated_at < NOW() - make_interval(secs => $1)`, idle.Seconds())
+	return err
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/vasiliy-maslov/go-subscription-service/internal/model"
 
@@ -12,11 +13,233 @@ import (
 // ErrNotFound возвращается, когда подписка не найдена в хранилище.
 var ErrNotFound = errors.New("subscription not found")
 
+// ErrServiceNotFound возвращается, когда сервис не найден в каталоге.
+var ErrServiceNotFound = errors.New("catalog service not found")
+
+// ErrCategoryNotFound возвращается, когда категория не найдена.
+var ErrCategoryNotFound = errors.New("category not found")
+
+// ErrTagNotFound возвращается, когда метка не найдена.
+var ErrTagNotFound = errors.New("tag not found")
+
+// ErrBudgetNotFound возвращается, когда бюджет не найден.
+var ErrBudgetNotFound = errors.New("budget not found")
+
+// ErrPriceChangeNotFound возвращается, когда изменение цены подписки не найдено.
+var ErrPriceChangeNotFound = errors.New("price change not found")
+
+// ErrDiscountNotFound возвращается, когда скидка подписки не найдена.
+var ErrDiscountNotFound = errors.New("discount not found")
+
+// ErrUserNotFound возвращается, когда пользователь не найден.
+var ErrUserNotFound = errors.New("user not found")
+
+// ErrOrganizationNotFound возвращается, когда организация не найдена.
+var ErrOrganizationNotFound = errors.New("organization not found")
+
+// ErrOrganizationMemberNotFound возвращается, когда пользователь не состоит в организации.
+var ErrOrganizationMemberNotFound = errors.New("organization member not found")
+
+// ErrRoleNotFound возвращается, когда роль не найдена.
+var ErrRoleNotFound = errors.New("role not found")
+
+// ErrRoleBindingNotFound возвращается, когда роль не выдана пользователю.
+var ErrRoleBindingNotFound = errors.New("role binding not found")
+
+// ErrAPIKeyNotFound возвращается, когда API-ключ не найден.
+var ErrAPIKeyNotFound = errors.New("api key not found")
+
+// ErrChangeNotFound возвращается, когда изменение подписки не найдено в outbox.
+var ErrChangeNotFound = errors.New("subscription change not found")
+
+// ErrAlreadyExists возвращается при нарушении уникальности записи.
+var ErrAlreadyExists = errors.New("already exists")
+
+// SubscriptionFilter задает условия выборки подписок пользователя. Поля со значением nil не ограничивают выборку.
+type SubscriptionFilter struct {
+	UserID uuid.UUID
+	// ServiceName сравнивается без учета регистра и лишних пробелов.
+	ServiceName *string
+	ServiceID   *uuid.UUID
+	CategoryID  *uuid.UUID
+	// Tag сравнивается без учета регистра.
+	Tag      *string
+	Currency *model.Currency
+	// OrganizationID выбирает подписки организации вместо подписок пользователя UserID.
+	OrganizationID *uuid.UUID
+}
+
 // SubscriptionRepository определяет методы для работы с хранилищем подписок.
 type SubscriptionRepository interface {
 	Create(ctx context.Context, sub model.Subscription) (uuid.UUID, error)
 	GetByID(ctx context.Context, id uuid.UUID) (model.Subscription, error)
+	GetByIDForUpdate(ctx context.Context, id uuid.UUID) (model.Subscription, error)
 	Update(ctx context.Context, id uuid.UUID, sub model.Subscription) error
+	SetStatus(ctx context.Context, id uuid.UUID, status model.SubscriptionStatus, endDate *model.Date) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	List(ctx context.Context, filter SubscriptionFilter) ([]model.Subscription, error)
+	AddTransition(ctx context.Context, t model.StatusTransition) error
+	ListTransitions(ctx context.Context, subscriptionIDs []uuid.UUID) (map[uuid.UUID][]model.StatusTransition, error)
+	AddMember(ctx context.Context, m model.SubscriptionMember) (model.SubscriptionMember, error)
+	RemoveMember(ctx context.Context, subscriptionID, userID uuid.UUID, leftAt model.Date) error
+	ListMembers(ctx context.Context, subscriptionIDs []uuid.UUID) (map[uuid.UUID][]model.SubscriptionMember, error)
+	AddPriceChange(ctx context.Context, change model.PriceChange) (model.PriceChange, error)
+	DeletePriceChange(ctx context.Context, subscriptionID, id uuid.UUID) error
+	ListPriceChanges(ctx context.Context, subscriptionIDs []uuid.UUID) (map[uuid.UUID][]model.PriceChange, error)
+	AddDiscount(ctx context.Context, d model.Discount) (model.Discount, error)
+	DeleteDiscount(ctx context.Context, subscriptionID, id uuid.UUID) error
+	ListDiscounts(ctx context.Context, subscriptionIDs []uuid.UUID) (map[uuid.UUID][]model.Discount, error)
+	// LockUser блокирует подписки пользователя от параллельного создания до конца транзакции.
+	LockUser(ctx context.Context, userID uuid.UUID) error
+}
+
+// OutboxRepository определяет методы для записи и разбора outbox-таблицы.
+// Событие захватывается ретранслятором на время публикации и по ее итогам отмечается
+// опубликованным, неудачным или освобождается.
+type OutboxRepository interface {
+	Add(ctx context.Context, eventType string, aggregateID uuid.UUID, payload any) error
+	// Claim захватывает до limit неопубликованных событий на время lease и возвращает их в порядке записи.
+	Claim(ctx context.Context, limit int, lease time.Duration) ([]model.Event, error)
+	MarkPublished(ctx context.Context, ids []uuid.UUID) error
+	// MarkFailed увеличивает счетчик попыток событий и освобождает их. События, исчерпавшие
+	// maxAttempts попыток, переводятся в dead letter; их ID возвращаются.
+	MarkFailed(ctx context.Context, failed map[uuid.UUID]error, maxAttempts int) ([]uuid.UUID, error)
+	// Release освобождает события, которые не публиковались, без увеличения счетчика попыток.
+	Release(ctx context.Context, ids []uuid.UUID) error
+}
+
+// ChangeRepository определяет методы для чтения изменений подписок из outbox
+// и ожидания новых изменений через LISTEN/NOTIFY.
+type ChangeRepository interface {
+	// Recent возвращает последние limit изменений в порядке seq.
+	Recent(ctx context.Context, limit int) ([]model.SubscriptionChange, error)
+	// After возвращает до limit изменений с seq больше after в порядке seq.
+	After(ctx context.Context, after int64, limit int) ([]model.SubscriptionChange, error)
+	// Get возвращает изменение с номером seq.
+	Get(ctx context.Context, seq int64) (model.SubscriptionChange, error)
+	// Listen подписывается на уведомления об изменениях и блокируется до отмены контекста
+	// или обрыва соединения. listening вызывается, когда подписка уже действует, notify - на каждое
+	// уведомление с seq изменения.
+	Listen(ctx context.Context, listening func(ctx context.Context) error, notify func(ctx context.Context, seq int64) error) error
+}
+
+// CatalogRepository определяет методы для работы с каталогом сервисов.
+type CatalogRepository interface {
+	Create(ctx context.Context, svc model.CatalogService) (uuid.UUID, error)
+	GetByID(ctx context.Context, id uuid.UUID) (model.CatalogService, error)
+	FindByAlias(ctx context.Context, normalized string) (model.CatalogService, error)
+	List(ctx context.Context, category *string) ([]model.CatalogService, error)
+	Update(ctx context.Context, id uuid.UUID, svc model.CatalogService) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	LinkSubscriptions(ctx context.Context, id uuid.UUID) (int64, error)
+}
+
+// CategoryRepository определяет методы для работы с категориями и метками подписок.
+type CategoryRepository interface {
+	CreateCategory(ctx context.Context, category model.Category) (model.Category, error)
+	GetCategories(ctx context.Context, ids []uuid.UUID) ([]model.Category, error)
+	ListCategories(ctx context.Context, userID uuid.UUID) ([]model.Category, error)
+	RenameCategory(ctx context.Context, id uuid.UUID, name string) (model.Category, error)
+	DeleteCategory(ctx context.Context, id uuid.UUID) error
+	CreateTag(ctx context.Context, tag model.Tag) (model.Tag, error)
+	GetTag(ctx context.Context, id uuid.UUID) (model.Tag, error)
+	ListTags(ctx context.Context, userID uuid.UUID) ([]model.Tag, error)
+	RenameTag(ctx context.Context, id uuid.UUID, name string) (model.Tag, error)
+	DeleteTag(ctx context.Context, id uuid.UUID) error
+	SetSubscriptionCategories(ctx context.Context, subscriptionID uuid.UUID, categoryIDs []uuid.UUID) error
+	SetSubscriptionTags(ctx context.Context, subscriptionID, userID uuid.UUID, names []string) error
+	ListSubscriptionCategories(ctx context.Context, subscriptionIDs []uuid.UUID) (map[uuid.UUID][]model.Category, error)
+	ListSubscriptionTags(ctx context.Context, subscriptionIDs []uuid.UUID) (map[uuid.UUID][]model.Tag, error)
+}
+
+// UserRepository определяет методы для работы с пользователями.
+type UserRepository interface {
+	Create(ctx context.Context, u model.User) (model.User, error)
+	GetByID(ctx context.Context, id uuid.UUID) (model.User, error)
+	GetByIDForUpdate(ctx context.Context, id uuid.UUID) (model.User, error)
+	Update(ctx context.Context, id uuid.UUID, u model.User) (model.User, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+// OrganizationRepository определяет методы для работы с организациями и их участниками.
+type OrganizationRepository interface {
+	Create(ctx context.Context, org model.Organization) (model.Organization, error)
+	GetByID(ctx context.Context, id uuid.UUID) (model.Organization, error)
+	GetByIDForUpdate(ctx context.Context, id uuid.UUID) (model.Organization, error)
+	ListByUserID(ctx context.Context, userID uuid.UUID) ([]model.Organization, error)
+	Update(ctx context.Context, id uuid.UUID, org model.Organization) (model.Organization, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+	SetMember(ctx context.Context, m model.OrganizationMember) (model.OrganizationMember, error)
+	GetMember(ctx context.Context, organizationID, userID uuid.UUID) (model.OrganizationMember, error)
+	ListMembers(ctx context.Context, organizationID uuid.UUID) ([]model.OrganizationMember, error)
+	RemoveMember(ctx context.Context, organizationID, userID uuid.UUID) error
+}
+
+// RoleRepository определяет методы для работы с ролями и их выдачей пользователям.
+type RoleRepository interface {
+	ListRoles(ctx context.Context) ([]model.Role, error)
+	Permissions(ctx context.Context, userID uuid.UUID) ([]model.Permission, error)
+	ListBindings(ctx context.Context, userID uuid.UUID) ([]model.RoleBinding, error)
+	Bind(ctx context.Context, b model.RoleBinding) (model.RoleBinding, error)
+	Unbind(ctx context.Context, userID uuid.UUID, role string) error
+}
+
+// APIKeyRepository определяет методы для работы с API-ключами машинных клиентов.
+type APIKeyRepository interface {
+	Create(ctx context.Context, key model.APIKey, secretHash string) (model.APIKey, error)
+	GetByPrefix(ctx context.Context, prefix string) (model.APIKey, string, error)
+	List(ctx context.Context) ([]model.APIKey, error)
+	Revoke(ctx context.Context, id uuid.UUID) (model.APIKey, error)
+	Touch(ctx context.Context, id uuid.UUID) error
+	// RemoveUser исключает пользователя из ограничений API-ключей и возвращает измененные ключи.
+	RemoveUser(ctx context.Context, userID uuid.UUID) ([]model.APIKey, error)
+}
+
+// AuditFilter - параметры выборки журнала аудита. Записи возвращаются от новых к старым.
+type AuditFilter struct {
+	ActorID    *uuid.UUID
+	ActorType  *model.AuditActorType
+	Action     *string
+	ResourceID *string
+	Outcome    *model.AuditOutcome
+	RequestID  *string
+	From       *time.Time
+	To         *time.Time
+	// Before выбирает записи с номером меньше заданного - курсор следующей страницы.
+	Before *int64
+	Limit  int
+}
+
+// AuditRepository определяет методы для работы с журналом аудита.
+type AuditRepository interface {
+	// LockChain блокирует добавление записей другими транзакциями до конца текущей.
+	LockChain(ctx context.Context) error
+	LastHash(ctx context.Context) (string, error)
+	Append(ctx context.Context, e model.AuditEntry) (model.AuditEntry, error)
+	List(ctx context.Context, filter AuditFilter) ([]model.AuditEntry, error)
+	// Chain возвращает до limit записей с номером больше afterSeq в порядке цепочки.
+	Chain(ctx context.Context, afterSeq int64, limit int) ([]model.AuditEntry, error)
+	// EraseUser удаляет из записей исполнителя, IP-адрес, ID ресурса и параметры, относящиеся
+	// к пользователю, оставляя записи в цепочке.
+	EraseUser(ctx context.Context, userID uuid.UUID) (int64, error)
+}
+
+// PrivacyRepository определяет методы для выполнения запросов субъектов данных.
+type PrivacyRepository interface {
+	ListEvents(ctx context.Context, userID uuid.UUID) ([]model.Event, error)
+	RedactEvents(ctx context.Context, userID, tombstoneID uuid.UUID, aggregateIDs []uuid.UUID) (int64, error)
+	CountReferences(ctx context.Context, userID uuid.UUID) (map[string]int64, error)
+	RecordErasure(ctx context.Context, e model.Erasure) (model.Erasure, error)
+}
+
+// BudgetRepository определяет методы для работы с бюджетами и отправленными по ним оповещениями.
+type BudgetRepository interface {
+	Create(ctx context.Context, b model.Budget) (model.Budget, error)
+	GetByID(ctx context.Context, id uuid.UUID) (model.Budget, error)
+	ListByUserID(ctx context.Context, userID uuid.UUID) ([]model.Budget, error)
+	ListAll(ctx context.Context) ([]model.Budget, error)
+	Update(ctx context.Context, id uuid.UUID, b model.Budget) (model.Budget, error)
 	Delete(ctx context.Context, id uuid.UUID) error
-	ListByUserID(ctx context.Context, userID uuid.UUID, serviceName *string) ([]model.Subscription, error)
+	// RecordAlert отмечает оповещение о пороге за месяц. Возвращает false, если оно уже было отправлено.
+	RecordAlert(ctx context.Context, budgetID uuid.UUID, period model.Date, threshold int) (bool, error)
 }
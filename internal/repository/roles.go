@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/vasiliy-maslov/go-subscription-service/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var _ RoleRepository = (*RoleRepo)(nil)
+
+type RoleRepo struct {
+	db *pgxpool.Pool
+}
+
+// NewRoleRepo создает новый экземпляр репозитория ролей.
+func NewRoleRepo(db *pgxpool.Pool) *RoleRepo {
+	return &RoleRepo{db: db}
+}
+
+// ListRoles возвращает все роли с их правами.
+func (r *RoleRepo) ListRoles(ctx context.Context) ([]model.Role, error) {
+	query := `
+		SELECT r.name, r.description,
+		       COALESCE(array_agg(rp.permission ORDER BY rp.permission) FILTER (WHERE rp.permission IS NOT NULL), '{}')
+		FROM roles r
+		LEFT JOIN role_permissions rp ON rp.role = r.name
+		GROUP BY r.name, r.description
+		ORDER BY r.name`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	roles := make([]model.Role, 0)
+	for rows.Next() {
+		var (
+			role        model.Role
+			permissions []string
+		)
+		if err := rows.Scan(&role.Name, &role.Description, &permissions); err != nil {
+			return nil, err
+		}
+		role.Permissions = toPermissions(permissions)
+		roles = append(roles, role)
+	}
+
+	return roles, rows.Err()
+}
+
+// Permissions возвращает права, которые дают пользователю все выданные ему роли.
+func (r *RoleRepo) Permissions(ctx context.Context, userID uuid.UUID) ([]model.Permission, error) {
+	query := `
+		SELECT DISTINCT rp.permission
+		FROM role_bindings rb
+		JOIN role_permissions rp ON rp.role = rb.role
+		WHERE rb.user_id = $1`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var permissions []model.Permission
+	for rows.Next() {
+		var p model.Permission
+		if err := rows.Scan(&p); err != nil {
+			return nil, err
+		}
+		permissions = append(permissions, p)
+	}
+
+	return permissions, rows.Err()
+}
+
+// ListBindings возвращает роли, выданные пользователю.
+func (r *RoleRepo) ListBindings(ctx context.Context, userID uuid.UUID) ([]model.RoleBinding, error) {
+	query := `SELECT user_id, role, created_at FROM role_bindings WHERE user_id = $1 ORDER BY role`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	bindings := make([]model.RoleBinding, 0)
+	for rows.Next() {
+		var b model.RoleBinding
+		if err := rows.Scan(&b.UserID, &b.Role, &b.CreatedAt); err != nil {
+			return nil, err
+		}
+		bindings = append(bindings, b)
+	}
+
+	return bindings, rows.Err()
+}
+
+// Bind выдает пользователю роль. Повторная выдача не меняет дату первой.
+func (r *RoleRepo) Bind(ctx context.Context, b model.RoleBinding) (model.RoleBinding, error) {
+	query := `
+		INSERT INTO role_bindings (user_id, role, created_at)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT (user_id, role) DO UPDATE SET role = EXCLUDED.role
+		RETURNING user_id, role, created_at`
+
+	var bound model.RoleBinding
+	err := conn(ctx, r.db).QueryRow(ctx, query, b.UserID, b.Role).Scan(&bound.UserID, &bound.Role, &bound.CreatedAt)
+	if err != nil {
+		if isUserViolation(err) {
+			return model.RoleBinding{}, ErrUserNotFound
+		}
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == foreignKeyViolationCode {
+			return model.RoleBinding{}, ErrRoleNotFound
+		}
+		return model.RoleBinding{}, err
+	}
+
+	return bound, nil
+}
+
+// Unbind отзывает у пользователя роль.
+func (r *RoleRepo) Unbind(ctx context.Context, userID uuid.UUID, role string) error {
+	res, err := conn(ctx, r.db).Exec(ctx, `DELETE FROM role_bindings WHERE user_id = $1 AND role = $2`, userID, role)
+	if err != nil {
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return ErrRoleBindingNotFound
+	}
+
+	return nil
+}
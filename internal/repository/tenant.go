@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// ErrTenantMismatch возвращается при попытке записать данные от имени пользователя,
+// подписки которого не видны в контексте.
+var ErrTenantMismatch = errors.New("subscription belongs to another tenant")
+
+type (
+	tenantKey    struct{}
+	userScopeKey struct{}
+	systemKey    struct{}
+)
+
+// WithTenant привязывает контекст к пользователю userID. Запросы к подпискам в этом контексте
+// видят только его подписки, подписки с его участием и подписки его организаций.
+// Контекст без пользователя не видит ни одной подписки, пока ограничение не снято явно
+// (WithoutTenant или WithSystem).
+func WithTenant(ctx context.Context, userID uuid.UUID) context.Context {
+	return context.WithValue(ctx, tenantKey{}, userID)
+}
+
+// WithoutTenant снимает с контекста привязку к пользователю: запросы видят подписки всех пользователей.
+// Используется сервисами, когда политика доступа уже разрешила действие над чужими данными,
+// и для API-ключей без ограничения пользователями. Ограничение WithUserScope сохраняется.
+func WithoutTenant(ctx context.Context) context.Context {
+	return context.WithValue(ctx, tenantKey{}, uuid.Nil)
+}
+
+// WithSystem помечает контекст доверенного внутреннего вызова (outbox relay, фоновая проверка бюджетов).
+// Политика доступа такой контекст не ограничивает. Обработчики API его не создают, поэтому запрос
+// клиента не может получить его ни с какими заголовками.
+func WithSystem(ctx context.Context) context.Context {
+	return context.WithValue(ctx, systemKey{}, true)
+}
+
+// IsSystem сообщает, что контекст принадлежит доверенному внутреннему вызову (WithSystem).
+func IsSystem(ctx context.Context) bool {
+	system, _ := ctx.Value(systemKey{}).(bool)
+	return system
+}
+
+// TenantFrom возвращает пользователя, к которому привязан контекст.
+func TenantFrom(ctx context.Context) (uuid.UUID, bool) {
+	userID, ok := ctx.Value(tenantKey{}).(uuid.UUID)
+	return userID, ok && userID != uuid.Nil
+}
+
+// WithUserScope ограничивает контекст подписками пользователей userIDs так же, как WithTenant
+// ограничивает его подписками одного пользователя. Используется для API-ключей, выданных
+// на данные конкретных пользователей; пустой список не ограничивает выборку.
+func WithUserScope(ctx context.Context, userIDs []uuid.UUID) context.Context {
+	return context.WithValue(ctx, userScopeKey{}, userIDs)
+}
+
+// unrestricted сообщает, что ограничение подписок пользователями снято с контекста явно:
+// это системный вызов (WithSystem) или контекст WithoutTenant.
+func unrestricted(ctx context.Context) bool {
+	if IsSystem(ctx) {
+		return true
+	}
+	userID, ok := ctx.Value(tenantKey{}).(uuid.UUID)
+	return ok && userID == uuid.Nil
+}
+
+// scopeUsers возвращает пользователей, подписки которых видны в контексте: пользователя WithTenant,
+// а без него - список WithUserScope. false означает, что контекст не ограничен. Контекст без
+// пользователя, с которого ограничение не снято явно, ограничен пустым списком и не видит ничего.
+func scopeUsers(ctx context.Context) ([]uuid.UUID, bool) {
+	if userID, ok := TenantFrom(ctx); ok {
+		return []uuid.UUID{userID}, true
+	}
+
+	if userIDs, _ := ctx.Value(userScopeKey{}).([]uuid.UUID); len(userIDs) > 0 {
+		return userIDs, true
+	}
+	if unrestricted(ctx) {
+		return nil, false
+	}
+	return []uuid.UUID{}, true
+}
+
+// tenantScope возвращает условие " AND column IN (...)", ограничивающее подписки с ID в колонке column
+// видимыми в контексте, и добавляет список пользователей в args. Без ограничения возвращает пустую строку.
+func tenantScope(ctx context.Context, column string, args *[]any) string {
+	userIDs, ok := scopeUsers(ctx)
+	if !ok {
+		return ""
+	}
+
+	*args = append(*args, userIDs)
+	return fmt.Sprintf(` AND %[1]s IN (
+		SELECT id FROM subscriptions WHERE user_id = ANY($%[2]d)
+		OR organization_id IN (SELECT organization_id FROM organization_members WHERE user_id = ANY($%[2]d))
+		OR id IN (SELECT subscription_id FROM subscription_members WHERE user_id = ANY($%[2]d)))`, column, len(*args))
+}
+
+// SetTenant передает пользователей, подписки которых видны в контексте, в настройку сессии app.user_ids
+// (через запятую), а снятое ограничение - в app.unrestricted = on. На них основана политика row-level
+// security таблицы subscriptions: при пустом app.user_ids без app.unrestricted строки не видны. Вызывается
+// пулом при выдаче каждого соединения, поэтому транзакции и одиночные запросы выполняются с настройкой
+// того запроса, который их начал.
+func SetTenant(ctx context.Context, c *pgx.Conn) bool {
+	value, all := "", "on"
+	if userIDs, ok := scopeUsers(ctx); ok {
+		ids := make([]string, len(userIDs))
+		for i, id := range userIDs {
+			ids[i] = id.String()
+		}
+		value, all = strings.Join(ids, ","), "off"
+	}
+
+	_, err := c.Exec(ctx, `SELECT set_config('app.user_ids', $1, false), set_config('app.unrestricted', $2, false)`, value, all)
+	return err == nil
+}
+
+// InScope сообщает, что подписки пользователя userID видны в контексте.
+func InScope(ctx context.Context, userID uuid.UUID) bool {
+	userIDs, ok := scopeUsers(ctx)
+	return !ok || slices.Contains(userIDs, userID)
+}
+
+// checkTenant проверяет, что подписка subscriptionID видна пользователю контекста.
+// Вызывается перед записью в таблицы, связанные с подпиской.
+func (r *SubscriptionRepo) checkTenant(ctx context.Context, subscriptionID uuid.UUID) error {
+	args := []any{subscriptionID}
+	scope := tenantScope(ctx, "id", &args)
+	if scope == "" {
+		return nil
+	}
+
+	var visible bool
+	query := `SELECT EXISTS (SELECT 1 FROM subscriptions WHERE id = $1` + scope + `)`
+	if err := conn(ctx, r.db).QueryRow(ctx, query, args...).Scan(&visible); err != nil {
+		return err
+	}
+	if !visible {
+		return ErrNotFound
+	}
+
+	return nil
+}
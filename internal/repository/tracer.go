@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/vasiliy-maslov/go-subscription-service/internal/logging"
+
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.39.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type queryStartKey struct{}
+
+type queryStart struct {
+	sql string
+	at  time.Time
+}
+
+// QueryLogger пишет в лог запросы к базе данных логгером из контекста, поэтому записи
+// репозиториев, в том числе SubscriptionRepo, получают идентификатор HTTP-запроса.
+// Успешные запросы пишутся на уровне Debug, ошибки - на уровне Warn. Аргументы запросов
+// не логируются: в них бывают персональные данные.
+type QueryLogger struct {
+	logger *slog.Logger
+}
+
+// NewQueryLogger создает трассировщик запросов для pgx.ConnConfig.Tracer.
+func NewQueryLogger(logger *slog.Logger) *QueryLogger {
+	return &QueryLogger{logger: logger}
+}
+
+// TraceQueryStart запоминает текст и время начала запроса.
+func (t *QueryLogger) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	return context.WithValue(ctx, queryStartKey{}, queryStart{sql: data.SQL, at: time.Now()})
+}
+
+// TraceQueryEnd пишет запрос в лог вместе с длительностью и результатом.
+func (t *QueryLogger) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	const op = "repository.QueryLogger"
+
+	start, ok := ctx.Value(queryStartKey{}).(queryStart)
+	if !ok {
+		return
+	}
+
+	log := logging.FromContext(ctx, t.logger).With(
+		slog.String("op", op),
+		slog.String("sql", strings.Join(strings.Fields(start.sql), " ")),
+		slog.Duration("duration", time.Since(start.at)),
+	)
+
+	if data.Err != nil {
+		log.Warn("Запрос к базе данных завершился ошибкой", slog.String("error", data.Err.Error()))
+		return
+	}
+	log.Debug("Запрос к базе данных", slog.Int64("rows", data.CommandTag.RowsAffected()))
+}
+
+// tracerName - имя инструментирования для спанов запросов к базе данных.
+const tracerName = "github.com/vasiliy-maslov/go-subscription-service/internal/repository"
+
+// SpanTracer создает спан OpenTelemetry на каждый запрос к базе данных с текстом запроса,
+// числом возвращенных или измененных строк и пользователем контекста.
+type SpanTracer struct {
+	tracer trace.Tracer
+}
+
+// NewSpanTracer создает трассировщик запросов для pgx.ConnConfig.Tracer.
+// Спаны пишутся глобальным провайдером OpenTelemetry.
+func NewSpanTracer() *SpanTracer {
+	return &SpanTracer{tracer: otel.Tracer(tracerName)}
+}
+
+// TraceQueryStart открывает спан запроса.
+func (t *SpanTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	sql := strings.Join(strings.Fields(data.SQL), " ")
+	operation, _, _ := strings.Cut(sql, " ")
+
+	attrs := []attribute.KeyValue{
+		semconv.DBSystemNamePostgreSQL,
+		semconv.DBQueryText(sql),
+		semconv.DBOperationName(strings.ToUpper(operation)),
+	}
+	if userID, ok := TenantFrom(ctx); ok {
+		attrs = append(attrs, semconv.EnduserID(userID.String()))
+	}
+
+	ctx, _ = t.tracer.Start(ctx, "db "+strings.ToUpper(operation),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+	)
+	return ctx
+}
+
+// TraceQueryEnd закрывает спан запроса и записывает результат.
+func (t *SpanTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	span := trace.SpanFromContext(ctx)
+	defer span.End()
+
+	if data.Err != nil {
+		span.RecordError(data.Err)
+		span.SetStatus(codes.Error, data.Err.Error())
+		return
+	}
+	span.SetAttributes(semconv.DBResponseReturnedRows(int(data.CommandTag.RowsAffected())))
+}
@@ -0,0 +1,239 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// serializationFailureCode - SQLSTATE ошибки сериализации, после которой транзакцию можно повторить.
+const serializationFailureCode = "40001"
+
+// IsolationLevel - уровень изоляции транзакции.
+type IsolationLevel string
+
+const (
+	ReadCommitted  IsolationLevel = "read committed"
+	RepeatableRead IsolationLevel = "repeatable read"
+	Serializable   IsolationLevel = "serializable"
+)
+
+// ParseIsolationLevel проверяет уровень изоляции из конфигурации. Пустая строка означает read committed.
+func ParseIsolationLevel(s string) (IsolationLevel, error) {
+	switch level := IsolationLevel(s); level {
+	case "":
+		return ReadCommitted, nil
+	case ReadCommitted, RepeatableRead, Serializable:
+		return level, nil
+	default:
+		return "", fmt.Errorf("неизвестный уровень изоляции: %s", s)
+	}
+}
+
+type txOptions struct {
+	isolation   IsolationLevel
+	maxAttempts int
+}
+
+// TxOption настраивает отдельный вызов WithinTx.
+type TxOption func(*txOptions)
+
+// WithIsolation задает уровень изоляции транзакции.
+func WithIsolation(level IsolationLevel) TxOption {
+	return func(o *txOptions) { o.isolation = level }
+}
+
+// WithMaxAttempts задает число попыток выполнения транзакции при ошибках сериализации.
+func WithMaxAttempts(n int) TxOption {
+	return func(o *txOptions) { o.maxAttempts = n }
+}
+
+// ErrNestedTxOptions возвращается, когда вложенный вызов WithinTx запрашивает уровень изоляции
+// или число попыток, отличные от параметров внешней транзакции, к которой он присоединяется.
+var ErrNestedTxOptions = errors.New("nested transaction options differ from the outer transaction")
+
+// TxManager выполняет функцию в рамках одной транзакции.
+// Репозитории автоматически используют транзакцию из переданного в fn контекста.
+// Вложенные вызовы присоединяются к внешней транзакции; если они задают другие параметры,
+// возвращается ErrNestedTxOptions.
+type TxManager interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error, opts ...TxOption) error
+}
+
+// txState - транзакция Postgres в контексте вместе с параметрами, с которыми она начата.
+type txState struct {
+	tx   pgx.Tx
+	opts txOptions
+}
+
+type txKey struct{}
+
+// querier - общий интерфейс для pgxpool.Pool и pgx.Tx.
+type querier interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// conn возвращает транзакцию из контекста, а если ее нет - пул соединений.
+func conn(ctx context.Context, db *pgxpool.Pool) querier {
+	if state, ok := ctx.Value(txKey{}).(txState); ok {
+		return state.tx
+	}
+	return db
+}
+
+// joinOptions проверяет, что вложенный вызов с opts может присоединиться к транзакции с параметрами outer.
+func joinOptions(outer txOptions, opts []TxOption) error {
+	requested := outer
+	for _, opt := range opts {
+		opt(&requested)
+	}
+	if requested != outer {
+		return fmt.Errorf("%w: requested %s with %d attempts inside %s with %d attempts", ErrNestedTxOptions,
+			requested.isolation, requested.maxAttempts, outer.isolation, outer.maxAttempts)
+	}
+	return nil
+}
+
+// withRetry выполняет попытки run, пока они завершаются ошибкой сериализации (SQLSTATE 40001),
+// но не больше maxAttempts раз.
+func withRetry(ctx context.Context, maxAttempts int, run func() error) error {
+	var err error
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		err = run()
+		if err == nil || !isSerializationFailure(err) {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Duration(attempt) * 10 * time.Millisecond):
+		}
+	}
+
+	return err
+}
+
+func isSerializationFailure(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == serializationFailureCode
+}
+
+var _ TxManager = (*PgTxManager)(nil)
+
+type PgTxManager struct {
+	db       *pgxpool.Pool
+	defaults txOptions
+}
+
+// NewPgTxManager создает менеджер транзакций Postgres с уровнем изоляции и числом попыток по умолчанию.
+func NewPgTxManager(db *pgxpool.Pool, isolation IsolationLevel, maxAttempts int) *PgTxManager {
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+	return &PgTxManager{
+		db:       db,
+		defaults: txOptions{isolation: isolation, maxAttempts: maxAttempts},
+	}
+}
+
+// WithinTx выполняет fn в транзакции и повторяет ее при ошибке сериализации (SQLSTATE 40001).
+func (m *PgTxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error, opts ...TxOption) error {
+	if state, ok := ctx.Value(txKey{}).(txState); ok {
+		if err := joinOptions(state.opts, opts); err != nil {
+			return err
+		}
+		return fn(ctx)
+	}
+
+	o := m.defaults
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return withRetry(ctx, o.maxAttempts, func() error { return m.run(ctx, fn, o) })
+}
+
+func (m *PgTxManager) run(ctx context.Context, fn func(ctx context.Context) error, o txOptions) error {
+	tx, err := m.db.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.TxIsoLevel(o.isolation)})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck
+
+	if err := fn(context.WithValue(ctx, txKey{}, txState{tx: tx, opts: o})); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+var _ TxManager = (*MemoryTxManager)(nil)
+
+// MemoryTxManager - менеджер транзакций для хранилищ в памяти и тестов. Функции выполняются
+// строго последовательно; хранилища регистрируют отмену своих изменений через OnRollback,
+// и при ошибке fn изменения отменяются в обратном порядке. Ошибки сериализации повторяются
+// так же, как в PgTxManager.
+type MemoryTxManager struct {
+	mu       sync.Mutex
+	defaults txOptions
+}
+
+// NewMemoryTxManager создает менеджер транзакций в памяти с параметрами по умолчанию read committed
+// и одной попыткой.
+func NewMemoryTxManager() *MemoryTxManager {
+	return &MemoryTxManager{defaults: txOptions{isolation: ReadCommitted, maxAttempts: 1}}
+}
+
+// memoryTx - журнал отмены изменений транзакции в памяти.
+type memoryTx struct {
+	opts txOptions
+	undo []func()
+}
+
+type memoryTxKey struct{}
+
+// OnRollback регистрирует отмену изменения хранилища в памяти. Вне транзакции MemoryTxManager
+// изменение сразу считается зафиксированным, и undo не вызывается.
+func OnRollback(ctx context.Context, undo func()) {
+	if tx, ok := ctx.Value(memoryTxKey{}).(*memoryTx); ok {
+		tx.undo = append(tx.undo, undo)
+	}
+}
+
+func (m *MemoryTxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error, opts ...TxOption) error {
+	if tx, ok := ctx.Value(memoryTxKey{}).(*memoryTx); ok {
+		if err := joinOptions(tx.opts, opts); err != nil {
+			return err
+		}
+		return fn(ctx)
+	}
+
+	o := m.defaults
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return withRetry(ctx, o.maxAttempts, func() error {
+		tx := &memoryTx{opts: o}
+		err := fn(context.WithValue(ctx, memoryTxKey{}, tx))
+		if err != nil {
+			for i := len(tx.undo) - 1; i >= 0; i-- {
+				tx.undo[i]()
+			}
+		}
+		return err
+	})
+}
@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// memoryStore - хранилище в памяти, которое регистрирует отмену изменений в MemoryTxManager.
+type memoryStore struct {
+	values map[string]string
+}
+
+func (s *memoryStore) Put(ctx context.Context, key, value string) {
+	previous, existed := s.values[key]
+	s.values[key] = value
+	OnRollback(ctx, func() {
+		if existed {
+			s.values[key] = previous
+		} else {
+			delete(s.values, key)
+		}
+	})
+}
+
+var errFailed = errors.New("failed")
+
+func TestMemoryTxManager(t *testing.T) {
+	serializationFailure := &pgconn.PgError{Code: serializationFailureCode}
+
+	tests := []struct {
+		name    string
+		opts    []TxOption
+		fn      func(ctx context.Context, tx TxManager, store *memoryStore, attempt int) error
+		wantErr error
+		want    map[string]string
+	}{
+		{
+			name: "commit keeps all writes",
+			fn: func(ctx context.Context, _ TxManager, store *memoryStore, _ int) error {
+				store.Put(ctx, "a", "2")
+				store.Put(ctx, "b", "1")
+				return nil
+			},
+			want: map[string]string{"a": "2", "b": "1"},
+		},
+		{
+			name: "error rolls back partial writes",
+			fn: func(ctx context.Context, _ TxManager, store *memoryStore, _ int) error {
+				store.Put(ctx, "a", "2")
+				store.Put(ctx, "a", "3")
+				store.Put(ctx, "b", "1")
+				return errFailed
+			},
+			wantErr: errFailed,
+			want:    map[string]string{"a": "1"},
+		},
+		{
+			name: "nested call joins the outer transaction",
+			fn: func(ctx context.Context, tx TxManager, store *memoryStore, _ int) error {
+				store.Put(ctx, "a", "2")
+				err := tx.WithinTx(ctx, func(ctx context.Context) error {
+					store.Put(ctx, "b", "1")
+					return nil
+				})
+				if err != nil {
+					return err
+				}
+				return errFailed
+			},
+			wantErr: errFailed,
+			want:    map[string]string{"a": "1"},
+		},
+		{
+			name: "nested call with the same options is allowed",
+			opts: []TxOption{WithIsolation(Serializable)},
+			fn: func(ctx context.Context, tx TxManager, store *memoryStore, _ int) error {
+				return tx.WithinTx(ctx, func(ctx context.Context) error {
+					store.Put(ctx, "b", "1")
+					return nil
+				}, WithIsolation(Serializable))
+			},
+			want: map[string]string{"a": "1", "b": "1"},
+		},
+		{
+			name: "nested call with another isolation level fails",
+			fn: func(ctx context.Context, tx TxManager, store *memoryStore, _ int) error {
+				store.Put(ctx, "a", "2")
+				return tx.WithinTx(ctx, func(ctx context.Context) error {
+					store.Put(ctx, "b", "1")
+					return nil
+				}, WithIsolation(Serializable))
+			},
+			wantErr: ErrNestedTxOptions,
+			want:    map[string]string{"a": "1"},
+		},
+		{
+			name: "nested call with another number of attempts fails",
+			fn: func(ctx context.Context, tx TxManager, _ *memoryStore, _ int) error {
+				return tx.WithinTx(ctx, func(context.Context) error { return nil }, WithMaxAttempts(5))
+			},
+			wantErr: ErrNestedTxOptions,
+			want:    map[string]string{"a": "1"},
+		},
+		{
+			name: "serialization failure is retried after rollback",
+			opts: []TxOption{WithMaxAttempts(3)},
+			fn: func(ctx context.Context, _ TxManager, store *memoryStore, attempt int) error {
+				store.Put(ctx, "attempt", string(rune('0'+attempt)))
+				if attempt < 3 {
+					store.Put(ctx, "failed", "yes")
+					return serializationFailure
+				}
+				return nil
+			},
+			want: map[string]string{"a": "1", "attempt": "3"},
+		},
+		{
+			name: "retries stop after the last attempt",
+			opts: []TxOption{WithMaxAttempts(2)},
+			fn: func(ctx context.Context, _ TxManager, store *memoryStore, _ int) error {
+				store.Put(ctx, "a", "2")
+				return serializationFailure
+			},
+			wantErr: serializationFailure,
+			want:    map[string]string{"a": "1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := NewMemoryTxManager()
+			store := &memoryStore{values: map[string]string{"a": "1"}}
+
+			attempt := 0
+			err := tx.WithinTx(context.Background(), func(ctx context.Context) error {
+				attempt++
+				return tt.fn(ctx, tx, store, attempt)
+			}, tt.opts...)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("WithinTx() error = %v, want %v", err, tt.wantErr)
+			}
+			if !maps.Equal(store.values, tt.want) {
+				t.Errorf("store = %v, want %v", store.values, tt.want)
+			}
+		})
+	}
+}
+
+func TestOnRollbackOutsideTransaction(t *testing.T) {
+	store := &memoryStore{values: map[string]string{}}
+	store.Put(context.Background(), "a", "1")
+
+	if store.values["a"] != "1" {
+		t.Fatalf("write outside a transaction was not applied")
+	}
+}
@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/vasiliy-maslov/go-subscription-service/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const userColumns = `id, display_name, COALESCE(email, ''), default_currency, time_zone, notification_preferences, created_at, updated_at`
+
+var _ UserRepository = (*UserRepo)(nil)
+
+type UserRepo struct {
+	db *pgxpool.Pool
+}
+
+// NewUserRepo создает новый экземпляр репозитория пользователей.
+func NewUserRepo(db *pgxpool.Pool) *UserRepo {
+	return &UserRepo{db: db}
+}
+
+// Create сохраняет нового пользователя с ID u.ID, а если он не задан - с новым ID.
+// Почта уникальна без учета регистра.
+func (r *UserRepo) Create(ctx context.Context, u model.User) (model.User, error) {
+	query := `
+		INSERT INTO users (id, display_name, email, default_currency, time_zone, notification_preferences, created_at, updated_at)
+		VALUES ($1, $2, NULLIF($3, ''), $4, $5, $6, NOW(), NOW())
+		RETURNING ` + userColumns
+
+	id := u.ID
+	if id == uuid.Nil {
+		id = uuid.New()
+	}
+
+	created, err := scanUser(conn(ctx, r.db).QueryRow(ctx, query,
+		id, u.DisplayName, u.Email, u.DefaultCurrency, u.TimeZone, u.Notifications))
+	if err != nil {
+		if isUniqueViolation(err) {
+			return model.User{}, ErrAlreadyExists
+		}
+		return model.User{}, err
+	}
+
+	return created, nil
+}
+
+// GetByID получает пользователя по ID.
+func (r *UserRepo) GetByID(ctx context.Context, id uuid.UUID) (model.User, error) {
+	return r.get(ctx, `SELECT `+userColumns+` FROM users WHERE id = $1`, id)
+}
+
+// GetByIDForUpdate получает пользователя и блокирует его строку до конца транзакции.
+// Вызывается только внутри TxManager.WithinTx.
+func (r *UserRepo) GetByIDForUpdate(ctx context.Context, id uuid.UUID) (model.User, error) {
+	return r.get(ctx, `SELECT `+userColumns+` FROM users WHERE id = $1 FOR UPDATE`, id)
+}
+
+// Update заменяет профиль и настройки пользователя.
+func (r *UserRepo) Update(ctx context.Context, id uuid.UUID, u model.User) (model.User, error) {
+	query := `
+		UPDATE users
+		SET display_name = $1, email = NULLIF($2, ''), default_currency = $3, time_zone = $4,
+		    notification_preferences = $5, updated_at = NOW()
+		WHERE id = $6
+		RETURNING ` + userColumns
+
+	updated, err := scanUser(conn(ctx, r.db).QueryRow(ctx, query,
+		u.DisplayName, u.Email, u.DefaultCurrency, u.TimeZone, u.Notifications, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.User{}, ErrUserNotFound
+		}
+		if isUniqueViolation(err) {
+			return model.User{}, ErrAlreadyExists
+		}
+		return model.User{}, err
+	}
+
+	return updated, nil
+}
+
+// Delete удаляет пользователя. Подписки, участие в чужих подписках, категории, метки
+// и бюджеты пользователя удаляются каскадно внешними ключами.
+func (r *UserRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	res, err := conn(ctx, r.db).Exec(ctx, `DELETE FROM users WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return ErrUserNotFound
+	}
+
+	return nil
+}
+
+func (r *UserRepo) get(ctx context.Context, query string, id uuid.UUID) (model.User, error) {
+	u, err := scanUser(conn(ctx, r.db).QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.User{}, ErrUserNotFound
+		}
+		return model.User{}, err
+	}
+
+	return u, nil
+}
+
+func scanUser(row pgx.Row) (model.User, error) {
+	var u model.User
+	err := row.Scan(&u.ID, &u.DisplayName, &u.Email, &u.DefaultCurrency, &u.TimeZone, &u.Notifications, &u.CreatedAt, &u.UpdatedAt)
+	return u, err
+}
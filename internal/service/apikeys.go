@@ -0,0 +1,317 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/vasiliy-maslov/go-subscription-service/internal/logging"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/model"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidAPIKey возвращается, когда API-ключ не существует, отозван, истек или его секрет не совпадает.
+var ErrInvalidAPIKey = errors.New("invalid api key")
+
+// Формат ключа: sk_<prefix>_<secret>. Префикс хранится открыто и служит для поиска ключа,
+// секрет - только в виде SHA-256: он случайный и достаточно длинный, поэтому медленный хеш не нужен.
+const (
+	apiKeyScheme      = "sk"
+	apiKeyPrefixBytes = 6
+	apiKeySecretBytes = 32
+)
+
+type apiKeyKey struct{}
+
+// WithAPIKey привязывает контекст к API-ключу: права на действия определяются правами ключа,
+// а если ключ выдан на данные конкретных пользователей, запросы видят только их подписки.
+// Ключ без user_ids видит подписки всех пользователей.
+func WithAPIKey(ctx context.Context, key model.APIKey) context.Context {
+	ctx = context.WithValue(ctx, apiKeyKey{}, key)
+	if len(key.UserIDs) > 0 {
+		return repository.WithUserScope(ctx, key.UserIDs)
+	}
+	return repository.WithoutTenant(ctx)
+}
+
+// APIKeyFrom возвращает API-ключ, которым аутентифицирован запрос.
+func APIKeyFrom(ctx context.Context) (model.APIKey, bool) {
+	key, ok := ctx.Value(apiKeyKey{}).(model.APIKey)
+	return key, ok
+}
+
+// APIKeyService определяет интерфейс для выпуска, отзыва и проверки API-ключей машинных клиентов.
+// Выпуск, просмотр и отзыв требуют права api_keys:manage.
+type APIKeyService interface {
+	// Create выпускает ключ. Полное значение ключа возвращается только здесь. Ключ не может получить
+	// права или пользователей, которых нет у того, кто его выпускает.
+	Create(ctx context.Context, key model.APIKey) (model.IssuedAPIKey, error)
+	List(ctx context.Context) ([]model.APIKey, error)
+	Revoke(ctx context.Context, id uuid.UUID) (model.APIKey, error)
+	// Authenticate проверяет значение ключа из заголовка Authorization и отмечает его использование.
+	Authenticate(ctx context.Context, raw string) (model.APIKey, error)
+}
+
+type apiKeyService struct {
+	repo   repository.APIKeyRepository
+	users  repository.UserRepository
+	policy Policy
+	outbox repository.OutboxRepository
+	tx     repository.TxManager
+	logger *slog.Logger
+}
+
+// NewAPIKeyService создает новый экземпляр сервиса API-ключей.
+func NewAPIKeyService(
+	repo repository.APIKeyRepository,
+	users repository.UserRepository,
+	policy Policy,
+	outbox repository.OutboxRepository,
+	tx repository.TxManager,
+	logger *slog.Logger,
+) APIKeyService {
+	return &apiKeyService{
+		repo:   repo,
+		users:  users,
+		policy: policy,
+		outbox: outbox,
+		tx:     tx,
+		logger: logger,
+	}
+}
+
+func (s *apiKeyService) Create(ctx context.Context, key model.APIKey) (model.IssuedAPIKey, error) {
+	const op = "apikeys.Create"
+	log := logging.FromContext(ctx, s.logger).With(slog.String("op", op), slog.String("name", key.Name))
+
+	log.Info("Выпуск API-ключа")
+
+	if err := s.policy.Authorize(ctx, model.PermissionAPIKeysManage); err != nil {
+		log.Warn("Выпуск API-ключа запрещен", slog.String("error", err.Error()))
+		return model.IssuedAPIKey{}, err
+	}
+	if err := s.validate(ctx, &key); err != nil {
+		log.Warn("API-ключ не прошел проверку", slog.String("error", err.Error()))
+		return model.IssuedAPIKey{}, err
+	}
+	if err := authorizeIssue(ctx, s.policy, key); err != nil {
+		log.Warn("Выпуск API-ключа с такими правами запрещен", slog.String("error", err.Error()))
+		return model.IssuedAPIKey{}, err
+	}
+
+	prefix, err := randomHex(apiKeyPrefixBytes)
+	if err != nil {
+		return model.IssuedAPIKey{}, err
+	}
+	secret, err := randomHex(apiKeySecretBytes)
+	if err != nil {
+		return model.IssuedAPIKey{}, err
+	}
+	key.Prefix = prefix
+
+	var created model.APIKey
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		created, err = s.repo.Create(ctx, key, hashSecret(secret))
+		if err != nil {
+			return err
+		}
+
+		return s.outbox.Add(ctx, model.EventAPIKeyCreated, created.ID, created)
+	})
+	if err != nil {
+		log.Error("Не удалось выпустить API-ключ", slog.String("error", err.Error()))
+		return model.IssuedAPIKey{}, err
+	}
+
+	log.Info("API-ключ успешно выпущен", slog.String("api_key_id", created.ID.String()), slog.String("prefix", created.Prefix))
+	return model.IssuedAPIKey{
+		APIKey: created,
+		Key:    apiKeyScheme + "_" + prefix + "_" + secret,
+	}, nil
+}
+
+func (s *apiKeyService) List(ctx context.Context) ([]model.APIKey, error) {
+	const op = "apikeys.List"
+	log := logging.FromContext(ctx, s.logger).With(slog.String("op", op))
+
+	if err := s.policy.Authorize(ctx, model.PermissionAPIKeysManage); err != nil {
+		log.Warn("Просмотр API-ключей запрещен", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	keys, err := s.repo.List(ctx)
+	if err != nil {
+		log.Error("Не удалось получить API-ключи", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+func (s *apiKeyService) Revoke(ctx context.Context, id uuid.UUID) (model.APIKey, error) {
+	const op = "apikeys.Revoke"
+	log := logging.FromContext(ctx, s.logger).With(slog.String("op", op), slog.String("api_key_id", id.String()))
+
+	log.Info("Отзыв API-ключа")
+
+	if err := s.policy.Authorize(ctx, model.PermissionAPIKeysManage); err != nil {
+		log.Warn("Отзыв API-ключа запрещен", slog.String("error", err.Error()))
+		return model.APIKey{}, err
+	}
+
+	var revoked model.APIKey
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		revoked, err = s.repo.Revoke(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		return s.outbox.Add(ctx, model.EventAPIKeyRevoked, id, revoked)
+	})
+	if err != nil {
+		log.Error("Не удалось отозвать API-ключ", slog.String("error", err.Error()))
+		return model.APIKey{}, err
+	}
+
+	log.Info("API-ключ успешно отозван")
+	return revoked, nil
+}
+
+func (s *apiKeyService) Authenticate(ctx context.Context, raw string) (model.APIKey, error) {
+	const op = "apikeys.Authenticate"
+	log := logging.FromContext(ctx, s.logger).With(slog.String("op", op))
+
+	scheme, rest, _ := strings.Cut(raw, "_")
+	prefix, secret, _ := strings.Cut(rest, "_")
+	if scheme != apiKeyScheme || prefix == "" || secret == "" {
+		return model.APIKey{}, ErrInvalidAPIKey
+	}
+	log = log.With(slog.String("prefix", prefix))
+
+	key, secretHash, err := s.repo.GetByPrefix(ctx, prefix)
+	if errors.Is(err, repository.ErrAPIKeyNotFound) {
+		log.Warn("API-ключ не найден")
+		return model.APIKey{}, ErrInvalidAPIKey
+	}
+	if err != nil {
+		log.Error("Не удалось получить API-ключ", slog.String("error", err.Error()))
+		return model.APIKey{}, err
+	}
+
+	if subtle.ConstantTimeCompare([]byte(hashSecret(secret)), []byte(secretHash)) != 1 {
+		log.Warn("Секрет API-ключа не совпадает")
+		return model.APIKey{}, ErrInvalidAPIKey
+	}
+	if !key.ActiveAt(time.Now()) {
+		log.Warn("API-ключ отозван или истек")
+		return model.APIKey{}, ErrInvalidAPIKey
+	}
+
+	// Ошибка отметки использования не должна отклонять корректный запрос.
+	if err := s.repo.Touch(ctx, key.ID); err != nil {
+		log.Error("Не удалось отметить использование API-ключа", slog.String("error", err.Error()))
+	}
+
+	return key, nil
+}
+
+// validate проверяет название, права, срок действия и пользователей ключа.
+func (s *apiKeyService) validate(ctx context.Context, key *model.APIKey) error {
+	key.Name = strings.TrimSpace(key.Name)
+	if key.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrValidation)
+	}
+
+	if len(key.Permissions) == 0 {
+		return fmt.Errorf("%w: at least one permission is required", ErrValidation)
+	}
+	for _, p := range key.Permissions {
+		if !p.Valid() {
+			return fmt.Errorf("%w: unknown permission %q", ErrValidation, p)
+		}
+	}
+
+	if key.ExpiresAt != nil && !key.ExpiresAt.After(time.Now()) {
+		return fmt.Errorf("%w: expires_at must be in the future", ErrValidation)
+	}
+
+	if key.UserIDs == nil {
+		key.UserIDs = []uuid.UUID{}
+	}
+	for _, userID := range key.UserIDs {
+		if _, err := s.users.GetByID(ctx, userID); err != nil {
+			if errors.Is(err, repository.ErrUserNotFound) {
+				return fmt.Errorf("%w: user %s not found", ErrValidation, userID)
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+// authorizeIssue проверяет, что выпускаемый ключ не шире того, кто его выпускает: каждое право ключа
+// должно быть у выпускающего, а пользователи ключа - входить в его область. Ключ с user_ids выпускает
+// ключи только на часть своих пользователей, пользователь токена - на себя, а с правом users:manage,
+// как и ключ без user_ids, - на любых пользователей, в том числе ключ без user_ids.
+func authorizeIssue(ctx context.Context, policy Policy, key model.APIKey) error {
+	for _, p := range key.Permissions {
+		if err := policy.Authorize(ctx, p); err != nil {
+			return err
+		}
+	}
+
+	if issuer, ok := APIKeyFrom(ctx); ok {
+		if len(issuer.UserIDs) == 0 {
+			return nil
+		}
+		if len(key.UserIDs) == 0 {
+			return ErrOutOfScope
+		}
+		for _, userID := range key.UserIDs {
+			if !slices.Contains(issuer.UserIDs, userID) {
+				return ErrOutOfScope
+			}
+		}
+		return nil
+	}
+
+	// Без пользователя и ключа сюда доходит только системный вызов: его область не ограничена.
+	tenant, ok := repository.TenantFrom(ctx)
+	if !ok {
+		return nil
+	}
+	self := len(key.UserIDs) > 0
+	for _, userID := range key.UserIDs {
+		self = self && userID == tenant
+	}
+	if self {
+		return nil
+	}
+	return policy.Authorize(ctx, model.PermissionUsersManage)
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/vasiliy-maslov/go-subscription-service/internal/model"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+func TestAuthorizeIssue(t *testing.T) {
+	issuer, admin, other := uuid.New(), uuid.New(), uuid.New()
+	policy := NewPolicy(&fakeRoles{permissions: map[uuid.UUID][]model.Permission{
+		issuer: {model.PermissionAPIKeysManage, model.PermissionSubscriptionsRead},
+		admin:  {model.PermissionAPIKeysManage, model.PermissionSubscriptionsRead, model.PermissionUsersManage},
+	}})
+	read := []model.Permission{model.PermissionSubscriptionsRead}
+	scopedIssuer := WithAPIKey(context.Background(), model.APIKey{
+		Permissions: []model.Permission{model.PermissionAPIKeysManage, model.PermissionSubscriptionsRead},
+		UserIDs:     []uuid.UUID{issuer, other},
+	})
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		key     model.APIKey
+		wantErr error
+	}{
+		{
+			name: "user issues a key on own data",
+			ctx:  repository.WithTenant(context.Background(), issuer),
+			key:  model.APIKey{Permissions: read, UserIDs: []uuid.UUID{issuer}},
+		},
+		{
+			name:    "user cannot grant a permission they lack",
+			ctx:     repository.WithTenant(context.Background(), issuer),
+			key:     model.APIKey{Permissions: []model.Permission{model.PermissionRolesManage}, UserIDs: []uuid.UUID{issuer}},
+			wantErr: ErrPermissionDenied,
+		},
+		{
+			name:    "user cannot issue a key on another user",
+			ctx:     repository.WithTenant(context.Background(), issuer),
+			key:     model.APIKey{Permissions: read, UserIDs: []uuid.UUID{issuer, other}},
+			wantErr: ErrPermissionDenied,
+		},
+		{
+			name:    "user cannot issue a key on all users",
+			ctx:     repository.WithTenant(context.Background(), issuer),
+			key:     model.APIKey{Permissions: read},
+			wantErr: ErrPermissionDenied,
+		},
+		{
+			name: "user with users:manage issues a key on all users",
+			ctx:  repository.WithTenant(context.Background(), admin),
+			key:  model.APIKey{Permissions: read},
+		},
+		{
+			name: "scoped key issues a key on part of its users",
+			ctx:  scopedIssuer,
+			key:  model.APIKey{Permissions: read, UserIDs: []uuid.UUID{other}},
+		},
+		{
+			name:    "scoped key cannot widen the scope",
+			ctx:     scopedIssuer,
+			key:     model.APIKey{Permissions: read, UserIDs: []uuid.UUID{admin}},
+			wantErr: ErrOutOfScope,
+		},
+		{
+			name:    "scoped key cannot issue a key on all users",
+			ctx:     scopedIssuer,
+			key:     model.APIKey{Permissions: read},
+			wantErr: ErrOutOfScope,
+		},
+		{
+			name:    "key cannot grant a permission it lacks",
+			ctx:     scopedIssuer,
+			key:     model.APIKey{Permissions: []model.Permission{model.PermissionAuditRead}, UserIDs: []uuid.UUID{other}},
+			wantErr: ErrPermissionDenied,
+		},
+		{
+			name: "system call issues any key",
+			ctx:  repository.WithSystem(context.Background()),
+			key:  model.APIKey{Permissions: []model.Permission{model.PermissionRolesManage}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := authorizeIssue(tt.ctx, policy, tt.key)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("authorizeIssue() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
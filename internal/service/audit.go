@@ -0,0 +1,205 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/vasiliy-maslov/go-subscription-service/internal/logging"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/model"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/repository"
+)
+
+const (
+	// defaultAuditLimit - размер страницы журнала аудита по умолчанию.
+	defaultAuditLimit = 100
+	// maxAuditLimit ограничивает размер страницы журнала аудита.
+	maxAuditLimit = 1000
+	// auditVerifyBatch - сколько записей читается за раз при проверке цепочки.
+	auditVerifyBatch = 1000
+)
+
+// AuditSink - дополнительный приемник записей журнала аудита, например файл JSON Lines.
+// Получает записи после сохранения в базе данных, уже с номером и хешем.
+type AuditSink interface {
+	Write(ctx context.Context, e model.AuditEntry) error
+}
+
+// AuditService определяет интерфейс журнала аудита изменяющих вызовов API.
+// Чтение журнала и проверка целостности требуют права audit:read.
+type AuditService interface {
+	// Record добавляет запись в конец цепочки. Права не проверяются: записи создает сам сервис.
+	Record(ctx context.Context, e model.AuditEntry) (model.AuditEntry, error)
+	List(ctx context.Context, filter repository.AuditFilter) ([]model.AuditEntry, error)
+	// Verify проверяет цепочку хешей от первой записи до последней.
+	Verify(ctx context.Context) (model.AuditVerification, error)
+}
+
+type auditService struct {
+	repo   repository.AuditRepository
+	sink   AuditSink
+	policy Policy
+	tx     repository.TxManager
+	logger *slog.Logger
+}
+
+// NewAuditService создает новый экземпляр сервиса журнала аудита. sink может быть nil.
+func NewAuditService(
+	repo repository.AuditRepository,
+	sink AuditSink,
+	policy Policy,
+	tx repository.TxManager,
+	logger *slog.Logger,
+) AuditService {
+	return &auditService{
+		repo:   repo,
+		sink:   sink,
+		policy: policy,
+		tx:     tx,
+		logger: logger,
+	}
+}
+
+func (s *auditService) Record(ctx context.Context, e model.AuditEntry) (model.AuditEntry, error) {
+	const op = "audit.Record"
+	log := logging.FromContext(ctx, s.logger).With(slog.String("op", op), slog.String("action", e.Action))
+
+	if e.OccurredAt.IsZero() {
+		e.OccurredAt = time.Now()
+	}
+	e.OccurredAt = e.OccurredAt.UTC().Truncate(time.Microsecond)
+
+	// Исполнитель, IP-адрес, ID ресурса и параметры входят в цепочку только хешами с солью:
+	// после их удаления восстановить их по хешам нельзя, а цепочка остается проверяемой.
+	identitySalt, err := randomHex(16)
+	if err != nil {
+		log.Error("Не удалось сгенерировать соль записи аудита", slog.String("error", err.Error()))
+		return model.AuditEntry{}, err
+	}
+	subjectSalt, err := randomHex(16)
+	if err != nil {
+		log.Error("Не удалось сгенерировать соль записи аудита", slog.String("error", err.Error()))
+		return model.AuditEntry{}, err
+	}
+	e.IdentitySalt, e.SubjectSalt = identitySalt, subjectSalt
+	e.IdentityHash = e.ComputeIdentityHash()
+	e.SubjectHash = e.ComputeSubjectHash()
+
+	// Блокировка упорядочивает записи: каждая ссылается на хеш действительно предыдущей.
+	var recorded model.AuditEntry
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.LockChain(ctx); err != nil {
+			return err
+		}
+		prev, err := s.repo.LastHash(ctx)
+		if err != nil {
+			return err
+		}
+
+		e.PrevHash = prev
+		e.Hash = e.ComputeHash()
+		recorded, err = s.repo.Append(ctx, e)
+		return err
+	})
+	if err != nil {
+		log.Error("Не удалось записать событие аудита", slog.String("error", err.Error()))
+		return model.AuditEntry{}, err
+	}
+
+	// Основной журнал - база данных, поэтому ошибка приемника только пишется в лог.
+	if s.sink != nil {
+		if err := s.sink.Write(ctx, recorded); err != nil {
+			log.Error("Не удалось записать событие аудита в приемник", slog.Int64("seq", recorded.Seq), slog.String("error", err.Error()))
+		}
+	}
+
+	return recorded, nil
+}
+
+func (s *auditService) List(ctx context.Context, filter repository.AuditFilter) ([]model.AuditEntry, error) {
+	const op = "audit.List"
+	log := logging.FromContext(ctx, s.logger).With(slog.String("op", op))
+
+	if err := s.policy.Authorize(ctx, model.PermissionAuditRead); err != nil {
+		log.Warn("Чтение журнала аудита запрещено", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	switch {
+	case filter.Limit == 0:
+		filter.Limit = defaultAuditLimit
+	case filter.Limit < 0 || filter.Limit > maxAuditLimit:
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", ErrValidation, maxAuditLimit)
+	}
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return nil, fmt.Errorf("%w: from must be before to", ErrValidation)
+	}
+
+	entries, err := s.repo.List(ctx, filter)
+	if err != nil {
+		log.Error("Не удалось получить журнал аудита", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+func (s *auditService) Verify(ctx context.Context) (model.AuditVerification, error) {
+	const op = "audit.Verify"
+	log := logging.FromContext(ctx, s.logger).With(slog.String("op", op))
+
+	if err := s.policy.Authorize(ctx, model.PermissionAuditRead); err != nil {
+		log.Warn("Проверка журнала аудита запрещена", slog.String("error", err.Error()))
+		return model.AuditVerification{}, err
+	}
+
+	var (
+		result  model.AuditVerification
+		prev    string
+		lastSeq int64
+	)
+	for {
+		entries, err := s.repo.Chain(ctx, lastSeq, auditVerifyBatch)
+		if err != nil {
+			log.Error("Не удалось прочитать журнал аудита", slog.String("error", err.Error()))
+			return model.AuditVerification{}, err
+		}
+
+		for _, e := range entries {
+			reason := ""
+			switch {
+			case e.PrevHash != prev:
+				reason = "previous hash mismatch"
+			// Содержимое старой записи с удаленными данными пользователя не проверить,
+			// проверяется только ее место в цепочке.
+			case !e.Verifiable():
+			case e.Hash != e.ComputeHash():
+				reason = "hash mismatch"
+			// Удаленные вместе с данными пользователя части проверяются только хешем записи.
+			case e.IdentityHash != "" && !e.IdentityErased && e.IdentityHash != e.ComputeIdentityHash():
+				reason = "identity hash mismatch"
+			case e.SubjectHash != "" && !e.SubjectErased && e.SubjectHash != e.ComputeSubjectHash():
+				reason = "subject hash mismatch"
+			}
+			if reason != "" {
+				seq := e.Seq
+				result.BrokenAt = &seq
+				result.Reason = reason
+				log.Warn("Цепочка журнала аудита нарушена", slog.Int64("seq", seq), slog.String("reason", reason))
+				return result, nil
+			}
+
+			result.Checked++
+			prev = e.Hash
+			lastSeq = e.Seq
+		}
+
+		if len(entries) < auditVerifyBatch {
+			break
+		}
+	}
+
+	result.Valid = true
+	return result, nil
+}
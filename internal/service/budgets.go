@@ -0,0 +1,382 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+
+	"github.com/vasiliy-maslov/go-subscription-service/internal/logging"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/model"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// BudgetService определяет интерфейс для работы с месячными бюджетами.
+type BudgetService interface {
+	Create(ctx context.Context, b model.Budget) (model.Budget, error)
+	GetByID(ctx context.Context, id uuid.UUID) (model.Budget, error)
+	List(ctx context.Context, userID uuid.UUID) ([]model.Budget, error)
+	Update(ctx context.Context, id uuid.UUID, b model.Budget) (model.Budget, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+	Status(ctx context.Context, userID uuid.UUID) ([]model.BudgetStatus, error)
+	// Evaluate проверяет все бюджеты за текущий месяц и записывает в outbox
+	// оповещения о впервые достигнутых порогах. Бюджеты пользователей, отключивших
+	// оповещения о бюджетах, пропускаются.
+	Evaluate(ctx context.Context) error
+}
+
+type budgetService struct {
+	repo          repository.BudgetRepository
+	catalog       repository.CatalogRepository
+	categories    repository.CategoryRepository
+	users         repository.UserRepository
+	subscriptions SubscriptionService
+	policy        Policy
+	outbox        repository.OutboxRepository
+	tx            repository.TxManager
+	logger        *slog.Logger
+}
+
+// NewBudgetService создает новый экземпляр сервиса бюджетов.
+func NewBudgetService(
+	repo repository.BudgetRepository,
+	catalog repository.CatalogRepository,
+	categories repository.CategoryRepository,
+	users repository.UserRepository,
+	subscriptions SubscriptionService,
+	policy Policy,
+	outbox repository.OutboxRepository,
+	tx repository.TxManager,
+	logger *slog.Logger,
+) BudgetService {
+	return &budgetService{
+		repo:          repo,
+		catalog:       catalog,
+		categories:    categories,
+		users:         users,
+		subscriptions: subscriptions,
+		policy:        policy,
+		outbox:        outbox,
+		tx:            tx,
+		logger:        logger,
+	}
+}
+
+func (s *budgetService) Create(ctx context.Context, b model.Budget) (model.Budget, error) {
+	const op = "budgets.Create"
+	log := logging.FromContext(ctx, s.logger).With(slog.String("op", op), slog.String("user_id", b.UserID.String()))
+
+	log.Info("Создание бюджета")
+
+	if b.UserID == uuid.Nil {
+		return model.Budget{}, fmt.Errorf("%w: user_id is required", ErrValidation)
+	}
+	if err := authorizeUser(ctx, s.policy, b.UserID); err != nil {
+		log.Warn("Действие запрещено политикой доступа", slog.String("error", err.Error()))
+		return model.Budget{}, err
+	}
+
+	owner, err := s.users.GetByID(ctx, b.UserID)
+	if err != nil {
+		log.Warn("Не удалось получить владельца бюджета", slog.String("error", err.Error()))
+		return model.Budget{}, err
+	}
+	if b.Currency == "" {
+		b.Currency = owner.DefaultCurrency
+	}
+
+	if err := s.validate(ctx, b.UserID, &b); err != nil {
+		log.Warn("Бюджет не прошел проверку", slog.String("error", err.Error()))
+		return model.Budget{}, err
+	}
+
+	created, err := s.repo.Create(ctx, b)
+	if err != nil {
+		log.Error("Не удалось создать бюджет", slog.String("error", err.Error()))
+		return model.Budget{}, err
+	}
+
+	log.Info("Бюджет успешно создан", slog.String("budget_id", created.ID.String()))
+	return created, nil
+}
+
+func (s *budgetService) GetByID(ctx context.Context, id uuid.UUID) (model.Budget, error) {
+	const op = "budgets.GetByID"
+	log := logging.FromContext(ctx, s.logger).With(slog.String("op", op), slog.String("budget_id", id.String()))
+
+	b, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		log.Error("Не удалось получить бюджет", slog.String("error", err.Error()))
+		return model.Budget{}, err
+	}
+	if err := authorizeUser(ctx, s.policy, b.UserID); err != nil {
+		log.Warn("Действие запрещено политикой доступа", slog.String("error", err.Error()))
+		return model.Budget{}, err
+	}
+
+	return b, nil
+}
+
+func (s *budgetService) List(ctx context.Context, userID uuid.UUID) ([]model.Budget, error) {
+	const op = "budgets.List"
+	log := logging.FromContext(ctx, s.logger).With(slog.String("op", op), slog.String("user_id", userID.String()))
+
+	if err := authorizeUser(ctx, s.policy, userID); err != nil {
+		log.Warn("Действие запрещено политикой доступа", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	budgets, err := s.repo.ListByUserID(ctx, userID)
+	if err != nil {
+		log.Error("Не удалось получить бюджеты", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return budgets, nil
+}
+
+// Update меняет лимит, область и пороги бюджета. Владелец бюджета не меняется.
+func (s *budgetService) Update(ctx context.Context, id uuid.UUID, b model.Budget) (model.Budget, error) {
+	const op = "budgets.Update"
+	log := logging.FromContext(ctx, s.logger).With(slog.String("op", op), slog.String("budget_id", id.String()))
+
+	log.Info("Обновление бюджета")
+
+	existing, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		log.Error("Не удалось получить бюджет", slog.String("error", err.Error()))
+		return model.Budget{}, err
+	}
+	if err := authorizeUser(ctx, s.policy, existing.UserID); err != nil {
+		log.Warn("Действие запрещено политикой доступа", slog.String("error", err.Error()))
+		return model.Budget{}, err
+	}
+
+	if b.Currency == "" {
+		b.Currency = existing.Currency
+	}
+
+	if err := s.validate(ctx, existing.UserID, &b); err != nil {
+		log.Warn("Бюджет не прошел проверку", slog.String("error", err.Error()))
+		return model.Budget{}, err
+	}
+
+	updated, err := s.repo.Update(ctx, id, b)
+	if err != nil {
+		log.Error("Не удалось обновить бюджет", slog.String("error", err.Error()))
+		return model.Budget{}, err
+	}
+
+	log.Info("Бюджет успешно обновлен")
+	return updated, nil
+}
+
+func (s *budgetService) Delete(ctx context.Context, id uuid.UUID) error {
+	const op = "budgets.Delete"
+	log := logging.FromContext(ctx, s.logger).With(slog.String("op", op), slog.String("budget_id", id.String()))
+
+	existing, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		log.Error("Не удалось получить бюджет", slog.String("error", err.Error()))
+		return err
+	}
+	if err := authorizeUser(ctx, s.policy, existing.UserID); err != nil {
+		log.Warn("Действие запрещено политикой доступа", slog.String("error", err.Error()))
+		return err
+	}
+
+	if err := s.repo.Delete(ctx, id); err != nil {
+		log.Error("Не удалось удалить бюджет", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("Бюджет успешно удален")
+	return nil
+}
+
+// Status возвращает состояние бюджетов пользователя за текущий месяц.
+func (s *budgetService) Status(ctx context.Context, userID uuid.UUID) ([]model.BudgetStatus, error) {
+	const op = "budgets.Status"
+	log := logging.FromContext(ctx, s.logger).With(slog.String("op", op), slog.String("user_id", userID.String()))
+
+	if err := authorizeUser(ctx, s.policy, userID); err != nil {
+		log.Warn("Действие запрещено политикой доступа", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	budgets, err := s.repo.ListByUserID(ctx, userID)
+	if err != nil {
+		log.Error("Не удалось получить бюджеты", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	// Текущий месяц определяется в часовом поясе владельца, если запрос не задал пояс явно.
+	if len(budgets) > 0 {
+		owner, err := s.users.GetByID(ctx, userID)
+		if err != nil {
+			log.Error("Не удалось получить владельца бюджетов", slog.String("error", err.Error()))
+			return nil, err
+		}
+		ctx = withUserTimeZone(ctx, owner)
+	}
+
+	period := today(ctx).StartOfMonth()
+	statuses := make([]model.BudgetStatus, 0, len(budgets))
+	for _, b := range budgets {
+		status, err := s.status(ctx, b, period)
+		if err != nil {
+			log.Error("Не удалось рассчитать расходы по бюджету",
+				slog.String("budget_id", b.ID.String()), slog.String("error", err.Error()))
+			return nil, err
+		}
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+// Evaluate определяет текущий месяц каждого бюджета в часовом поясе его владельца.
+func (s *budgetService) Evaluate(ctx context.Context) error {
+	const op = "budgets.Evaluate"
+	log := logging.FromContext(ctx, s.logger).With(slog.String("op", op))
+
+	budgets, err := s.repo.ListAll(ctx)
+	if err != nil {
+		log.Error("Не удалось получить бюджеты", slog.String("error", err.Error()))
+		return err
+	}
+
+	owners := make(map[uuid.UUID]model.User)
+	var errs []error
+	for _, b := range budgets {
+		owner, ok := owners[b.UserID]
+		if !ok {
+			owner, err = s.users.GetByID(ctx, b.UserID)
+			if err != nil {
+				log.Error("Не удалось получить владельца бюджета",
+					slog.String("budget_id", b.ID.String()), slog.String("error", err.Error()))
+				errs = append(errs, err)
+				continue
+			}
+			owners[b.UserID] = owner
+		}
+		if !owner.Notifications.BudgetAlerts {
+			continue
+		}
+
+		userCtx := withUserTimeZone(ctx, owner)
+		if err := s.evaluate(userCtx, b, today(userCtx).StartOfMonth()); err != nil {
+			log.Error("Не удалось проверить бюджет",
+				slog.String("budget_id", b.ID.String()), slog.String("error", err.Error()))
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// evaluate записывает оповещения о порогах бюджета, достигнутых в месяце period.
+// Отметка об оповещении и событие сохраняются в одной транзакции, поэтому событие
+// по каждому порогу попадает в outbox ровно один раз за месяц.
+func (s *budgetService) evaluate(ctx context.Context, b model.Budget, period model.Date) error {
+	status, err := s.status(ctx, b, period)
+	if err != nil {
+		return err
+	}
+
+	for _, threshold := range status.Crossed {
+		err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+			recorded, err := s.repo.RecordAlert(ctx, b.ID, period, threshold)
+			if err != nil || !recorded {
+				return err
+			}
+
+			s.logger.Info("Достигнут порог бюджета",
+				slog.String("budget_id", b.ID.String()),
+				slog.Int("threshold", threshold),
+				slog.String("spent", status.Spent.String()),
+			)
+
+			return s.outbox.Add(ctx, model.EventBudgetThresholdCrossed, b.ID, model.BudgetAlert{
+				BudgetID:  b.ID,
+				UserID:    b.UserID,
+				Period:    period,
+				Threshold: threshold,
+				Amount:    b.Amount,
+				Spent:     status.Spent,
+				Currency:  b.Currency,
+			})
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// status рассчитывает прогноз расходов по бюджету за месяц period.
+func (s *budgetService) status(ctx context.Context, b model.Budget, period model.Date) (model.BudgetStatus, error) {
+	filter := repository.SubscriptionFilter{
+		UserID:     b.UserID,
+		ServiceID:  b.ServiceID,
+		CategoryID: b.CategoryID,
+		Currency:   &b.Currency,
+	}
+
+	summary, err := s.subscriptions.CalculateTotalCost(ctx, filter, model.CostPeriod{From: period, To: period.EndOfMonth()})
+	if err != nil {
+		return model.BudgetStatus{}, err
+	}
+
+	return model.NewBudgetStatus(b, period, summary.TotalCost)
+}
+
+// validate проверяет лимит и область бюджета пользователя userID и нормализует пороги.
+func (s *budgetService) validate(ctx context.Context, userID uuid.UUID, b *model.Budget) error {
+	if b.Amount.Sign() <= 0 {
+		return fmt.Errorf("%w: amount must be positive", ErrValidation)
+	}
+	if err := normalizeCurrency(&b.Currency); err != nil {
+		return err
+	}
+	if err := normalizeAmount(&b.Amount, b.Currency, "amount"); err != nil {
+		return err
+	}
+
+	if b.CategoryID != nil && b.ServiceID != nil {
+		return fmt.Errorf("%w: budget can be limited to either a category or a service", ErrValidation)
+	}
+
+	if len(b.Thresholds) == 0 {
+		b.Thresholds = slices.Clone(model.DefaultBudgetThresholds)
+	}
+	for _, threshold := range b.Thresholds {
+		if threshold <= 0 || threshold > 1000 {
+			return fmt.Errorf("%w: thresholds must be between 1 and 1000 percent", ErrValidation)
+		}
+	}
+	slices.Sort(b.Thresholds)
+	b.Thresholds = slices.Compact(b.Thresholds)
+
+	if b.CategoryID != nil {
+		categories, err := s.categories.GetCategories(ctx, []uuid.UUID{*b.CategoryID})
+		if err != nil {
+			return err
+		}
+		if len(categories) == 0 || categories[0].UserID != userID {
+			return repository.ErrCategoryNotFound
+		}
+	}
+
+	if b.ServiceID != nil {
+		if _, err := s.catalog.GetByID(ctx, *b.ServiceID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,220 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/vasiliy-maslov/go-subscription-service/internal/logging"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/model"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// CatalogService определяет интерфейс для работы с каталогом сервисов.
+type CatalogService interface {
+	Create(ctx context.Context, svc model.CatalogService) (model.CatalogService, error)
+	GetByID(ctx context.Context, id uuid.UUID) (model.CatalogService, error)
+	List(ctx context.Context, category *string) ([]model.CatalogService, error)
+	Update(ctx context.Context, id uuid.UUID, svc model.CatalogService) (model.CatalogService, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+type catalogService struct {
+	repo   repository.CatalogRepository
+	policy Policy
+	tx     repository.TxManager
+	logger *slog.Logger
+}
+
+// NewCatalogService создает новый экземпляр сервиса каталога.
+func NewCatalogService(repo repository.CatalogRepository, policy Policy, tx repository.TxManager, logger *slog.Logger) CatalogService {
+	return &catalogService{
+		repo:   repo,
+		policy: policy,
+		tx:     tx,
+		logger: logger,
+	}
+}
+
+// Create добавляет сервис в каталог и привязывает к нему существующие подписки с совпадающими названиями.
+// Каталог общий для всех пользователей, поэтому к сервису привязываются подписки всех пользователей,
+// а не только видимые в запросе.
+func (s *catalogService) Create(ctx context.Context, svc model.CatalogService) (model.CatalogService, error) {
+	const op = "catalog.Create"
+	log := logging.FromContext(ctx, s.logger).With(slog.String("op", op), slog.String("name", svc.Name))
+
+	log.Info("Создание сервиса в каталоге")
+
+	ctx, err := s.authorizeManage(ctx)
+	if err != nil {
+		log.Warn("Действие запрещено политикой доступа", slog.String("error", err.Error()))
+		return model.CatalogService{}, err
+	}
+
+	if err := validateCatalogService(&svc); err != nil {
+		return model.CatalogService{}, err
+	}
+
+	var created model.CatalogService
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		id, err := s.repo.Create(ctx, svc)
+		if err != nil {
+			return err
+		}
+
+		linked, err := s.repo.LinkSubscriptions(ctx, id)
+		if err != nil {
+			return err
+		}
+		log.Info("Подписки привязаны к каталогу", slog.Int64("linked", linked))
+
+		created, err = s.repo.GetByID(ctx, id)
+		return err
+	})
+	if err != nil {
+		log.Error("Не удалось создать сервис в каталоге", slog.String("error", err.Error()))
+		return model.CatalogService{}, err
+	}
+
+	log.Info("Сервис успешно добавлен в каталог", slog.String("service_id", created.ID.String()))
+	return created, nil
+}
+
+func (s *catalogService) GetByID(ctx context.Context, id uuid.UUID) (model.CatalogService, error) {
+	const op = "catalog.GetByID"
+	log := logging.FromContext(ctx, s.logger).With(slog.String("op", op), slog.String("service_id", id.String()))
+
+	if err := s.policy.Authorize(ctx, model.PermissionSubscriptionsRead); err != nil {
+		log.Warn("Действие запрещено политикой доступа", slog.String("error", err.Error()))
+		return model.CatalogService{}, err
+	}
+
+	svc, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		log.Error("Не удалось получить сервис из каталога", slog.String("error", err.Error()))
+		return model.CatalogService{}, err
+	}
+
+	return svc, nil
+}
+
+func (s *catalogService) List(ctx context.Context, category *string) ([]model.CatalogService, error) {
+	const op = "catalog.List"
+	log := logging.FromContext(ctx, s.logger).With(slog.String("op", op))
+
+	if err := s.policy.Authorize(ctx, model.PermissionSubscriptionsRead); err != nil {
+		log.Warn("Действие запрещено политикой доступа", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	services, err := s.repo.List(ctx, category)
+	if err != nil {
+		log.Error("Не удалось получить каталог сервисов", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return services, nil
+}
+
+// Update заменяет данные сервиса каталога и привязывает подписки, совпавшие с новыми псевдонимами.
+func (s *catalogService) Update(ctx context.Context, id uuid.UUID, svc model.CatalogService) (model.CatalogService, error) {
+	const op = "catalog.Update"
+	log := logging.FromContext(ctx, s.logger).With(slog.String("op", op), slog.String("service_id", id.String()))
+
+	log.Info("Обновление сервиса в каталоге")
+
+	ctx, err := s.authorizeManage(ctx)
+	if err != nil {
+		log.Warn("Действие запрещено политикой доступа", slog.String("error", err.Error()))
+		return model.CatalogService{}, err
+	}
+
+	if err := validateCatalogService(&svc); err != nil {
+		return model.CatalogService{}, err
+	}
+
+	var updated model.CatalogService
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.Update(ctx, id, svc); err != nil {
+			return err
+		}
+
+		if _, err := s.repo.LinkSubscriptions(ctx, id); err != nil {
+			return err
+		}
+
+		var err error
+		updated, err = s.repo.GetByID(ctx, id)
+		return err
+	})
+	if err != nil {
+		log.Error("Не удалось обновить сервис в каталоге", slog.String("error", err.Error()))
+		return model.CatalogService{}, err
+	}
+
+	log.Info("Сервис каталога успешно обновлен")
+	return updated, nil
+}
+
+func (s *catalogService) Delete(ctx context.Context, id uuid.UUID) error {
+	const op = "catalog.Delete"
+	log := logging.FromContext(ctx, s.logger).With(slog.String("op", op), slog.String("service_id", id.String()))
+
+	log.Info("Удаление сервиса из каталога")
+
+	ctx, err := s.authorizeManage(ctx)
+	if err != nil {
+		log.Warn("Действие запрещено политикой доступа", slog.String("error", err.Error()))
+		return err
+	}
+
+	if err := s.repo.Delete(ctx, id); err != nil {
+		log.Error("Не удалось удалить сервис из каталога", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("Сервис успешно удален из каталога")
+	return nil
+}
+
+// authorizeManage проверяет право на изменение каталога и возвращает контекст без привязки к пользователю:
+// сервис каталога привязывается к подпискам всех пользователей.
+func (s *catalogService) authorizeManage(ctx context.Context) (context.Context, error) {
+	if err := s.policy.Authorize(ctx, model.PermissionCatalogManage); err != nil {
+		return ctx, err
+	}
+	return repository.WithoutTenant(ctx), nil
+}
+
+// validateCatalogService проверяет сервис каталога и приводит цены тарифов к точности их валют.
+func validateCatalogService(svc *model.CatalogService) error {
+	if strings.TrimSpace(svc.Name) == "" {
+		return fmt.Errorf("%w: name is required", ErrValidation)
+	}
+
+	plans := make(map[string]struct{}, len(svc.Plans))
+	for i := range svc.Plans {
+		plan := &svc.Plans[i]
+		if strings.TrimSpace(plan.Name) == "" {
+			return fmt.Errorf("%w: plan name is required", ErrValidation)
+		}
+		if plan.Price.Sign() < 0 {
+			return fmt.Errorf("%w: plan price must not be negative", ErrValidation)
+		}
+		if err := normalizeCurrency(&plan.Currency); err != nil {
+			return err
+		}
+		if err := normalizeAmount(&plan.Price, plan.Currency, "plan price"); err != nil {
+			return err
+		}
+		if _, ok := plans[plan.Name]; ok {
+			return fmt.Errorf("%w: duplicate plan %q", ErrValidation, plan.Name)
+		}
+		plans[plan.Name] = struct{}{}
+	}
+
+	return nil
+}
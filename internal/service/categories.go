@@ -0,0 +1,349 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/vasiliy-maslov/go-subscription-service/internal/logging"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/model"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// CategoryService определяет интерфейс для работы с пользовательскими категориями и метками.
+type CategoryService interface {
+	CreateCategory(ctx context.Context, category model.Category) (model.Category, error)
+	ListCategories(ctx context.Context, userID uuid.UUID) ([]model.Category, error)
+	// ListSubscriptionCategories возвращает категории сразу нескольких подписок.
+	ListSubscriptionCategories(ctx context.Context, subscriptionIDs []uuid.UUID) (map[uuid.UUID][]model.Category, error)
+	RenameCategory(ctx context.Context, id uuid.UUID, name string) (model.Category, error)
+	DeleteCategory(ctx context.Context, id uuid.UUID) error
+	CreateTag(ctx context.Context, tag model.Tag) (model.Tag, error)
+	ListTags(ctx context.Context, userID uuid.UUID) ([]model.Tag, error)
+	RenameTag(ctx context.Context, id uuid.UUID, name string) (model.Tag, error)
+	DeleteTag(ctx context.Context, id uuid.UUID) error
+}
+
+type categoryService struct {
+	repo   repository.CategoryRepository
+	policy Policy
+	logger *slog.Logger
+}
+
+// NewCategoryService создает новый экземпляр сервиса категорий и меток.
+func NewCategoryService(repo repository.CategoryRepository, policy Policy, logger *slog.Logger) CategoryService {
+	return &categoryService{
+		repo:   repo,
+		policy: policy,
+		logger: logger,
+	}
+}
+
+func (s *categoryService) CreateCategory(ctx context.Context, category model.Category) (model.Category, error) {
+	const op = "categories.CreateCategory"
+	log := logging.FromContext(ctx, s.logger).With(slog.String("op", op), slog.String("user_id", category.UserID.String()))
+
+	category.Name = strings.TrimSpace(category.Name)
+	if category.UserID == uuid.Nil || category.Name == "" {
+		return model.Category{}, fmt.Errorf("%w: user_id and name are required", ErrValidation)
+	}
+	if err := authorizeUser(ctx, s.policy, category.UserID); err != nil {
+		log.Warn("Действие запрещено политикой доступа", slog.String("error", err.Error()))
+		return model.Category{}, err
+	}
+
+	created, err := s.repo.CreateCategory(ctx, category)
+	if err != nil {
+		log.Error("Не удалось создать категорию", slog.String("error", err.Error()))
+		return model.Category{}, err
+	}
+
+	log.Info("Категория успешно создана", slog.String("category_id", created.ID.String()))
+	return created, nil
+}
+
+func (s *categoryService) ListCategories(ctx context.Context, userID uuid.UUID) ([]model.Category, error) {
+	const op = "categories.ListCategories"
+	log := logging.FromContext(ctx, s.logger).With(slog.String("op", op), slog.String("user_id", userID.String()))
+
+	if err := authorizeUser(ctx, s.policy, userID); err != nil {
+		log.Warn("Действие запрещено политикой доступа", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	categories, err := s.repo.ListCategories(ctx, userID)
+	if err != nil {
+		log.Error("Не удалось получить категории", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return categories, nil
+}
+
+func (s *categoryService) ListSubscriptionCategories(ctx context.Context, subscriptionIDs []uuid.UUID) (map[uuid.UUID][]model.Category, error) {
+	const op = "categories.ListSubscriptionCategories"
+	log := logging.FromContext(ctx, s.logger).With(slog.String("op", op), slog.Int("subscriptions", len(subscriptionIDs)))
+
+	if err := s.policy.Authorize(ctx, model.PermissionSubscriptionsRead); err != nil {
+		log.Warn("Действие запрещено политикой доступа", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	categories, err := s.repo.ListSubscriptionCategories(ctx, subscriptionIDs)
+	if err != nil {
+		log.Error("Не удалось получить категории подписок", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return categories, nil
+}
+
+func (s *categoryService) RenameCategory(ctx context.Context, id uuid.UUID, name string) (model.Category, error) {
+	const op = "categories.RenameCategory"
+	log := logging.FromContext(ctx, s.logger).With(slog.String("op", op), slog.String("category_id", id.String()))
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return model.Category{}, fmt.Errorf("%w: name is required", ErrValidation)
+	}
+	if err := s.authorizeCategory(ctx, id); err != nil {
+		log.Warn("Действие запрещено политикой доступа", slog.String("error", err.Error()))
+		return model.Category{}, err
+	}
+
+	updated, err := s.repo.RenameCategory(ctx, id, name)
+	if err != nil {
+		log.Error("Не удалось переименовать категорию", slog.String("error", err.Error()))
+		return model.Category{}, err
+	}
+
+	log.Info("Категория успешно переименована")
+	return updated, nil
+}
+
+func (s *categoryService) DeleteCategory(ctx context.Context, id uuid.UUID) error {
+	const op = "categories.DeleteCategory"
+	log := logging.FromContext(ctx, s.logger).With(slog.String("op", op), slog.String("category_id", id.String()))
+
+	if err := s.authorizeCategory(ctx, id); err != nil {
+		log.Warn("Действие запрещено политикой доступа", slog.String("error", err.Error()))
+		return err
+	}
+
+	if err := s.repo.DeleteCategory(ctx, id); err != nil {
+		log.Error("Не удалось удалить категорию", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("Категория успешно удалена")
+	return nil
+}
+
+func (s *categoryService) CreateTag(ctx context.Context, tag model.Tag) (model.Tag, error) {
+	const op = "categories.CreateTag"
+	log := logging.FromContext(ctx, s.logger).With(slog.String("op", op), slog.String("user_id", tag.UserID.String()))
+
+	tag.Name = strings.TrimSpace(tag.Name)
+	if tag.UserID == uuid.Nil || tag.Name == "" {
+		return model.Tag{}, fmt.Errorf("%w: user_id and name are required", ErrValidation)
+	}
+	if err := authorizeUser(ctx, s.policy, tag.UserID); err != nil {
+		log.Warn("Действие запрещено политикой доступа", slog.String("error", err.Error()))
+		return model.Tag{}, err
+	}
+
+	created, err := s.repo.CreateTag(ctx, tag)
+	if err != nil {
+		log.Error("Не удалось создать метку", slog.String("error", err.Error()))
+		return model.Tag{}, err
+	}
+
+	log.Info("Метка успешно создана", slog.String("tag_id", created.ID.String()))
+	return created, nil
+}
+
+func (s *categoryService) ListTags(ctx context.Context, userID uuid.UUID) ([]model.Tag, error) {
+	const op = "categories.ListTags"
+	log := logging.FromContext(ctx, s.logger).With(slog.String("op", op), slog.String("user_id", userID.String()))
+
+	if err := authorizeUser(ctx, s.policy, userID); err != nil {
+		log.Warn("Действие запрещено политикой доступа", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	tags, err := s.repo.ListTags(ctx, userID)
+	if err != nil {
+		log.Error("Не удалось получить метки", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+func (s *categoryService) RenameTag(ctx context.Context, id uuid.UUID, name string) (model.Tag, error) {
+	const op = "categories.RenameTag"
+	log := logging.FromContext(ctx, s.logger).With(slog.String("op", op), slog.String("tag_id", id.String()))
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return model.Tag{}, fmt.Errorf("%w: name is required", ErrValidation)
+	}
+	if err := s.authorizeTag(ctx, id); err != nil {
+		log.Warn("Действие запрещено политикой доступа", slog.String("error", err.Error()))
+		return model.Tag{}, err
+	}
+
+	updated, err := s.repo.RenameTag(ctx, id, name)
+	if err != nil {
+		log.Error("Не удалось переименовать метку", slog.String("error", err.Error()))
+		return model.Tag{}, err
+	}
+
+	log.Info("Метка успешно переименована")
+	return updated, nil
+}
+
+func (s *categoryService) DeleteTag(ctx context.Context, id uuid.UUID) error {
+	const op = "categories.DeleteTag"
+	log := logging.FromContext(ctx, s.logger).With(slog.String("op", op), slog.String("tag_id", id.String()))
+
+	if err := s.authorizeTag(ctx, id); err != nil {
+		log.Warn("Действие запрещено политикой доступа", slog.String("error", err.Error()))
+		return err
+	}
+
+	if err := s.repo.DeleteTag(ctx, id); err != nil {
+		log.Error("Не удалось удалить метку", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("Метка успешно удалена")
+	return nil
+}
+
+// authorizeCategory проверяет право на изменение категории id ее владельцем или с правом users:manage.
+func (s *categoryService) authorizeCategory(ctx context.Context, id uuid.UUID) error {
+	categories, err := s.repo.GetCategories(ctx, []uuid.UUID{id})
+	if err != nil {
+		return err
+	}
+	if len(categories) == 0 {
+		return repository.ErrCategoryNotFound
+	}
+	return authorizeUser(ctx, s.policy, categories[0].UserID)
+}
+
+// authorizeTag проверяет право на изменение метки id ее владельцем или с правом users:manage.
+func (s *categoryService) authorizeTag(ctx context.Context, id uuid.UUID) error {
+	tag, err := s.repo.GetTag(ctx, id)
+	if err != nil {
+		return err
+	}
+	return authorizeUser(ctx, s.policy, tag.UserID)
+}
+
+// classify заменяет категории и метки подписки. Поле со значением nil оставляет текущий набор без изменений.
+// Категории должны принадлежать владельцу подписки, отсутствующие метки создаются.
+func (s *subscriptionService) classify(ctx context.Context, id, ownerID uuid.UUID, sub model.Subscription) error {
+	if sub.CategoryIDs != nil {
+		categories, err := s.categories.GetCategories(ctx, sub.CategoryIDs)
+		if err != nil {
+			return err
+		}
+
+		owned := make(map[uuid.UUID]struct{}, len(categories))
+		for _, category := range categories {
+			if category.UserID == ownerID {
+				owned[category.ID] = struct{}{}
+			}
+		}
+		for _, categoryID := range sub.CategoryIDs {
+			if _, ok := owned[categoryID]; !ok {
+				return fmt.Errorf("%w: unknown category %s", ErrValidation, categoryID)
+			}
+		}
+
+		if err := s.categories.SetSubscriptionCategories(ctx, id, sub.CategoryIDs); err != nil {
+			return err
+		}
+	}
+
+	if sub.Tags != nil {
+		seen := make(map[string]struct{}, len(sub.Tags))
+		tags := make([]string, 0, len(sub.Tags))
+		for _, tag := range sub.Tags {
+			tag = strings.TrimSpace(tag)
+			key := strings.ToLower(tag)
+			if _, ok := seen[key]; ok || tag == "" {
+				continue
+			}
+			seen[key] = struct{}{}
+			tags = append(tags, tag)
+		}
+
+		if err := s.categories.SetSubscriptionTags(ctx, id, ownerID, tags); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// enrich заполняет категории, метки и фактический статус подписок.
+func (s *subscriptionService) enrich(ctx context.Context, subscriptions []model.Subscription) error {
+	ids := make([]uuid.UUID, 0, len(subscriptions))
+	for _, sub := range subscriptions {
+		ids = append(ids, sub.ID)
+	}
+
+	categories, err := s.categories.ListSubscriptionCategories(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	tags, err := s.categories.ListSubscriptionTags(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	day := today(ctx)
+	for i := range subscriptions {
+		sub := &subscriptions[i]
+		sub.Status = sub.StatusAt(day)
+
+		sub.CategoryIDs = make([]uuid.UUID, 0, len(categories[sub.ID]))
+		for _, category := range categories[sub.ID] {
+			sub.CategoryIDs = append(sub.CategoryIDs, category.ID)
+		}
+
+		sub.Tags = make([]string, 0, len(tags[sub.ID]))
+		for _, tag := range tags[sub.ID] {
+			sub.Tags = append(sub.Tags, tag.Name)
+		}
+	}
+
+	return nil
+}
+
+// categoryNames возвращает названия категорий, встречающихся у подписок.
+func (s *subscriptionService) categoryNames(ctx context.Context, subscriptions []model.Subscription) (map[uuid.UUID]string, error) {
+	var ids []uuid.UUID
+	for _, sub := range subscriptions {
+		ids = append(ids, sub.CategoryIDs...)
+	}
+
+	categories, err := s.categories.GetCategories(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[uuid.UUID]string, len(categories))
+	for _, category := range categories {
+		names[category.ID] = category.Name
+	}
+
+	return names, nil
+}
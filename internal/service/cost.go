@@ -0,0 +1,379 @@
+package service
+
+import (
+	"sort"
+	"time"
+
+	"github.com/vasiliy-maslov/go-subscription-service/internal/model"
+
+	"github.com/google/uuid"
+)
+
+// dateRange - закрытый диапазон дат [from, to].
+type dateRange struct {
+	from model.Date
+	to   model.Date
+}
+
+func (r dateRange) empty() bool {
+	return r.from.After(r.to)
+}
+
+// days возвращает число дней в диапазоне.
+func (r dateRange) days() int {
+	if r.empty() {
+		return 0
+	}
+	return r.to.Sub(r.from) + 1
+}
+
+// subtract вычитает из диапазона отрезок cut и возвращает оставшиеся части.
+func (r dateRange) subtract(cut dateRange) []dateRange {
+	if cut.empty() || cut.to.Before(r.from) || cut.from.After(r.to) {
+		return []dateRange{r}
+	}
+
+	var rest []dateRange
+	if cut.from.After(r.from) {
+		rest = append(rest, dateRange{from: r.from, to: cut.from.AddDate(0, 0, -1)})
+	}
+	if cut.to.Before(r.to) {
+		rest = append(rest, dateRange{from: cut.to.AddDate(0, 0, 1), to: r.to})
+	}
+	return rest
+}
+
+// costInputs - история статусов, участники, изменения цен и скидки набора подписок, нужные для расчета стоимости.
+type costInputs struct {
+	transitions map[uuid.UUID][]model.StatusTransition
+	members     map[uuid.UUID][]model.SubscriptionMember
+	prices      map[uuid.UUID][]model.PriceChange
+	discounts   map[uuid.UUID][]model.Discount
+}
+
+// charge - списание за подписку в одном оплачиваемом периоде.
+type charge struct {
+	month     model.Date
+	basePrice model.Money
+	price     model.Money
+	discount  *model.Discount
+	amount    model.Money
+	// days - оплачиваемые дни периода, попавшие в расчет, periodDays - длина периода.
+	// Заполняются только при посуточном расчете.
+	days       int
+	periodDays int
+}
+
+// billingPeriod - период, за который списывается оплата: календарный месяц для ежемесячных подписок,
+// год от годовщины начала для годовых. month - месяц списания.
+type billingPeriod struct {
+	dateRange
+	month model.Date
+}
+
+// charges возвращает списания за подписку в периоде period, приходящиеся на пользователя userID.
+// Годовые подписки оплачиваются только в месяцы продления, цена берется с учетом ее изменений и скидок.
+// При посуточном расчете цена периода умножается на долю его оплачиваемых дней, попавших в period.
+func charges(sub model.Subscription, inputs costInputs, userID uuid.UUID, period model.CostPeriod) ([]charge, error) {
+	// Списания считаются с начала подписки, чтобы знать номер оплачиваемого периода для скидок.
+	ranges := billableRanges(sub, inputs.transitions[sub.ID], sub.StartDate, period.To)
+	daily := period.Proration == model.ProrationDaily
+	firstMonth := period.From.StartOfMonth()
+
+	var result []charge
+	for i, p := range billingPeriods(sub, ranges, daily) {
+		cycle := i + 1
+
+		c := charge{month: p.month}
+		if daily {
+			c.days = coveredDays(ranges, dateRange{from: maxDate(p.from, period.From), to: p.to})
+			c.periodDays = p.days()
+			if c.days == 0 {
+				continue
+			}
+		} else if p.month.Before(firstMonth) {
+			continue
+		}
+
+		var err error
+		c.basePrice = priceAt(sub, inputs.prices[sub.ID], p.month)
+		c.price, c.discount, err = bestDiscount(inputs.discounts[sub.ID], c.basePrice, p.month, cycle)
+		if err != nil {
+			return nil, err
+		}
+
+		if daily && c.days < c.periodDays {
+			if c.price, err = c.price.MulDivRound(int64(c.days), int64(c.periodDays)); err != nil {
+				return nil, err
+			}
+		}
+
+		priced := sub
+		priced.Price = c.price
+		c.amount, err = userShare(priced, inputs.members[sub.ID], userID, p.month)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, c)
+	}
+	return result, nil
+}
+
+// billingPeriods возвращает оплачиваемые периоды подписки по возрастанию.
+// Ежемесячная подписка оплачивается за каждый месяц с хотя бы одним оплачиваемым днем.
+// Годовая при помесячном расчете оплачивается, если оплачивается месяц продления,
+// а при посуточном - за каждый год с хотя бы одним оплачиваемым днем.
+func billingPeriods(sub model.Subscription, ranges []dateRange, daily bool) []billingPeriod {
+	var periods []billingPeriod
+
+	if sub.BillingPeriod != model.BillingYearly {
+		for _, month := range billableMonths(ranges) {
+			periods = append(periods, billingPeriod{dateRange: dateRange{from: month, to: month.EndOfMonth()}, month: month})
+		}
+		return periods
+	}
+
+	if !daily {
+		for _, month := range billableMonths(ranges) {
+			if sub.ChargedIn(month) {
+				periods = append(periods, billingPeriod{dateRange: dateRange{from: month, to: month.AddDate(1, 0, -1)}, month: month})
+			}
+		}
+		return periods
+	}
+
+	if len(ranges) == 0 {
+		return nil
+	}
+	last := ranges[len(ranges)-1].to
+	for n := 0; !anniversary(sub.StartDate, n).After(last); n++ {
+		year := dateRange{from: anniversary(sub.StartDate, n), to: anniversary(sub.StartDate, n+1).AddDate(0, 0, -1)}
+		if coveredDays(ranges, year) > 0 {
+			periods = append(periods, billingPeriod{dateRange: year, month: year.from.StartOfMonth()})
+		}
+	}
+	return periods
+}
+
+// anniversary возвращает n-ю годовщину даты start. Годовщина 29 февраля
+// в невисокосный год приходится на 28 февраля.
+func anniversary(start model.Date, n int) model.Date {
+	year := start.Year() + n
+	day := min(start.Day(), model.NewDate(year, start.Month(), 1).EndOfMonth().Day())
+	return model.NewDate(year, start.Month(), day)
+}
+
+// coveredDays возвращает число дней отрезков ranges, попадающих в диапазон r.
+func coveredDays(ranges []dateRange, r dateRange) int {
+	days := 0
+	for _, rng := range ranges {
+		days += dateRange{from: maxDate(rng.from, r.from), to: minDate(rng.to, r.to)}.days()
+	}
+	return days
+}
+
+// bestDiscount применяет к цене самую выгодную из скидок, действующих для cycle-го списания в месяце month.
+// Скидки не суммируются.
+func bestDiscount(discounts []model.Discount, price model.Money, month model.Date, cycle int) (model.Money, *model.Discount, error) {
+	best, applied := price, (*model.Discount)(nil)
+	for i := range discounts {
+		if !discounts[i].ActiveIn(month, cycle) {
+			continue
+		}
+		discounted, err := discounts[i].Apply(price)
+		if err != nil {
+			return model.Money{}, nil, err
+		}
+		if discounted.Cmp(best) < 0 {
+			best, applied = discounted, &discounts[i]
+		}
+	}
+	return best, applied, nil
+}
+
+// priceAt возвращает цену подписки в месяце month. Price подписки действует до первого
+// изменения цены; изменения упорядочены по дате и применяются с месяца вступления в силу.
+func priceAt(sub model.Subscription, changes []model.PriceChange, month model.Date) model.Money {
+	price := sub.Price
+	for _, change := range changes {
+		if change.EffectiveDate.StartOfMonth().After(month) {
+			break
+		}
+		price = change.Price
+	}
+	return price
+}
+
+// sumCharges складывает суммы списаний.
+func sumCharges(total model.Money, list []charge) (model.Money, error) {
+	for _, c := range list {
+		var err error
+		total, err = total.Add(c.amount)
+		if err != nil {
+			return model.Money{}, err
+		}
+	}
+	return total, nil
+}
+
+// billableRanges возвращает отрезки периода [startPeriod, endPeriod], за которые подписка оплачивается.
+// Пробный период и паузы из истории статусов исключаются.
+func billableRanges(sub model.Subscription, transitions []model.StatusTransition, startPeriod, endPeriod model.Date) []dateRange {
+	// Определяем фактический конец подписки. Если его нет, считаем, что она активна до конца нашего периода.
+	subEnd := endPeriod
+	if sub.EndDate != nil && sub.EndDate.Before(endPeriod) {
+		subEnd = *sub.EndDate
+	}
+
+	// Находим период пересечения [sub.StartDate, subEnd] и [startPeriod, endPeriod]
+	overlap := dateRange{from: maxDate(sub.StartDate, startPeriod), to: minDate(subEnd, endPeriod)}
+	if overlap.empty() {
+		return nil
+	}
+
+	cuts := pausedRanges(transitions)
+	if sub.TrialEndDate != nil {
+		cuts = append(cuts, dateRange{from: sub.StartDate, to: sub.TrialEndDate.AddDate(0, 0, -1)})
+	}
+
+	ranges := []dateRange{overlap}
+	for _, cut := range cuts {
+		var next []dateRange
+		for _, r := range ranges {
+			next = append(next, r.subtract(cut)...)
+		}
+		ranges = next
+	}
+
+	return ranges
+}
+
+// pausedRanges восстанавливает периоды паузы по упорядоченной истории статусов.
+// День возобновления уже оплачивается, незавершенная пауза длится бессрочно.
+func pausedRanges(transitions []model.StatusTransition) []dateRange {
+	var (
+		ranges      []dateRange
+		pausedSince *model.Date
+	)
+
+	for _, t := range transitions {
+		switch {
+		case t.ToStatus == model.StatusPaused && pausedSince == nil:
+			since := t.EffectiveDate
+			pausedSince = &since
+		case t.FromStatus == model.StatusPaused && pausedSince != nil:
+			ranges = append(ranges, dateRange{from: *pausedSince, to: t.EffectiveDate.AddDate(0, 0, -1)})
+			pausedSince = nil
+		}
+	}
+
+	if pausedSince != nil {
+		ranges = append(ranges, dateRange{from: *pausedSince, to: model.NewDate(9999, time.December, 31)})
+	}
+
+	return ranges
+}
+
+// billableMonths возвращает первые дни календарных месяцев, в которые попадает хотя бы один день
+// из отрезков, в порядке возрастания. Неполный месяц оплачивается целиком.
+func billableMonths(ranges []dateRange) []model.Date {
+	seen := make(map[model.Date]struct{})
+	var months []model.Date
+	for _, r := range ranges {
+		currentMonth := r.from.StartOfMonth()
+		for !currentMonth.After(r.to) {
+			if _, ok := seen[currentMonth]; !ok {
+				seen[currentMonth] = struct{}{}
+				months = append(months, currentMonth)
+			}
+			currentMonth = currentMonth.AddDate(0, 1, 0)
+		}
+	}
+	sort.Slice(months, func(i, j int) bool { return months[i].Before(months[j]) })
+	return months
+}
+
+// userShare возвращает часть цены подписки за месяц month, приходящуюся на пользователя userID.
+// Участники с фиксированной суммой платят ее (но не больше остатка цены), остаток делится
+// между владельцем и участниками с весами пропорционально весу. Доли участников округляются
+// вниз до минимальных единиц валюты, владелец, не указанный среди участников явно, имеет вес 1
+// и получает остаток от деления, поэтому сумма долей всегда равна цене.
+func userShare(sub model.Subscription, members []model.SubscriptionMember, userID uuid.UUID, month model.Date) (model.Money, error) {
+	monthEnd := month.EndOfMonth()
+
+	remaining := sub.Price
+	ownerWeight := 1
+	totalWeight := 0
+	var weighted []model.SubscriptionMember
+	fixed := make(map[uuid.UUID]model.Money)
+
+	for _, m := range members {
+		if !m.ActiveBetween(month, monthEnd) {
+			continue
+		}
+		switch m.ShareType {
+		case model.ShareFixed:
+			amount := model.MinMoney(m.Amount, remaining)
+			var err error
+			if fixed[m.UserID], err = fixed[m.UserID].Add(amount); err != nil {
+				return model.Money{}, err
+			}
+			if remaining, err = remaining.Sub(amount); err != nil {
+				return model.Money{}, err
+			}
+		case model.ShareWeight:
+			if m.UserID == sub.UserID {
+				ownerWeight = m.Weight
+				continue
+			}
+			weighted = append(weighted, m)
+			totalWeight += m.Weight
+		}
+	}
+	totalWeight += ownerWeight
+
+	own := fixed[userID]
+	distributed := model.Money{}
+	if totalWeight > 0 {
+		for _, m := range weighted {
+			part, err := remaining.MulDiv(int64(m.Weight), int64(totalWeight))
+			if err != nil {
+				return model.Money{}, err
+			}
+			if distributed, err = distributed.Add(part); err != nil {
+				return model.Money{}, err
+			}
+			if m.UserID == userID {
+				if own, err = own.Add(part); err != nil {
+					return model.Money{}, err
+				}
+			}
+		}
+	}
+
+	if userID != sub.UserID {
+		return own, nil
+	}
+
+	ownerPart, err := remaining.Sub(distributed)
+	if err != nil {
+		return model.Money{}, err
+	}
+	return own.Add(ownerPart)
+}
+
+// Вспомогательные функции для работы с датами
+func maxDate(a, b model.Date) model.Date {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+func minDate(a, b model.Date) model.Date {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,349 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/vasiliy-maslov/go-subscription-service/internal/model"
+
+	"github.com/google/uuid"
+)
+
+func date(year int, month time.Month, day int) model.Date {
+	return model.NewDate(year, month, day)
+}
+
+func money(t *testing.T, s string) model.Money {
+	t.Helper()
+	m, err := model.ParseMoney(s)
+	if err != nil {
+		t.Fatalf("ParseMoney(%q) error = %v", s, err)
+	}
+	return m
+}
+
+// pause возвращает историю статусов подписки, начатой start и приостановленной с from по to включительно.
+// Без to пауза не завершена.
+func pause(start, from model.Date, to *model.Date) []model.StatusTransition {
+	transitions := []model.StatusTransition{
+		{ToStatus: model.StatusActive, EffectiveDate: start},
+		{FromStatus: model.StatusActive, ToStatus: model.StatusPaused, EffectiveDate: from},
+	}
+	if to != nil {
+		transitions = append(transitions, model.StatusTransition{
+			FromStatus: model.StatusPaused, ToStatus: model.StatusActive, EffectiveDate: to.AddDate(0, 0, 1),
+		})
+	}
+	return transitions
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestCharges(t *testing.T) {
+	owner := uuid.New()
+
+	tests := []struct {
+		name        string
+		price       string
+		billing     model.BillingPeriod
+		start       model.Date
+		end         *model.Date
+		transitions func(start model.Date) []model.StatusTransition
+		period      model.CostPeriod
+		// want - суммы списаний по месяцам списания (первый день месяца в формате 2006-01-02).
+		want map[string]string
+	}{
+		{
+			name:   "last day of a month and first day of the next are two full months",
+			price:  "10.00",
+			start:  date(2024, time.January, 31),
+			end:    ptr(date(2024, time.February, 1)),
+			period: model.CostPeriod{From: date(2024, time.January, 1), To: date(2024, time.December, 31), Proration: model.ProrationMonthly},
+			want:   map[string]string{"2024-01-01": "10.00", "2024-02-01": "10.00"},
+		},
+		{
+			name:   "daily proration of a partial first and last month",
+			price:  "31.00",
+			start:  date(2024, time.January, 15),
+			end:    ptr(date(2024, time.March, 10)),
+			period: model.CostPeriod{From: date(2024, time.January, 1), To: date(2024, time.December, 31), Proration: model.ProrationDaily},
+			want:   map[string]string{"2024-01-01": "17.00", "2024-02-01": "31.00", "2024-03-01": "10.00"},
+		},
+		{
+			name:   "february of a leap year has 29 days",
+			price:  "29.00",
+			start:  date(2024, time.February, 15),
+			period: model.CostPeriod{From: date(2024, time.February, 1), To: date(2024, time.February, 29), Proration: model.ProrationDaily},
+			want:   map[string]string{"2024-02-01": "15.00"},
+		},
+		{
+			name:   "february of a common year has 28 days",
+			price:  "28.00",
+			start:  date(2023, time.February, 15),
+			period: model.CostPeriod{From: date(2023, time.February, 1), To: date(2023, time.February, 28), Proration: model.ProrationDaily},
+			want:   map[string]string{"2023-02-01": "14.00"},
+		},
+		{
+			name:   "period starting mid-month counts only its days",
+			price:  "30.00",
+			start:  date(2024, time.January, 1),
+			period: model.CostPeriod{From: date(2024, time.April, 21), To: date(2024, time.April, 30), Proration: model.ProrationDaily},
+			want:   map[string]string{"2024-04-01": "10.00"},
+		},
+		{
+			name:    "yearly subscription started on 29 february renews on 28 february",
+			price:   "120.00",
+			billing: model.BillingYearly,
+			start:   date(2024, time.February, 29),
+			period:  model.CostPeriod{From: date(2025, time.January, 1), To: date(2025, time.December, 31), Proration: model.ProrationMonthly},
+			want:    map[string]string{"2025-02-01": "120.00"},
+		},
+		{
+			name:    "yearly subscription ended early is prorated by days of its year",
+			price:   "365.00",
+			billing: model.BillingYearly,
+			start:   date(2025, time.March, 1),
+			end:     ptr(date(2025, time.March, 10)),
+			period:  model.CostPeriod{From: date(2025, time.January, 1), To: date(2025, time.December, 31), Proration: model.ProrationDaily},
+			want:    map[string]string{"2025-03-01": "10.00"},
+		},
+		{
+			name:  "fully paused month is not charged, partially paused month is charged in full",
+			price: "10.00",
+			start: date(2024, time.January, 1),
+			transitions: func(start model.Date) []model.StatusTransition {
+				return pause(start, date(2024, time.February, 10), ptr(date(2024, time.March, 31)))
+			},
+			period: model.CostPeriod{From: date(2024, time.January, 1), To: date(2024, time.April, 30), Proration: model.ProrationMonthly},
+			want:   map[string]string{"2024-01-01": "10.00", "2024-02-01": "10.00", "2024-04-01": "10.00"},
+		},
+		{
+			name:  "paused days are excluded with daily proration and the resume day is charged",
+			price: "29.00",
+			start: date(2024, time.January, 1),
+			transitions: func(start model.Date) []model.StatusTransition {
+				return pause(start, date(2024, time.February, 10), ptr(date(2024, time.March, 30)))
+			},
+			period: model.CostPeriod{From: date(2024, time.February, 1), To: date(2024, time.March, 31), Proration: model.ProrationDaily},
+			want:   map[string]string{"2024-02-01": "9.00", "2024-03-01": "0.94"},
+		},
+		{
+			name:  "unfinished pause stops charges",
+			price: "10.00",
+			start: date(2024, time.January, 1),
+			transitions: func(start model.Date) []model.StatusTransition {
+				return pause(start, date(2024, time.March, 1), nil)
+			},
+			period: model.CostPeriod{From: date(2024, time.January, 1), To: date(2024, time.June, 30), Proration: model.ProrationMonthly},
+			want:   map[string]string{"2024-01-01": "10.00", "2024-02-01": "10.00"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			billing := tt.billing
+			if billing == "" {
+				billing = model.BillingMonthly
+			}
+			sub := model.Subscription{
+				ID:            uuid.New(),
+				UserID:        owner,
+				Price:         money(t, tt.price),
+				Currency:      model.DefaultCurrency,
+				BillingPeriod: billing,
+				StartDate:     tt.start,
+				EndDate:       tt.end,
+				Status:        model.StatusActive,
+			}
+			inputs := costInputs{transitions: map[uuid.UUID][]model.StatusTransition{}}
+			if tt.transitions != nil {
+				inputs.transitions[sub.ID] = tt.transitions(tt.start)
+			}
+
+			got, err := charges(sub, inputs, owner, tt.period)
+			if err != nil {
+				t.Fatalf("charges() error = %v", err)
+			}
+
+			amounts := make(map[string]string, len(got))
+			for _, c := range got {
+				amounts[c.month.String()] = c.amount.String()
+			}
+			if len(amounts) != len(tt.want) {
+				t.Fatalf("charges() = %v, want %v", amounts, tt.want)
+			}
+			for month, want := range tt.want {
+				if amounts[month] != want {
+					t.Errorf("charge for %s = %q, want %q (all: %v)", month, amounts[month], want, amounts)
+				}
+			}
+		})
+	}
+}
+
+func TestAnniversary(t *testing.T) {
+	tests := []struct {
+		start model.Date
+		n     int
+		want  model.Date
+	}{
+		{start: date(2024, time.March, 15), n: 1, want: date(2025, time.March, 15)},
+		{start: date(2024, time.February, 29), n: 1, want: date(2025, time.February, 28)},
+		{start: date(2024, time.February, 29), n: 4, want: date(2028, time.February, 29)},
+		{start: date(2023, time.February, 28), n: 1, want: date(2024, time.February, 28)},
+	}
+
+	for _, tt := range tests {
+		if got := anniversary(tt.start, tt.n); !got.Equal(tt.want) {
+			t.Errorf("anniversary(%s, %d) = %s, want %s", tt.start, tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestUserShare(t *testing.T) {
+	owner, first, second := uuid.New(), uuid.New(), uuid.New()
+	month := date(2024, time.March, 1)
+
+	weighted := func(userID uuid.UUID, weight int) model.SubscriptionMember {
+		return model.SubscriptionMember{UserID: userID, ShareType: model.ShareWeight, Weight: weight, JoinedAt: date(2024, time.January, 1)}
+	}
+	fixed := func(userID uuid.UUID, amount model.Money) model.SubscriptionMember {
+		return model.SubscriptionMember{UserID: userID, ShareType: model.ShareFixed, Amount: amount, JoinedAt: date(2024, time.January, 1)}
+	}
+
+	tests := []struct {
+		name    string
+		price   string
+		members func(t *testing.T) []model.SubscriptionMember
+		want    map[uuid.UUID]string
+	}{
+		{
+			name:    "owner without members pays everything",
+			price:   "10.00",
+			members: func(*testing.T) []model.SubscriptionMember { return nil },
+			want:    map[uuid.UUID]string{owner: "10.00", first: "0"},
+		},
+		{
+			name:  "weights are rounded down and the owner gets the remainder",
+			price: "10.00",
+			members: func(*testing.T) []model.SubscriptionMember {
+				return []model.SubscriptionMember{weighted(first, 1), weighted(second, 1)}
+			},
+			want: map[uuid.UUID]string{owner: "3.34", first: "3.33", second: "3.33"},
+		},
+		{
+			name:  "explicit owner weight replaces the default",
+			price: "9.00",
+			members: func(*testing.T) []model.SubscriptionMember {
+				return []model.SubscriptionMember{weighted(owner, 2), weighted(first, 1)}
+			},
+			want: map[uuid.UUID]string{owner: "6.00", first: "3.00"},
+		},
+		{
+			name:  "fixed amounts are paid first and the rest is split by weight",
+			price: "10.00",
+			members: func(t *testing.T) []model.SubscriptionMember {
+				return []model.SubscriptionMember{fixed(first, money(t, "3.00")), weighted(second, 1)}
+			},
+			want: map[uuid.UUID]string{owner: "3.50", first: "3.00", second: "3.50"},
+		},
+		{
+			name:  "fixed amount is capped by the price",
+			price: "10.00",
+			members: func(t *testing.T) []model.SubscriptionMember {
+				return []model.SubscriptionMember{fixed(first, money(t, "15.00"))}
+			},
+			want: map[uuid.UUID]string{owner: "0.00", first: "10.00"},
+		},
+		{
+			name:  "member who joined during the month shares it, member who left before does not",
+			price: "10.00",
+			members: func(*testing.T) []model.SubscriptionMember {
+				joined := weighted(first, 1)
+				joined.JoinedAt = date(2024, time.March, 31)
+				left := weighted(second, 1)
+				left.LeftAt = ptr(date(2024, time.February, 29))
+				return []model.SubscriptionMember{joined, left}
+			},
+			want: map[uuid.UUID]string{owner: "5.00", first: "5.00", second: "0"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sub := model.Subscription{UserID: owner, Price: money(t, tt.price)}
+			members := tt.members(t)
+
+			for userID, want := range tt.want {
+				got, err := userShare(sub, members, userID, month)
+				if err != nil {
+					t.Fatalf("userShare() error = %v", err)
+				}
+				if got.String() != want {
+					t.Errorf("userShare(%s) = %q, want %q", userID, got.String(), want)
+				}
+			}
+		})
+	}
+}
+
+func TestNormalizeCostPeriod(t *testing.T) {
+	moment := func(s string) model.Date {
+		d, err := model.ParseDateOrMoment(s)
+		if err != nil {
+			t.Fatalf("ParseDateOrMoment(%q) error = %v", s, err)
+		}
+		return d
+	}
+
+	tests := []struct {
+		name     string
+		zone     string
+		period   model.CostPeriod
+		from, to string
+	}{
+		{
+			name:   "calendar months are expanded without the zone",
+			zone:   "Asia/Vladivostok",
+			period: model.CostPeriod{From: date(2024, time.January, 1), To: date(2024, time.February, 1)},
+			from:   "2024-01-01", to: "2024-02-29",
+		},
+		{
+			name:   "month boundaries are taken in the user zone",
+			zone:   "Asia/Vladivostok",
+			period: model.CostPeriod{From: moment("2024-01-31T15:00:00Z"), To: moment("2024-03-31T13:59:59Z")},
+			from:   "2024-02-01", to: "2024-03-31",
+		},
+		{
+			name:   "spring forward does not shift the day",
+			zone:   "America/New_York",
+			period: model.CostPeriod{From: moment("2024-03-10T05:00:00Z"), To: moment("2024-03-11T03:59:59Z"), Proration: model.ProrationDaily},
+			from:   "2024-03-10", to: "2024-03-10",
+		},
+		{
+			name:   "fall back does not shift the day",
+			zone:   "America/New_York",
+			period: model.CostPeriod{From: moment("2024-11-03T04:00:00Z"), To: moment("2024-11-04T04:59:59Z"), Proration: model.ProrationDaily},
+			from:   "2024-11-03", to: "2024-11-03",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loc, err := time.LoadLocation(tt.zone)
+			if err != nil {
+				t.Fatalf("LoadLocation(%q) error = %v", tt.zone, err)
+			}
+			period := tt.period
+			if err := normalizeCostPeriod(WithTimeZone(context.Background(), loc), &period); err != nil {
+				t.Fatalf("normalizeCostPeriod() error = %v", err)
+			}
+			if period.From.String() != tt.from || period.To.String() != tt.to {
+				t.Errorf("period = %s..%s, want %s..%s", period.From, period.To, tt.from, tt.to)
+			}
+		})
+	}
+}
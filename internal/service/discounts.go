@@ -0,0 +1,254 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+
+	"github.com/vasiliy-maslov/go-subscription-service/internal/logging"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/model"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// AddDiscount добавляет подписке скидку, промо-период или купон.
+func (s *subscriptionService) AddDiscount(ctx context.Context, subscriptionID uuid.UUID, d model.Discount) (model.Discount, error) {
+	const op = "service.AddDiscount"
+	log := logging.FromContext(ctx, s.logger).With(
+		slog.String("op", op),
+		slog.String("subscription_id", subscriptionID.String()),
+	)
+
+	if err := s.policy.Authorize(ctx, model.PermissionSubscriptionsWrite); err != nil {
+		log.Warn("Действие запрещено политикой доступа", slog.String("error", err.Error()))
+		return model.Discount{}, err
+	}
+
+	log.Info("Добавление скидки подписки")
+
+	ctx, err := s.inSubscriptionZone(ctx, subscriptionID)
+	if err != nil {
+		log.Error("Не удалось определить часовой пояс владельца подписки", slog.String("error", err.Error()))
+		return model.Discount{}, err
+	}
+	resolveDates(ctx, d.StartDate, d.EndDate)
+	if err := validateDiscount(&d); err != nil {
+		log.Warn("Скидка не прошла проверку", slog.String("error", err.Error()))
+		return model.Discount{}, err
+	}
+
+	var created model.Discount
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		sub, err := s.repo.GetByIDForUpdate(ctx, subscriptionID)
+		if err != nil {
+			return err
+		}
+
+		if d.Kind == model.DiscountFixed {
+			if err := normalizeAmount(&d.Value, sub.Currency, "value"); err != nil {
+				return err
+			}
+		}
+
+		d.SubscriptionID = subscriptionID
+		created, err = s.repo.AddDiscount(ctx, d)
+		if err != nil {
+			return err
+		}
+
+		updated, err := s.load(ctx, subscriptionID)
+		if err != nil {
+			return err
+		}
+
+		return s.outbox.Add(ctx, model.EventSubscriptionUpdated, subscriptionID, updated)
+	})
+	if err != nil {
+		log.Error("Не удалось добавить скидку", slog.String("error", err.Error()))
+		return model.Discount{}, err
+	}
+
+	log.Info("Скидка успешно добавлена", slog.String("discount_id", created.ID.String()))
+	return created, nil
+}
+
+// DeleteDiscount удаляет скидку подписки.
+func (s *subscriptionService) DeleteDiscount(ctx context.Context, subscriptionID, id uuid.UUID) error {
+	const op = "service.DeleteDiscount"
+	log := logging.FromContext(ctx, s.logger).With(
+		slog.String("op", op),
+		slog.String("subscription_id", subscriptionID.String()),
+		slog.String("discount_id", id.String()),
+	)
+
+	if err := s.policy.Authorize(ctx, model.PermissionSubscriptionsWrite); err != nil {
+		log.Warn("Действие запрещено политикой доступа", slog.String("error", err.Error()))
+		return err
+	}
+
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.DeleteDiscount(ctx, subscriptionID, id); err != nil {
+			return err
+		}
+
+		updated, err := s.load(ctx, subscriptionID)
+		if err != nil {
+			return err
+		}
+
+		return s.outbox.Add(ctx, model.EventSubscriptionUpdated, subscriptionID, updated)
+	})
+	if err != nil {
+		log.Error("Не удалось удалить скидку", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("Скидка удалена")
+	return nil
+}
+
+// ListDiscounts возвращает скидки подписки.
+func (s *subscriptionService) ListDiscounts(ctx context.Context, subscriptionID uuid.UUID) ([]model.Discount, error) {
+	const op = "service.ListDiscounts"
+	log := logging.FromContext(ctx, s.logger).With(
+		slog.String("op", op),
+		slog.String("subscription_id", subscriptionID.String()),
+	)
+
+	if err := s.policy.Authorize(ctx, model.PermissionSubscriptionsRead); err != nil {
+		log.Warn("Действие запрещено политикой доступа", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if _, err := s.repo.GetByID(ctx, subscriptionID); err != nil {
+		log.Error("Не удалось получить подписку", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	discounts, err := s.repo.ListDiscounts(ctx, []uuid.UUID{subscriptionID})
+	if err != nil {
+		log.Error("Не удалось получить скидки", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	result := discounts[subscriptionID]
+	if result == nil {
+		result = []model.Discount{}
+	}
+	return result, nil
+}
+
+// CalculateCostLineItems вычисляет суммарную стоимость подписок за период вместе
+// с помесячными списаниями, поясняющими примененные скидки.
+func (s *subscriptionService) CalculateCostLineItems(ctx context.Context, filter repository.SubscriptionFilter, period model.CostPeriod) (model.CostSummary, error) {
+	const op = "service.CalculateCostLineItems"
+	log := logging.FromContext(ctx, s.logger).With(
+		slog.String("op", op),
+		slog.String("user_id", filter.UserID.String()),
+	)
+
+	ctx, err := s.authorizeReport(ctx, filter)
+	if err != nil {
+		log.Warn("Отчет запрещен политикой доступа", slog.String("error", err.Error()))
+		return model.CostSummary{}, err
+	}
+
+	log.Info("Начат расчет стоимости по списаниям")
+
+	if ctx, err = s.inOwnerZone(ctx, filter.UserID); err != nil {
+		log.Error("Не удалось определить часовой пояс пользователя", slog.String("error", err.Error()))
+		return model.CostSummary{}, err
+	}
+	if err := normalizeCostPeriod(ctx, &period); err != nil {
+		log.Warn("Период расчета не прошел проверку", slog.String("error", err.Error()))
+		return model.CostSummary{}, err
+	}
+
+	filter, err = s.resolveFilter(ctx, filter)
+	if err != nil {
+		log.Error("Не удалось разобрать фильтр по сервису", slog.String("error", err.Error()))
+		return model.CostSummary{}, err
+	}
+
+	subscriptions, err := s.repo.List(ctx, filter)
+	if err != nil {
+		log.Error("Не удалось получить список подписок", slog.String("error", err.Error()))
+		return model.CostSummary{}, err
+	}
+
+	summary := model.CostSummary{LineItems: []model.CostLineItem{}}
+	summary.Currency, err = commonCurrency(subscriptions)
+	if err != nil {
+		log.Warn("Подписки в разных валютах", slog.String("error", err.Error()))
+		return model.CostSummary{}, err
+	}
+
+	inputs, err := s.loadCostInputs(ctx, subscriptions)
+	if err != nil {
+		log.Error("Не удалось получить данные для расчета стоимости", slog.String("error", err.Error()))
+		return model.CostSummary{}, err
+	}
+
+	for _, sub := range subscriptions {
+		list, err := charges(sub, inputs, payer(filter, sub), period)
+		if err != nil {
+			log.Error("Не удалось рассчитать списания", slog.String("error", err.Error()))
+			return model.CostSummary{}, err
+		}
+		if summary.TotalCost, err = sumCharges(summary.TotalCost, list); err != nil {
+			log.Error("Не удалось рассчитать списания", slog.String("error", err.Error()))
+			return model.CostSummary{}, err
+		}
+
+		for _, c := range list {
+			summary.LineItems = append(summary.LineItems, model.CostLineItem{
+				Month:          c.month,
+				SubscriptionID: sub.ID,
+				ServiceName:    sub.ServiceName,
+				BasePrice:      c.basePrice,
+				Price:          c.price,
+				Discount:       c.discount,
+				Days:           c.days,
+				PeriodDays:     c.periodDays,
+				Cost:           c.amount,
+			})
+		}
+	}
+
+	items := summary.LineItems
+	sort.SliceStable(items, func(i, j int) bool { return items[i].Month.Before(items[j].Month) })
+
+	log.Info("Расчет успешно завершен", slog.String("total_cost", summary.TotalCost.String()))
+	return summary, nil
+}
+
+// validateDiscount проверяет скидку и приводит процент к целому числу.
+// Фиксированная сумма приводится к точности валюты подписки при добавлении.
+func validateDiscount(d *model.Discount) error {
+	switch d.Kind {
+	case model.DiscountPercent:
+		percent, err := d.Value.Rescale(0)
+		if err != nil || percent.Sign() <= 0 || percent.Minor() > 100 {
+			return fmt.Errorf("%w: percent discount value must be an integer between 1 and 100", ErrValidation)
+		}
+		d.Value = percent
+	case model.DiscountFixed:
+		if d.Value.Sign() <= 0 {
+			return fmt.Errorf("%w: fixed discount value must be positive", ErrValidation)
+		}
+	default:
+		return fmt.Errorf("%w: kind must be percent or fixed", ErrValidation)
+	}
+
+	if d.Cycles != nil && *d.Cycles <= 0 {
+		return fmt.Errorf("%w: cycles must be positive", ErrValidation)
+	}
+
+	if d.StartDate != nil && d.EndDate != nil && d.EndDate.Before(*d.StartDate) {
+		return fmt.Errorf("%w: end_date must not be before start_date", ErrValidation)
+	}
+
+	return nil
+}
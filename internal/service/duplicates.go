@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+
+	"github.com/vasiliy-maslov/go-subscription-service/internal/logging"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/model"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// ErrDuplicateSubscription возвращается в строгом режиме, если период подписки пересекается
+// с другой подпиской пользователя на тот же сервис.
+var ErrDuplicateSubscription = errors.New("overlapping subscription to the same service exists")
+
+// FindDuplicates находит пересекающиеся подписки пользователя на один и тот же сервис.
+func (s *subscriptionService) FindDuplicates(ctx context.Context, userID uuid.UUID) ([]model.SubscriptionOverlap, error) {
+	const op = "service.FindDuplicates"
+	log := logging.FromContext(ctx, s.logger).With(
+		slog.String("op", op),
+		slog.String("user_id", userID.String()),
+	)
+
+	ctx, err := s.authorizeReport(ctx, repository.SubscriptionFilter{UserID: userID})
+	if err != nil {
+		log.Warn("Отчет запрещен политикой доступа", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	subscriptions, err := s.ownedSubscriptions(ctx, userID)
+	if err != nil {
+		log.Error("Не удалось получить подписки пользователя", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	overlaps := findOverlaps(subscriptions)
+
+	log.Info("Поиск дубликатов завершен", slog.Int("count", len(overlaps)))
+	return overlaps, nil
+}
+
+// checkDuplicates ищет подписки пользователя, пересекающиеся с sub. В строгом режиме
+// пересечение запрещено, иначе о нем только пишется предупреждение в лог.
+// Вызывается внутри транзакции создания или обновления подписки.
+func (s *subscriptionService) checkDuplicates(ctx context.Context, log *slog.Logger, sub model.Subscription) error {
+	if s.options.StrictDuplicates {
+		// Блокировка не дает двум параллельным запросам создать одинаковые подписки.
+		if err := s.repo.LockUser(ctx, sub.UserID); err != nil {
+			return err
+		}
+	}
+
+	others, err := s.ownedSubscriptions(ctx, sub.UserID)
+	if err != nil {
+		return err
+	}
+
+	for _, other := range others {
+		if other.ID == sub.ID {
+			continue
+		}
+
+		overlap, ok := overlapOf(sub, other)
+		if !ok {
+			continue
+		}
+
+		if s.options.StrictDuplicates {
+			return fmt.Errorf("%w: %s", ErrDuplicateSubscription, other.ID)
+		}
+
+		log.Warn("Подписка пересекается с другой подпиской на тот же сервис",
+			slog.String("other_id", other.ID.String()),
+			slog.String("overlap_start", overlap.OverlapStart.String()),
+		)
+	}
+
+	return nil
+}
+
+// ownedSubscriptions возвращает подписки, владельцем которых является пользователь.
+func (s *subscriptionService) ownedSubscriptions(ctx context.Context, userID uuid.UUID) ([]model.Subscription, error) {
+	subscriptions, err := s.repo.List(ctx, repository.SubscriptionFilter{UserID: userID})
+	if err != nil {
+		return nil, err
+	}
+
+	owned := subscriptions[:0]
+	for _, sub := range subscriptions {
+		if sub.UserID == userID {
+			owned = append(owned, sub)
+		}
+	}
+	return owned, nil
+}
+
+// findOverlaps возвращает все пары пересекающихся подписок на один сервис.
+func findOverlaps(subscriptions []model.Subscription) []model.SubscriptionOverlap {
+	groups := make(map[string][]model.Subscription)
+	for _, sub := range subscriptions {
+		key := model.NormalizeServiceName(sub.ServiceName)
+		groups[key] = append(groups[key], sub)
+	}
+
+	overlaps := []model.SubscriptionOverlap{}
+	for _, group := range groups {
+		for i := 0; i < len(group); i++ {
+			for j := i + 1; j < len(group); j++ {
+				if overlap, ok := overlapOf(group[i], group[j]); ok {
+					overlaps = append(overlaps, overlap)
+				}
+			}
+		}
+	}
+
+	sort.Slice(overlaps, func(i, j int) bool {
+		if overlaps[i].ServiceName != overlaps[j].ServiceName {
+			return overlaps[i].ServiceName < overlaps[j].ServiceName
+		}
+		return overlaps[i].OverlapStart.Before(overlaps[j].OverlapStart)
+	})
+
+	return overlaps
+}
+
+// overlapOf проверяет, пересекаются ли периоды двух подписок на один сервис, и предлагает исправление:
+// подписку, начавшуюся в тот же день и созданную позже, предлагается удалить, иначе - завершить более раннюю накануне начала более поздней.
+func overlapOf(a, b model.Subscription) (model.SubscriptionOverlap, bool) {
+	if model.NormalizeServiceName(a.ServiceName) != model.NormalizeServiceName(b.ServiceName) {
+		return model.SubscriptionOverlap{}, false
+	}
+
+	if b.StartDate.Before(a.StartDate) || (b.StartDate.Equal(a.StartDate) && b.CreatedAt.Before(a.CreatedAt)) {
+		a, b = b, a
+	}
+
+	if a.EndDate != nil && a.EndDate.Before(b.StartDate) {
+		return model.SubscriptionOverlap{}, false
+	}
+
+	overlap := model.SubscriptionOverlap{
+		ServiceName:  a.ServiceName,
+		First:        a,
+		Second:       b,
+		OverlapStart: b.StartDate,
+	}
+
+	switch {
+	case a.EndDate == nil:
+		overlap.OverlapEnd = b.EndDate
+	case b.EndDate == nil:
+		overlap.OverlapEnd = a.EndDate
+	default:
+		end := minDate(*a.EndDate, *b.EndDate)
+		overlap.OverlapEnd = &end
+	}
+
+	if a.StartDate.Equal(b.StartDate) {
+		overlap.Suggestion = model.MergeSuggestion{Action: model.MergeDelete, SubscriptionID: b.ID}
+	} else {
+		end := b.StartDate.AddDate(0, 0, -1)
+		overlap.Suggestion = model.MergeSuggestion{Action: model.MergeSetEndDate, SubscriptionID: a.ID, EndDate: &end}
+	}
+
+	return overlap, true
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/vasiliy-maslov/go-subscription-service/internal/logging"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/model"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/repository"
+)
+
+// maxForecastMonths ограничивает горизонт прогноза.
+const maxForecastMonths = 60
+
+// Forecast прогнозирует расходы пользователя на months месяцев вперед, начиная с текущего.
+// Учитываются периодичность оплаты, запланированные изменения цен и отмены. Подписки на паузе
+// не оплачиваются, пока не будут возобновлены.
+func (s *subscriptionService) Forecast(ctx context.Context, filter repository.SubscriptionFilter, months int) (model.Forecast, error) {
+	const op = "service.Forecast"
+	log := logging.FromContext(ctx, s.logger).With(
+		slog.String("op", op),
+		slog.String("user_id", filter.UserID.String()),
+		slog.Int("months", months),
+	)
+
+	ctx, err := s.authorizeReport(ctx, filter)
+	if err != nil {
+		log.Warn("Отчет запрещен политикой доступа", slog.String("error", err.Error()))
+		return model.Forecast{}, err
+	}
+
+	log.Info("Построение прогноза расходов")
+
+	if months < 1 || months > maxForecastMonths {
+		return model.Forecast{}, fmt.Errorf("%w: months must be between 1 and %d", ErrValidation, maxForecastMonths)
+	}
+
+	filter, err = s.resolveFilter(ctx, filter)
+	if err != nil {
+		log.Error("Не удалось разобрать фильтр по сервису", slog.String("error", err.Error()))
+		return model.Forecast{}, err
+	}
+
+	subscriptions, err := s.repo.List(ctx, filter)
+	if err != nil {
+		log.Error("Не удалось получить список подписок", slog.String("error", err.Error()))
+		return model.Forecast{}, err
+	}
+
+	currency, err := commonCurrency(subscriptions)
+	if err != nil {
+		log.Warn("Подписки в разных валютах", slog.String("error", err.Error()))
+		return model.Forecast{}, err
+	}
+
+	inputs, err := s.loadCostInputs(ctx, subscriptions)
+	if err != nil {
+		log.Error("Не удалось получить данные для расчета стоимости", slog.String("error", err.Error()))
+		return model.Forecast{}, err
+	}
+
+	if ctx, err = s.inOwnerZone(ctx, filter.UserID); err != nil {
+		log.Error("Не удалось определить часовой пояс пользователя", slog.String("error", err.Error()))
+		return model.Forecast{}, err
+	}
+	start := today(ctx).StartOfMonth()
+	period := model.CostPeriod{From: start, To: start.AddDate(0, months-1, 0).EndOfMonth(), Proration: model.ProrationMonthly}
+
+	forecast := model.Forecast{Months: make([]model.ForecastMonth, months), Currency: currency}
+	index := make(map[model.Date]int, months)
+	for i := range forecast.Months {
+		month := start.AddDate(0, i, 0)
+		forecast.Months[i] = model.ForecastMonth{Month: month, Renewals: []model.ForecastRenewal{}}
+		index[month] = i
+	}
+
+	for _, sub := range subscriptions {
+		list, err := charges(sub, inputs, payer(filter, sub), period)
+		if err != nil {
+			log.Error("Не удалось рассчитать списания", slog.String("error", err.Error()))
+			return model.Forecast{}, err
+		}
+
+		for _, c := range list {
+			m := &forecast.Months[index[c.month]]
+			if m.TotalCost, err = m.TotalCost.Add(c.amount); err != nil {
+				log.Error("Не удалось рассчитать списания", slog.String("error", err.Error()))
+				return model.Forecast{}, err
+			}
+			if forecast.TotalCost, err = forecast.TotalCost.Add(c.amount); err != nil {
+				log.Error("Не удалось рассчитать списания", slog.String("error", err.Error()))
+				return model.Forecast{}, err
+			}
+
+			if sub.BillingPeriod == model.BillingYearly {
+				m.Renewals = append(m.Renewals, model.ForecastRenewal{
+					SubscriptionID: sub.ID,
+					ServiceName:    sub.ServiceName,
+					Cost:           c.amount,
+				})
+			}
+		}
+	}
+
+	log.Info("Прогноз расходов построен", slog.String("total_cost", forecast.TotalCost.String()))
+	return forecast, nil
+}
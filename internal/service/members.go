@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/vasiliy-maslov/go-subscription-service/internal/logging"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/model"
+
+	"github.com/google/uuid"
+)
+
+// AddMember добавляет участника в совместную подписку.
+func (s *subscriptionService) AddMember(ctx context.Context, subscriptionID uuid.UUID, m model.SubscriptionMember) (model.SubscriptionMember, error) {
+	const op = "service.AddMember"
+	log := logging.FromContext(ctx, s.logger).With(
+		slog.String("op", op),
+		slog.String("subscription_id", subscriptionID.String()),
+		slog.String("user_id", m.UserID.String()),
+	)
+
+	if err := s.policy.Authorize(ctx, model.PermissionSubscriptionsWrite); err != nil {
+		log.Warn("Действие запрещено политикой доступа", slog.String("error", err.Error()))
+		return model.SubscriptionMember{}, err
+	}
+
+	log.Info("Добавление участника подписки")
+
+	ctx, err := s.inSubscriptionZone(ctx, subscriptionID)
+	if err != nil {
+		log.Error("Не удалось определить часовой пояс владельца подписки", slog.String("error", err.Error()))
+		return model.SubscriptionMember{}, err
+	}
+	resolveDates(ctx, &m.JoinedAt, m.LeftAt)
+
+	var created model.SubscriptionMember
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		sub, err := s.repo.GetByIDForUpdate(ctx, subscriptionID)
+		if err != nil {
+			return err
+		}
+
+		if err := validateMember(sub, &m); err != nil {
+			return err
+		}
+
+		m.SubscriptionID = subscriptionID
+		if m.JoinedAt.IsZero() {
+			m.JoinedAt = today(ctx)
+		}
+
+		created, err = s.repo.AddMember(ctx, m)
+		if err != nil {
+			return err
+		}
+
+		return s.outbox.Add(ctx, model.EventMemberAdded, subscriptionID, created)
+	})
+	if err != nil {
+		log.Error("Не удалось добавить участника подписки", slog.String("error", err.Error()))
+		return model.SubscriptionMember{}, err
+	}
+
+	log.Info("Участник подписки успешно добавлен")
+	return created, nil
+}
+
+// RemoveMember завершает участие пользователя в подписке. Участник оплачивает месяц выхода целиком.
+func (s *subscriptionService) RemoveMember(ctx context.Context, subscriptionID, userID uuid.UUID, leftAt model.Date) error {
+	const op = "service.RemoveMember"
+	log := logging.FromContext(ctx, s.logger).With(
+		slog.String("op", op),
+		slog.String("subscription_id", subscriptionID.String()),
+		slog.String("user_id", userID.String()),
+	)
+
+	if err := s.policy.Authorize(ctx, model.PermissionSubscriptionsWrite); err != nil {
+		log.Warn("Действие запрещено политикой доступа", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("Удаление участника подписки")
+
+	ctx, err := s.inSubscriptionZone(ctx, subscriptionID)
+	if err != nil {
+		log.Error("Не удалось определить часовой пояс владельца подписки", slog.String("error", err.Error()))
+		return err
+	}
+	if leftAt.IsZero() {
+		leftAt = today(ctx)
+	}
+	resolveDates(ctx, &leftAt)
+
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.RemoveMember(ctx, subscriptionID, userID, leftAt); err != nil {
+			return err
+		}
+
+		payload := map[string]any{"user_id": userID, "left_at": leftAt}
+		return s.outbox.Add(ctx, model.EventMemberRemoved, subscriptionID, payload)
+	})
+	if err != nil {
+		log.Error("Не удалось удалить участника подписки", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("Участник подписки успешно удален")
+	return nil
+}
+
+// ListMembers возвращает текущих и бывших участников подписки.
+func (s *subscriptionService) ListMembers(ctx context.Context, subscriptionID uuid.UUID) ([]model.SubscriptionMember, error) {
+	const op = "service.ListMembers"
+	log := logging.FromContext(ctx, s.logger).With(
+		slog.String("op", op),
+		slog.String("subscription_id", subscriptionID.String()),
+	)
+
+	if err := s.policy.Authorize(ctx, model.PermissionSubscriptionsRead); err != nil {
+		log.Warn("Действие запрещено политикой доступа", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if _, err := s.repo.GetByID(ctx, subscriptionID); err != nil {
+		log.Error("Не удалось получить подписку", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	members, err := s.repo.ListMembers(ctx, []uuid.UUID{subscriptionID})
+	if err != nil {
+		log.Error("Не удалось получить участников подписки", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	result := members[subscriptionID]
+	if result == nil {
+		result = []model.SubscriptionMember{}
+	}
+	return result, nil
+}
+
+// validateMember проверяет долю участника и приводит фиксированную сумму к точности валюты подписки.
+func validateMember(sub model.Subscription, m *model.SubscriptionMember) error {
+	if m.UserID == uuid.Nil {
+		return fmt.Errorf("%w: user_id is required", ErrValidation)
+	}
+
+	switch m.ShareType {
+	case model.ShareWeight:
+		if m.Weight <= 0 && m.UserID != sub.UserID {
+			return fmt.Errorf("%w: weight must be positive", ErrValidation)
+		}
+	case model.ShareFixed:
+		if m.UserID == sub.UserID {
+			return fmt.Errorf("%w: owner pays the remainder and cannot have a fixed share", ErrValidation)
+		}
+		if m.Amount.Sign() <= 0 || m.Amount.Cmp(sub.Price) > 0 {
+			return fmt.Errorf("%w: amount must be positive and not exceed the subscription price", ErrValidation)
+		}
+		if err := normalizeAmount(&m.Amount, sub.Currency, "amount"); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("%w: share_type must be weight or fixed", ErrValidation)
+	}
+
+	if m.LeftAt != nil && m.LeftAt.Before(m.JoinedAt) {
+		return fmt.Errorf("%w: left_at must not be before joined_at", ErrValidation)
+	}
+
+	return nil
+}
@@ -0,0 +1,49 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/vasiliy-maslov/go-subscription-service/internal/model"
+)
+
+// normalizeCurrency подставляет валюту по умолчанию, если она не указана, и проверяет код.
+func normalizeCurrency(currency *model.Currency) error {
+	if *currency == "" {
+		*currency = model.DefaultCurrency
+	}
+	if !currency.Valid() {
+		return fmt.Errorf("%w: currency must be an ISO 4217 code", ErrValidation)
+	}
+	return nil
+}
+
+// normalizeAmount приводит сумму field к числу знаков после запятой валюты currency.
+// Суммы с большей точностью, чем допускает валюта, отклоняются.
+func normalizeAmount(amount *model.Money, currency model.Currency, field string) error {
+	normalized, err := amount.Rescale(currency.Exponent())
+	if err != nil {
+		if errors.Is(err, model.ErrMoneyPrecision) {
+			return fmt.Errorf("%w: %s has too many decimal places for %s", ErrValidation, field, currency)
+		}
+		if errors.Is(err, model.ErrMoneyOverflow) {
+			return fmt.Errorf("%w: %s is too large", ErrValidation, field)
+		}
+		return err
+	}
+	*amount = normalized
+	return nil
+}
+
+// commonCurrency возвращает валюту подписок. Суммы в разных валютах не складываются,
+// поэтому для подписок в нескольких валютах возвращается ошибка валидации.
+func commonCurrency(subscriptions []model.Subscription) (model.Currency, error) {
+	var currency model.Currency
+	for _, sub := range subscriptions {
+		if currency != "" && sub.Currency != currency {
+			return "", fmt.Errorf("%w: subscriptions use different currencies, filter by currency", ErrValidation)
+		}
+		currency = sub.Currency
+	}
+	return currency, nil
+}
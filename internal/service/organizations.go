@@ -0,0 +1,475 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/vasiliy-maslov/go-subscription-service/internal/logging"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/model"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// ErrOrganizationForbidden возвращается, когда роли пользователя в организации недостаточно для действия.
+var ErrOrganizationForbidden = errors.New("insufficient organization role")
+
+// OrganizationService определяет интерфейс для работы с организациями и их участниками.
+// Если контекст привязан к пользователю (repository.WithTenant), действия проверяются по его роли:
+// читать может любой участник, менять профиль и состав - администратор или владелец,
+// удалять организацию и назначать владельцев - только владелец. Для посторонних организация не существует.
+// API-ключу и другому участнику без пользователя нужно право organizations:manage, а ключу с user_ids -
+// еще и участие в организации кого-то из его пользователей и эти пользователи в качестве владельца
+// новой организации и изменяемого участника.
+type OrganizationService interface {
+	// Create создает организацию, владельцем которой становится пользователь ownerID.
+	Create(ctx context.Context, org model.Organization, ownerID uuid.UUID) (model.Organization, error)
+	GetByID(ctx context.Context, id uuid.UUID) (model.Organization, error)
+	ListForUser(ctx context.Context, userID uuid.UUID) ([]model.Organization, error)
+	Update(ctx context.Context, id uuid.UUID, org model.Organization) (model.Organization, error)
+	// Delete удаляет организацию вместе с ее рабочими подписками.
+	Delete(ctx context.Context, id uuid.UUID) error
+	ListMembers(ctx context.Context, organizationID uuid.UUID) ([]model.OrganizationMember, error)
+	// SetMember добавляет участника или меняет его роль. В организации всегда остается хотя бы один владелец.
+	SetMember(ctx context.Context, m model.OrganizationMember) (model.OrganizationMember, error)
+	// RemoveMember исключает участника. Участник может выйти из организации сам.
+	RemoveMember(ctx context.Context, organizationID, userID uuid.UUID) error
+}
+
+type organizationService struct {
+	repo          repository.OrganizationRepository
+	subscriptions repository.SubscriptionRepository
+	policy        Policy
+	outbox        repository.OutboxRepository
+	tx            repository.TxManager
+	logger        *slog.Logger
+}
+
+// NewOrganizationService создает новый экземпляр сервиса организаций.
+func NewOrganizationService(
+	repo repository.OrganizationRepository,
+	subscriptions repository.SubscriptionRepository,
+	policy Policy,
+	outbox repository.OutboxRepository,
+	tx repository.TxManager,
+	logger *slog.Logger,
+) OrganizationService {
+	return &organizationService{
+		repo:          repo,
+		subscriptions: subscriptions,
+		policy:        policy,
+		outbox:        outbox,
+		tx:            tx,
+		logger:        logger,
+	}
+}
+
+func (s *organizationService) Create(ctx context.Context, org model.Organization, ownerID uuid.UUID) (model.Organization, error) {
+	const op = "organizations.Create"
+	log := logging.FromContext(ctx, s.logger).With(slog.String("op", op), slog.String("owner_id", ownerID.String()))
+
+	log.Info("Создание организации")
+
+	if ownerID == uuid.Nil {
+		return model.Organization{}, fmt.Errorf("%w: owner_id is required", ErrValidation)
+	}
+	if err := s.authorizeUser(ctx, ownerID); err != nil {
+		log.Warn("Действие запрещено политикой доступа", slog.String("error", err.Error()))
+		return model.Organization{}, err
+	}
+	if err := validateOrganization(&org); err != nil {
+		log.Warn("Организация не прошла проверку", slog.String("error", err.Error()))
+		return model.Organization{}, err
+	}
+
+	var created model.Organization
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		created, err = s.repo.Create(ctx, org)
+		if err != nil {
+			return err
+		}
+
+		_, err = s.repo.SetMember(ctx, model.OrganizationMember{
+			OrganizationID: created.ID,
+			UserID:         ownerID,
+			Role:           model.OrganizationRoleOwner,
+		})
+		if err != nil {
+			return err
+		}
+
+		return s.outbox.Add(ctx, model.EventOrganizationCreated, created.ID, created)
+	})
+	if err != nil {
+		log.Error("Не удалось создать организацию", slog.String("error", err.Error()))
+		return model.Organization{}, err
+	}
+
+	log.Info("Организация успешно создана", slog.String("organization_id", created.ID.String()))
+	return created, nil
+}
+
+func (s *organizationService) GetByID(ctx context.Context, id uuid.UUID) (model.Organization, error) {
+	const op = "organizations.GetByID"
+	log := logging.FromContext(ctx, s.logger).With(slog.String("op", op), slog.String("organization_id", id.String()))
+
+	if _, err := s.authorize(ctx, id, nil); err != nil {
+		log.Warn("Нет доступа к организации", slog.String("error", err.Error()))
+		return model.Organization{}, err
+	}
+
+	org, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		log.Error("Не удалось получить организацию", slog.String("error", err.Error()))
+		return model.Organization{}, err
+	}
+
+	return org, nil
+}
+
+func (s *organizationService) ListForUser(ctx context.Context, userID uuid.UUID) ([]model.Organization, error) {
+	const op = "organizations.ListForUser"
+	log := logging.FromContext(ctx, s.logger).With(slog.String("op", op), slog.String("user_id", userID.String()))
+
+	if err := s.authorizeUser(ctx, userID); err != nil {
+		log.Warn("Действие запрещено политикой доступа", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	organizations, err := s.repo.ListByUserID(ctx, userID)
+	if err != nil {
+		log.Error("Не удалось получить организации", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return organizations, nil
+}
+
+func (s *organizationService) Update(ctx context.Context, id uuid.UUID, org model.Organization) (model.Organization, error) {
+	const op = "organizations.Update"
+	log := logging.FromContext(ctx, s.logger).With(slog.String("op", op), slog.String("organization_id", id.String()))
+
+	log.Info("Обновление организации")
+
+	if err := validateOrganization(&org); err != nil {
+		log.Warn("Организация не прошла проверку", slog.String("error", err.Error()))
+		return model.Organization{}, err
+	}
+
+	var updated model.Organization
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if _, err := s.authorize(ctx, id, model.OrganizationRole.CanManage); err != nil {
+			return err
+		}
+
+		var err error
+		updated, err = s.repo.Update(ctx, id, org)
+		if err != nil {
+			return err
+		}
+
+		return s.outbox.Add(ctx, model.EventOrganizationUpdated, id, updated)
+	})
+	if err != nil {
+		log.Error("Не удалось обновить организацию", slog.String("error", err.Error()))
+		return model.Organization{}, err
+	}
+
+	log.Info("Организация успешно обновлена")
+	return updated, nil
+}
+
+// Delete удаляет рабочие подписки организации с событием subscription.deleted по каждой из них.
+func (s *organizationService) Delete(ctx context.Context, id uuid.UUID) error {
+	const op = "organizations.Delete"
+	log := logging.FromContext(ctx, s.logger).With(slog.String("op", op), slog.String("organization_id", id.String()))
+
+	log.Info("Удаление организации")
+
+	deletedSubscriptions := 0
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		deletedSubscriptions = 0
+
+		if _, err := s.authorize(ctx, id, isOwner); err != nil {
+			return err
+		}
+
+		org, err := s.repo.GetByIDForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		subscriptions, err := s.subscriptions.List(ctx, repository.SubscriptionFilter{OrganizationID: &id})
+		if err != nil {
+			return err
+		}
+
+		for _, sub := range subscriptions {
+			if err := s.subscriptions.Delete(ctx, sub.ID); err != nil {
+				return err
+			}
+			if err := s.outbox.Add(ctx, model.EventSubscriptionDeleted, sub.ID, sub); err != nil {
+				return err
+			}
+			deletedSubscriptions++
+		}
+
+		if err := s.repo.Delete(ctx, id); err != nil {
+			return err
+		}
+
+		return s.outbox.Add(ctx, model.EventOrganizationDeleted, id, org)
+	})
+	if err != nil {
+		log.Error("Не удалось удалить организацию", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("Организация удалена", slog.Int("subscriptions", deletedSubscriptions))
+	return nil
+}
+
+func (s *organizationService) ListMembers(ctx context.Context, organizationID uuid.UUID) ([]model.OrganizationMember, error) {
+	const op = "organizations.ListMembers"
+	log := logging.FromContext(ctx, s.logger).With(slog.String("op", op), slog.String("organization_id", organizationID.String()))
+
+	if _, err := s.authorize(ctx, organizationID, nil); err != nil {
+		log.Warn("Нет доступа к организации", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if _, err := s.repo.GetByID(ctx, organizationID); err != nil {
+		log.Error("Не удалось получить организацию", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	members, err := s.repo.ListMembers(ctx, organizationID)
+	if err != nil {
+		log.Error("Не удалось получить участников организации", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return members, nil
+}
+
+// SetMember блокирует строку организации, чтобы параллельные изменения ролей не оставили ее без владельца.
+func (s *organizationService) SetMember(ctx context.Context, m model.OrganizationMember) (model.OrganizationMember, error) {
+	const op = "organizations.SetMember"
+	log := logging.FromContext(ctx, s.logger).With(
+		slog.String("op", op),
+		slog.String("organization_id", m.OrganizationID.String()),
+		slog.String("user_id", m.UserID.String()),
+	)
+
+	log.Info("Изменение участника организации", slog.String("role", string(m.Role)))
+
+	if m.UserID == uuid.Nil {
+		return model.OrganizationMember{}, fmt.Errorf("%w: user_id is required", ErrValidation)
+	}
+	if m.Role == "" {
+		m.Role = model.OrganizationRoleMember
+	}
+	if !m.Role.Valid() {
+		return model.OrganizationMember{}, fmt.Errorf("%w: role must be owner, admin or member", ErrValidation)
+	}
+
+	var member model.OrganizationMember
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		actorRole, err := s.authorize(ctx, m.OrganizationID, model.OrganizationRole.CanManage)
+		if err != nil {
+			return err
+		}
+		if !keyInScope(ctx, m.UserID) {
+			return ErrOutOfScope
+		}
+
+		if _, err := s.repo.GetByIDForUpdate(ctx, m.OrganizationID); err != nil {
+			return err
+		}
+
+		current, err := s.repo.GetMember(ctx, m.OrganizationID, m.UserID)
+		if err != nil && !errors.Is(err, repository.ErrOrganizationMemberNotFound) {
+			return err
+		}
+
+		// Назначать и снимать владельцев может только владелец.
+		touchesOwner := m.Role == model.OrganizationRoleOwner || current.Role == model.OrganizationRoleOwner
+		if touchesOwner && actorRole != "" && actorRole != model.OrganizationRoleOwner {
+			return ErrOrganizationForbidden
+		}
+
+		if current.Role == model.OrganizationRoleOwner && m.Role != model.OrganizationRoleOwner {
+			if err := s.keepOwner(ctx, m.OrganizationID); err != nil {
+				return err
+			}
+		}
+
+		member, err = s.repo.SetMember(ctx, m)
+		return err
+	})
+	if err != nil {
+		log.Error("Не удалось изменить участника организации", slog.String("error", err.Error()))
+		return model.OrganizationMember{}, err
+	}
+
+	log.Info("Участник организации сохранен")
+	return member, nil
+}
+
+func (s *organizationService) RemoveMember(ctx context.Context, organizationID, userID uuid.UUID) error {
+	const op = "organizations.RemoveMember"
+	log := logging.FromContext(ctx, s.logger).With(
+		slog.String("op", op),
+		slog.String("organization_id", organizationID.String()),
+		slog.String("user_id", userID.String()),
+	)
+
+	log.Info("Исключение участника организации")
+
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		allowed := model.OrganizationRole.CanManage
+		if tenant, ok := repository.TenantFrom(ctx); ok && tenant == userID {
+			allowed = nil
+		}
+		actorRole, err := s.authorize(ctx, organizationID, allowed)
+		if err != nil {
+			return err
+		}
+		if !keyInScope(ctx, userID) {
+			return ErrOutOfScope
+		}
+
+		if _, err := s.repo.GetByIDForUpdate(ctx, organizationID); err != nil {
+			return err
+		}
+
+		current, err := s.repo.GetMember(ctx, organizationID, userID)
+		if err != nil {
+			return err
+		}
+
+		if current.Role == model.OrganizationRoleOwner {
+			if allowed != nil && actorRole != "" && actorRole != model.OrganizationRoleOwner {
+				return ErrOrganizationForbidden
+			}
+			if err := s.keepOwner(ctx, organizationID); err != nil {
+				return err
+			}
+		}
+
+		return s.repo.RemoveMember(ctx, organizationID, userID)
+	})
+	if err != nil {
+		log.Error("Не удалось исключить участника организации", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("Участник исключен из организации")
+	return nil
+}
+
+// authorize проверяет, что пользователь контекста состоит в организации и его роль удовлетворяет allowed
+// (nil - любая роль), и возвращает эту роль. Постороннему пользователю возвращается ErrOrganizationNotFound.
+// Без пользователя в контексте нужно право organizations:manage, а API-ключу с user_ids - участие
+// в организации кого-то из его пользователей; роль в этом случае пуста.
+func (s *organizationService) authorize(ctx context.Context, organizationID uuid.UUID, allowed func(model.OrganizationRole) bool) (model.OrganizationRole, error) {
+	tenant, ok := repository.TenantFrom(ctx)
+	if !ok {
+		return "", s.authorizeManage(ctx, organizationID)
+	}
+
+	member, err := s.repo.GetMember(ctx, organizationID, tenant)
+	if errors.Is(err, repository.ErrOrganizationMemberNotFound) {
+		return "", repository.ErrOrganizationNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if allowed != nil && !allowed(member.Role) {
+		return "", ErrOrganizationForbidden
+	}
+
+	return member.Role, nil
+}
+
+// authorizeManage проверяет право organizations:manage участника без пользователя. API-ключ с user_ids
+// видит только организации, в которых состоит кто-то из его пользователей; остальные для него не существуют.
+func (s *organizationService) authorizeManage(ctx context.Context, organizationID uuid.UUID) error {
+	if err := s.policy.Authorize(ctx, model.PermissionOrganizationsManage); err != nil {
+		return err
+	}
+
+	key, ok := APIKeyFrom(ctx)
+	if !ok || len(key.UserIDs) == 0 {
+		return nil
+	}
+
+	members, err := s.repo.ListMembers(ctx, organizationID)
+	if err != nil {
+		return err
+	}
+	for _, m := range members {
+		if repository.InScope(ctx, m.UserID) {
+			return nil
+		}
+	}
+
+	return repository.ErrOrganizationNotFound
+}
+
+// authorizeUser разрешает пользователю создавать свои организации и смотреть свой список организаций.
+// Для другого пользователя нужно право organizations:manage, а API-ключу с user_ids - еще и этот
+// пользователь среди них.
+func (s *organizationService) authorizeUser(ctx context.Context, userID uuid.UUID) error {
+	if tenant, ok := repository.TenantFrom(ctx); ok {
+		if tenant != userID {
+			return repository.ErrTenantMismatch
+		}
+		return nil
+	}
+
+	return authorizeSelfOr(ctx, s.policy, userID, model.PermissionOrganizationsManage)
+}
+
+// keepOwner запрещает лишать организацию последнего владельца.
+func (s *organizationService) keepOwner(ctx context.Context, organizationID uuid.UUID) error {
+	members, err := s.repo.ListMembers(ctx, organizationID)
+	if err != nil {
+		return err
+	}
+
+	owners := 0
+	for _, m := range members {
+		if m.Role == model.OrganizationRoleOwner {
+			owners++
+		}
+	}
+	if owners <= 1 {
+		return fmt.Errorf("%w: organization must keep at least one owner", ErrValidation)
+	}
+
+	return nil
+}
+
+func isOwner(role model.OrganizationRole) bool {
+	return role == model.OrganizationRoleOwner
+}
+
+// validateOrganization проверяет профиль организации и подставляет валюту по умолчанию.
+func validateOrganization(org *model.Organization) error {
+	org.Name = strings.TrimSpace(org.Name)
+	if org.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrValidation)
+	}
+
+	if err := normalizeCurrency(&org.DefaultCurrency); err != nil {
+		return fmt.Errorf("%w: default_currency must be an ISO 4217 code", ErrValidation)
+	}
+
+	return nil
+}
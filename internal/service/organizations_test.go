@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/vasiliy-maslov/go-subscription-service/internal/model"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// fakeTx выполняет функцию без транзакции.
+type fakeTx struct{}
+
+func (fakeTx) WithinTx(ctx context.Context, fn func(ctx context.Context) error, _ ...repository.TxOption) error {
+	return fn(ctx)
+}
+
+// fakeOutbox отбрасывает события.
+type fakeOutbox struct {
+	repository.OutboxRepository
+}
+
+func (fakeOutbox) Add(context.Context, string, uuid.UUID, any) error {
+	return nil
+}
+
+// fakeSubscriptions - репозиторий без подписок.
+type fakeSubscriptions struct {
+	repository.SubscriptionRepository
+}
+
+func (fakeSubscriptions) List(context.Context, repository.SubscriptionFilter) ([]model.Subscription, error) {
+	return nil, nil
+}
+
+// fakeOrganizations - организации в памяти: участники с ролями по организациям.
+type fakeOrganizations struct {
+	repository.OrganizationRepository
+	members map[uuid.UUID]map[uuid.UUID]model.OrganizationRole
+}
+
+func (f *fakeOrganizations) Create(_ context.Context, org model.Organization) (model.Organization, error) {
+	org.ID = uuid.New()
+	f.members[org.ID] = map[uuid.UUID]model.OrganizationRole{}
+	return org, nil
+}
+
+func (f *fakeOrganizations) get(id uuid.UUID) (model.Organization, error) {
+	if _, ok := f.members[id]; !ok {
+		return model.Organization{}, repository.ErrOrganizationNotFound
+	}
+	return model.Organization{ID: id, Name: "Acme", DefaultCurrency: "RUB"}, nil
+}
+
+func (f *fakeOrganizations) GetByID(_ context.Context, id uuid.UUID) (model.Organization, error) {
+	return f.get(id)
+}
+
+func (f *fakeOrganizations) GetByIDForUpdate(_ context.Context, id uuid.UUID) (model.Organization, error) {
+	return f.get(id)
+}
+
+func (f *fakeOrganizations) ListByUserID(context.Context, uuid.UUID) ([]model.Organization, error) {
+	return nil, nil
+}
+
+func (f *fakeOrganizations) Update(_ context.Context, id uuid.UUID, org model.Organization) (model.Organization, error) {
+	org.ID = id
+	return org, nil
+}
+
+func (f *fakeOrganizations) Delete(_ context.Context, id uuid.UUID) error {
+	delete(f.members, id)
+	return nil
+}
+
+func (f *fakeOrganizations) SetMember(_ context.Context, m model.OrganizationMember) (model.OrganizationMember, error) {
+	f.members[m.OrganizationID][m.UserID] = m.Role
+	return m, nil
+}
+
+func (f *fakeOrganizations) GetMember(_ context.Context, organizationID, userID uuid.UUID) (model.OrganizationMember, error) {
+	role, ok := f.members[organizationID][userID]
+	if !ok {
+		return model.OrganizationMember{}, repository.ErrOrganizationMemberNotFound
+	}
+	return model.OrganizationMember{OrganizationID: organizationID, UserID: userID, Role: role}, nil
+}
+
+func (f *fakeOrganizations) ListMembers(_ context.Context, organizationID uuid.UUID) ([]model.OrganizationMember, error) {
+	members := make([]model.OrganizationMember, 0)
+	for userID, role := range f.members[organizationID] {
+		members = append(members, model.OrganizationMember{OrganizationID: organizationID, UserID: userID, Role: role})
+	}
+	return members, nil
+}
+
+func (f *fakeOrganizations) RemoveMember(_ context.Context, organizationID, userID uuid.UUID) error {
+	delete(f.members[organizationID], userID)
+	return nil
+}
+
+func TestOrganizationServiceAPIKey(t *testing.T) {
+	owner, scoped, stranger := uuid.New(), uuid.New(), uuid.New()
+	manage := []model.Permission{model.PermissionOrganizationsManage}
+
+	tests := []struct {
+		name    string
+		key     model.APIKey
+		wantErr error
+	}{
+		{
+			name:    "key without organizations:manage",
+			key:     model.APIKey{Permissions: []model.Permission{model.PermissionSubscriptionsRead}},
+			wantErr: ErrPermissionDenied,
+		},
+		{
+			name:    "key scoped to a user outside the organization",
+			key:     model.APIKey{Permissions: manage, UserIDs: []uuid.UUID{stranger}},
+			wantErr: repository.ErrOrganizationNotFound,
+		},
+		{name: "key for all users", key: model.APIKey{Permissions: manage}},
+		{name: "key scoped to a member", key: model.APIKey{Permissions: manage, UserIDs: []uuid.UUID{owner, scoped}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeOrganizations{members: map[uuid.UUID]map[uuid.UUID]model.OrganizationRole{}}
+			orgID := uuid.New()
+			repo.members[orgID] = map[uuid.UUID]model.OrganizationRole{
+				owner:  model.OrganizationRoleOwner,
+				scoped: model.OrganizationRoleMember,
+			}
+			organizations := NewOrganizationService(repo, fakeSubscriptions{}, NewPolicy(&fakeRoles{}), fakeOutbox{}, fakeTx{},
+				slog.New(slog.NewTextHandler(io.Discard, nil)))
+			ctx := WithAPIKey(context.Background(), tt.key)
+
+			calls := map[string]error{}
+			_, calls["GetByID"] = organizations.GetByID(ctx, orgID)
+			_, calls["ListMembers"] = organizations.ListMembers(ctx, orgID)
+			_, calls["Update"] = organizations.Update(ctx, orgID, model.Organization{Name: "Renamed"})
+			_, calls["SetMember"] = organizations.SetMember(ctx,
+				model.OrganizationMember{OrganizationID: orgID, UserID: scoped, Role: model.OrganizationRoleOwner})
+			calls["RemoveMember"] = organizations.RemoveMember(ctx, orgID, scoped)
+			calls["Delete"] = organizations.Delete(ctx, orgID)
+
+			for method, err := range calls {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("%s() error = %v, want %v", method, err, tt.wantErr)
+				}
+			}
+		})
+	}
+}
+
+func TestOrganizationServiceAPIKeyScope(t *testing.T) {
+	owner, stranger := uuid.New(), uuid.New()
+	repo := &fakeOrganizations{members: map[uuid.UUID]map[uuid.UUID]model.OrganizationRole{}}
+	orgID := uuid.New()
+	repo.members[orgID] = map[uuid.UUID]model.OrganizationRole{owner: model.OrganizationRoleOwner}
+	organizations := NewOrganizationService(repo, fakeSubscriptions{}, NewPolicy(&fakeRoles{}), fakeOutbox{}, fakeTx{},
+		slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	ctx := WithAPIKey(context.Background(), model.APIKey{
+		Permissions: []model.Permission{model.PermissionOrganizationsManage},
+		UserIDs:     []uuid.UUID{owner},
+	})
+
+	if _, err := organizations.Create(ctx, model.Organization{Name: "Acme"}, stranger); !errors.Is(err, ErrOutOfScope) {
+		t.Errorf("Create() for a user outside the key error = %v, want %v", err, ErrOutOfScope)
+	}
+	if _, err := organizations.ListForUser(ctx, stranger); !errors.Is(err, ErrOutOfScope) {
+		t.Errorf("ListForUser() for a user outside the key error = %v, want %v", err, ErrOutOfScope)
+	}
+	_, err := organizations.SetMember(ctx, model.OrganizationMember{OrganizationID: orgID, UserID: stranger, Role: model.OrganizationRoleOwner})
+	if !errors.Is(err, ErrOutOfScope) {
+		t.Errorf("SetMember() for a user outside the key error = %v, want %v", err, ErrOutOfScope)
+	}
+	if _, ok := repo.members[orgID][stranger]; ok {
+		t.Error("SetMember() added a user outside the key")
+	}
+
+	if _, err := organizations.Create(ctx, model.Organization{Name: "Acme"}, owner); err != nil {
+		t.Errorf("Create() for a user of the key error = %v", err)
+	}
+	if _, err := organizations.Create(context.Background(), model.Organization{Name: "Acme"}, owner); !errors.Is(err, ErrForbidden) {
+		t.Errorf("Create() without a principal error = %v, want %v", err, ErrForbidden)
+	}
+}
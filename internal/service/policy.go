@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/vasiliy-maslov/go-subscription-service/internal/model"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// ErrForbidden возвращается, когда в контексте нет ни пользователя, ни API-ключа, ни системного вызова:
+// действие без аутентифицированного участника запрещено.
+var ErrForbidden = errors.New("forbidden: request is not authenticated")
+
+// ErrPermissionDenied возвращается, когда ролям пользователя не хватает права на действие.
+// Конкретное право сообщает *PermissionError, которая оборачивает эту ошибку.
+var ErrPermissionDenied = errors.New("permission denied")
+
+// ErrOutOfScope возвращается, когда API-ключ, выданный на данные конкретных пользователей,
+// обращается к данным пользователя не из их числа.
+var ErrOutOfScope = errors.New("forbidden: user is outside the scope of the API key")
+
+// PermissionError описывает право, которого не хватило пользователю. Для API-ключа UserID пуст.
+type PermissionError struct {
+	UserID     uuid.UUID
+	Permission model.Permission
+}
+
+func (e *PermissionError) Error() string {
+	return fmt.Sprintf("%s: %s is required", ErrPermissionDenied, e.Permission)
+}
+
+func (e *PermissionError) Unwrap() error {
+	return ErrPermissionDenied
+}
+
+// Policy решает, разрешено ли действие пользователю, к которому привязан контекст (repository.WithTenant),
+// или API-ключу, которым аутентифицирован запрос (WithAPIKey).
+type Policy interface {
+	// Authorize возвращает *PermissionError, если ни одна роль пользователя не дает права permission,
+	// а для API-ключа - если права нет среди прав ключа. Контекст без пользователя и ключа получает
+	// ErrForbidden; ограничения снимаются только для системного вызова (repository.WithSystem).
+	Authorize(ctx context.Context, permission model.Permission) error
+}
+
+type rolePolicy struct {
+	roles repository.RoleRepository
+}
+
+// NewPolicy создает политику доступа на основе ролей, выданных пользователям.
+func NewPolicy(roles repository.RoleRepository) Policy {
+	return &rolePolicy{roles: roles}
+}
+
+func (p *rolePolicy) Authorize(ctx context.Context, permission model.Permission) error {
+	if repository.IsSystem(ctx) {
+		return nil
+	}
+
+	if key, ok := APIKeyFrom(ctx); ok {
+		if !slices.Contains(key.Permissions, permission) {
+			return &PermissionError{Permission: permission}
+		}
+		return nil
+	}
+
+	userID, ok := repository.TenantFrom(ctx)
+	if !ok {
+		return ErrForbidden
+	}
+
+	permissions, err := p.roles.Permissions(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if !slices.Contains(permissions, permission) {
+		return &PermissionError{UserID: userID, Permission: permission}
+	}
+
+	return nil
+}
+
+// authorizeReport проверяет право на отчет по подпискам filter. Отчет по своим подпискам и подпискам
+// своих организаций требует subscriptions:read, отчет по подпискам другого пользователя - reports:read_all.
+// Во втором случае возвращает контекст без привязки к пользователю, чтобы выборка не ограничивалась
+// его собственными подписками.
+func (s *subscriptionService) authorizeReport(ctx context.Context, filter repository.SubscriptionFilter) (context.Context, error) {
+	tenant, ok := repository.TenantFrom(ctx)
+	if !ok || filter.OrganizationID != nil || filter.UserID == tenant {
+		return ctx, s.policy.Authorize(ctx, model.PermissionSubscriptionsRead)
+	}
+
+	if err := s.policy.Authorize(ctx, model.PermissionReportsReadAll); err != nil {
+		return ctx, err
+	}
+	return repository.WithoutTenant(ctx), nil
+}
+
+// authorizeUser проверяет право на действие с данными пользователя userID: профилем, бюджетами,
+// категориями и метками. Пользователь распоряжается своими данными без отдельного права, для данных
+// другого пользователя нужно users:manage.
+func authorizeUser(ctx context.Context, policy Policy, userID uuid.UUID) error {
+	return authorizeSelfOr(ctx, policy, userID, model.PermissionUsersManage)
+}
+
+// authorizeSelfOr разрешает пользователю действие со своими данными, а с данными пользователя userID
+// в остальных случаях требует право permission, а от API-ключа с user_ids - еще и userID среди них.
+func authorizeSelfOr(ctx context.Context, policy Policy, userID uuid.UUID, permission model.Permission) error {
+	if tenant, ok := repository.TenantFrom(ctx); ok && tenant == userID {
+		return nil
+	}
+
+	if err := policy.Authorize(ctx, permission); err != nil {
+		return err
+	}
+	if !keyInScope(ctx, userID) {
+		return ErrOutOfScope
+	}
+
+	return nil
+}
+
+// keyInScope сообщает, что API-ключ запроса выдан и на данные пользователя userID. Без ключа
+// возвращает true: пользователя запроса ограничивают его собственные проверки.
+func keyInScope(ctx context.Context, userID uuid.UUID) bool {
+	_, ok := APIKeyFrom(ctx)
+	return !ok || repository.InScope(ctx, userID)
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/vasiliy-maslov/go-subscription-service/internal/model"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// fakeRoles - роли в памяти: права пользователей без привязки к ролям.
+type fakeRoles struct {
+	repository.RoleRepository
+	permissions map[uuid.UUID][]model.Permission
+}
+
+func (f *fakeRoles) Permissions(_ context.Context, userID uuid.UUID) ([]model.Permission, error) {
+	return f.permissions[userID], nil
+}
+
+func TestPolicyAuthorize(t *testing.T) {
+	reader, stranger := uuid.New(), uuid.New()
+	policy := NewPolicy(&fakeRoles{permissions: map[uuid.UUID][]model.Permission{
+		reader: {model.PermissionSubscriptionsRead},
+	}})
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		wantErr error
+	}{
+		{name: "no principal is forbidden", ctx: context.Background(), wantErr: ErrForbidden},
+		{name: "context without tenant is forbidden", ctx: repository.WithoutTenant(context.Background()), wantErr: ErrForbidden},
+		{name: "system call is allowed", ctx: repository.WithSystem(context.Background())},
+		{name: "user with the permission", ctx: repository.WithTenant(context.Background(), reader)},
+		{name: "user without the permission", ctx: repository.WithTenant(context.Background(), stranger), wantErr: ErrPermissionDenied},
+		{
+			name: "API key with the permission",
+			ctx:  WithAPIKey(context.Background(), model.APIKey{Permissions: []model.Permission{model.PermissionSubscriptionsRead}}),
+		},
+		{
+			name:    "API key without the permission",
+			ctx:     WithAPIKey(context.Background(), model.APIKey{Permissions: []model.Permission{model.PermissionAuditRead}}),
+			wantErr: ErrPermissionDenied,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := policy.Authorize(tt.ctx, model.PermissionSubscriptionsRead)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Authorize() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAuthorizeUser(t *testing.T) {
+	owner, admin, stranger := uuid.New(), uuid.New(), uuid.New()
+	policy := NewPolicy(&fakeRoles{permissions: map[uuid.UUID][]model.Permission{
+		admin: {model.PermissionUsersManage},
+	}})
+	manage := []model.Permission{model.PermissionUsersManage}
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		wantErr error
+	}{
+		{name: "no principal is forbidden", ctx: context.Background(), wantErr: ErrForbidden},
+		{name: "user acts on own data", ctx: repository.WithTenant(context.Background(), owner)},
+		{name: "user acts on another user", ctx: repository.WithTenant(context.Background(), stranger), wantErr: ErrPermissionDenied},
+		{name: "user with users:manage", ctx: repository.WithTenant(context.Background(), admin)},
+		{name: "API key without users:manage", ctx: WithAPIKey(context.Background(), model.APIKey{}), wantErr: ErrPermissionDenied},
+		{name: "API key for all users", ctx: WithAPIKey(context.Background(), model.APIKey{Permissions: manage})},
+		{
+			name: "API key scoped to the user",
+			ctx:  WithAPIKey(context.Background(), model.APIKey{Permissions: manage, UserIDs: []uuid.UUID{owner}}),
+		},
+		{
+			name:    "API key scoped to other users",
+			ctx:     WithAPIKey(context.Background(), model.APIKey{Permissions: manage, UserIDs: []uuid.UUID{stranger}}),
+			wantErr: ErrOutOfScope,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := authorizeUser(tt.ctx, policy, owner)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("authorizeUser() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
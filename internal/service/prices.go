@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/vasiliy-maslov/go-subscription-service/internal/logging"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/model"
+
+	"github.com/google/uuid"
+)
+
+// AddPriceChange планирует изменение цены подписки с указанной даты.
+func (s *subscriptionService) AddPriceChange(ctx context.Context, subscriptionID uuid.UUID, change model.PriceChange) (model.PriceChange, error) {
+	const op = "service.AddPriceChange"
+	log := logging.FromContext(ctx, s.logger).With(
+		slog.String("op", op),
+		slog.String("subscription_id", subscriptionID.String()),
+	)
+
+	if err := s.policy.Authorize(ctx, model.PermissionSubscriptionsWrite); err != nil {
+		log.Warn("Действие запрещено политикой доступа", slog.String("error", err.Error()))
+		return model.PriceChange{}, err
+	}
+
+	log.Info("Изменение цены подписки")
+
+	if change.Price.Sign() < 0 {
+		return model.PriceChange{}, fmt.Errorf("%w: price must not be negative", ErrValidation)
+	}
+	if change.EffectiveDate.IsZero() {
+		return model.PriceChange{}, fmt.Errorf("%w: effective_date is required", ErrValidation)
+	}
+	ctx, err := s.inSubscriptionZone(ctx, subscriptionID)
+	if err != nil {
+		log.Error("Не удалось определить часовой пояс владельца подписки", slog.String("error", err.Error()))
+		return model.PriceChange{}, err
+	}
+	resolveDates(ctx, &change.EffectiveDate)
+
+	var created model.PriceChange
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		sub, err := s.repo.GetByIDForUpdate(ctx, subscriptionID)
+		if err != nil {
+			return err
+		}
+
+		if change.EffectiveDate.Before(sub.StartDate) {
+			return fmt.Errorf("%w: effective_date must not be before start_date", ErrValidation)
+		}
+		if err := normalizeAmount(&change.Price, sub.Currency, "price"); err != nil {
+			return err
+		}
+
+		change.SubscriptionID = subscriptionID
+		created, err = s.repo.AddPriceChange(ctx, change)
+		if err != nil {
+			return err
+		}
+
+		return s.outbox.Add(ctx, model.EventPriceChanged, subscriptionID, created)
+	})
+	if err != nil {
+		log.Error("Не удалось изменить цену подписки", slog.String("error", err.Error()))
+		return model.PriceChange{}, err
+	}
+
+	log.Info("Изменение цены подписки сохранено", slog.String("effective_date", created.EffectiveDate.String()))
+	return created, nil
+}
+
+// DeletePriceChange отменяет изменение цены подписки.
+func (s *subscriptionService) DeletePriceChange(ctx context.Context, subscriptionID, id uuid.UUID) error {
+	const op = "service.DeletePriceChange"
+	log := logging.FromContext(ctx, s.logger).With(
+		slog.String("op", op),
+		slog.String("subscription_id", subscriptionID.String()),
+		slog.String("price_change_id", id.String()),
+	)
+
+	if err := s.policy.Authorize(ctx, model.PermissionSubscriptionsWrite); err != nil {
+		log.Warn("Действие запрещено политикой доступа", slog.String("error", err.Error()))
+		return err
+	}
+
+	if err := s.repo.DeletePriceChange(ctx, subscriptionID, id); err != nil {
+		log.Error("Не удалось удалить изменение цены", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("Изменение цены удалено")
+	return nil
+}
+
+// ListPriceChanges возвращает изменения цены подписки по дате вступления в силу.
+func (s *subscriptionService) ListPriceChanges(ctx context.Context, subscriptionID uuid.UUID) ([]model.PriceChange, error) {
+	const op = "service.ListPriceChanges"
+	log := logging.FromContext(ctx, s.logger).With(
+		slog.String("op", op),
+		slog.String("subscription_id", subscriptionID.String()),
+	)
+
+	if err := s.policy.Authorize(ctx, model.PermissionSubscriptionsRead); err != nil {
+		log.Warn("Действие запрещено политикой доступа", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if _, err := s.repo.GetByID(ctx, subscriptionID); err != nil {
+		log.Error("Не удалось получить подписку", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	changes, err := s.repo.ListPriceChanges(ctx, []uuid.UUID{subscriptionID})
+	if err != nil {
+		log.Error("Не удалось получить изменения цены", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	result := changes[subscriptionID]
+	if result == nil {
+		result = []model.PriceChange{}
+	}
+	return result, nil
+}
@@ -0,0 +1,320 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/vasiliy-maslov/go-subscription-service/internal/logging"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/model"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// ErrErasureIncomplete возвращается, когда после удаления данных пользователя на него остались ссылки.
+// Транзакция удаления в этом случае откатывается.
+var ErrErasureIncomplete = errors.New("user data erasure is incomplete")
+
+// PrivacyService определяет интерфейс для выполнения запросов субъектов данных.
+type PrivacyService interface {
+	// Export собирает все данные пользователя на один момент времени.
+	Export(ctx context.Context, userID uuid.UUID) (model.UserDataExport, error)
+	// Erase удаляет данные пользователя, заменяет его ID на tombstone в журнале событий, удаляет его ID
+	// из журнала аудита, проверяет, что ссылок на пользователя не осталось, и записывает удаление в журнал.
+	Erase(ctx context.Context, userID uuid.UUID) (model.Erasure, error)
+}
+
+type privacyService struct {
+	repo          repository.PrivacyRepository
+	users         repository.UserRepository
+	subscriptions repository.SubscriptionRepository
+	categories    repository.CategoryRepository
+	budgets       repository.BudgetRepository
+	apiKeys       repository.APIKeyRepository
+	audit         repository.AuditRepository
+	policy        Policy
+	outbox        repository.OutboxRepository
+	tx            repository.TxManager
+	logger        *slog.Logger
+}
+
+// NewPrivacyService создает новый экземпляр сервиса запросов субъектов данных.
+func NewPrivacyService(
+	repo repository.PrivacyRepository,
+	users repository.UserRepository,
+	subscriptions repository.SubscriptionRepository,
+	categories repository.CategoryRepository,
+	budgets repository.BudgetRepository,
+	apiKeys repository.APIKeyRepository,
+	audit repository.AuditRepository,
+	policy Policy,
+	outbox repository.OutboxRepository,
+	tx repository.TxManager,
+	logger *slog.Logger,
+) PrivacyService {
+	return &privacyService{
+		repo:          repo,
+		users:         users,
+		subscriptions: subscriptions,
+		categories:    categories,
+		budgets:       budgets,
+		apiKeys:       apiKeys,
+		audit:         audit,
+		policy:        policy,
+		outbox:        outbox,
+		tx:            tx,
+		logger:        logger,
+	}
+}
+
+// Export читает данные в одной транзакции repeatable read, поэтому выгрузка согласована.
+// Свои данные пользователь выгружает сам, данные другого пользователя - с правом privacy:manage.
+func (s *privacyService) Export(ctx context.Context, userID uuid.UUID) (model.UserDataExport, error) {
+	const op = "privacy.Export"
+	log := logging.FromContext(ctx, s.logger).With(slog.String("op", op), slog.String("user_id", userID.String()))
+
+	log.Info("Выгрузка данных пользователя")
+
+	ctx, err := s.authorize(ctx, userID)
+	if err != nil {
+		log.Warn("Действие запрещено политикой доступа", slog.String("error", err.Error()))
+		return model.UserDataExport{}, err
+	}
+
+	var export model.UserDataExport
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		export = model.UserDataExport{ExportedAt: time.Now().UTC()}
+
+		export.User, err = s.users.GetByID(ctx, userID)
+		if err != nil {
+			return err
+		}
+
+		export.Subscriptions, err = s.subscriptionHistory(ctx, userID)
+		if err != nil {
+			return err
+		}
+
+		export.Categories, err = s.categories.ListCategories(ctx, userID)
+		if err != nil {
+			return err
+		}
+
+		export.Tags, err = s.categories.ListTags(ctx, userID)
+		if err != nil {
+			return err
+		}
+
+		export.Budgets, err = s.budgets.ListByUserID(ctx, userID)
+		if err != nil {
+			return err
+		}
+
+		export.Notifications, err = s.repo.ListEvents(ctx, userID)
+		return err
+	}, repository.WithIsolation(repository.RepeatableRead))
+	if err != nil {
+		log.Error("Не удалось выгрузить данные пользователя", slog.String("error", err.Error()))
+		return model.UserDataExport{}, err
+	}
+
+	log.Info("Данные пользователя выгружены",
+		slog.Int("subscriptions", len(export.Subscriptions)), slog.Int("notifications", len(export.Notifications)))
+	return export, nil
+}
+
+// subscriptionHistory возвращает подписки пользователя, включая чужие подписки с его участием,
+// с категориями, метками и полной историей.
+func (s *privacyService) subscriptionHistory(ctx context.Context, userID uuid.UUID) ([]model.SubscriptionHistory, error) {
+	subscriptions, err := s.subscriptions.List(ctx, repository.SubscriptionFilter{UserID: userID})
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]uuid.UUID, 0, len(subscriptions))
+	for _, sub := range subscriptions {
+		ids = append(ids, sub.ID)
+	}
+
+	transitions, err := s.subscriptions.ListTransitions(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	members, err := s.subscriptions.ListMembers(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	prices, err := s.subscriptions.ListPriceChanges(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	discounts, err := s.subscriptions.ListDiscounts(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	categories, err := s.categories.ListSubscriptionCategories(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := s.categories.ListSubscriptionTags(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	history := make([]model.SubscriptionHistory, 0, len(subscriptions))
+	for _, sub := range subscriptions {
+		sub.CategoryIDs = make([]uuid.UUID, 0, len(categories[sub.ID]))
+		for _, category := range categories[sub.ID] {
+			sub.CategoryIDs = append(sub.CategoryIDs, category.ID)
+		}
+		sub.Tags = make([]string, 0, len(tags[sub.ID]))
+		for _, tag := range tags[sub.ID] {
+			sub.Tags = append(sub.Tags, tag.Name)
+		}
+
+		history = append(history, model.SubscriptionHistory{
+			Subscription: sub,
+			Transitions:  nonNil(transitions[sub.ID]),
+			Members:      nonNil(members[sub.ID]),
+			PriceChanges: nonNil(prices[sub.ID]),
+			Discounts:    nonNil(discounts[sub.ID]),
+		})
+	}
+
+	return history, nil
+}
+
+// Erase выполняется в одной транзакции: блокировка строки пользователя не дает параллельно
+// добавить ему подписку или участие, а при неполном удалении все изменения откатываются.
+// Подписки пользователя удаляются с событием subscription.deleted; содержимое этих событий,
+// как и всех прежних событий о пользователе, его подписках и бюджетах, заменяется на tombstone.
+// Пользователь исключается из API-ключей, а ключ, у которого не осталось пользователей, отзывается
+// с событием api_key.revoked. Из журнала аудита удаляются исполнитель и IP-адрес записей пользователя,
+// а также ID ресурса и параметры записей, в которых встречается его ID: иначе проверка ссылок не пройдет.
+// Свои данные пользователь удаляет сам, данные другого пользователя - с правом privacy:manage.
+func (s *privacyService) Erase(ctx context.Context, userID uuid.UUID) (model.Erasure, error) {
+	const op = "privacy.Erase"
+	log := logging.FromContext(ctx, s.logger).With(slog.String("op", op), slog.String("user_id", userID.String()))
+
+	log.Info("Удаление данных пользователя")
+
+	ctx, err := s.authorize(ctx, userID)
+	if err != nil {
+		log.Warn("Действие запрещено политикой доступа", slog.String("error", err.Error()))
+		return model.Erasure{}, err
+	}
+
+	var erasure model.Erasure
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		erasure = model.Erasure{TombstoneID: uuid.New()}
+
+		if _, err := s.users.GetByIDForUpdate(ctx, userID); err != nil {
+			return err
+		}
+
+		subscriptions, err := s.subscriptions.List(ctx, repository.SubscriptionFilter{UserID: userID})
+		if err != nil {
+			return err
+		}
+
+		var erased []uuid.UUID
+		for _, sub := range subscriptions {
+			if sub.UserID != userID {
+				continue
+			}
+			if err := s.subscriptions.Delete(ctx, sub.ID); err != nil {
+				return err
+			}
+			if err := s.outbox.Add(ctx, model.EventSubscriptionDeleted, sub.ID, sub); err != nil {
+				return err
+			}
+			erased = append(erased, sub.ID)
+			erasure.Subscriptions++
+		}
+
+		budgets, err := s.budgets.ListByUserID(ctx, userID)
+		if err != nil {
+			return err
+		}
+		for _, b := range budgets {
+			erased = append(erased, b.ID)
+		}
+
+		if err := s.users.Delete(ctx, userID); err != nil {
+			return err
+		}
+
+		keys, err := s.apiKeys.RemoveUser(ctx, userID)
+		if err != nil {
+			return err
+		}
+		for _, key := range keys {
+			if len(key.UserIDs) > 0 {
+				continue
+			}
+			if err := s.outbox.Add(ctx, model.EventAPIKeyRevoked, key.ID, key); err != nil {
+				return err
+			}
+		}
+
+		auditEntries, err := s.audit.EraseUser(ctx, userID)
+		if err != nil {
+			return err
+		}
+		log.Info("Пользователь удален из API-ключей и журнала аудита",
+			slog.Int("api_keys", len(keys)), slog.Int64("audit_entries", auditEntries))
+
+		erasure.RedactedEvents, err = s.repo.RedactEvents(ctx, userID, erasure.TombstoneID, erased)
+		if err != nil {
+			return err
+		}
+
+		refs, err := s.repo.CountReferences(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if len(refs) > 0 {
+			return fmt.Errorf("%w: %v", ErrErasureIncomplete, refs)
+		}
+
+		erasure, err = s.repo.RecordErasure(ctx, erasure)
+		if err != nil {
+			return err
+		}
+
+		return s.outbox.Add(ctx, model.EventUserErased, erasure.TombstoneID, erasure)
+	})
+	if err != nil {
+		log.Error("Не удалось удалить данные пользователя", slog.String("error", err.Error()))
+		return model.Erasure{}, err
+	}
+
+	log.Info("Данные пользователя удалены",
+		slog.String("erasure_id", erasure.ID.String()),
+		slog.Int("subscriptions", erasure.Subscriptions),
+		slog.Int64("redacted_events", erasure.RedactedEvents),
+	)
+	return erasure, nil
+}
+
+// authorize проверяет право на запрос субъекта данных userID и возвращает контекст без привязки
+// к пользователю запроса: выгрузка и удаление охватывают все подписки пользователя, в том числе
+// не видимые тому, кто выполняет запрос, и каждая удаляемая подписка получает событие subscription.deleted.
+func (s *privacyService) authorize(ctx context.Context, userID uuid.UUID) (context.Context, error) {
+	if err := authorizeSelfOr(ctx, s.policy, userID, model.PermissionPrivacyManage); err != nil {
+		return ctx, err
+	}
+	return repository.WithoutTenant(ctx), nil
+}
+
+// nonNil возвращает пустой срез вместо nil, чтобы в выгрузке отсутствующая история была [] а не null.
+func nonNil[T any](items []T) []T {
+	if items == nil {
+		return []T{}
+	}
+	return items
+}
@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/vasiliy-maslov/go-subscription-service/internal/model"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// fakeUsers - репозиторий с одним пользователем.
+type fakeUsers struct {
+	repository.UserRepository
+	id      uuid.UUID
+	deleted bool
+}
+
+func (f *fakeUsers) GetByIDForUpdate(_ context.Context, id uuid.UUID) (model.User, error) {
+	if id != f.id || f.deleted {
+		return model.User{}, repository.ErrUserNotFound
+	}
+	return model.User{ID: id}, nil
+}
+
+func (f *fakeUsers) Delete(context.Context, uuid.UUID) error {
+	f.deleted = true
+	return nil
+}
+
+// fakeBudgets - репозиторий без бюджетов.
+type fakeBudgets struct {
+	repository.BudgetRepository
+}
+
+func (fakeBudgets) ListByUserID(context.Context, uuid.UUID) ([]model.Budget, error) {
+	return nil, nil
+}
+
+// fakeAPIKeys - репозиторий без ключей, ограниченных пользователями.
+type fakeAPIKeys struct {
+	repository.APIKeyRepository
+}
+
+func (fakeAPIKeys) RemoveUser(context.Context, uuid.UUID) ([]model.APIKey, error) {
+	return nil, nil
+}
+
+// fakeAudit - журнал аудита в памяти. С ignoreSubjects EraseUser удаляет только исполнителя,
+// как до переноса ID ресурса в стираемую таблицу.
+type fakeAudit struct {
+	repository.AuditRepository
+	entries        []model.AuditEntry
+	ignoreSubjects bool
+}
+
+func (f *fakeAudit) EraseUser(_ context.Context, userID uuid.UUID) (int64, error) {
+	var erased int64
+	for i, e := range f.entries {
+		if e.ActorID != nil && *e.ActorID == userID {
+			f.entries[i].ActorID, f.entries[i].SourceIP, f.entries[i].IdentityErased = nil, "", true
+			erased++
+		}
+		if !f.ignoreSubjects && mentions(e, userID) {
+			f.entries[i].ResourceID, f.entries[i].Params, f.entries[i].SubjectErased = "", nil, true
+			erased++
+		}
+	}
+	return erased, nil
+}
+
+// mentions сообщает, что ID ресурса или параметры записи содержат ID пользователя.
+func mentions(e model.AuditEntry, userID uuid.UUID) bool {
+	if strings.Contains(e.ResourceID, userID.String()) {
+		return true
+	}
+	for _, v := range e.Params {
+		if strings.Contains(v, userID.String()) {
+			return true
+		}
+	}
+	return false
+}
+
+// fakePrivacy считает оставшиеся ссылки на пользователя по пользователям и журналу аудита.
+type fakePrivacy struct {
+	repository.PrivacyRepository
+	users *fakeUsers
+	audit *fakeAudit
+}
+
+func (f *fakePrivacy) RedactEvents(context.Context, uuid.UUID, uuid.UUID, []uuid.UUID) (int64, error) {
+	return 0, nil
+}
+
+func (f *fakePrivacy) CountReferences(_ context.Context, userID uuid.UUID) (map[string]int64, error) {
+	refs := make(map[string]int64)
+	if f.users.id == userID && !f.users.deleted {
+		refs["users"]++
+	}
+	for _, e := range f.audit.entries {
+		if e.ActorID != nil && *e.ActorID == userID {
+			refs["audit_identities"]++
+		}
+		if mentions(e, userID) {
+			refs["audit_subjects"]++
+		}
+	}
+	return refs, nil
+}
+
+func (f *fakePrivacy) RecordErasure(_ context.Context, e model.Erasure) (model.Erasure, error) {
+	e.ID = uuid.New()
+	return e, nil
+}
+
+func TestPrivacyServiceEraseAudit(t *testing.T) {
+	tests := []struct {
+		name           string
+		ignoreSubjects bool
+		wantErr        error
+	}{
+		{name: "audit entries about the user are erased"},
+		{name: "audit entry left with the user ID", ignoreSubjects: true, wantErr: ErrErasureIncomplete},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userID, adminID := uuid.New(), uuid.New()
+			users := &fakeUsers{id: userID}
+			audit := &fakeAudit{ignoreSubjects: tt.ignoreSubjects, entries: []model.AuditEntry{
+				{ActorType: model.AuditActorUser, ActorID: &userID, Action: "POST /api/v1/subscriptions/", SourceIP: "192.0.2.1"},
+				{ActorType: model.AuditActorUser, ActorID: &adminID, Action: "PUT /api/v1/users/:id", ResourceID: userID.String()},
+				{
+					ActorType:  model.AuditActorUser,
+					ActorID:    &adminID,
+					Action:     "DELETE /api/v1/subscriptions/:id/members/:user_id",
+					ResourceID: uuid.NewString(),
+					Params:     map[string]string{"user_id": userID.String()},
+				},
+			}}
+			privacy := NewPrivacyService(&fakePrivacy{users: users, audit: audit}, users, fakeSubscriptions{}, nil, fakeBudgets{},
+				fakeAPIKeys{}, audit, NewPolicy(&fakeRoles{}), fakeOutbox{}, fakeTx{}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+			_, err := privacy.Erase(repository.WithTenant(context.Background(), userID), userID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Erase() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			for _, e := range audit.entries {
+				if (e.ActorID != nil && *e.ActorID == userID) || mentions(e, userID) {
+					t.Errorf("audit entry %q still refers to the erased user", e.Action)
+				}
+			}
+			if audit.entries[1].ActorID == nil || *audit.entries[1].ActorID != adminID {
+				t.Error("Erase() removed the actor of an entry made by another user")
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/vasiliy-maslov/go-subscription-service/internal/logging"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/model"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// RoleService определяет интерфейс для просмотра ролей и управления их выдачей пользователям.
+// Выдавать и отзывать роли, а также смотреть чужие роли можно только с правом roles:manage.
+type RoleService interface {
+	ListRoles(ctx context.Context) ([]model.Role, error)
+	ListBindings(ctx context.Context, userID uuid.UUID) ([]model.RoleBinding, error)
+	Bind(ctx context.Context, userID uuid.UUID, role string) (model.RoleBinding, error)
+	Unbind(ctx context.Context, userID uuid.UUID, role string) error
+}
+
+type roleService struct {
+	repo   repository.RoleRepository
+	policy Policy
+	outbox repository.OutboxRepository
+	tx     repository.TxManager
+	logger *slog.Logger
+}
+
+// NewRoleService создает новый экземпляр сервиса ролей.
+func NewRoleService(
+	repo repository.RoleRepository,
+	policy Policy,
+	outbox repository.OutboxRepository,
+	tx repository.TxManager,
+	logger *slog.Logger,
+) RoleService {
+	return &roleService{
+		repo:   repo,
+		policy: policy,
+		outbox: outbox,
+		tx:     tx,
+		logger: logger,
+	}
+}
+
+func (s *roleService) ListRoles(ctx context.Context) ([]model.Role, error) {
+	const op = "roles.ListRoles"
+	log := logging.FromContext(ctx, s.logger).With(slog.String("op", op))
+
+	roles, err := s.repo.ListRoles(ctx)
+	if err != nil {
+		log.Error("Не удалось получить роли", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return roles, nil
+}
+
+// ListBindings возвращает роли пользователя. Свои роли пользователь видит без дополнительных прав.
+func (s *roleService) ListBindings(ctx context.Context, userID uuid.UUID) ([]model.RoleBinding, error) {
+	const op = "roles.ListBindings"
+	log := logging.FromContext(ctx, s.logger).With(slog.String("op", op), slog.String("user_id", userID.String()))
+
+	if tenant, ok := repository.TenantFrom(ctx); !ok || tenant != userID {
+		if err := s.policy.Authorize(ctx, model.PermissionRolesManage); err != nil {
+			log.Warn("Доступ к ролям пользователя запрещен", slog.String("error", err.Error()))
+			return nil, err
+		}
+	}
+
+	bindings, err := s.repo.ListBindings(ctx, userID)
+	if err != nil {
+		log.Error("Не удалось получить роли пользователя", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return bindings, nil
+}
+
+func (s *roleService) Bind(ctx context.Context, userID uuid.UUID, role string) (model.RoleBinding, error) {
+	const op = "roles.Bind"
+	log := logging.FromContext(ctx, s.logger).With(slog.String("op", op), slog.String("user_id", userID.String()), slog.String("role", role))
+
+	log.Info("Выдача роли")
+
+	if err := s.policy.Authorize(ctx, model.PermissionRolesManage); err != nil {
+		log.Warn("Выдача роли запрещена", slog.String("error", err.Error()))
+		return model.RoleBinding{}, err
+	}
+
+	role = strings.TrimSpace(role)
+	if role == "" {
+		return model.RoleBinding{}, fmt.Errorf("%w: role is required", ErrValidation)
+	}
+
+	var bound model.RoleBinding
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		bound, err = s.repo.Bind(ctx, model.RoleBinding{UserID: userID, Role: role})
+		if err != nil {
+			return err
+		}
+
+		return s.outbox.Add(ctx, model.EventRoleBound, userID, bound)
+	})
+	if err != nil {
+		log.Error("Не удалось выдать роль", slog.String("error", err.Error()))
+		return model.RoleBinding{}, err
+	}
+
+	log.Info("Роль успешно выдана")
+	return bound, nil
+}
+
+func (s *roleService) Unbind(ctx context.Context, userID uuid.UUID, role string) error {
+	const op = "roles.Unbind"
+	log := logging.FromContext(ctx, s.logger).With(slog.String("op", op), slog.String("user_id", userID.String()), slog.String("role", role))
+
+	log.Info("Отзыв роли")
+
+	if err := s.policy.Authorize(ctx, model.PermissionRolesManage); err != nil {
+		log.Warn("Отзыв роли запрещен", slog.String("error", err.Error()))
+		return err
+	}
+
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.Unbind(ctx, userID, role); err != nil {
+			return err
+		}
+
+		return s.outbox.Add(ctx, model.EventRoleUnbound, userID, model.RoleBinding{UserID: userID, Role: role})
+	})
+	if err != nil {
+		log.Error("Не удалось отозвать роль", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("Роль успешно отозвана")
+	return nil
+}
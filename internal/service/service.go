@@ -2,47 +2,182 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log/slog"
-	"time"
+	"sort"
+	"strings"
 
+	"github.com/vasiliy-maslov/go-subscription-service/internal/logging"
 	"github.com/vasiliy-maslov/go-subscription-service/internal/model"
 	"github.com/vasiliy-maslov/go-subscription-service/internal/repository"
 
 	"github.com/google/uuid"
 )
 
+// ErrInvalidTransition возвращается, когда переход подписки в запрошенный статус недопустим.
+var ErrInvalidTransition = errors.New("invalid status transition")
+
+// ErrValidation возвращается, когда входные данные не проходят проверку бизнес-правил.
+var ErrValidation = errors.New("validation failed")
+
 // SubscriptionService определяет интерфейс для бизнес-логики работы с подписками.
 type SubscriptionService interface {
 	Create(ctx context.Context, sub model.Subscription) (uuid.UUID, error)
 	GetByID(ctx context.Context, id uuid.UUID) (model.Subscription, error)
+	List(ctx context.Context, filter repository.SubscriptionFilter) ([]model.Subscription, error)
 	Update(ctx context.Context, id uuid.UUID, sub model.Subscription) error
 	Delete(ctx context.Context, id uuid.UUID) error
-	CalculateTotalCost(ctx context.Context, userID uuid.UUID, serviceName *string, startPeriod, endPeriod time.Time) (int, error)
+	Pause(ctx context.Context, id uuid.UUID) (model.Subscription, error)
+	Resume(ctx context.Context, id uuid.UUID) (model.Subscription, error)
+	Cancel(ctx context.Context, id uuid.UUID, immediate bool) (model.Subscription, error)
+	Restore(ctx context.Context, id uuid.UUID) (model.Subscription, error)
+	// ListTransitions возвращает историю статусов сразу нескольких подписок.
+	ListTransitions(ctx context.Context, subscriptionIDs []uuid.UUID) (map[uuid.UUID][]model.StatusTransition, error)
+	AddMember(ctx context.Context, subscriptionID uuid.UUID, m model.SubscriptionMember) (model.SubscriptionMember, error)
+	RemoveMember(ctx context.Context, subscriptionID, userID uuid.UUID, leftAt model.Date) error
+	ListMembers(ctx context.Context, subscriptionID uuid.UUID) ([]model.SubscriptionMember, error)
+	AddPriceChange(ctx context.Context, subscriptionID uuid.UUID, change model.PriceChange) (model.PriceChange, error)
+	DeletePriceChange(ctx context.Context, subscriptionID, id uuid.UUID) error
+	ListPriceChanges(ctx context.Context, subscriptionID uuid.UUID) ([]model.PriceChange, error)
+	CalculateTotalCost(ctx context.Context, filter repository.SubscriptionFilter, period model.CostPeriod) (model.CostSummary, error)
+	CalculateCostBreakdown(ctx context.Context, filter repository.SubscriptionFilter, groupBy string, period model.CostPeriod) (model.CostSummary, error)
+	Forecast(ctx context.Context, filter repository.SubscriptionFilter, months int) (model.Forecast, error)
+	FindDuplicates(ctx context.Context, userID uuid.UUID) ([]model.SubscriptionOverlap, error)
+	AddDiscount(ctx context.Context, subscriptionID uuid.UUID, d model.Discount) (model.Discount, error)
+	DeleteDiscount(ctx context.Context, subscriptionID, id uuid.UUID) error
+	ListDiscounts(ctx context.Context, subscriptionID uuid.UUID) ([]model.Discount, error)
+	CalculateCostLineItems(ctx context.Context, filter repository.SubscriptionFilter, period model.CostPeriod) (model.CostSummary, error)
+}
+
+// SubscriptionOptions задает настраиваемое поведение сервиса подписок.
+type SubscriptionOptions struct {
+	// StrictDuplicates запрещает создавать подписки, пересекающиеся с другими подписками пользователя на тот же сервис.
+	StrictDuplicates bool
 }
 
 type subscriptionService struct {
-	repo   repository.SubscriptionRepository
-	logger *slog.Logger
+	repo          repository.SubscriptionRepository
+	catalog       repository.CatalogRepository
+	categories    repository.CategoryRepository
+	users         repository.UserRepository
+	organizations repository.OrganizationRepository
+	policy        Policy
+	outbox        repository.OutboxRepository
+	tx            repository.TxManager
+	options       SubscriptionOptions
+	logger        *slog.Logger
 }
 
 // NewSubscriptionService создает новый экземпляр сервиса.
-func NewSubscriptionService(repo repository.SubscriptionRepository, logger *slog.Logger) SubscriptionService {
+func NewSubscriptionService(
+	repo repository.SubscriptionRepository,
+	catalog repository.CatalogRepository,
+	categories repository.CategoryRepository,
+	users repository.UserRepository,
+	organizations repository.OrganizationRepository,
+	policy Policy,
+	outbox repository.OutboxRepository,
+	tx repository.TxManager,
+	options SubscriptionOptions,
+	logger *slog.Logger,
+) SubscriptionService {
 	return &subscriptionService{
-		repo:   repo,
-		logger: logger,
+		repo:          repo,
+		catalog:       catalog,
+		categories:    categories,
+		users:         users,
+		organizations: organizations,
+		policy:        policy,
+		outbox:        outbox,
+		tx:            tx,
+		options:       options,
+		logger:        logger,
 	}
 }
 
 func (s *subscriptionService) Create(ctx context.Context, sub model.Subscription) (uuid.UUID, error) {
 	const op = "service.Create"
-	log := s.logger.With(
+	log := logging.FromContext(ctx, s.logger).With(
 		slog.String("op", op),
 		slog.String("user_id", sub.UserID.String()),
 	)
 
+	if err := s.policy.Authorize(ctx, model.PermissionSubscriptionsWrite); err != nil {
+		log.Warn("Действие запрещено политикой доступа", slog.String("error", err.Error()))
+		return uuid.Nil, err
+	}
+
 	log.Info("Создание подписки")
 
-	id, err := s.repo.Create(ctx, sub)
+	// Подписка может принадлежать только существующему пользователю. Его профиль задает
+	// валюту и часовой пояс подписки, если они не указаны в запросе; для рабочей подписки
+	// валюту по умолчанию задает организация.
+	owner, err := s.users.GetByID(ctx, sub.UserID)
+	if err != nil {
+		log.Warn("Не удалось получить владельца подписки", slog.String("error", err.Error()))
+		return uuid.Nil, err
+	}
+	org, err := s.checkOrganization(ctx, sub)
+	if err != nil {
+		log.Warn("Подписка не прошла проверку организации", slog.String("error", err.Error()))
+		return uuid.Nil, err
+	}
+	ctx = withUserTimeZone(ctx, owner)
+	if sub.Currency == "" {
+		sub.Currency = owner.DefaultCurrency
+		if org != nil {
+			sub.Currency = org.DefaultCurrency
+		}
+	}
+
+	resolveDates(ctx, &sub.StartDate, sub.EndDate, sub.TrialEndDate)
+	sub.Status = model.StatusActive
+	if sub.TrialEndDate != nil && sub.TrialEndDate.After(sub.StartDate) {
+		sub.Status = model.StatusTrialing
+	}
+
+	if err := normalizeBilling(&sub); err != nil {
+		log.Warn("Подписка не прошла проверку", slog.String("error", err.Error()))
+		return uuid.Nil, err
+	}
+
+	var id uuid.UUID
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.resolveService(ctx, &sub); err != nil {
+			return err
+		}
+
+		if err := s.checkDuplicates(ctx, log, sub); err != nil {
+			return err
+		}
+
+		var err error
+		id, err = s.repo.Create(ctx, sub)
+		if err != nil {
+			return err
+		}
+
+		err = s.repo.AddTransition(ctx, model.StatusTransition{
+			SubscriptionID: id,
+			ToStatus:       sub.Status,
+			EffectiveDate:  sub.StartDate,
+		})
+		if err != nil {
+			return err
+		}
+
+		if err := s.classify(ctx, id, sub.UserID, sub); err != nil {
+			return err
+		}
+
+		created, err := s.load(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		return s.outbox.Add(ctx, model.EventSubscriptionCreated, id, created)
+	})
 	if err != nil {
 		log.Error("Не удалось создать подписку в репозитории", slog.String("error", err.Error()))
 		return uuid.Nil, err
@@ -54,14 +189,19 @@ func (s *subscriptionService) Create(ctx context.Context, sub model.Subscription
 
 func (s *subscriptionService) GetByID(ctx context.Context, id uuid.UUID) (model.Subscription, error) {
 	const op = "service.GetByID"
-	log := s.logger.With(
+	log := logging.FromContext(ctx, s.logger).With(
 		slog.String("op", op),
 		slog.String("subscription_id", id.String()),
 	)
 
+	if err := s.policy.Authorize(ctx, model.PermissionSubscriptionsRead); err != nil {
+		log.Warn("Действие запрещено политикой доступа", slog.String("error", err.Error()))
+		return model.Subscription{}, err
+	}
+
 	log.Info("Получение подписки")
 
-	sub, err := s.repo.GetByID(ctx, id)
+	sub, err := s.load(ctx, id)
 	if err != nil {
 		log.Error("Не удалось получить подписку из репозитория", slog.String("error", err.Error()))
 		return model.Subscription{}, err
@@ -73,14 +213,66 @@ func (s *subscriptionService) GetByID(ctx context.Context, id uuid.UUID) (model.
 
 func (s *subscriptionService) Update(ctx context.Context, id uuid.UUID, sub model.Subscription) error {
 	const op = "service.Update"
-	log := s.logger.With(
+	log := logging.FromContext(ctx, s.logger).With(
 		slog.String("op", op),
 		slog.String("subscription_id", id.String()),
 	)
 
+	if err := s.policy.Authorize(ctx, model.PermissionSubscriptionsWrite); err != nil {
+		log.Warn("Действие запрещено политикой доступа", slog.String("error", err.Error()))
+		return err
+	}
+
 	log.Info("Обновление подписки")
 
-	err := s.repo.Update(ctx, id, sub)
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		existing, err := s.repo.GetByIDForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		owner, err := s.users.GetByID(ctx, existing.UserID)
+		if err != nil {
+			return err
+		}
+
+		// Даты толкуются в часовом поясе владельца, валюта, не указанная в запросе, не меняется.
+		resolveDates(withUserTimeZone(ctx, owner), &sub.StartDate, sub.EndDate, sub.TrialEndDate)
+		if sub.Currency == "" {
+			sub.Currency = existing.Currency
+		}
+		if err := normalizeBilling(&sub); err != nil {
+			return err
+		}
+
+		if err := s.resolveService(ctx, &sub); err != nil {
+			return err
+		}
+
+		candidate := sub
+		candidate.ID, candidate.UserID, candidate.CreatedAt = id, existing.UserID, existing.CreatedAt
+		if _, err := s.checkOrganization(ctx, candidate); err != nil {
+			return err
+		}
+		if err := s.checkDuplicates(ctx, log, candidate); err != nil {
+			return err
+		}
+
+		if err := s.repo.Update(ctx, id, sub); err != nil {
+			return err
+		}
+
+		if err := s.classify(ctx, id, existing.UserID, sub); err != nil {
+			return err
+		}
+
+		updated, err := s.load(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		return s.outbox.Add(ctx, model.EventSubscriptionUpdated, id, updated)
+	})
 	if err != nil {
 		log.Error("Не удалось обновить подписку в репозитории", slog.String("error", err.Error()))
 		return err
@@ -92,14 +284,30 @@ func (s *subscriptionService) Update(ctx context.Context, id uuid.UUID, sub mode
 
 func (s *subscriptionService) Delete(ctx context.Context, id uuid.UUID) error {
 	const op = "service.Delete"
-	log := s.logger.With(
+	log := logging.FromContext(ctx, s.logger).With(
 		slog.String("op", op),
 		slog.String("subscription_id", id.String()),
 	)
 
+	if err := s.policy.Authorize(ctx, model.PermissionSubscriptionsDelete); err != nil {
+		log.Warn("Действие запрещено политикой доступа", slog.String("error", err.Error()))
+		return err
+	}
+
 	log.Info("Удаление подписки")
 
-	err := s.repo.Delete(ctx, id)
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		deleted, err := s.load(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if err := s.repo.Delete(ctx, id); err != nil {
+			return err
+		}
+
+		return s.outbox.Add(ctx, model.EventSubscriptionDeleted, id, deleted)
+	})
 	if err != nil {
 		log.Error("Не удалось удалить подписку в репозитории", slog.String("error", err.Error()))
 		return err
@@ -109,68 +317,613 @@ func (s *subscriptionService) Delete(ctx context.Context, id uuid.UUID) error {
 	return nil
 }
 
+// Pause приостанавливает подписку с сегодняшнего дня. Месяцы паузы не оплачиваются.
+func (s *subscriptionService) Pause(ctx context.Context, id uuid.UUID) (model.Subscription, error) {
+	return s.transition(ctx, "service.Pause", model.PermissionSubscriptionsWrite, id,
+		func(_ context.Context, sub model.Subscription, current model.SubscriptionStatus, day model.Date) (model.SubscriptionStatus, *model.Date, error) {
+			return model.StatusPaused, sub.EndDate, nil
+		})
+}
+
+// Resume возобновляет приостановленную подписку или отменяет запланированную отмену.
+// При отмене запланированной отмены возвращается дата окончания, действовавшая до нее.
+func (s *subscriptionService) Resume(ctx context.Context, id uuid.UUID) (model.Subscription, error) {
+	return s.transition(ctx, "service.Resume", model.PermissionSubscriptionsWrite, id,
+		func(ctx context.Context, sub model.Subscription, current model.SubscriptionStatus, day model.Date) (model.SubscriptionStatus, *model.Date, error) {
+			switch current {
+			case model.StatusPaused:
+				if sub.TrialEndDate != nil && sub.TrialEndDate.After(day) {
+					return model.StatusTrialing, sub.EndDate, nil
+				}
+				return model.StatusActive, sub.EndDate, nil
+			case model.StatusCancelScheduled:
+				endDate, err := s.endDateBeforeCancel(ctx, sub.ID, day)
+				if err != nil {
+					return "", nil, err
+				}
+				return model.StatusActive, endDate, nil
+			default:
+				return "", nil, fmt.Errorf("%w: cannot resume subscription in status %s", ErrInvalidTransition, current)
+			}
+		})
+}
+
+// Cancel отменяет подписку. По умолчанию подписка остается активной до конца текущего периода
+// (конца месяца или пробного периода), при immediate она завершается сегодняшним днем.
+func (s *subscriptionService) Cancel(ctx context.Context, id uuid.UUID, immediate bool) (model.Subscription, error) {
+	return s.transition(ctx, "service.Cancel", model.PermissionSubscriptionsWrite, id,
+		func(_ context.Context, sub model.Subscription, current model.SubscriptionStatus, day model.Date) (model.SubscriptionStatus, *model.Date, error) {
+			if immediate {
+				return model.StatusEnded, &day, nil
+			}
+
+			periodEnd := day.EndOfMonth()
+			if current == model.StatusTrialing && sub.TrialEndDate != nil {
+				periodEnd = sub.TrialEndDate.AddDate(0, 0, -1)
+			}
+			if sub.EndDate != nil && sub.EndDate.Before(periodEnd) {
+				periodEnd = *sub.EndDate
+			}
+			return model.StatusCancelScheduled, &periodEnd, nil
+		})
+}
+
+// Restore возвращает завершенную подписку в активный статус. Подписке возвращается дата окончания,
+// действовавшая до отмены, если она еще не наступила, иначе дата окончания снимается.
+func (s *subscriptionService) Restore(ctx context.Context, id uuid.UUID) (model.Subscription, error) {
+	return s.transition(ctx, "service.Restore", model.PermissionSubscriptionsRestore, id,
+		func(ctx context.Context, sub model.Subscription, current model.SubscriptionStatus, day model.Date) (model.SubscriptionStatus, *model.Date, error) {
+			if current != model.StatusEnded {
+				return "", nil, fmt.Errorf("%w: cannot restore subscription in status %s", ErrInvalidTransition, current)
+			}
+			endDate, err := s.endDateBeforeCancel(ctx, sub.ID, day)
+			if err != nil {
+				return "", nil, err
+			}
+			return model.StatusActive, endDate, nil
+		})
+}
+
+// endDateBeforeCancel возвращает дату окончания подписки, действовавшую до последней отмены.
+// Если последний переход не отмена (подписка завершилась по своей дате окончания) или прежняя
+// дата уже прошла, возвращает nil.
+func (s *subscriptionService) endDateBeforeCancel(ctx context.Context, id uuid.UUID, day model.Date) (*model.Date, error) {
+	transitions, err := s.repo.ListTransitions(ctx, []uuid.UUID{id})
+	if err != nil {
+		return nil, err
+	}
+
+	history := transitions[id]
+	if len(history) == 0 {
+		return nil, nil
+	}
+	last := history[len(history)-1]
+	if last.ToStatus != model.StatusCancelScheduled && last.ToStatus != model.StatusEnded {
+		return nil, nil
+	}
+	if last.PreviousEndDate == nil || last.PreviousEndDate.Before(day) {
+		return nil, nil
+	}
+
+	return last.PreviousEndDate, nil
+}
+
+// ListTransitions возвращает переходы статусов подписок subscriptionIDs в хронологическом порядке.
+// Подписки, недоступные пользователю запроса, пропускаются.
+func (s *subscriptionService) ListTransitions(ctx context.Context, subscriptionIDs []uuid.UUID) (map[uuid.UUID][]model.StatusTransition, error) {
+	const op = "service.ListTransitions"
+	log := logging.FromContext(ctx, s.logger).With(slog.String("op", op), slog.Int("subscriptions", len(subscriptionIDs)))
+
+	if err := s.policy.Authorize(ctx, model.PermissionSubscriptionsRead); err != nil {
+		log.Warn("Действие запрещено политикой доступа", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	transitions, err := s.repo.ListTransitions(ctx, subscriptionIDs)
+	if err != nil {
+		log.Error("Не удалось получить историю статусов", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return transitions, nil
+}
+
+// transitionFunc вычисляет новый статус и дату окончания подписки для перехода.
+// ctx - контекст транзакции перехода.
+type transitionFunc func(ctx context.Context, sub model.Subscription, current model.SubscriptionStatus, day model.Date) (model.SubscriptionStatus, *model.Date, error)
+
+// transition атомарно переводит подписку в новый статус, сохраняет переход и событие об изменении.
+// Переход требует права permission.
+func (s *subscriptionService) transition(ctx context.Context, op string, permission model.Permission, id uuid.UUID, next transitionFunc) (model.Subscription, error) {
+	log := logging.FromContext(ctx, s.logger).With(
+		slog.String("op", op),
+		slog.String("subscription_id", id.String()),
+	)
+
+	if err := s.policy.Authorize(ctx, permission); err != nil {
+		log.Warn("Действие запрещено политикой доступа", slog.String("error", err.Error()))
+		return model.Subscription{}, err
+	}
+
+	log.Info("Изменение статуса подписки")
+
+	var updated model.Subscription
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		sub, err := s.repo.GetByIDForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		// Дата перехода - сегодняшний день в часовом поясе владельца.
+		zoned, err := s.inOwnerZone(ctx, sub.UserID)
+		if err != nil {
+			return err
+		}
+		day := today(zoned)
+
+		current := sub.StatusAt(day)
+		status, endDate, err := next(ctx, sub, current, day)
+		if err != nil {
+			return err
+		}
+		if !current.CanTransitionTo(status) {
+			return fmt.Errorf("%w: %s -> %s", ErrInvalidTransition, current, status)
+		}
+
+		if err := s.repo.SetStatus(ctx, id, status, endDate); err != nil {
+			return err
+		}
+
+		err = s.repo.AddTransition(ctx, model.StatusTransition{
+			SubscriptionID:  id,
+			FromStatus:      current,
+			ToStatus:        status,
+			EffectiveDate:   day,
+			PreviousEndDate: sub.EndDate,
+		})
+		if err != nil {
+			return err
+		}
+
+		updated, err = s.load(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		return s.outbox.Add(ctx, model.EventStatusChanged, id, updated)
+	})
+	if err != nil {
+		log.Error("Не удалось изменить статус подписки", slog.String("error", err.Error()))
+		return model.Subscription{}, err
+	}
+
+	log.Info("Статус подписки успешно изменен", slog.String("status", string(updated.Status)))
+	return updated, nil
+}
+
+// load получает подписку с категориями, метками и фактическим статусом на сегодня.
+func (s *subscriptionService) load(ctx context.Context, id uuid.UUID) (model.Subscription, error) {
+	sub, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return model.Subscription{}, err
+	}
+
+	subscriptions := []model.Subscription{sub}
+	if err := s.enrich(ctx, subscriptions); err != nil {
+		return model.Subscription{}, err
+	}
+
+	return subscriptions[0], nil
+}
+
+// normalizeBilling подставляет ежемесячную оплату и валюту по умолчанию, если они не указаны,
+// и приводит цену к точности валюты.
+func normalizeBilling(sub *model.Subscription) error {
+	if sub.BillingPeriod == "" {
+		sub.BillingPeriod = model.BillingMonthly
+	}
+	if !sub.BillingPeriod.Valid() {
+		return fmt.Errorf("%w: billing_period must be monthly or yearly", ErrValidation)
+	}
+	if sub.Price.Sign() < 0 {
+		return fmt.Errorf("%w: price must not be negative", ErrValidation)
+	}
+	if err := normalizeCurrency(&sub.Currency); err != nil {
+		return err
+	}
+	return normalizeAmount(&sub.Price, sub.Currency, "price")
+}
+
+// normalizeCostPeriod подставляет помесячный расчет, если способ не указан, определяет календарные дни
+// границ периода в часовом поясе пользователя и проверяет их. При помесячном расчете период
+// расширяется до первого дня месяца From и последнего дня месяца To.
+func normalizeCostPeriod(ctx context.Context, period *model.CostPeriod) error {
+	if period.Proration == "" {
+		period.Proration = model.ProrationMonthly
+	}
+	if !period.Proration.Valid() {
+		return fmt.Errorf("%w: proration must be monthly or daily", ErrValidation)
+	}
+
+	resolveDates(ctx, &period.From, &period.To)
+	if period.Proration == model.ProrationMonthly {
+		period.From, period.To = period.From.StartOfMonth(), period.To.EndOfMonth()
+	}
+
+	if period.To.Before(period.From) {
+		return fmt.Errorf("%w: end of the period must not be before its start", ErrValidation)
+	}
+	return nil
+}
+
+// checkOrganization проверяет, что рабочую подписку заводит участник ее организации, и возвращает
+// организацию. Для личной подписки возвращает nil.
+func (s *subscriptionService) checkOrganization(ctx context.Context, sub model.Subscription) (*model.Organization, error) {
+	if sub.OrganizationID == nil {
+		return nil, nil
+	}
+
+	org, err := s.organizations.GetByID(ctx, *sub.OrganizationID)
+	if errors.Is(err, repository.ErrOrganizationNotFound) {
+		return nil, fmt.Errorf("%w: organization not found", ErrValidation)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.organizations.GetMember(ctx, org.ID, sub.UserID)
+	if errors.Is(err, repository.ErrOrganizationMemberNotFound) {
+		return nil, fmt.Errorf("%w: user is not a member of the organization", ErrValidation)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &org, nil
+}
+
+// memberNames возвращает отображаемые имена пользователей, управляющих подписками.
+func (s *subscriptionService) memberNames(ctx context.Context, subscriptions []model.Subscription) (map[uuid.UUID]string, error) {
+	names := make(map[uuid.UUID]string)
+	for _, sub := range subscriptions {
+		if _, ok := names[sub.UserID]; ok {
+			continue
+		}
+		u, err := s.users.GetByID(ctx, sub.UserID)
+		if err != nil {
+			return nil, err
+		}
+		names[sub.UserID] = u.DisplayName
+	}
+	return names, nil
+}
+
+// payer возвращает пользователя, чьи расходы по подписке считаются. В отчете организации это
+// участник, который управляет рабочей подпиской, иначе - пользователь фильтра.
+func payer(filter repository.SubscriptionFilter, sub model.Subscription) uuid.UUID {
+	if filter.OrganizationID != nil {
+		return sub.UserID
+	}
+	return filter.UserID
+}
+
+// resolveService связывает подписку с каталогом: по service_id или по названию через псевдонимы.
+// Найденный сервис задает каноническое название, цена тарифа подставляется, если цена не указана.
+// Подписки на сервисы вне каталога сохраняются со строковым названием как есть.
+func (s *subscriptionService) resolveService(ctx context.Context, sub *model.Subscription) error {
+	var (
+		entry model.CatalogService
+		err   error
+	)
+
+	if sub.ServiceID != nil {
+		entry, err = s.catalog.GetByID(ctx, *sub.ServiceID)
+		if errors.Is(err, repository.ErrServiceNotFound) {
+			return fmt.Errorf("%w: unknown service_id", ErrValidation)
+		}
+	} else {
+		entry, err = s.catalog.FindByAlias(ctx, model.NormalizeServiceName(sub.ServiceName))
+		if errors.Is(err, repository.ErrServiceNotFound) {
+			if sub.PlanID != nil {
+				return fmt.Errorf("%w: plan_id requires a service from the catalog", ErrValidation)
+			}
+			return nil
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	sub.ServiceID = &entry.ID
+	sub.ServiceName = entry.Name
+
+	if sub.PlanID == nil {
+		return nil
+	}
+
+	for _, plan := range entry.Plans {
+		if plan.ID == *sub.PlanID {
+			if sub.Price.IsZero() {
+				sub.Price, sub.Currency = plan.Price, plan.Currency
+			}
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: plan_id does not belong to service %s", ErrValidation, entry.Name)
+}
+
+// resolveFilter заменяет фильтр по названию сервиса фильтром по ID каталога, если название
+// совпадает с каноническим именем или псевдонимом. Иначе сохраняется сравнение строк.
+func (s *subscriptionService) resolveFilter(ctx context.Context, filter repository.SubscriptionFilter) (repository.SubscriptionFilter, error) {
+	if filter.ServiceName == nil || filter.ServiceID != nil {
+		return filter, nil
+	}
+
+	entry, err := s.catalog.FindByAlias(ctx, model.NormalizeServiceName(*filter.ServiceName))
+	if errors.Is(err, repository.ErrServiceNotFound) {
+		return filter, nil
+	}
+	if err != nil {
+		return filter, err
+	}
+
+	filter.ServiceID = &entry.ID
+	filter.ServiceName = nil
+	return filter, nil
+}
+
+// List возвращает подписки пользователя с учетом фильтров по сервису, категории и метке.
+func (s *subscriptionService) List(ctx context.Context, filter repository.SubscriptionFilter) ([]model.Subscription, error) {
+	const op = "service.List"
+	log := logging.FromContext(ctx, s.logger).With(
+		slog.String("op", op),
+		slog.String("user_id", filter.UserID.String()),
+	)
+
+	if err := s.policy.Authorize(ctx, model.PermissionSubscriptionsRead); err != nil {
+		log.Warn("Действие запрещено политикой доступа", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	log.Info("Получение списка подписок")
+
+	filter, err := s.resolveFilter(ctx, filter)
+	if err != nil {
+		log.Error("Не удалось разобрать фильтр по сервису", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	subscriptions, err := s.repo.List(ctx, filter)
+	if err != nil {
+		log.Error("Не удалось получить список подписок", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	if err := s.enrich(ctx, subscriptions); err != nil {
+		log.Error("Не удалось получить категории и метки подписок", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	log.Info("Список подписок успешно получен", slog.Int("count", len(subscriptions)))
+	return subscriptions, nil
+}
+
 // CalculateTotalCost вычисляет суммарную стоимость подписок за период.
-func (s *subscriptionService) CalculateTotalCost(ctx context.Context, userID uuid.UUID, serviceName *string, startPeriod, endPeriod time.Time) (int, error) {
+// Подписки в разных валютах не складываются: такой запрос нужно ограничить фильтром по валюте.
+func (s *subscriptionService) CalculateTotalCost(ctx context.Context, filter repository.SubscriptionFilter, period model.CostPeriod) (model.CostSummary, error) {
 	const op = "service.CalculateTotalCost"
-	log := s.logger.With(
+	log := logging.FromContext(ctx, s.logger).With(
 		slog.String("op", op),
-		slog.String("user_id", userID.String()),
+		slog.String("user_id", filter.UserID.String()),
 	)
 
+	ctx, err := s.authorizeReport(ctx, filter)
+	if err != nil {
+		log.Warn("Отчет запрещен политикой доступа", slog.String("error", err.Error()))
+		return model.CostSummary{}, err
+	}
+
 	log.Info("Начат расчет суммарной стоимости")
 
-	// 1. Получаем все релевантные подписки из базы
-	subscriptions, err := s.repo.ListByUserID(ctx, userID, serviceName)
+	_, _, summary, err := s.subscriptionCosts(ctx, filter, period)
 	if err != nil {
-		log.Error("Не удалось получить список подписок", slog.String("error", err.Error()))
-		return 0, err
+		log.Error("Не удалось рассчитать стоимость подписок", slog.String("error", err.Error()))
+		return model.CostSummary{}, err
 	}
 
-	totalCost := 0
+	log.Info("Расчет успешно завершен", slog.String("total_cost", summary.TotalCost.String()))
+	return summary, nil
+}
 
-	// 2. Итерируемся по каждой подписке и считаем ее вклад
-	for _, sub := range subscriptions {
-		// Определяем фактический конец подписки. Если его нет, считаем, что она активна до конца нашего периода.
-		subEnd := endPeriod
-		if sub.EndDate != nil && sub.EndDate.Before(endPeriod) {
-			subEnd = *sub.EndDate
+// CalculateCostBreakdown вычисляет суммарную стоимость и ее разбивку по категориям, меткам или участникам.
+// Подписка с несколькими категориями (метками) входит в каждую из них, поэтому сумма групп
+// может превышать общий итог. Подписки без категорий (меток) попадают в группу с пустым ключом.
+func (s *subscriptionService) CalculateCostBreakdown(ctx context.Context, filter repository.SubscriptionFilter, groupBy string, period model.CostPeriod) (model.CostSummary, error) {
+	const op = "service.CalculateCostBreakdown"
+	log := logging.FromContext(ctx, s.logger).With(
+		slog.String("op", op),
+		slog.String("user_id", filter.UserID.String()),
+		slog.String("group_by", groupBy),
+	)
+
+	ctx, err := s.authorizeReport(ctx, filter)
+	if err != nil {
+		log.Warn("Отчет запрещен политикой доступа", slog.String("error", err.Error()))
+		return model.CostSummary{}, err
+	}
+
+	log.Info("Начат расчет стоимости с разбивкой")
+
+	if groupBy != model.GroupByCategory && groupBy != model.GroupByTag && groupBy != model.GroupByMember {
+		return model.CostSummary{}, fmt.Errorf("%w: group_by must be category, tag or member", ErrValidation)
+	}
+
+	subscriptions, costs, summary, err := s.subscriptionCosts(ctx, filter, period)
+	if err != nil {
+		log.Error("Не удалось рассчитать стоимость подписок", slog.String("error", err.Error()))
+		return model.CostSummary{}, err
+	}
+
+	if err := s.enrich(ctx, subscriptions); err != nil {
+		log.Error("Не удалось получить категории и метки подписок", slog.String("error", err.Error()))
+		return model.CostSummary{}, err
+	}
+
+	var categoryNames, memberNames map[uuid.UUID]string
+	switch groupBy {
+	case model.GroupByCategory:
+		categoryNames, err = s.categoryNames(ctx, subscriptions)
+		if err != nil {
+			log.Error("Не удалось получить категории", slog.String("error", err.Error()))
+			return model.CostSummary{}, err
+		}
+	case model.GroupByMember:
+		memberNames, err = s.memberNames(ctx, subscriptions)
+		if err != nil {
+			log.Error("Не удалось получить участников", slog.String("error", err.Error()))
+			return model.CostSummary{}, err
 		}
+	}
 
-		// Находим период пересечения [sub.StartDate, subEnd] и [startPeriod, endPeriod]
-		overlapStart := maxTime(sub.StartDate, startPeriod)
-		overlapEnd := minTime(subEnd, endPeriod)
+	groups := make(map[string]*model.CostGroup)
+	var order []string
+	add := func(key, name string, cost model.Money) error {
+		group, ok := groups[key]
+		if !ok {
+			group = &model.CostGroup{Key: key, Name: name}
+			groups[key] = group
+			order = append(order, key)
+		}
+		var err error
+		group.TotalCost, err = group.TotalCost.Add(cost)
+		return err
+	}
 
-		// Если нет пересечения, пропускаем подписку
-		if overlapStart.After(overlapEnd) {
-			continue
+	for _, sub := range subscriptions {
+		cost := costs[sub.ID]
+
+		var keys, names []string
+		switch groupBy {
+		case model.GroupByCategory:
+			for _, id := range sub.CategoryIDs {
+				keys, names = append(keys, id.String()), append(names, categoryNames[id])
+			}
+		case model.GroupByTag:
+			for _, tag := range sub.Tags {
+				keys, names = append(keys, strings.ToLower(tag)), append(names, tag)
+			}
+		case model.GroupByMember:
+			keys, names = []string{sub.UserID.String()}, []string{memberNames[sub.UserID]}
+		}
+		if len(keys) == 0 {
+			keys, names = []string{""}, []string{""}
 		}
 
-		// Считаем количество полных месяцев в периоде пересечения.
-		months := 0
-		currentMonth := time.Date(overlapStart.Year(), overlapStart.Month(), 1, 0, 0, 0, 0, time.UTC)
-		for !currentMonth.After(overlapEnd) {
-			months++
-			currentMonth = currentMonth.AddDate(0, 1, 0)
+		for i := range keys {
+			if err := add(keys[i], names[i], cost); err != nil {
+				log.Error("Не удалось рассчитать стоимость группы", slog.String("error", err.Error()))
+				return model.CostSummary{}, err
+			}
 		}
+	}
 
-		totalCost += sub.Price * months
+	summary.Breakdown = make([]model.CostGroup, 0, len(order))
+	for _, key := range order {
+		summary.Breakdown = append(summary.Breakdown, *groups[key])
 	}
+	breakdown := summary.Breakdown
+	sort.SliceStable(breakdown, func(i, j int) bool { return breakdown[i].TotalCost.Cmp(breakdown[j].TotalCost) > 0 })
 
-	log.Info("Расчет успешно завершен", slog.Int("total_cost", totalCost))
-	return totalCost, nil
+	log.Info("Расчет с разбивкой успешно завершен",
+		slog.String("total_cost", summary.TotalCost.String()), slog.Int("groups", len(breakdown)))
+	return summary, nil
 }
 
-// Вспомогательные функции для работы с датами (можно вынести в отдельный пакет utils)
-func maxTime(a, b time.Time) time.Time {
-	if a.After(b) {
-		return a
+// subscriptionCosts выбирает подписки по фильтру и считает вклад каждой из них в расходы пользователя за период.
+// Пробный период и паузы не оплачиваются, неполный месяц считается целиком,
+// в совместных подписках пользователю начисляется только его доля.
+// Итог и валюта подписок возвращаются в summary.
+func (s *subscriptionService) subscriptionCosts(ctx context.Context, filter repository.SubscriptionFilter, period model.CostPeriod) ([]model.Subscription, map[uuid.UUID]model.Money, model.CostSummary, error) {
+	ctx, err := s.inOwnerZone(ctx, filter.UserID)
+	if err != nil {
+		return nil, nil, model.CostSummary{}, err
+	}
+	if err := normalizeCostPeriod(ctx, &period); err != nil {
+		return nil, nil, model.CostSummary{}, err
 	}
-	return b
+
+	filter, err = s.resolveFilter(ctx, filter)
+	if err != nil {
+		return nil, nil, model.CostSummary{}, err
+	}
+
+	// 1. Получаем все релевантные подписки из базы
+	subscriptions, err := s.repo.List(ctx, filter)
+	if err != nil {
+		return nil, nil, model.CostSummary{}, err
+	}
+
+	var summary model.CostSummary
+	summary.Currency, err = commonCurrency(subscriptions)
+	if err != nil {
+		return nil, nil, model.CostSummary{}, err
+	}
+
+	inputs, err := s.loadCostInputs(ctx, subscriptions)
+	if err != nil {
+		return nil, nil, model.CostSummary{}, err
+	}
+
+	// 2. Итерируемся по каждой подписке и считаем вклад пользователя.
+	costs := make(map[uuid.UUID]model.Money, len(subscriptions))
+	for _, sub := range subscriptions {
+		list, err := charges(sub, inputs, payer(filter, sub), period)
+		if err != nil {
+			return nil, nil, model.CostSummary{}, err
+		}
+		if costs[sub.ID], err = sumCharges(costs[sub.ID], list); err != nil {
+			return nil, nil, model.CostSummary{}, err
+		}
+		if summary.TotalCost, err = summary.TotalCost.Add(costs[sub.ID]); err != nil {
+			return nil, nil, model.CostSummary{}, err
+		}
+	}
+
+	return subscriptions, costs, summary, nil
 }
 
-func minTime(a, b time.Time) time.Time {
-	if a.Before(b) {
-		return a
+// loadCostInputs загружает историю статусов, участников, изменения цен и скидки подписок.
+func (s *subscriptionService) loadCostInputs(ctx context.Context, subscriptions []model.Subscription) (costInputs, error) {
+	ids := make([]uuid.UUID, 0, len(subscriptions))
+	for _, sub := range subscriptions {
+		ids = append(ids, sub.ID)
 	}
-	return b
+
+	var (
+		inputs costInputs
+		err    error
+	)
+
+	inputs.transitions, err = s.repo.ListTransitions(ctx, ids)
+	if err != nil {
+		return costInputs{}, err
+	}
+
+	inputs.members, err = s.repo.ListMembers(ctx, ids)
+	if err != nil {
+		return costInputs{}, err
+	}
+
+	inputs.prices, err = s.repo.ListPriceChanges(ctx, ids)
+	if err != nil {
+		return costInputs{}, err
+	}
+
+	inputs.discounts, err = s.repo.ListDiscounts(ctx, ids)
+	if err != nil {
+		return costInputs{}, err
+	}
+
+	return inputs, nil
 }
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/vasiliy-maslov/go-subscription-service/internal/logging"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/model"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/repository"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/stream"
+
+	"github.com/google/uuid"
+)
+
+// ChangeFeed - источник изменений подписок, общий для всех клиентов потока. Реализуется stream.Hub.
+type ChangeFeed interface {
+	Subscribe(userID uuid.UUID, lastEventID *int64) *stream.Subscription
+}
+
+// StreamService определяет интерфейс потока изменений подписок пользователя.
+type StreamService interface {
+	// Subscribe подписывает на изменения подписок, которыми владеет пользователь userID.
+	// lastEventID - идентификатор последнего полученного события при переподключении.
+	// Подписку нужно закрыть вызовом Close.
+	Subscribe(ctx context.Context, userID uuid.UUID, lastEventID *int64) (*stream.Subscription, error)
+}
+
+type streamService struct {
+	feed   ChangeFeed
+	policy Policy
+	logger *slog.Logger
+}
+
+// NewStreamService создает новый экземпляр сервиса потока изменений подписок.
+func NewStreamService(feed ChangeFeed, policy Policy, logger *slog.Logger) StreamService {
+	return &streamService{
+		feed:   feed,
+		policy: policy,
+		logger: logger,
+	}
+}
+
+func (s *streamService) Subscribe(ctx context.Context, userID uuid.UUID, lastEventID *int64) (*stream.Subscription, error) {
+	const op = "stream.Subscribe"
+	log := logging.FromContext(ctx, s.logger).With(slog.String("op", op), slog.String("user_id", userID.String()))
+
+	if userID == uuid.Nil {
+		return nil, fmt.Errorf("%w: user_id is required", ErrValidation)
+	}
+	if err := s.policy.Authorize(ctx, model.PermissionSubscriptionsRead); err != nil {
+		log.Warn("Действие запрещено политикой доступа", slog.String("error", err.Error()))
+		return nil, err
+	}
+	if !repository.InScope(ctx, userID) {
+		return nil, repository.ErrTenantMismatch
+	}
+
+	sub := s.feed.Subscribe(userID, lastEventID)
+	log.Info("Клиент подписан на изменения подписок", slog.Int("replay", len(sub.Replay)), slog.Bool("reset", sub.Reset))
+
+	return sub, nil
+}
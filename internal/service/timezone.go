@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/vasiliy-maslov/go-subscription-service/internal/model"
+
+	"github.com/google/uuid"
+)
+
+type timeZoneKey struct{}
+
+// WithTimeZone возвращает контекст с часовым поясом пользователя. В этом поясе определяется
+// текущая дата и толкуются даты, переданные как момент времени. Без пояса используется UTC.
+func WithTimeZone(ctx context.Context, loc *time.Location) context.Context {
+	return context.WithValue(ctx, timeZoneKey{}, loc)
+}
+
+// withUserTimeZone подставляет часовой пояс из профиля пользователя, если запрос не задал его явно.
+func withUserTimeZone(ctx context.Context, u model.User) context.Context {
+	if _, ok := ctx.Value(timeZoneKey{}).(*time.Location); ok {
+		return ctx
+	}
+	loc, err := time.LoadLocation(u.TimeZone)
+	if err != nil {
+		return ctx
+	}
+	return WithTimeZone(ctx, loc)
+}
+
+// TimeZoneFrom возвращает часовой пояс пользователя из контекста или UTC.
+func TimeZoneFrom(ctx context.Context) *time.Location {
+	if loc, ok := ctx.Value(timeZoneKey{}).(*time.Location); ok && loc != nil {
+		return loc
+	}
+	return time.UTC
+}
+
+// today возвращает текущую дату в часовом поясе пользователя.
+func today(ctx context.Context) model.Date {
+	return model.DateOf(time.Now(), TimeZoneFrom(ctx))
+}
+
+// resolveDates определяет календарные дни дат, переданных как момент времени, в часовом поясе
+// пользователя: "2024-01-31T23:30:00-05:00" для пользователя в UTC+10 - это 1 февраля.
+// Календарные даты (YYYY-MM-DD) не меняются. nil пропускается.
+func resolveDates(ctx context.Context, dates ...*model.Date) {
+	loc := TimeZoneFrom(ctx)
+	for _, date := range dates {
+		if date != nil {
+			*date = date.Resolve(loc)
+		}
+	}
+}
+
+// inOwnerZone возвращает контекст с часовым поясом из профиля пользователя userID, если запрос
+// не задал пояс явно. В этом поясе толкуются даты и границы периодов подписок пользователя.
+// Для uuid.Nil (например, отчета по организации) контекст не меняется.
+func (s *subscriptionService) inOwnerZone(ctx context.Context, userID uuid.UUID) (context.Context, error) {
+	if _, ok := ctx.Value(timeZoneKey{}).(*time.Location); ok || userID == uuid.Nil {
+		return ctx, nil
+	}
+	owner, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return withUserTimeZone(ctx, owner), nil
+}
+
+// inSubscriptionZone возвращает контекст с часовым поясом владельца подписки id, если запрос
+// не задал пояс явно.
+func (s *subscriptionService) inSubscriptionZone(ctx context.Context, id uuid.UUID) (context.Context, error) {
+	if _, ok := ctx.Value(timeZoneKey{}).(*time.Location); ok {
+		return ctx, nil
+	}
+	sub, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.inOwnerZone(ctx, sub.UserID)
+}
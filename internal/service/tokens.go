@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/vasiliy-maslov/go-subscription-service/internal/logging"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidToken возвращается, когда токен пользователя поврежден, подписан другим ключом или истек.
+var ErrInvalidToken = errors.New("invalid token")
+
+// minTokenSecretLength - минимальная длина ключа подписи токенов: для HMAC-SHA256 ключ короче
+// размера хеша ослабляет подпись.
+const minTokenSecretLength = 32
+
+// tokenHeader - заголовок JWT. Другие алгоритмы, включая "none", не принимаются.
+var tokenHeader = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+// tokenClaims - содержимое токена: пользователь, время выпуска и окончания действия (Unix-время в секундах).
+type tokenClaims struct {
+	Subject   string `json:"sub"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// IsAPIKey сообщает, что значение заголовка Authorization - API-ключ (sk_<prefix>_<secret>),
+// а не токен пользователя.
+func IsAPIKey(raw string) bool {
+	return strings.HasPrefix(raw, apiKeyScheme+"_")
+}
+
+// TokenService определяет интерфейс выпуска и проверки токенов пользователей. Токен - JWT
+// с подписью HS256, в котором sub - ID пользователя; он подтверждает, от имени какого
+// пользователя выполняется запрос.
+type TokenService interface {
+	// Issue выпускает токен пользователя userID, действующий ttl.
+	Issue(userID uuid.UUID, ttl time.Duration) (string, error)
+	// Authenticate проверяет подпись и срок действия токена и возвращает ID пользователя.
+	Authenticate(ctx context.Context, token string) (uuid.UUID, error)
+}
+
+type tokenService struct {
+	secret []byte
+	logger *slog.Logger
+}
+
+// NewTokenService создает сервис токенов с ключом подписи secret. Пустой ключ отключает токены:
+// любой токен отклоняется, и аутентифицироваться можно только API-ключом.
+func NewTokenService(secret string, logger *slog.Logger) (TokenService, error) {
+	if secret != "" && len(secret) < minTokenSecretLength {
+		return nil, fmt.Errorf("token secret must be at least %d bytes long", minTokenSecretLength)
+	}
+	return &tokenService{secret: []byte(secret), logger: logger}, nil
+}
+
+func (s *tokenService) Issue(userID uuid.UUID, ttl time.Duration) (string, error) {
+	if len(s.secret) == 0 {
+		return "", errors.New("token secret is not configured")
+	}
+	if userID == uuid.Nil || ttl <= 0 {
+		return "", fmt.Errorf("%w: user and a positive lifetime are required", ErrValidation)
+	}
+
+	now := time.Now()
+	payload, err := json.Marshal(tokenClaims{
+		Subject:   userID.String(),
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(ttl).Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	signed := tokenHeader + "." + base64.RawURLEncoding.EncodeToString(payload)
+	return signed + "." + s.sign(signed), nil
+}
+
+func (s *tokenService) Authenticate(ctx context.Context, token string) (uuid.UUID, error) {
+	const op = "tokens.Authenticate"
+	log := logging.FromContext(ctx, s.logger).With(slog.String("op", op))
+
+	if len(s.secret) == 0 {
+		log.Warn("Токены пользователей отключены: не задан ключ подписи")
+		return uuid.Nil, ErrInvalidToken
+	}
+
+	header, rest, _ := strings.Cut(token, ".")
+	payload, signature, _ := strings.Cut(rest, ".")
+	if header != tokenHeader || payload == "" {
+		log.Warn("Токен поврежден или подписан другим алгоритмом")
+		return uuid.Nil, ErrInvalidToken
+	}
+	if !hmac.Equal([]byte(signature), []byte(s.sign(header+"."+payload))) {
+		log.Warn("Подпись токена не совпадает")
+		return uuid.Nil, ErrInvalidToken
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return uuid.Nil, ErrInvalidToken
+	}
+	var claims tokenClaims
+	if err := json.Unmarshal(raw, &claims); err != nil {
+		return uuid.Nil, ErrInvalidToken
+	}
+
+	if claims.ExpiresAt == 0 || !time.Now().Before(time.Unix(claims.ExpiresAt, 0)) {
+		log.Warn("Срок действия токена истек")
+		return uuid.Nil, ErrInvalidToken
+	}
+	userID, err := uuid.Parse(claims.Subject)
+	if err != nil || userID == uuid.Nil {
+		log.Warn("В токене нет пользователя")
+		return uuid.Nil, ErrInvalidToken
+	}
+
+	return userID, nil
+}
+
+// sign возвращает подпись HMAC-SHA256 строки signed в кодировке base64url.
+func (s *tokenService) sign(signed string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(signed))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const testTokenSecret = "test-token-secret-of-at-least-32-bytes"
+
+func newTestTokenService(t *testing.T, secret string) TokenService {
+	t.Helper()
+	tokens, err := NewTokenService(secret, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("NewTokenService() error = %v", err)
+	}
+	return tokens
+}
+
+func TestTokenServiceAuthenticate(t *testing.T) {
+	userID := uuid.New()
+	tokens := newTestTokenService(t, testTokenSecret)
+
+	valid, err := tokens.Issue(userID, time.Hour)
+	if err != nil {
+		t.Fatalf("Issue() error = %v", err)
+	}
+	expired, err := tokens.Issue(userID, time.Nanosecond)
+	if err != nil {
+		t.Fatalf("Issue() error = %v", err)
+	}
+	time.Sleep(time.Millisecond)
+	foreign, err := newTestTokenService(t, strings.Repeat("x", minTokenSecretLength)).Issue(userID, time.Hour)
+	if err != nil {
+		t.Fatalf("Issue() error = %v", err)
+	}
+
+	parts := strings.Split(valid, ".")
+	otherUser := base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"` + uuid.NewString() + `","exp":9999999999}`))
+	unsigned := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","typ":"JWT"}`))
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr error
+	}{
+		{name: "valid token", token: valid},
+		{name: "expired token", token: expired, wantErr: ErrInvalidToken},
+		{name: "token signed with another secret", token: foreign, wantErr: ErrInvalidToken},
+		{name: "payload replaced", token: parts[0] + "." + otherUser + "." + parts[2], wantErr: ErrInvalidToken},
+		{name: "algorithm none", token: unsigned + "." + parts[1] + ".", wantErr: ErrInvalidToken},
+		{name: "not a token", token: "garbage", wantErr: ErrInvalidToken},
+		{name: "empty token", token: "", wantErr: ErrInvalidToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tokens.Authenticate(context.Background(), tt.token)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Authenticate() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && got != userID {
+				t.Errorf("Authenticate() = %s, want %s", got, userID)
+			}
+		})
+	}
+}
+
+func TestTokenServiceSecret(t *testing.T) {
+	if _, err := NewTokenService("short", slog.Default()); err == nil {
+		t.Error("NewTokenService() accepted a secret shorter than the minimum")
+	}
+
+	disabled := newTestTokenService(t, "")
+	if _, err := disabled.Issue(uuid.New(), time.Hour); err == nil {
+		t.Error("Issue() issued a token without a secret")
+	}
+	token, err := newTestTokenService(t, testTokenSecret).Issue(uuid.New(), time.Hour)
+	if err != nil {
+		t.Fatalf("Issue() error = %v", err)
+	}
+	if _, err := disabled.Authenticate(context.Background(), token); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Authenticate() without a secret error = %v, want %v", err, ErrInvalidToken)
+	}
+}
+
+func TestIsAPIKey(t *testing.T) {
+	if !IsAPIKey("sk_abcd1234_secret") {
+		t.Error("IsAPIKey() = false for an API key")
+	}
+	if IsAPIKey("eyJhbGciOiJIUzI1NiJ9.e30.sig") {
+		t.Error("IsAPIKey() = true for a user token")
+	}
+}
@@ -0,0 +1,259 @@
+package service
+
+import (
+	"context"
+
+	"github.com/vasiliy-maslov/go-subscription-service/internal/model"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/repository"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName - имя инструментирования для спанов сервисного слоя.
+const tracerName = "github.com/vasiliy-maslov/go-subscription-service/internal/service"
+
+var _ SubscriptionService = (*tracedSubscriptionService)(nil)
+
+// tracedSubscriptionService открывает спан OpenTelemetry на каждый вызов SubscriptionService.
+// Спан получает имя операции (op), пользователя запроса или API-ключ, аргументы-идентификаторы
+// и число возвращенных записей; ошибка вызова записывается в спан.
+type tracedSubscriptionService struct {
+	next   SubscriptionService
+	tracer trace.Tracer
+}
+
+// NewTracedSubscriptionService оборачивает сервис подписок трассировкой.
+// Спаны пишутся глобальным провайдером OpenTelemetry.
+func NewTracedSubscriptionService(next SubscriptionService) SubscriptionService {
+	return &tracedSubscriptionService{next: next, tracer: otel.Tracer(tracerName)}
+}
+
+// start открывает спан операции op.
+func (s *tracedSubscriptionService) start(ctx context.Context, op string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	attrs = append(attrs, attribute.String("op", op))
+	if key, ok := APIKeyFrom(ctx); ok {
+		attrs = append(attrs, attribute.String("api_key_id", key.ID.String()))
+	} else if userID, ok := repository.TenantFrom(ctx); ok {
+		attrs = append(attrs, attribute.String("user_id", userID.String()))
+	}
+	return s.tracer.Start(ctx, op, trace.WithAttributes(attrs...))
+}
+
+// endSpan записывает ошибку вызова и закрывает спан.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// spanRows записывает в спан число возвращенных записей.
+func spanRows(span trace.Span, n int) {
+	span.SetAttributes(attribute.Int("rows", n))
+}
+
+func subscriptionAttr(id uuid.UUID) attribute.KeyValue {
+	return attribute.String("subscription_id", id.String())
+}
+
+func filterAttrs(filter repository.SubscriptionFilter) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{attribute.String("filter.user_id", filter.UserID.String())}
+	if filter.OrganizationID != nil {
+		attrs = append(attrs, attribute.String("filter.organization_id", filter.OrganizationID.String()))
+	}
+	if filter.Currency != nil {
+		attrs = append(attrs, attribute.String("filter.currency", string(*filter.Currency)))
+	}
+	return attrs
+}
+
+func periodAttrs(period model.CostPeriod) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.String("period.from", period.From.String()),
+		attribute.String("period.to", period.To.String()),
+		attribute.String("period.proration", string(period.Proration)),
+	}
+}
+
+func (s *tracedSubscriptionService) Create(ctx context.Context, sub model.Subscription) (uuid.UUID, error) {
+	ctx, span := s.start(ctx, "service.Create", attribute.String("owner_id", sub.UserID.String()))
+	id, err := s.next.Create(ctx, sub)
+	span.SetAttributes(subscriptionAttr(id))
+	endSpan(span, err)
+	return id, err
+}
+
+func (s *tracedSubscriptionService) GetByID(ctx context.Context, id uuid.UUID) (model.Subscription, error) {
+	ctx, span := s.start(ctx, "service.GetByID", subscriptionAttr(id))
+	sub, err := s.next.GetByID(ctx, id)
+	endSpan(span, err)
+	return sub, err
+}
+
+func (s *tracedSubscriptionService) List(ctx context.Context, filter repository.SubscriptionFilter) ([]model.Subscription, error) {
+	ctx, span := s.start(ctx, "service.List", filterAttrs(filter)...)
+	subs, err := s.next.List(ctx, filter)
+	spanRows(span, len(subs))
+	endSpan(span, err)
+	return subs, err
+}
+
+func (s *tracedSubscriptionService) Update(ctx context.Context, id uuid.UUID, sub model.Subscription) error {
+	ctx, span := s.start(ctx, "service.Update", subscriptionAttr(id))
+	err := s.next.Update(ctx, id, sub)
+	endSpan(span, err)
+	return err
+}
+
+func (s *tracedSubscriptionService) Delete(ctx context.Context, id uuid.UUID) error {
+	ctx, span := s.start(ctx, "service.Delete", subscriptionAttr(id))
+	err := s.next.Delete(ctx, id)
+	endSpan(span, err)
+	return err
+}
+
+func (s *tracedSubscriptionService) Pause(ctx context.Context, id uuid.UUID) (model.Subscription, error) {
+	ctx, span := s.start(ctx, "service.Pause", subscriptionAttr(id))
+	sub, err := s.next.Pause(ctx, id)
+	endSpan(span, err)
+	return sub, err
+}
+
+func (s *tracedSubscriptionService) Resume(ctx context.Context, id uuid.UUID) (model.Subscription, error) {
+	ctx, span := s.start(ctx, "service.Resume", subscriptionAttr(id))
+	sub, err := s.next.Resume(ctx, id)
+	endSpan(span, err)
+	return sub, err
+}
+
+func (s *tracedSubscriptionService) Cancel(ctx context.Context, id uuid.UUID, immediate bool) (model.Subscription, error) {
+	ctx, span := s.start(ctx, "service.Cancel", subscriptionAttr(id), attribute.Bool("immediate", immediate))
+	sub, err := s.next.Cancel(ctx, id, immediate)
+	endSpan(span, err)
+	return sub, err
+}
+
+func (s *tracedSubscriptionService) Restore(ctx context.Context, id uuid.UUID) (model.Subscription, error) {
+	ctx, span := s.start(ctx, "service.Restore", subscriptionAttr(id))
+	sub, err := s.next.Restore(ctx, id)
+	endSpan(span, err)
+	return sub, err
+}
+
+func (s *tracedSubscriptionService) ListTransitions(ctx context.Context, subscriptionIDs []uuid.UUID) (map[uuid.UUID][]model.StatusTransition, error) {
+	ctx, span := s.start(ctx, "service.ListTransitions", attribute.Int("subscriptions", len(subscriptionIDs)))
+	transitions, err := s.next.ListTransitions(ctx, subscriptionIDs)
+	spanRows(span, len(transitions))
+	endSpan(span, err)
+	return transitions, err
+}
+
+func (s *tracedSubscriptionService) AddMember(ctx context.Context, subscriptionID uuid.UUID, m model.SubscriptionMember) (model.SubscriptionMember, error) {
+	ctx, span := s.start(ctx, "service.AddMember", subscriptionAttr(subscriptionID), attribute.String("member_id", m.UserID.String()))
+	member, err := s.next.AddMember(ctx, subscriptionID, m)
+	endSpan(span, err)
+	return member, err
+}
+
+func (s *tracedSubscriptionService) RemoveMember(ctx context.Context, subscriptionID, userID uuid.UUID, leftAt model.Date) error {
+	ctx, span := s.start(ctx, "service.RemoveMember", subscriptionAttr(subscriptionID), attribute.String("member_id", userID.String()))
+	err := s.next.RemoveMember(ctx, subscriptionID, userID, leftAt)
+	endSpan(span, err)
+	return err
+}
+
+func (s *tracedSubscriptionService) ListMembers(ctx context.Context, subscriptionID uuid.UUID) ([]model.SubscriptionMember, error) {
+	ctx, span := s.start(ctx, "service.ListMembers", subscriptionAttr(subscriptionID))
+	members, err := s.next.ListMembers(ctx, subscriptionID)
+	spanRows(span, len(members))
+	endSpan(span, err)
+	return members, err
+}
+
+func (s *tracedSubscriptionService) AddPriceChange(ctx context.Context, subscriptionID uuid.UUID, change model.PriceChange) (model.PriceChange, error) {
+	ctx, span := s.start(ctx, "service.AddPriceChange", subscriptionAttr(subscriptionID))
+	change, err := s.next.AddPriceChange(ctx, subscriptionID, change)
+	endSpan(span, err)
+	return change, err
+}
+
+func (s *tracedSubscriptionService) DeletePriceChange(ctx context.Context, subscriptionID, id uuid.UUID) error {
+	ctx, span := s.start(ctx, "service.DeletePriceChange", subscriptionAttr(subscriptionID), attribute.String("price_change_id", id.String()))
+	err := s.next.DeletePriceChange(ctx, subscriptionID, id)
+	endSpan(span, err)
+	return err
+}
+
+func (s *tracedSubscriptionService) ListPriceChanges(ctx context.Context, subscriptionID uuid.UUID) ([]model.PriceChange, error) {
+	ctx, span := s.start(ctx, "service.ListPriceChanges", subscriptionAttr(subscriptionID))
+	changes, err := s.next.ListPriceChanges(ctx, subscriptionID)
+	spanRows(span, len(changes))
+	endSpan(span, err)
+	return changes, err
+}
+
+func (s *tracedSubscriptionService) CalculateTotalCost(ctx context.Context, filter repository.SubscriptionFilter, period model.CostPeriod) (model.CostSummary, error) {
+	ctx, span := s.start(ctx, "service.CalculateTotalCost", append(filterAttrs(filter), periodAttrs(period)...)...)
+	summary, err := s.next.CalculateTotalCost(ctx, filter, period)
+	endSpan(span, err)
+	return summary, err
+}
+
+func (s *tracedSubscriptionService) CalculateCostBreakdown(ctx context.Context, filter repository.SubscriptionFilter, groupBy string, period model.CostPeriod) (model.CostSummary, error) {
+	attrs := append(filterAttrs(filter), periodAttrs(period)...)
+	ctx, span := s.start(ctx, "service.CalculateCostBreakdown", append(attrs, attribute.String("group_by", groupBy))...)
+	summary, err := s.next.CalculateCostBreakdown(ctx, filter, groupBy, period)
+	spanRows(span, len(summary.Breakdown))
+	endSpan(span, err)
+	return summary, err
+}
+
+func (s *tracedSubscriptionService) Forecast(ctx context.Context, filter repository.SubscriptionFilter, months int) (model.Forecast, error) {
+	ctx, span := s.start(ctx, "service.Forecast", append(filterAttrs(filter), attribute.Int("months", months))...)
+	forecast, err := s.next.Forecast(ctx, filter, months)
+	endSpan(span, err)
+	return forecast, err
+}
+
+func (s *tracedSubscriptionService) FindDuplicates(ctx context.Context, userID uuid.UUID) ([]model.SubscriptionOverlap, error) {
+	ctx, span := s.start(ctx, "service.FindDuplicates", attribute.String("owner_id", userID.String()))
+	overlaps, err := s.next.FindDuplicates(ctx, userID)
+	spanRows(span, len(overlaps))
+	endSpan(span, err)
+	return overlaps, err
+}
+
+func (s *tracedSubscriptionService) AddDiscount(ctx context.Context, subscriptionID uuid.UUID, d model.Discount) (model.Discount, error) {
+	ctx, span := s.start(ctx, "service.AddDiscount", subscriptionAttr(subscriptionID))
+	discount, err := s.next.AddDiscount(ctx, subscriptionID, d)
+	endSpan(span, err)
+	return discount, err
+}
+
+func (s *tracedSubscriptionService) DeleteDiscount(ctx context.Context, subscriptionID, id uuid.UUID) error {
+	ctx, span := s.start(ctx, "service.DeleteDiscount", subscriptionAttr(subscriptionID), attribute.String("discount_id", id.String()))
+	err := s.next.DeleteDiscount(ctx, subscriptionID, id)
+	endSpan(span, err)
+	return err
+}
+
+func (s *tracedSubscriptionService) ListDiscounts(ctx context.Context, subscriptionID uuid.UUID) ([]model.Discount, error) {
+	ctx, span := s.start(ctx, "service.ListDiscounts", subscriptionAttr(subscriptionID))
+	discounts, err := s.next.ListDiscounts(ctx, subscriptionID)
+	spanRows(span, len(discounts))
+	endSpan(span, err)
+	return discounts, err
+}
+
+func (s *tracedSubscriptionService) CalculateCostLineItems(ctx context.Context, filter repository.SubscriptionFilter, period model.CostPeriod) (model.CostSummary, error) {
+	ctx, span := s.start(ctx, "service.CalculateCostLineItems", append(filterAttrs(filter), periodAttrs(period)...)...)
+	summary, err := s.next.CalculateCostLineItems(ctx, filter, period)
+	spanRows(span, len(summary.LineItems))
+	endSpan(span, err)
+	return summary, err
+}
@@ -0,0 +1,240 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/vasiliy-maslov/go-subscription-service/internal/logging"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/model"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// UserService определяет интерфейс для работы с пользователями.
+type UserService interface {
+	// Create создает пользователя. Пользователь токена без права users:manage регистрирует
+	// собственный профиль: ID пользователя берется из токена.
+	Create(ctx context.Context, u model.User) (model.User, error)
+	GetByID(ctx context.Context, id uuid.UUID) (model.User, error)
+	Update(ctx context.Context, id uuid.UUID, u model.User) (model.User, error)
+	// Delete удаляет пользователя вместе со всеми его данными в одной транзакции.
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+type userService struct {
+	repo          repository.UserRepository
+	subscriptions repository.SubscriptionRepository
+	policy        Policy
+	outbox        repository.OutboxRepository
+	tx            repository.TxManager
+	logger        *slog.Logger
+}
+
+// NewUserService создает новый экземпляр сервиса пользователей.
+func NewUserService(
+	repo repository.UserRepository,
+	subscriptions repository.SubscriptionRepository,
+	policy Policy,
+	outbox repository.OutboxRepository,
+	tx repository.TxManager,
+	logger *slog.Logger,
+) UserService {
+	return &userService{
+		repo:          repo,
+		subscriptions: subscriptions,
+		policy:        policy,
+		outbox:        outbox,
+		tx:            tx,
+		logger:        logger,
+	}
+}
+
+func (s *userService) Create(ctx context.Context, u model.User) (model.User, error) {
+	const op = "users.Create"
+	log := logging.FromContext(ctx, s.logger).With(slog.String("op", op))
+
+	log.Info("Создание пользователя")
+
+	u.ID = uuid.New()
+	if tenant, ok := repository.TenantFrom(ctx); ok {
+		if err := s.policy.Authorize(ctx, model.PermissionUsersManage); errors.Is(err, ErrPermissionDenied) {
+			u.ID = tenant
+		}
+	}
+	if err := authorizeUser(ctx, s.policy, u.ID); err != nil {
+		log.Warn("Действие запрещено политикой доступа", slog.String("error", err.Error()))
+		return model.User{}, err
+	}
+
+	if err := validateUser(&u); err != nil {
+		log.Warn("Пользователь не прошел проверку", slog.String("error", err.Error()))
+		return model.User{}, err
+	}
+
+	var created model.User
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		created, err = s.repo.Create(ctx, u)
+		if err != nil {
+			return err
+		}
+
+		return s.outbox.Add(ctx, model.EventUserCreated, created.ID, created)
+	})
+	if err != nil {
+		log.Error("Не удалось создать пользователя", slog.String("error", err.Error()))
+		return model.User{}, err
+	}
+
+	log.Info("Пользователь успешно создан", slog.String("user_id", created.ID.String()))
+	return created, nil
+}
+
+func (s *userService) GetByID(ctx context.Context, id uuid.UUID) (model.User, error) {
+	const op = "users.GetByID"
+	log := logging.FromContext(ctx, s.logger).With(slog.String("op", op), slog.String("user_id", id.String()))
+
+	if err := authorizeUser(ctx, s.policy, id); err != nil {
+		log.Warn("Действие запрещено политикой доступа", slog.String("error", err.Error()))
+		return model.User{}, err
+	}
+
+	u, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		log.Error("Не удалось получить пользователя", slog.String("error", err.Error()))
+		return model.User{}, err
+	}
+
+	return u, nil
+}
+
+// Update заменяет профиль и настройки пользователя.
+func (s *userService) Update(ctx context.Context, id uuid.UUID, u model.User) (model.User, error) {
+	const op = "users.Update"
+	log := logging.FromContext(ctx, s.logger).With(slog.String("op", op), slog.String("user_id", id.String()))
+
+	log.Info("Обновление пользователя")
+
+	if err := authorizeUser(ctx, s.policy, id); err != nil {
+		log.Warn("Действие запрещено политикой доступа", slog.String("error", err.Error()))
+		return model.User{}, err
+	}
+
+	if err := validateUser(&u); err != nil {
+		log.Warn("Пользователь не прошел проверку", slog.String("error", err.Error()))
+		return model.User{}, err
+	}
+
+	var updated model.User
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		updated, err = s.repo.Update(ctx, id, u)
+		if err != nil {
+			return err
+		}
+
+		return s.outbox.Add(ctx, model.EventUserUpdated, id, updated)
+	})
+	if err != nil {
+		log.Error("Не удалось обновить пользователя", slog.String("error", err.Error()))
+		return model.User{}, err
+	}
+
+	log.Info("Пользователь успешно обновлен")
+	return updated, nil
+}
+
+// Delete удаляет пользователя. Подписки пользователя удаляются в той же транзакции с событием
+// subscription.deleted по каждой из них; участие в чужих подписках, категории, метки и бюджеты
+// удаляются каскадно. Блокировка строки пользователя не дает параллельно создать ему новую подписку.
+// После проверки права подписки выбираются без привязки к пользователю запроса: администратор
+// удаляет и те подписки, которые сам не видит, и по каждой из них записывается событие.
+func (s *userService) Delete(ctx context.Context, id uuid.UUID) error {
+	const op = "users.Delete"
+	log := logging.FromContext(ctx, s.logger).With(slog.String("op", op), slog.String("user_id", id.String()))
+
+	log.Info("Удаление пользователя")
+
+	if err := authorizeUser(ctx, s.policy, id); err != nil {
+		log.Warn("Действие запрещено политикой доступа", slog.String("error", err.Error()))
+		return err
+	}
+	ctx = repository.WithoutTenant(ctx)
+
+	deletedSubscriptions := 0
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		deletedSubscriptions = 0
+
+		u, err := s.repo.GetByIDForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		subscriptions, err := s.subscriptions.List(ctx, repository.SubscriptionFilter{UserID: id})
+		if err != nil {
+			return err
+		}
+
+		for _, sub := range subscriptions {
+			if sub.UserID != id {
+				continue
+			}
+			if err := s.subscriptions.Delete(ctx, sub.ID); err != nil {
+				return err
+			}
+			if err := s.outbox.Add(ctx, model.EventSubscriptionDeleted, sub.ID, sub); err != nil {
+				return err
+			}
+			deletedSubscriptions++
+		}
+
+		if err := s.repo.Delete(ctx, id); err != nil {
+			return err
+		}
+
+		return s.outbox.Add(ctx, model.EventUserDeleted, id, u)
+	})
+	if err != nil {
+		log.Error("Не удалось удалить пользователя", slog.String("error", err.Error()))
+		return err
+	}
+
+	log.Info("Пользователь удален", slog.Int("subscriptions", deletedSubscriptions))
+	return nil
+}
+
+// validateUser проверяет профиль пользователя и подставляет значения по умолчанию.
+func validateUser(u *model.User) error {
+	u.DisplayName = strings.TrimSpace(u.DisplayName)
+	if u.DisplayName == "" {
+		return fmt.Errorf("%w: display_name is required", ErrValidation)
+	}
+
+	u.Email = strings.TrimSpace(u.Email)
+	if u.Email != "" {
+		address, err := mail.ParseAddress(u.Email)
+		if err != nil || address.Name != "" {
+			return fmt.Errorf("%w: email must be a valid address", ErrValidation)
+		}
+		u.Email = address.Address
+	}
+
+	if err := normalizeCurrency(&u.DefaultCurrency); err != nil {
+		return fmt.Errorf("%w: default_currency must be an ISO 4217 code", ErrValidation)
+	}
+
+	if u.TimeZone == "" {
+		u.TimeZone = model.DefaultTimeZone
+	}
+	if _, err := time.LoadLocation(u.TimeZone); err != nil || u.TimeZone == "Local" {
+		return fmt.Errorf("%w: time_zone must be an IANA time zone name", ErrValidation)
+	}
+
+	return nil
+}
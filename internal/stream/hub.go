@@ -0,0 +1,268 @@
+package stream
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/vasiliy-maslov/go-subscription-service/internal/model"
+	"github.com/vasiliy-maslov/go-subscription-service/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// subscriberBuffer - сколько изменений ждет отправки клиенту. Клиент, который не успевает
+	// их читать, отключается и переподключается с Last-Event-ID.
+	subscriberBuffer = 64
+	// retryInterval - пауза перед повторной подпиской на уведомления после обрыва соединения.
+	retryInterval = 5 * time.Second
+)
+
+// Subscription - подписка клиента на изменения подписок одного пользователя.
+type Subscription struct {
+	// Replay - изменения после Last-Event-ID из буфера, которые нужно отправить до новых.
+	Replay []model.SubscriptionChange
+	// Reset сообщает, что события после Last-Event-ID уже вытеснены из буфера или неизвестны
+	// и клиенту нужно заново загрузить подписки.
+	Reset bool
+
+	userID  uuid.UUID
+	changes chan model.SubscriptionChange
+	hub     *Hub
+}
+
+// Changes возвращает канал новых изменений. Канал закрывается, когда клиент не успевает
+// читать изменения или поток останавливается.
+func (s *Subscription) Changes() <-chan model.SubscriptionChange {
+	return s.changes
+}
+
+// Close отменяет подписку клиента.
+func (s *Subscription) Close() {
+	s.hub.remove(s)
+}
+
+// Hub получает изменения подписок из Postgres через LISTEN/NOTIFY и рассылает их клиентам.
+// Последние изменения хранятся в буфере ограниченного размера, из которого клиенты
+// продолжают поток после переподключения. Порядок изменений в буфере - порядок фиксации
+// транзакций, одинаковый на всех репликах.
+type Hub struct {
+	repo   repository.ChangeRepository
+	logger *slog.Logger
+	size   int
+
+	mu     sync.Mutex
+	buffer []model.SubscriptionChange
+	// known содержит seq изменений из буфера: после переподключения уведомления
+	// и дочитанные из outbox изменения могут повторяться.
+	known       map[int64]struct{}
+	lastSeq     int64
+	subscribers map[*Subscription]struct{}
+	stopped     bool
+}
+
+// NewHub создает поток изменений с буфером на size последних изменений.
+func NewHub(repo repository.ChangeRepository, logger *slog.Logger, size int) *Hub {
+	return &Hub{
+		repo:        repo,
+		logger:      logger,
+		size:        size,
+		known:       make(map[int64]struct{}, size),
+		subscribers: make(map[*Subscription]struct{}),
+	}
+}
+
+// Run заполняет буфер последними изменениями и получает новые, пока не будет отменен контекст.
+// При обрыве соединения подписка на уведомления возобновляется, а пропущенные изменения
+// дочитываются из outbox. После остановки каналы всех подписок закрываются.
+func (h *Hub) Run(ctx context.Context) {
+	const op = "stream.Run"
+	log := h.logger.With(slog.String("op", op))
+
+	log.Info("Запущен поток изменений подписок", slog.Int("buffer_size", h.size))
+	defer h.stop()
+
+	for {
+		err := h.repo.Listen(ctx, h.catchUp, h.receive)
+		if ctx.Err() != nil {
+			log.Info("Поток изменений подписок остановлен")
+			return
+		}
+		log.Error("Подписка на изменения подписок прервана", slog.String("error", err.Error()))
+
+		select {
+		case <-ctx.Done():
+			log.Info("Поток изменений подписок остановлен")
+			return
+		case <-time.After(retryInterval):
+		}
+	}
+}
+
+// catchUp дочитывает изменения, записанные, пока уведомления не принимались. При запуске
+// и после долгого перерыва, когда пропущено больше изменений, чем помещается в буфер,
+// буфер заполняется заново последними изменениями.
+func (h *Hub) catchUp(ctx context.Context) error {
+	h.mu.Lock()
+	after := h.lastSeq
+	h.mu.Unlock()
+
+	if after > 0 {
+		changes, err := h.repo.After(ctx, after, h.size)
+		if err != nil {
+			return err
+		}
+		if len(changes) < h.size {
+			h.publish(changes...)
+			return nil
+		}
+	}
+
+	recent, err := h.repo.Recent(ctx, h.size)
+	if err != nil {
+		return err
+	}
+	h.refill(recent)
+
+	return nil
+}
+
+// receive загружает изменение из уведомления и рассылает его.
+func (h *Hub) receive(ctx context.Context, seq int64) error {
+	change, err := h.repo.Get(ctx, seq)
+	if errors.Is(err, repository.ErrChangeNotFound) {
+		// Событие удалено из outbox до того, как его прочитали.
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	h.publish(change)
+	return nil
+}
+
+// publish добавляет изменения в буфер и отправляет их подписчикам. Уже известные изменения пропускаются.
+func (h *Hub) publish(changes ...model.SubscriptionChange) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, change := range changes {
+		if _, ok := h.known[change.Seq]; ok {
+			continue
+		}
+
+		h.buffer = append(h.buffer, change)
+		h.known[change.Seq] = struct{}{}
+		if len(h.buffer) > h.size {
+			delete(h.known, h.buffer[0].Seq)
+			h.buffer = h.buffer[1:]
+		}
+		h.lastSeq = max(h.lastSeq, change.Seq)
+
+		for sub := range h.subscribers {
+			if sub.userID != change.UserID {
+				continue
+			}
+			select {
+			case sub.changes <- change:
+			default:
+				h.logger.Warn("Клиент потока не успевает получать изменения и отключен",
+					slog.String("user_id", sub.userID.String()))
+				h.closeLocked(sub)
+			}
+		}
+	}
+}
+
+// refill заменяет содержимое буфера изменениями changes. Подписчики отключаются: часть изменений
+// до них не дошла, и после переподключения с Last-Event-ID они получат Reset.
+func (h *Hub) refill(changes []model.SubscriptionChange) {
+	h.mu.Lock()
+	h.buffer = nil
+	clear(h.known)
+	for sub := range h.subscribers {
+		h.closeLocked(sub)
+	}
+	h.mu.Unlock()
+
+	h.publish(changes...)
+}
+
+// Subscribe подписывает клиента на изменения подписок пользователя userID. Если задан lastEventID,
+// в Replay попадают изменения этого пользователя, записанные после события lastEventID.
+func (h *Hub) Subscribe(userID uuid.UUID, lastEventID *int64) *Subscription {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	sub := &Subscription{
+		userID:  userID,
+		changes: make(chan model.SubscriptionChange, subscriberBuffer),
+		hub:     h,
+	}
+
+	if lastEventID != nil {
+		sub.Replay, sub.Reset = h.replayLocked(userID, *lastEventID)
+	}
+
+	if h.stopped {
+		close(sub.changes)
+		return sub
+	}
+	h.subscribers[sub] = struct{}{}
+
+	return sub
+}
+
+// replayLocked возвращает изменения пользователя после события lastEventID. Событие ищется по seq,
+// а не сравнением номеров: транзакции фиксируются не в порядке seq. Если события нет в буфере
+// и оно не новее всех известных, возвращает reset.
+func (h *Hub) replayLocked(userID uuid.UUID, lastEventID int64) (replay []model.SubscriptionChange, reset bool) {
+	start := -1
+	for i, change := range h.buffer {
+		if change.Seq == lastEventID {
+			start = i + 1
+			break
+		}
+	}
+	if start < 0 {
+		return nil, lastEventID < h.lastSeq
+	}
+
+	for _, change := range h.buffer[start:] {
+		if change.UserID == userID {
+			replay = append(replay, change)
+		}
+	}
+
+	return replay, false
+}
+
+func (h *Hub) remove(sub *Subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.closeLocked(sub)
+}
+
+func (h *Hub) closeLocked(sub *Subscription) {
+	if _, ok := h.subscribers[sub]; !ok {
+		return
+	}
+	delete(h.subscribers, sub)
+	close(sub.changes)
+}
+
+// stop закрывает каналы подписчиков, чтобы открытые потоки завершились вместе с приложением.
+func (h *Hub) stop() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.stopped = true
+	for sub := range h.subscribers {
+		h.closeLocked(sub)
+	}
+}
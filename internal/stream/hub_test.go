@@ -0,0 +1,137 @@
+package stream
+
+import (
+	"io"
+	"log/slog"
+	"slices"
+	"testing"
+
+	"github.com/vasiliy-maslov/go-subscription-service/internal/model"
+
+	"github.com/google/uuid"
+)
+
+func newTestHub(size int) *Hub {
+	return NewHub(nil, slog.New(slog.NewTextHandler(io.Discard, nil)), size)
+}
+
+// changes возвращает изменения с номерами seqs, чередуя пользователей users.
+func changes(users []uuid.UUID, seqs ...int64) []model.SubscriptionChange {
+	result := make([]model.SubscriptionChange, len(seqs))
+	for i, seq := range seqs {
+		result[i] = model.SubscriptionChange{Seq: seq, UserID: users[i%len(users)]}
+	}
+	return result
+}
+
+func seqs(changes []model.SubscriptionChange) []int64 {
+	result := make([]int64, len(changes))
+	for i, change := range changes {
+		result[i] = change.Seq
+	}
+	return result
+}
+
+func ptr(v int64) *int64 {
+	return &v
+}
+
+func TestHubReplay(t *testing.T) {
+	alice, bob := uuid.New(), uuid.New()
+	users := []uuid.UUID{alice, bob}
+
+	tests := []struct {
+		name        string
+		size        int
+		published   []int64
+		lastEventID *int64
+		wantReplay  []int64
+		wantReset   bool
+	}{
+		{name: "no Last-Event-ID", size: 10, published: []int64{1, 2, 3}},
+		{name: "changes of the user after the event", size: 10, published: []int64{1, 2, 3, 4, 5}, lastEventID: ptr(1), wantReplay: []int64{3, 5}},
+		{name: "last event is the newest", size: 10, published: []int64{1, 2, 3}, lastEventID: ptr(3)},
+		// Транзакция с seq 2 зафиксирована после транзакции с seq 3.
+		{name: "commit order differs from seq", size: 10, published: []int64{1, 3, 2}, lastEventID: ptr(3), wantReplay: []int64{2}},
+		{name: "duplicates are skipped", size: 10, published: []int64{1, 2, 3, 3, 1}, lastEventID: ptr(1), wantReplay: []int64{3}},
+		{name: "event evicted from the buffer", size: 3, published: []int64{1, 2, 3, 4, 5}, lastEventID: ptr(1), wantReset: true},
+		{name: "oldest event still in the buffer", size: 3, published: []int64{1, 2, 3, 4, 5}, lastEventID: ptr(3), wantReplay: []int64{5}},
+		{name: "unknown event older than the buffer", size: 3, published: []int64{10, 11}, lastEventID: ptr(7), wantReset: true},
+		{name: "event newer than all known", size: 3, published: []int64{1, 2}, lastEventID: ptr(9)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hub := newTestHub(tt.size)
+			hub.publish(changes(users, tt.published...)...)
+
+			sub := hub.Subscribe(alice, tt.lastEventID)
+			defer sub.Close()
+
+			if got := seqs(sub.Replay); !slices.Equal(got, tt.wantReplay) {
+				t.Errorf("Replay = %v, want %v", got, tt.wantReplay)
+			}
+			if sub.Reset != tt.wantReset {
+				t.Errorf("Reset = %v, want %v", sub.Reset, tt.wantReset)
+			}
+		})
+	}
+}
+
+func TestHubDelivery(t *testing.T) {
+	alice, bob := uuid.New(), uuid.New()
+	hub := newTestHub(10)
+
+	sub := hub.Subscribe(alice, nil)
+	hub.publish(changes([]uuid.UUID{alice, bob}, 1, 2, 3)...)
+	sub.Close()
+
+	var got []int64
+	for change := range sub.Changes() {
+		got = append(got, change.Seq)
+	}
+	if want := []int64{1, 3}; !slices.Equal(got, want) {
+		t.Errorf("Changes() = %v, want %v", got, want)
+	}
+}
+
+func TestHubDisconnect(t *testing.T) {
+	alice := uuid.New()
+
+	tests := []struct {
+		name string
+		act  func(hub *Hub)
+	}{
+		{
+			name: "slow subscriber",
+			act: func(hub *Hub) {
+				for seq := range int64(subscriberBuffer + 1) {
+					hub.publish(model.SubscriptionChange{Seq: seq + 1, UserID: alice})
+				}
+			},
+		},
+		{name: "buffer refilled", act: func(hub *Hub) { hub.refill(changes([]uuid.UUID{alice}, 1)) }},
+		{name: "hub stopped", act: func(hub *Hub) { hub.stop() }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hub := newTestHub(subscriberBuffer * 2)
+			sub := hub.Subscribe(alice, nil)
+
+			tt.act(hub)
+
+			for range sub.Changes() {
+			}
+			if len(hub.subscribers) != 0 {
+				t.Errorf("hub has %d subscribers, want 0", len(hub.subscribers))
+			}
+		})
+	}
+
+	hub := newTestHub(1)
+	hub.stop()
+	if _, ok := <-hub.Subscribe(alice, nil).Changes(); ok {
+		t.Error("Subscribe() after stop returned an open channel")
+	}
+}
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS subscription_status_transitions;
+
+ALTER TABLE subscriptions
+    DROP COLUMN IF EXISTS trial_end_date,
+    DROP COLUMN IF EXISTS status;
@@ -0,0 +1,18 @@
+ALTER TABLE subscriptions
+    ADD COLUMN IF NOT EXISTS status VARCHAR(32) NOT NULL DEFAULT 'active'
+        CHECK (status IN ('trialing', 'active', 'paused', 'cancel_scheduled', 'ended')),
+    ADD COLUMN IF NOT EXISTS trial_end_date DATE;
+
+UPDATE subscriptions SET status = 'ended' WHERE end_date IS NOT NULL AND end_date < CURRENT_DATE;
+
+CREATE TABLE IF NOT EXISTS subscription_status_transitions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    subscription_id UUID NOT NULL REFERENCES subscriptions(id) ON DELETE CASCADE,
+    from_status VARCHAR(32),
+    to_status VARCHAR(32) NOT NULL,
+    effective_date DATE NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_status_transitions_subscription_id
+    ON subscription_status_transitions(subscription_id, effective_date);
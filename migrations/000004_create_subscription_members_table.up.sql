@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS subscription_members (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    subscription_id UUID NOT NULL REFERENCES subscriptions(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL,
+    share_type VARCHAR(16) NOT NULL CHECK (share_type IN ('weight', 'fixed')),
+    weight INTEGER NOT NULL DEFAULT 0 CHECK (weight >= 0),
+    amount INTEGER NOT NULL DEFAULT 0 CHECK (amount >= 0),
+    joined_at DATE NOT NULL,
+    left_at DATE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_subscription_members_user_id ON subscription_members(user_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_subscription_members_active
+    ON subscription_members(subscription_id, user_id) WHERE left_at IS NULL;
@@ -0,0 +1,31 @@
+CREATE TABLE IF NOT EXISTS services (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(255) NOT NULL UNIQUE,
+    category VARCHAR(100) NOT NULL DEFAULT '',
+    logo_url TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Псевдонимы хранятся в нормализованном виде, каноническое название тоже является псевдонимом.
+CREATE TABLE IF NOT EXISTS service_aliases (
+    alias_normalized VARCHAR(255) PRIMARY KEY,
+    alias VARCHAR(255) NOT NULL,
+    service_id UUID NOT NULL REFERENCES services(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_service_aliases_service_id ON service_aliases(service_id);
+
+CREATE TABLE IF NOT EXISTS service_plans (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    service_id UUID NOT NULL REFERENCES services(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    price INTEGER NOT NULL CHECK (price >= 0),
+    UNIQUE (service_id, name)
+);
+
+ALTER TABLE subscriptions
+    ADD COLUMN IF NOT EXISTS service_id UUID REFERENCES services(id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS plan_id UUID REFERENCES service_plans(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_subscriptions_service_id ON subscriptions(service_id);
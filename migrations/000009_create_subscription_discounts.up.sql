@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS subscription_discounts (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    subscription_id UUID NOT NULL REFERENCES subscriptions(id) ON DELETE CASCADE,
+    kind VARCHAR(16) NOT NULL CHECK (kind IN ('percent', 'fixed')),
+    value INTEGER NOT NULL CHECK (value > 0),
+    cycles INTEGER CHECK (cycles > 0),
+    start_date DATE,
+    end_date DATE,
+    description VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (kind <> 'percent' OR value <= 100),
+    CHECK (end_date IS NULL OR start_date IS NULL OR end_date >= start_date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_subscription_discounts_subscription_id ON subscription_discounts(subscription_id);
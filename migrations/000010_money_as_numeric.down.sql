@@ -0,0 +1,21 @@
+-- Дробные части сумм округляются до целых.
+ALTER TABLE budgets
+    ALTER COLUMN amount TYPE INTEGER USING round(amount)::integer,
+    DROP COLUMN IF EXISTS currency;
+
+ALTER TABLE subscription_discounts
+    ALTER COLUMN value TYPE INTEGER USING round(value)::integer;
+
+ALTER TABLE subscription_members
+    ALTER COLUMN amount TYPE INTEGER USING round(amount)::integer;
+
+ALTER TABLE subscription_price_changes
+    ALTER COLUMN price TYPE INTEGER USING round(price)::integer;
+
+ALTER TABLE service_plans
+    ALTER COLUMN price TYPE INTEGER USING round(price)::integer,
+    DROP COLUMN IF EXISTS currency;
+
+ALTER TABLE subscriptions
+    ALTER COLUMN price TYPE INTEGER USING round(price)::integer,
+    DROP COLUMN IF EXISTS currency;
@@ -0,0 +1,22 @@
+-- Цены хранятся как NUMERIC без ограничения масштаба: число знаков после запятой
+-- соответствует валюте суммы. Прежние целые цены считаются суммами в рублях.
+ALTER TABLE subscriptions
+    ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT 'RUB',
+    ALTER COLUMN price TYPE NUMERIC USING round(price::numeric, 2);
+
+ALTER TABLE service_plans
+    ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT 'RUB',
+    ALTER COLUMN price TYPE NUMERIC USING round(price::numeric, 2);
+
+ALTER TABLE subscription_price_changes
+    ALTER COLUMN price TYPE NUMERIC USING round(price::numeric, 2);
+
+ALTER TABLE subscription_members
+    ALTER COLUMN amount TYPE NUMERIC USING round(amount::numeric, 2);
+
+ALTER TABLE subscription_discounts
+    ALTER COLUMN value TYPE NUMERIC USING CASE WHEN kind = 'fixed' THEN round(value::numeric, 2) ELSE value::numeric END;
+
+ALTER TABLE budgets
+    ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT 'RUB',
+    ALTER COLUMN amount TYPE NUMERIC USING round(amount::numeric, 2);
@@ -0,0 +1,7 @@
+ALTER TABLE budgets DROP CONSTRAINT IF EXISTS budgets_user_id_fkey;
+ALTER TABLE tags DROP CONSTRAINT IF EXISTS tags_user_id_fkey;
+ALTER TABLE categories DROP CONSTRAINT IF EXISTS categories_user_id_fkey;
+ALTER TABLE subscription_members DROP CONSTRAINT IF EXISTS subscription_members_user_id_fkey;
+ALTER TABLE subscriptions DROP CONSTRAINT IF EXISTS subscriptions_user_id_fkey;
+
+DROP TABLE IF EXISTS users;
@@ -0,0 +1,33 @@
+CREATE TABLE IF NOT EXISTS users (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    display_name VARCHAR(255) NOT NULL DEFAULT '',
+    -- Почта необязательна только у пользователей, перенесенных из существующих данных.
+    email VARCHAR(320),
+    default_currency CHAR(3) NOT NULL DEFAULT 'RUB',
+    time_zone VARCHAR(64) NOT NULL DEFAULT 'UTC',
+    notification_preferences JSONB NOT NULL DEFAULT '{"budget_alerts": true}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email ON users(lower(email)) WHERE email IS NOT NULL;
+
+-- Пользователи, на которых уже ссылаются данные, создаются без профиля.
+INSERT INTO users (id)
+SELECT user_id FROM subscriptions
+UNION SELECT user_id FROM subscription_members
+UNION SELECT user_id FROM categories
+UNION SELECT user_id FROM tags
+UNION SELECT user_id FROM budgets
+ON CONFLICT (id) DO NOTHING;
+
+ALTER TABLE subscriptions
+    ADD CONSTRAINT subscriptions_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+ALTER TABLE subscription_members
+    ADD CONSTRAINT subscription_members_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+ALTER TABLE categories
+    ADD CONSTRAINT categories_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+ALTER TABLE tags
+    ADD CONSTRAINT tags_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+ALTER TABLE budgets
+    ADD CONSTRAINT budgets_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
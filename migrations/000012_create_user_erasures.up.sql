@@ -0,0 +1,9 @@
+-- Журнал удалений данных по запросам пользователей. ID пользователя не хранится:
+-- его место во всех оставшихся записях занимает tombstone_id.
+CREATE TABLE IF NOT EXISTS user_erasures (
+    id UUID PRIMARY KEY,
+    tombstone_id UUID NOT NULL UNIQUE,
+    subscriptions INTEGER NOT NULL,
+    redacted_events BIGINT NOT NULL,
+    erased_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,41 @@
+CREATE TABLE IF NOT EXISTS organizations (
+    id UUID PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    default_currency CHAR(3) NOT NULL DEFAULT 'RUB',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS organization_members (
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL,
+    role VARCHAR(16) NOT NULL CHECK (role IN ('owner', 'admin', 'member')),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (organization_id, user_id),
+    CONSTRAINT organization_members_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_organization_members_user_id ON organization_members(user_id);
+
+-- Рабочие подписки принадлежат организации; user_id остается за участником, который ими управляет.
+ALTER TABLE subscriptions
+    ADD COLUMN IF NOT EXISTS organization_id UUID REFERENCES organizations(id) ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS idx_subscriptions_organization_id ON subscriptions(organization_id);
+
+-- Изоляция арендаторов на уровне базы: если соединение привязано к пользователю (app.user_id),
+-- видны только его подписки, подписки с его участием и подписки его организаций.
+-- Фоновые задачи работают без app.user_id и видят все строки. FORCE распространяет политику
+-- на владельца таблицы; суперпользователь и роли с BYPASSRLS ее не соблюдают.
+ALTER TABLE subscriptions ENABLE ROW LEVEL SECURITY;
+ALTER TABLE subscriptions FORCE ROW LEVEL SECURITY;
+
+CREATE POLICY subscriptions_tenant_isolation ON subscriptions
+    USING (
+        COALESCE(current_setting('app.user_id', true), '') = ''
+        OR user_id = current_setting('app.user_id')::uuid
+        OR organization_id IN (
+            SELECT organization_id FROM organization_members WHERE user_id = current_setting('app.user_id')::uuid)
+        OR id IN (
+            SELECT subscription_id FROM subscription_members WHERE user_id = current_setting('app.user_id')::uuid)
+    );
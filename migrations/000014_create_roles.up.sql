@@ -0,0 +1,53 @@
+CREATE TABLE IF NOT EXISTS permissions (
+    name VARCHAR(64) PRIMARY KEY,
+    description TEXT NOT NULL DEFAULT ''
+);
+
+CREATE TABLE IF NOT EXISTS roles (
+    name VARCHAR(64) PRIMARY KEY,
+    description TEXT NOT NULL DEFAULT ''
+);
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role VARCHAR(64) NOT NULL REFERENCES roles(name) ON DELETE CASCADE,
+    permission VARCHAR(64) NOT NULL REFERENCES permissions(name) ON DELETE CASCADE,
+    PRIMARY KEY (role, permission)
+);
+
+CREATE TABLE IF NOT EXISTS role_bindings (
+    user_id UUID NOT NULL,
+    role VARCHAR(64) NOT NULL REFERENCES roles(name) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, role),
+    CONSTRAINT role_bindings_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+INSERT INTO permissions (name, description) VALUES
+    ('subscriptions:read', 'Read subscriptions, their history and own cost reports'),
+    ('subscriptions:write', 'Create, update, pause, resume and cancel subscriptions'),
+    ('subscriptions:delete', 'Delete subscriptions'),
+    ('subscriptions:restore', 'Restore ended subscriptions'),
+    ('reports:read_all', 'Run cost reports for any user'),
+    ('roles:manage', 'Grant and revoke roles')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO roles (name, description) VALUES
+    ('viewer', 'Reads subscriptions'),
+    ('editor', 'Reads and changes subscriptions'),
+    ('finance', 'Reads subscriptions and runs reports across users'),
+    ('admin', 'Full access, including deletion, restoration and role management')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('viewer', 'subscriptions:read'),
+    ('editor', 'subscriptions:read'),
+    ('editor', 'subscriptions:write'),
+    ('finance', 'subscriptions:read'),
+    ('finance', 'reports:read_all'),
+    ('admin', 'subscriptions:read'),
+    ('admin', 'subscriptions:write'),
+    ('admin', 'subscriptions:delete'),
+    ('admin', 'subscriptions:restore'),
+    ('admin', 'reports:read_all'),
+    ('admin', 'roles:manage')
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,16 @@
+DROP POLICY IF EXISTS subscriptions_tenant_isolation ON subscriptions;
+
+CREATE POLICY subscriptions_tenant_isolation ON subscriptions
+    USING (
+        COALESCE(current_setting('app.user_id', true), '') = ''
+        OR user_id = current_setting('app.user_id')::uuid
+        OR organization_id IN (
+            SELECT organization_id FROM organization_members WHERE user_id = current_setting('app.user_id')::uuid)
+        OR id IN (
+            SELECT subscription_id FROM subscription_members WHERE user_id = current_setting('app.user_id')::uuid)
+    );
+
+DELETE FROM role_permissions WHERE permission = 'api_keys:manage';
+DELETE FROM permissions WHERE name = 'api_keys:manage';
+
+DROP TABLE IF EXISTS api_keys;
@@ -0,0 +1,38 @@
+CREATE TABLE IF NOT EXISTS api_keys (
+    id UUID PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    -- prefix - открытая часть ключа для поиска; секрет хранится только в виде SHA-256.
+    prefix VARCHAR(16) NOT NULL UNIQUE,
+    secret_hash CHAR(64) NOT NULL,
+    permissions TEXT[] NOT NULL,
+    -- Пустой список - ключ не ограничен пользователями.
+    user_ids UUID[] NOT NULL DEFAULT '{}',
+    expires_at TIMESTAMPTZ,
+    last_used_at TIMESTAMPTZ,
+    revoked_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO permissions (name, description) VALUES
+    ('api_keys:manage', 'Create and revoke API keys')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('admin', 'api_keys:manage')
+ON CONFLICT DO NOTHING;
+
+-- API-ключ может быть ограничен несколькими пользователями, поэтому политика изоляции
+-- читает список ID через запятую из app.user_ids вместо одного app.user_id.
+DROP POLICY IF EXISTS subscriptions_tenant_isolation ON subscriptions;
+
+CREATE POLICY subscriptions_tenant_isolation ON subscriptions
+    USING (
+        COALESCE(current_setting('app.user_ids', true), '') = ''
+        OR user_id = ANY(string_to_array(current_setting('app.user_ids'), ',')::uuid[])
+        OR organization_id IN (
+            SELECT organization_id FROM organization_members
+            WHERE user_id = ANY(string_to_array(current_setting('app.user_ids'), ',')::uuid[]))
+        OR id IN (
+            SELECT subscription_id FROM subscription_members
+            WHERE user_id = ANY(string_to_array(current_setting('app.user_ids'), ',')::uuid[]))
+    );
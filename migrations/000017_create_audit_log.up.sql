@@ -0,0 +1,47 @@
+-- Журнал аудита изменяющих вызовов API. Записи связаны цепочкой SHA-256: hash каждой записи
+-- вычисляется от prev_hash и ее полей, поэтому изменение или удаление записи обнаруживается.
+CREATE TABLE IF NOT EXISTS audit_log (
+    seq BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    occurred_at TIMESTAMPTZ NOT NULL,
+    actor_type VARCHAR(16) NOT NULL,
+    -- Без внешнего ключа: запись остается после удаления пользователя или ключа.
+    actor_id UUID,
+    action TEXT NOT NULL,
+    resource_type VARCHAR(64) NOT NULL,
+    resource_id TEXT NOT NULL DEFAULT '',
+    params JSONB NOT NULL DEFAULT '{}',
+    request_id TEXT NOT NULL,
+    outcome VARCHAR(16) NOT NULL,
+    status INTEGER NOT NULL,
+    source_ip TEXT NOT NULL,
+    -- TEXT, а не CHAR: пустой хеш первой записи не должен дополняться пробелами.
+    prev_hash TEXT NOT NULL DEFAULT '',
+    hash CHAR(64) NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_actor ON audit_log(actor_id, seq);
+CREATE INDEX IF NOT EXISTS idx_audit_log_resource ON audit_log(resource_id, seq);
+CREATE INDEX IF NOT EXISTS idx_audit_log_occurred_at ON audit_log(occurred_at);
+
+-- Журнал только дополняется.
+CREATE OR REPLACE FUNCTION audit_log_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_log_no_update
+    BEFORE UPDATE OR DELETE ON audit_log
+    FOR EACH ROW EXECUTE FUNCTION audit_log_append_only();
+
+CREATE TRIGGER audit_log_no_truncate
+    BEFORE TRUNCATE ON audit_log
+    FOR EACH STATEMENT EXECUTE FUNCTION audit_log_append_only();
+
+INSERT INTO permissions (name, description) VALUES
+    ('audit:read', 'Read and verify the audit log')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('admin', 'audit:read')
+ON CONFLICT DO NOTHING;
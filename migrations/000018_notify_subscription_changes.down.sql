@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_outbox_subscription_changes;
+DROP TRIGGER IF EXISTS outbox_notify_subscription_change ON outbox;
+DROP FUNCTION IF EXISTS notify_subscription_change();
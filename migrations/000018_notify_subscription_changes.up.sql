@@ -0,0 +1,19 @@
+-- Изменения подписок рассылаются всем репликам через LISTEN/NOTIFY: уведомление отправляется
+-- при фиксации транзакции, записавшей событие в outbox, и содержит только seq события.
+-- Само событие реплики читают из outbox, поэтому размер payload не ограничен 8000 байтами NOTIFY.
+CREATE OR REPLACE FUNCTION notify_subscription_change() RETURNS trigger AS $$
+BEGIN
+    PERFORM pg_notify('subscription_changes', NEW.seq::text);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER outbox_notify_subscription_change
+    AFTER INSERT ON outbox
+    FOR EACH ROW
+    WHEN (NEW.event_type IN ('subscription.created', 'subscription.updated', 'subscription.status_changed', 'subscription.deleted'))
+    EXECUTE FUNCTION notify_subscription_change();
+
+-- Последние изменения подписок читаются при запуске реплики и после переподключения к базе.
+CREATE INDEX IF NOT EXISTS idx_outbox_subscription_changes ON outbox(seq)
+    WHERE event_type IN ('subscription.created', 'subscription.updated', 'subscription.status_changed', 'subscription.deleted');
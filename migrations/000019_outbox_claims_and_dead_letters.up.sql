@@ -0,0 +1,10 @@
+-- Ретранслятор захватывает пачку событий на время публикации (claimed_until) и публикует ее
+-- вне транзакции. Событие, не опубликованное за outbox.max_attempts попыток, переводится
+-- в dead letter (dead_at) и больше не задерживает следующие события своей подписки.
+ALTER TABLE outbox
+    ADD COLUMN IF NOT EXISTS claimed_until TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS dead_at TIMESTAMPTZ;
+
+DROP INDEX IF EXISTS idx_outbox_pending;
+CREATE INDEX IF NOT EXISTS idx_outbox_pending ON outbox(seq) WHERE published_at IS NULL AND dead_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_outbox_dead ON outbox(dead_at) WHERE dead_at IS NOT NULL;
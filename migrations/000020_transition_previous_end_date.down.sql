@@ -0,0 +1,2 @@
+ALTER TABLE subscription_status_transitions
+    DROP COLUMN IF EXISTS previous_end_date;
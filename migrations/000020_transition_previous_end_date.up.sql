@@ -0,0 +1,2 @@
+ALTER TABLE subscription_status_transitions
+    ADD COLUMN IF NOT EXISTS previous_end_date DATE;
@@ -0,0 +1,13 @@
+DROP POLICY IF EXISTS subscriptions_tenant_isolation ON subscriptions;
+
+CREATE POLICY subscriptions_tenant_isolation ON subscriptions
+    USING (
+        COALESCE(current_setting('app.user_ids', true), '') = ''
+        OR user_id = ANY(string_to_array(current_setting('app.user_ids'), ',')::uuid[])
+        OR organization_id IN (
+            SELECT organization_id FROM organization_members
+            WHERE user_id = ANY(string_to_array(current_setting('app.user_ids'), ',')::uuid[]))
+        OR id IN (
+            SELECT subscription_id FROM subscription_members
+            WHERE user_id = ANY(string_to_array(current_setting('app.user_ids'), ',')::uuid[]))
+    );
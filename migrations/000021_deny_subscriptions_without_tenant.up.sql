@@ -0,0 +1,16 @@
+-- Пустой app.user_ids больше не открывает все подписки: соединение без пользователя видит строки,
+-- только если сервис явно снял ограничение (системный вызов, API-ключ без user_ids или действие,
+-- уже разрешенное политикой доступа) и передал app.unrestricted = on.
+DROP POLICY IF EXISTS subscriptions_tenant_isolation ON subscriptions;
+
+CREATE POLICY subscriptions_tenant_isolation ON subscriptions
+    USING (
+        COALESCE(current_setting('app.unrestricted', true), '') = 'on'
+        OR user_id = ANY(string_to_array(NULLIF(current_setting('app.user_ids', true), ''), ',')::uuid[])
+        OR organization_id IN (
+            SELECT organization_id FROM organization_members
+            WHERE user_id = ANY(string_to_array(NULLIF(current_setting('app.user_ids', true), ''), ',')::uuid[]))
+        OR id IN (
+            SELECT subscription_id FROM subscription_members
+            WHERE user_id = ANY(string_to_array(NULLIF(current_setting('app.user_ids', true), ''), ',')::uuid[]))
+    );
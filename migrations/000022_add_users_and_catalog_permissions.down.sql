@@ -0,0 +1,2 @@
+DELETE FROM role_permissions WHERE permission IN ('users:manage', 'catalog:manage');
+DELETE FROM permissions WHERE name IN ('users:manage', 'catalog:manage');
@@ -0,0 +1,11 @@
+-- Права на данные других пользователей и на каталог сервисов. Своими профилем, бюджетами,
+-- категориями и метками пользователь распоряжается без отдельного права.
+INSERT INTO permissions (name, description) VALUES
+    ('users:manage', 'Read and change profiles, budgets, categories and tags of other users and delete users'),
+    ('catalog:manage', 'Add, change and remove catalog services')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('admin', 'users:manage'),
+    ('admin', 'catalog:manage')
+ON CONFLICT DO NOTHING;
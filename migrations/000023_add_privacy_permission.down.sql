@@ -0,0 +1,2 @@
+DELETE FROM role_permissions WHERE permission = 'privacy:manage';
+DELETE FROM permissions WHERE name = 'privacy:manage';
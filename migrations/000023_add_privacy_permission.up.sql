@@ -0,0 +1,8 @@
+-- Выгрузка и удаление данных другого пользователя. Свои данные пользователь выгружает и удаляет сам.
+INSERT INTO permissions (name, description) VALUES
+    ('privacy:manage', 'Export and erase data of other users on data subject requests')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('admin', 'privacy:manage')
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,15 @@
+-- Записи, добавленные после переноса, после отката не пройдут проверку цепочки: их хеш вычислен
+-- от identity_hash. У записей с удаленным исполнителем actor_id и source_ip остаются пустыми.
+ALTER TABLE audit_log ADD COLUMN IF NOT EXISTS actor_id UUID, ADD COLUMN IF NOT EXISTS source_ip TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE audit_log DISABLE TRIGGER audit_log_no_update;
+UPDATE audit_log a SET actor_id = i.actor_id, source_ip = i.source_ip
+FROM audit_identities i
+WHERE i.seq = a.seq;
+ALTER TABLE audit_log ENABLE TRIGGER audit_log_no_update;
+
+ALTER TABLE audit_log DROP COLUMN IF EXISTS identity_hash;
+CREATE INDEX IF NOT EXISTS idx_audit_log_actor ON audit_log(actor_id, seq);
+
+DROP TABLE IF EXISTS audit_identities;
+DROP FUNCTION IF EXISTS audit_identities_no_update();
@@ -0,0 +1,32 @@
+-- Исполнитель и IP-адрес записей журнала аудита хранятся отдельно от журнала и удаляются вместе
+-- с данными пользователя. В цепочку хешей новых записей входит только identity_hash - SHA-256
+-- от случайной соли, исполнителя и IP-адреса: после удаления цепочка по-прежнему проверяется,
+-- а восстановить исполнителя по хешу нельзя.
+CREATE TABLE IF NOT EXISTS audit_identities (
+    seq BIGINT PRIMARY KEY REFERENCES audit_log(seq),
+    actor_id UUID,
+    source_ip TEXT NOT NULL,
+    -- Пустая соль - у записей, добавленных до переноса: их хеш вычислен от самих actor_id и source_ip.
+    salt TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_identities_actor ON audit_identities(actor_id, seq);
+
+-- Исполнителя можно только удалить вместе с данными пользователя, но не изменить.
+CREATE OR REPLACE FUNCTION audit_identities_no_update() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_identities rows can only be erased';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_identities_no_update
+    BEFORE UPDATE ON audit_identities
+    FOR EACH ROW EXECUTE FUNCTION audit_identities_no_update();
+
+INSERT INTO audit_identities (seq, actor_id, source_ip, salt)
+SELECT seq, actor_id, source_ip, '' FROM audit_log
+ON CONFLICT DO NOTHING;
+
+-- ALTER TABLE не вызывает построчные триггеры, поэтому журнал остается только дополняемым.
+ALTER TABLE audit_log ADD COLUMN IF NOT EXISTS identity_hash TEXT NOT NULL DEFAULT '';
+ALTER TABLE audit_log DROP COLUMN IF EXISTS actor_id, DROP COLUMN IF EXISTS source_ip;
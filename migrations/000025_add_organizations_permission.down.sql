@@ -0,0 +1,2 @@
+DELETE FROM role_permissions WHERE permission = 'organizations:manage';
+DELETE FROM permissions WHERE name = 'organizations:manage';
@@ -0,0 +1,9 @@
+-- Управление организациями без членства в них: для API-ключей и администраторов сервиса.
+-- Участник организации действует по своей роли в ней без отдельного права.
+INSERT INTO permissions (name, description) VALUES
+    ('organizations:manage', 'Read, change and delete organizations and their members without being a member')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('admin', 'organizations:manage')
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,15 @@
+-- Записи, добавленные после переноса, после отката не пройдут проверку цепочки: их хеш вычислен
+-- от subject_hash. У записей с удаленным ID ресурса resource_id и params остаются пустыми.
+ALTER TABLE audit_log ADD COLUMN IF NOT EXISTS resource_id TEXT NOT NULL DEFAULT '', ADD COLUMN IF NOT EXISTS params JSONB NOT NULL DEFAULT '{}';
+
+ALTER TABLE audit_log DISABLE TRIGGER audit_log_no_update;
+UPDATE audit_log a SET resource_id = s.resource_id, params = s.params
+FROM audit_subjects s
+WHERE s.seq = a.seq;
+ALTER TABLE audit_log ENABLE TRIGGER audit_log_no_update;
+
+ALTER TABLE audit_log DROP COLUMN IF EXISTS subject_hash;
+CREATE INDEX IF NOT EXISTS idx_audit_log_resource ON audit_log(resource_id, seq);
+
+DROP TABLE IF EXISTS audit_subjects;
+DROP FUNCTION IF EXISTS audit_subjects_no_update();
@@ -0,0 +1,32 @@
+-- ID ресурса и параметры пути записей журнала аудита хранятся отдельно от журнала, как и исполнитель:
+-- в них бывает ID пользователя (маршруты /users/:id, user_id участника), и при удалении его данных
+-- они удаляются. В цепочку хешей новых записей входит только subject_hash - SHA-256 от случайной соли,
+-- ID ресурса и параметров.
+CREATE TABLE IF NOT EXISTS audit_subjects (
+    seq BIGINT PRIMARY KEY REFERENCES audit_log(seq),
+    resource_id TEXT NOT NULL DEFAULT '',
+    params JSONB NOT NULL DEFAULT '{}',
+    -- Пустая соль - у записей, добавленных до переноса: их хеш вычислен от самих resource_id и params.
+    salt TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_subjects_resource ON audit_subjects(resource_id, seq);
+
+-- ID ресурса и параметры можно только удалить вместе с данными пользователя, но не изменить.
+CREATE OR REPLACE FUNCTION audit_subjects_no_update() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_subjects rows can only be erased';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_subjects_no_update
+    BEFORE UPDATE ON audit_subjects
+    FOR EACH ROW EXECUTE FUNCTION audit_subjects_no_update();
+
+INSERT INTO audit_subjects (seq, resource_id, params, salt)
+SELECT seq, resource_id, params, '' FROM audit_log
+ON CONFLICT DO NOTHING;
+
+-- ALTER TABLE не вызывает построчные триггеры, поэтому журнал остается только дополняемым.
+ALTER TABLE audit_log ADD COLUMN IF NOT EXISTS subject_hash TEXT NOT NULL DEFAULT '';
+ALTER TABLE audit_log DROP COLUMN IF EXISTS resource_id, DROP COLUMN IF EXISTS params;